@@ -22,7 +22,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieve all payment records",
+                "description": "Retrieve one page of payment records, newest first by default (admin only)",
                 "consumes": [
                     "application/json"
                 ],
@@ -33,6 +33,62 @@ const docTemplate = `{
                     "payments"
                 ],
                 "summary": "List payments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Attempt status: pending, success or failed",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Payment method: credit_card or promptpay",
+                        "name": "method",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "order_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the paying patient",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest paid_at, RFC 3339",
+                        "name": "paid_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Time before which payments were made, RFC 3339",
+                        "name": "paid_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc (default desc)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum payments to return (default 50, at most 200)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "Payments retrieved successfully",
@@ -40,12 +96,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/dto.GetAllPaymentsResponseDto"
                         }
                     },
+                    "400": {
+                        "description": "Invalid filter or cursor",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Failed to retrieve payments",
                         "schema": {
@@ -60,7 +128,7 @@ const docTemplate = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Create a payment record for a successful payment attempt",
+                "description": "Create a payment record for a successful payment attempt. The amount must equal what the payable is due.",
                 "consumes": [
                     "application/json"
                 ],
@@ -107,6 +175,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Payment attempt already paid",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Failed to create payment",
                         "schema": {
@@ -153,7 +227,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request body or identifiers",
+                        "description": "Invalid request body or identifiers, or line items that do not add up to the amount due",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -210,6 +284,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/dto.UpdatePaymentAttemptRequestDto"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version the change is based on, or * for the current version; required unless the body has version",
+                        "name": "If-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -217,6 +297,12 @@ const docTemplate = `{
                         "description": "Payment attempt updated successfully",
                         "schema": {
                             "$ref": "#/definitions/dto.UpdatePaymentAttemptResponseDto"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the attempt"
+                            }
                         }
                     },
                     "400": {
@@ -237,6 +323,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
+                    "409": {
+                        "description": "Payment attempt was changed by someone else, already has a payment or is held for risk review",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "428": {
+                        "description": "Neither If-Match nor version was sent",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Failed to update payment attempt",
                         "schema": {
@@ -278,6 +376,12 @@ const docTemplate = `{
                         "description": "Payment attempt retrieved successfully",
                         "schema": {
                             "$ref": "#/definitions/dto.GetPaymentAttemptResponseDto"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the attempt, for If-Match"
+                            }
                         }
                     },
                     "400": {
@@ -307,29 +411,50 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/payment/v1/info": {
+        "/api/payment/v1/attempt/{id}/events": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieve all payment information records for the authenticated patient",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Server-Sent Events stream of a payment attempt's status. The first status event carries the current status, or the events missed since Last-Event-ID when the client reconnects. Later changes follow as they happen, with a comment every 15 seconds while idle. The stream ends after success or failed; a client that reconnects after seeing either gets 204, which stops EventSource from retrying.",
                 "produces": [
-                    "application/json"
+                    "text/event-stream"
                 ],
                 "tags": [
-                    "payment-info"
+                    "payment-attempt"
+                ],
+                "summary": "Watch payment attempt status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payment attempt ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the last event received, to resume after it",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    }
                 ],
-                "summary": "List payment information",
                 "responses": {
                     "200": {
-                        "description": "Payment information retrieved successfully",
+                        "description": "status events",
                         "schema": {
-                            "$ref": "#/definitions/dto.GetAllPaymentInfosResponseDto"
+                            "$ref": "#/definitions/dto.PaymentAttemptEventDto"
+                        }
+                    },
+                    "204": {
+                        "description": "The client has already seen the final status"
+                    },
+                    "400": {
+                        "description": "Invalid payment attempt ID",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
                     "401": {
@@ -338,21 +463,29 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Payment attempt not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
                     "500": {
-                        "description": "Failed to retrieve payment information",
+                        "description": "Failed to retrieve payment attempt",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/api/payment/v1/audit": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Update an existing payment information record",
+                "description": "List audit log entries for payment info, attempts and payments, newest first (admin only)",
                 "consumes": [
                     "application/json"
                 ],
@@ -360,29 +493,68 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "payment-info"
+                    "audit"
                 ],
-                "summary": "Update payment information",
+                "summary": "Query audit log",
                 "parameters": [
                     {
-                        "description": "Payment information to update",
-                        "name": "payment_info",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.UpdatePaymentInfoRequestDto"
-                        }
+                        "type": "string",
+                        "description": "Table name, e.g. payments",
+                        "name": "entity_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the changed row",
+                        "name": "entity_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User who made the change",
+                        "name": "actor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "create, update or delete",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Request ID",
+                        "name": "request_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Earliest time, RFC 3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Time before which entries were written, RFC 3339",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum entries to return (default 100, at most 1000)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Payment information updated successfully",
+                        "description": "Audit log retrieved successfully",
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdatePaymentInfoResponseDto"
+                            "$ref": "#/definitions/dto.GetAuditLogsResponseDto"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body",
+                        "description": "Invalid filter",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -393,27 +565,29 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Payment information not found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Failed to update payment information",
+                        "description": "Failed to retrieve audit log",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/payment/v1/documents/{documentId}/reissue": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Create a new payment method for the authenticated patient",
+                "description": "Void a receipt or tax invoice and issue a corrected copy under a new number (admin only)",
                 "consumes": [
                     "application/json"
                 ],
@@ -421,25 +595,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "payment-info"
+                    "payment-documents"
                 ],
-                "summary": "Create payment information",
+                "summary": "Reissue document",
                 "parameters": [
                     {
-                        "description": "Payment information to create",
-                        "name": "payment_info",
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "documentId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason and buyer corrections",
+                        "name": "reissue",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.CreatePaymentInfoRequestDto"
+                            "$ref": "#/definitions/dto.ReissueDocumentRequestDto"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Payment information created successfully",
+                        "description": "Document reissued successfully",
                         "schema": {
-                            "$ref": "#/definitions/dto.CreatePaymentInfoResponseDto"
+                            "$ref": "#/definitions/dto.PaymentDocumentResponseDto"
                         }
                     },
                     "400": {
@@ -460,21 +641,35 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Document already voided",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
                     "500": {
-                        "description": "Failed to create payment information",
+                        "description": "Failed to reissue document",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/payment/v1/documents/{documentId}/void": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Delete an existing payment information record",
+                "description": "Void a receipt or tax invoice; its number is never reused (admin only)",
                 "consumes": [
                     "application/json"
                 ],
@@ -482,25 +677,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "payment-info"
+                    "payment-documents"
                 ],
-                "summary": "Delete payment information",
+                "summary": "Void document",
                 "parameters": [
                     {
-                        "description": "Payment information ID to delete",
-                        "name": "payment_info",
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "documentId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Void reason",
+                        "name": "void",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.DeletePaymentInfoRequestDto"
+                            "$ref": "#/definitions/dto.VoidDocumentRequestDto"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Payment information deleted successfully",
+                        "description": "Document voided successfully",
                         "schema": {
-                            "$ref": "#/definitions/dto.DeletePaymentInfoResponseDto"
+                            "$ref": "#/definitions/dto.PaymentDocumentResponseDto"
                         }
                     },
                     "400": {
@@ -515,14 +717,26 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
                     "404": {
-                        "description": "Payment information not found",
+                        "description": "Document not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Document already voided",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Failed to delete payment information",
+                        "description": "Failed to void document",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -530,42 +744,39 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/payment/v1/info/{id}": {
+        "/api/payment/v1/exports/jobs/{jobId}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieve a payment information record by its identifier",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Report the status of a background export and, once done, its download link (admin only)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "payment-info"
+                    "exports"
                 ],
-                "summary": "Get payment information by ID",
+                "summary": "Get an export job",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Payment information ID",
-                        "name": "id",
+                        "description": "Export job ID",
+                        "name": "jobId",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Payment information retrieved successfully",
+                        "description": "Export job retrieved successfully",
                         "schema": {
-                            "$ref": "#/definitions/dto.GetPaymentInfoByIDResponseDto"
+                            "$ref": "#/definitions/dto.ExportJobResponseDto"
                         }
                     },
                     "400": {
-                        "description": "Invalid payment information ID",
+                        "description": "Invalid job ID",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -576,14 +787,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Payment information not found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Failed to retrieve payment information",
+                    "404": {
+                        "description": "Export job not found",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -591,42 +802,40 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/payment/v1/{id}": {
+        "/api/payment/v1/exports/jobs/{jobId}/download": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieve a payment record by its identifier",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Download the file of a finished background export (admin only)",
                 "produces": [
-                    "application/json"
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
                 ],
                 "tags": [
-                    "payments"
+                    "exports"
                 ],
-                "summary": "Get payment by ID",
+                "summary": "Download an export",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Payment ID",
-                        "name": "id",
+                        "description": "Export job ID",
+                        "name": "jobId",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Payment retrieved successfully",
+                        "description": "Export file",
                         "schema": {
-                            "$ref": "#/definitions/dto.GetPaymentByIDResponseDto"
+                            "type": "file"
                         }
                     },
                     "400": {
-                        "description": "Invalid payment ID",
+                        "description": "Invalid job ID",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
@@ -637,97 +846,2271 @@ const docTemplate = `{
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
                     "404": {
-                        "description": "Payment not found",
+                        "description": "Export job not found or expired",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Export is not done",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Failed to retrieve payment",
+                        "description": "Failed to open export file",
                         "schema": {
                             "$ref": "#/definitions/response.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "dto.CreatePaymentAttemptRequestDto": {
-            "type": "object",
-            "required": [
-                "order_id",
-                "payment_info_id"
-            ],
-            "properties": {
-                "order_id": {
-                    "type": "string"
-                },
-                "payment_info_id": {
-                    "type": "string"
-                }
-            }
         },
-        "dto.CreatePaymentAttemptResponseDto": {
-            "type": "object",
-            "properties": {
-                "payment_attempt_id": {
+        "/api/payment/v1/exports/payments": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Stream the payments made in a period as CSV or XLSX for reconciliation (admin only). Rows are read from the database as they are written, oldest first.",
+                "produces": [
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "exports"
+                ],
+                "summary": "Export payments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start of the period, RFC 3339 or YYYY-MM-DD",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the period, exclusive, RFC 3339 or YYYY-MM-DD",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "csv or xlsx (default csv)",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated columns: payment_id, paid_at, payable_type, payable_id, attempt_id, user_id, method, amount, patient_amount, payer_amount, healthcare_entitlement (default all)",
+                        "name": "columns",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "en or th, the Accept-Language when absent; th writes amounts with thousands separators and Buddhist era dates",
+                        "name": "locale",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Export file",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid period, format, column or locale",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/payment/v1/exports/payments/jobs": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Export payments in the background, for periods too large to download at once (admin only). Poll the job until it is done, then fetch its download_url.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "exports"
+                ],
+                "summary": "Start a payment export job",
+                "parameters": [
+                    {
+                        "description": "Period, format, columns and locale, as for the streaming export",
+                        "name": "export",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ExportPaymentsRequestDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Export started",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ExportJobResponseDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid period, format, column or locale",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/payment/v1/info": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieve one page of payment information records, newest first by default (admin only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment-info"
+                ],
+                "summary": "List payment information",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Owner of the payment information",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Payment method: credit_card or promptpay",
+                        "name": "method",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "asc or desc (default desc)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum records to return (default 50, at most 200)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payment information retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetAllPaymentInfosResponseDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter or cursor",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve payment information",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Update an existing payment information record",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment-info"
+                ],
+                "summary": "Update payment information",
+                "parameters": [
+                    {
+                        "description": "Payment information to update",
+                        "name": "payment_info",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdatePaymentInfoRequestDto"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the version the change is based on, or * for the current version; required unless the body has version",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payment information updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdatePaymentInfoResponseDto"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New version of the payment information"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Payment information not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Payment information was changed by someone else, or a method of the new type is already saved",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "428": {
+                        "description": "Neither If-Match nor version was sent",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update payment information",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Create a new payment method for the authenticated patient",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment-info"
+                ],
+                "summary": "Create payment information",
+                "parameters": [
+                    {
+                        "description": "Payment information to create",
+                        "name": "payment_info",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreatePaymentInfoRequestDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Payment information created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreatePaymentInfoResponseDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A payment method of this type is already saved",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create payment information",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Delete an existing payment information record",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment-info"
+                ],
+                "summary": "Delete payment information",
+                "parameters": [
+                    {
+                        "description": "Payment information ID to delete",
+                        "name": "payment_info",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.DeletePaymentInfoRequestDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payment information deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.DeletePaymentInfoResponseDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Payment information not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete payment information",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/payment/v1/info/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieve a payment information record by its identifier",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment-info"
+                ],
+                "summary": "Get payment information by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payment information ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payment information retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetPaymentInfoByIDResponseDto"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the payment information, for If-Match"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payment information ID",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Payment information not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve payment information",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/payment/v1/me/payments": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List the authenticated patient's payments, newest first, with the order or appointment, the masked payment method, the refund status, a receipt link and the doctor",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "List my payments",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Only payments made in this year, Asia/Bangkok time",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum payments to return (default 50, at most 200)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payments retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetMyPaymentsResponseDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid year or cursor",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve payments",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/payment/v1/orders/{orderId}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List every attempt and payment of an order with the total paid, total refunded, outstanding balance and derived payment status (unpaid, partially_paid, paid, overpaid, refunded). Patients see their own orders; admins, and services on the internal route, see any order.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Get order payments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "orderId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order payments retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetOrderPaymentsResponseDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid order ID",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found or belongs to another user",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve order payments",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/payment/v1/receivables": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieve the insurer and government shares of payments, by status, for invoicing",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "receivables"
+                ],
+                "summary": "List payer receivables",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Receivable status (accrued, invoiced, settled); defaults to accrued",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Receivables retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetReceivablesResponseDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid receivable status",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve receivables",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/payment/v1/risk/reviews": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List payment attempts held for review by the risk checks, oldest first (admin only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risk"
+                ],
+                "summary": "List risk review queue",
+                "responses": {
+                    "200": {
+                        "description": "Risk reviews retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetRiskReviewsResponseDto"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve risk reviews",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/payment/v1/risk/reviews/{reviewId}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Let a payment attempt held for review go ahead (admin only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risk"
+                ],
+                "summary": "Approve risk review",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Risk review ID",
+                        "name": "reviewId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Risk review approved",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RiskReviewResponseDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid risk review ID",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Risk review not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Risk review already closed",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to close risk review",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/payment/v1/risk/reviews/{reviewId}/deny": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Fail a payment attempt held for review (admin only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risk"
+                ],
+                "summary": "Deny risk review",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Risk review ID",
+                        "name": "reviewId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Risk review denied",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RiskReviewResponseDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid risk review ID",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Risk review not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Risk review already closed",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to close risk review",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/payment/v1/tokens/revoke": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Revoke one token by its jti, or every current token of a user, e.g. after logout or suspension (admin only)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Revoke tokens",
+                "parameters": [
+                    {
+                        "description": "Token or user to revoke",
+                        "name": "revocation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.RevokeTokenRequestDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Token revoked successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.RevokeTokenResponseDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to revoke token",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/payment/v1/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieve a payment record by its identifier",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Get payment by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payment retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetPaymentByIDResponseDto"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the payment"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payment ID",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Payment not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve payment",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/payment/v1/{id}/documents": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieve every receipt and tax invoice of a payment, including voided ones, with their audit trail",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment-documents"
+                ],
+                "summary": "List payment documents",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Documents retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetPaymentDocumentsResponseDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payment ID",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Payment not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve documents",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/payment/v1/{id}/receipt": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Render the issued receipt or tax invoice of a payment as PDF",
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "payment-documents"
+                ],
+                "summary": "Download payment receipt",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Document type (receipt, tax_invoice); defaults to receipt",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "en or th; receipts are printed in it, tax invoices always in Thai with English",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rendered document",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payment ID or document type",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Payment not found or document not issued",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to render document",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Issue the receipt of a payment, made out to the patient",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment-documents"
+                ],
+                "summary": "Issue receipt",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Receipt issued successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PaymentDocumentResponseDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payment ID",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Payment or patient profile not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Receipt already issued",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to issue receipt",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/payment/v1/{id}/tax-invoice": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Issue a Thai tax invoice for a payment with the buyer's tax details",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment-documents"
+                ],
+                "summary": "Issue tax invoice",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Buyer details",
+                        "name": "tax_invoice",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.IssueTaxInvoiceRequestDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Tax invoice issued successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PaymentDocumentResponseDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Payment not found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Tax invoice already issued",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to issue tax invoice",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/internal/v1/orders/{orderId}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "List every attempt and payment of an order with the total paid, total refunded, outstanding balance and derived payment status (unpaid, partially_paid, paid, overpaid, refunded). Patients see their own orders; admins, and services on the internal route, see any order.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Get order payments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "orderId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order payments retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetOrderPaymentsResponseDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid order ID",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found or belongs to another user",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve order payments",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/internal/v1/payments/status:batch": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Report the payment state of up to 100 orders or appointments in one call, in the order requested; requires a service token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "internal"
+                ],
+                "summary": "Get payment status of many payables (internal)",
+                "parameters": [
+                    {
+                        "description": "Payables to look up",
+                        "name": "payables",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.BatchPayableStatusRequestDto"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payment statuses retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BatchPayableStatusResponseDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid or too many payables",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid service token",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve payment status",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/internal/v1/payments/{payableType}/{payableId}/status": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Report the payments made on a payable such as an order or appointment, for its owning service to compare with the amount due; requires a service token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "internal"
+                ],
+                "summary": "Get payable payment status (internal)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payable type (order, appointment, delivery, deposit)",
+                        "name": "payableType",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Payable ID",
+                        "name": "payableId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payment status retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.PayableStatusResponseDto"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payable",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid service token",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to retrieve payment status",
+                        "schema": {
+                            "$ref": "#/definitions/response.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.AuditLogDto": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/models.AuditAction"
+                },
+                "actor_id": {
+                    "type": "string"
+                },
+                "actor_role": {
+                    "type": "string"
+                },
+                "after": {
+                    "type": "object"
+                },
+                "before": {
+                    "type": "object"
+                },
+                "client_ip": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "entity_id": {
+                    "type": "string"
+                },
+                "entity_type": {
+                    "type": "string"
+                },
+                "hash": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "prev_hash": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "seq": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.BatchPayableStatusRequestDto": {
+            "type": "object",
+            "required": [
+                "payables"
+            ],
+            "properties": {
+                "payables": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dto.PayableRefDto"
+                    }
+                }
+            }
+        },
+        "dto.BatchPayableStatusResponseDto": {
+            "type": "object",
+            "properties": {
+                "statuses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PayableStatusResponseDto"
+                    }
+                }
+            }
+        },
+        "dto.CreatePaymentAttemptRequestDto": {
+            "type": "object",
+            "required": [
+                "payable_id",
+                "payable_type",
+                "payment_info_id"
+            ],
+            "properties": {
+                "line_items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.LineItemRequestDto"
+                    }
+                },
+                "payable_id": {
+                    "type": "string"
+                },
+                "payable_type": {
+                    "$ref": "#/definitions/models.PayableType"
+                },
+                "payment_info_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreatePaymentAttemptResponseDto": {
+            "type": "object",
+            "properties": {
+                "payment_attempt_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.PaymentStatus"
+                }
+            }
+        },
+        "dto.CreatePaymentInfoRequestDto": {
+            "type": "object",
+            "required": [
+                "details",
+                "payment_method"
+            ],
+            "properties": {
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "payment_method": {
+                    "enum": [
+                        "credit_card",
+                        "promptpay"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PaymentMethod"
+                        }
+                    ]
+                }
+            }
+        },
+        "dto.CreatePaymentInfoResponseDto": {
+            "type": "object",
+            "properties": {
+                "payment_info": {
+                    "$ref": "#/definitions/dto.PaymentInfoDto"
+                }
+            }
+        },
+        "dto.CreatePaymentRequestDto": {
+            "type": "object",
+            "required": [
+                "amount",
+                "payment_attempt_id"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "payment_attempt_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.CreatePaymentResponseDto": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "attempt_id": {
+                    "type": "string"
+                },
+                "healthcare_entitlement": {
+                    "type": "string"
+                },
+                "paid_at": {
+                    "type": "string"
+                },
+                "patient_amount": {
+                    "type": "number"
+                },
+                "payable_id": {
+                    "type": "string"
+                },
+                "payable_type": {
+                    "$ref": "#/definitions/models.PayableType"
+                },
+                "payer_amount": {
+                    "type": "number"
+                },
+                "payment_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.DeletePaymentInfoRequestDto": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.DeletePaymentInfoResponseDto": {
+            "type": "object",
+            "properties": {
+                "deleted_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ExportJobDto": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "download_url": {
+                    "type": "string"
+                },
+                "filename": {
+                    "type": "string"
+                },
+                "format": {
+                    "$ref": "#/definitions/export.Format"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "rows": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/export.JobStatus"
+                }
+            }
+        },
+        "dto.ExportJobResponseDto": {
+            "type": "object",
+            "properties": {
+                "job": {
+                    "$ref": "#/definitions/dto.ExportJobDto"
+                }
+            }
+        },
+        "dto.ExportPaymentsRequestDto": {
+            "type": "object",
+            "properties": {
+                "columns": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "locale": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GetAllPaymentInfosResponseDto": {
+            "type": "object",
+            "properties": {
+                "delivery_infos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PaymentInfoDto"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor is passed as cursor to fetch the next page; null on the last",
+                    "type": "string"
+                }
+            }
+        },
+        "dto.GetAllPaymentsResponseDto": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "description": "NextCursor is passed as cursor to fetch the next page; null on the last",
+                    "type": "string"
+                },
+                "payments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PaymentDto"
+                    }
+                }
+            }
+        },
+        "dto.GetAuditLogsResponseDto": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.AuditLogDto"
+                    }
+                }
+            }
+        },
+        "dto.GetMyPaymentsResponseDto": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "description": "NextCursor is passed as cursor to fetch the next page; null on the last",
+                    "type": "string"
+                },
+                "payments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.MyPaymentDto"
+                    }
+                }
+            }
+        },
+        "dto.GetOrderPaymentsResponseDto": {
+            "type": "object",
+            "properties": {
+                "amount_due": {
+                    "type": "number"
+                },
+                "attempts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PaymentAttemptDto"
+                    }
+                },
+                "order_id": {
+                    "type": "string"
+                },
+                "outstanding_balance": {
+                    "type": "number"
+                },
+                "payments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PaymentDto"
+                    }
+                },
+                "status": {
+                    "$ref": "#/definitions/dto.OrderPaymentStatus"
+                },
+                "total_paid": {
+                    "type": "number"
+                },
+                "total_refunded": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.GetPaymentAttemptResponseDto": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "method": {
+                    "$ref": "#/definitions/models.PaymentMethod"
+                },
+                "payable_id": {
+                    "type": "string"
+                },
+                "payable_type": {
+                    "$ref": "#/definitions/models.PayableType"
+                },
+                "payment_attempt_id": {
+                    "type": "string"
+                },
+                "payment_info_id": {
                     "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.PaymentStatus"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "dto.CreatePaymentInfoRequestDto": {
+        "dto.GetPaymentByIDResponseDto": {
             "type": "object",
-            "required": [
-                "details",
-                "payment_method"
-            ],
             "properties": {
-                "details": {
+                "line_items": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "$ref": "#/definitions/dto.LineItemDto"
                     }
                 },
-                "payment_method": {
+                "payment": {
+                    "$ref": "#/definitions/dto.PaymentDto"
+                }
+            }
+        },
+        "dto.GetPaymentDocumentsResponseDto": {
+            "type": "object",
+            "properties": {
+                "documents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PaymentDocumentDto"
+                    }
+                }
+            }
+        },
+        "dto.GetPaymentInfoByIDResponseDto": {
+            "type": "object",
+            "properties": {
+                "payment_info": {
+                    "$ref": "#/definitions/dto.PaymentInfoDto"
+                }
+            }
+        },
+        "dto.GetReceivablesResponseDto": {
+            "type": "object",
+            "properties": {
+                "receivables": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ReceivableDto"
+                    }
+                }
+            }
+        },
+        "dto.GetRiskReviewsResponseDto": {
+            "type": "object",
+            "properties": {
+                "reviews": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.RiskAssessmentDto"
+                    }
+                }
+            }
+        },
+        "dto.IssueTaxInvoiceRequestDto": {
+            "type": "object",
+            "required": [
+                "buyer_address",
+                "buyer_name",
+                "buyer_tax_id"
+            ],
+            "properties": {
+                "buyer_address": {
+                    "type": "string"
+                },
+                "buyer_branch": {
+                    "type": "string"
+                },
+                "buyer_name": {
+                    "type": "string"
+                },
+                "buyer_tax_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.LineItemDto": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "$ref": "#/definitions/models.LineItemCategory"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "tax": {
+                    "type": "number"
+                },
+                "total": {
+                    "type": "number"
+                },
+                "unit_price": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.LineItemRequestDto": {
+            "type": "object",
+            "required": [
+                "category",
+                "description"
+            ],
+            "properties": {
+                "category": {
                     "enum": [
-                        "credit_card",
-                        "promptpay"
+                        "medicine",
+                        "delivery_fee",
+                        "consultation_fee",
+                        "discount",
+                        "other"
                     ],
                     "allOf": [
                         {
-                            "$ref": "#/definitions/models.PaymentMethod"
+                            "$ref": "#/definitions/models.LineItemCategory"
                         }
                     ]
+                },
+                "description": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "tax": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "unit_price": {
+                    "type": "number"
                 }
             }
         },
-        "dto.CreatePaymentInfoResponseDto": {
+        "dto.MyPaymentDto": {
             "type": "object",
             "properties": {
-                "payment_info": {
-                    "$ref": "#/definitions/dto.PaymentInfoDto"
+                "amount": {
+                    "type": "number"
+                },
+                "doctor_id": {
+                    "type": "string"
+                },
+                "doctor_name": {
+                    "type": "string"
+                },
+                "masked_payment_method": {
+                    "type": "string"
+                },
+                "paid_at": {
+                    "type": "string"
+                },
+                "patient_amount": {
+                    "type": "number"
+                },
+                "payable_id": {
+                    "type": "string"
+                },
+                "payable_type": {
+                    "$ref": "#/definitions/models.PayableType"
+                },
+                "payment_id": {
+                    "type": "string"
+                },
+                "payment_method": {
+                    "description": "PaymentMethod is empty when the payment information has been deleted",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PaymentMethod"
+                        }
+                    ]
+                },
+                "receipt_url": {
+                    "type": "string"
+                },
+                "refund_status": {
+                    "$ref": "#/definitions/dto.RefundStatus"
                 }
             }
         },
-        "dto.CreatePaymentRequestDto": {
+        "dto.OrderPaymentStatus": {
+            "type": "string",
+            "enum": [
+                "unpaid",
+                "partially_paid",
+                "paid",
+                "overpaid",
+                "refunded"
+            ],
+            "x-enum-varnames": [
+                "OrderPaymentStatusUnpaid",
+                "OrderPaymentStatusPartiallyPaid",
+                "OrderPaymentStatusPaid",
+                "OrderPaymentStatusOverpaid",
+                "OrderPaymentStatusRefunded"
+            ]
+        },
+        "dto.PayableRefDto": {
             "type": "object",
             "required": [
-                "amount",
-                "payment_attempt_id"
+                "payable_id",
+                "payable_type"
             ],
             "properties": {
-                "amount": {
-                    "type": "number",
-                    "minimum": 0
+                "payable_id": {
+                    "type": "string"
+                },
+                "payable_type": {
+                    "$ref": "#/definitions/models.PayableType"
+                }
+            }
+        },
+        "dto.PayableStatusResponseDto": {
+            "type": "object",
+            "properties": {
+                "attempt_count": {
+                    "type": "integer"
+                },
+                "has_payments": {
+                    "type": "boolean"
+                },
+                "latest_attempt_status": {
+                    "$ref": "#/definitions/models.PaymentStatus"
+                },
+                "payable_id": {
+                    "type": "string"
+                },
+                "payable_type": {
+                    "$ref": "#/definitions/models.PayableType"
+                },
+                "payment_count": {
+                    "type": "integer"
+                },
+                "total_paid": {
+                    "type": "number"
+                }
+            }
+        },
+        "dto.PaymentAttemptDto": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "method": {
+                    "$ref": "#/definitions/models.PaymentMethod"
+                },
+                "payment_attempt_id": {
+                    "type": "string"
+                },
+                "payment_info_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.PaymentStatus"
+                }
+            }
+        },
+        "dto.PaymentAttemptEventDto": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string"
+                },
+                "payment_attempt_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.PaymentStatus"
+                }
+            }
+        },
+        "dto.PaymentDocumentDto": {
+            "type": "object",
+            "properties": {
+                "buyer_name": {
+                    "type": "string"
+                },
+                "buyer_tax_id": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.PaymentDocumentEventDto"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "issued_at": {
+                    "type": "string"
+                },
+                "number": {
+                    "type": "string"
+                },
+                "payment_id": {
+                    "type": "string"
+                },
+                "replaces_document_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.DocumentStatus"
+                },
+                "subtotal": {
+                    "type": "number"
+                },
+                "total": {
+                    "type": "number"
+                },
+                "type": {
+                    "$ref": "#/definitions/models.DocumentType"
+                },
+                "vat_amount": {
+                    "type": "number"
+                },
+                "void_reason": {
+                    "type": "string"
+                },
+                "voided_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.PaymentDocumentEventDto": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/models.DocumentAction"
+                },
+                "actor_id": {
+                    "type": "string"
+                },
+                "actor_role": {
+                    "type": "string"
                 },
-                "payment_attempt_id": {
+                "created_at": {
+                    "type": "string"
+                },
+                "reason": {
                     "type": "string"
                 }
             }
         },
-        "dto.CreatePaymentResponseDto": {
+        "dto.PaymentDocumentResponseDto": {
+            "type": "object",
+            "properties": {
+                "document": {
+                    "$ref": "#/definitions/dto.PaymentDocumentDto"
+                }
+            }
+        },
+        "dto.PaymentDto": {
             "type": "object",
             "properties": {
                 "amount": {
@@ -736,98 +3119,146 @@ const docTemplate = `{
                 "attempt_id": {
                     "type": "string"
                 },
-                "order_id": {
+                "healthcare_entitlement": {
                     "type": "string"
                 },
                 "paid_at": {
                     "type": "string"
                 },
+                "patient_amount": {
+                    "type": "number"
+                },
+                "payable_id": {
+                    "type": "string"
+                },
+                "payable_type": {
+                    "$ref": "#/definitions/models.PayableType"
+                },
+                "payer_amount": {
+                    "type": "number"
+                },
                 "payment_id": {
                     "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "dto.DeletePaymentInfoRequestDto": {
+        "dto.PaymentInfoDto": {
             "type": "object",
-            "required": [
-                "id"
-            ],
             "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
                 "id": {
                     "type": "string"
+                },
+                "payment_method": {
+                    "$ref": "#/definitions/models.PaymentMethod"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
-        "dto.DeletePaymentInfoResponseDto": {
+        "dto.ReceivableDto": {
             "type": "object",
             "properties": {
-                "deleted_at": {
+                "amount": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "healthcare_entitlement": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
+                },
+                "payer_name": {
+                    "type": "string"
+                },
+                "payer_type": {
+                    "$ref": "#/definitions/models.PayerType"
+                },
+                "payment_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.ReceivableStatus"
                 }
             }
         },
-        "dto.GetAllPaymentInfosResponseDto": {
-            "type": "object",
-            "properties": {
-                "delivery_infos": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dto.PaymentInfoDto"
-                    }
-                }
-            }
-        },
-        "dto.GetAllPaymentsResponseDto": {
-            "type": "object",
-            "properties": {
-                "payments": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/dto.PaymentDto"
-                    }
-                }
-            }
+        "dto.RefundStatus": {
+            "type": "string",
+            "enum": [
+                "none",
+                "partially_refunded",
+                "refunded"
+            ],
+            "x-enum-varnames": [
+                "RefundStatusNone",
+                "RefundStatusPartial",
+                "RefundStatusRefunded"
+            ]
         },
-        "dto.GetPaymentAttemptResponseDto": {
+        "dto.ReissueDocumentRequestDto": {
             "type": "object",
+            "required": [
+                "reason"
+            ],
             "properties": {
-                "method": {
-                    "$ref": "#/definitions/models.PaymentMethod"
+                "buyer_address": {
+                    "type": "string"
                 },
-                "order_id": {
+                "buyer_branch": {
                     "type": "string"
                 },
-                "payment_attempt_id": {
+                "buyer_name": {
                     "type": "string"
                 },
-                "payment_info_id": {
+                "buyer_tax_id": {
                     "type": "string"
                 },
-                "status": {
-                    "$ref": "#/definitions/models.PaymentStatus"
+                "reason": {
+                    "type": "string"
                 }
             }
         },
-        "dto.GetPaymentByIDResponseDto": {
+        "dto.RevokeTokenRequestDto": {
             "type": "object",
             "properties": {
-                "payment": {
-                    "$ref": "#/definitions/dto.PaymentDto"
+                "jti": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
                 }
             }
         },
-        "dto.GetPaymentInfoByIDResponseDto": {
+        "dto.RevokeTokenResponseDto": {
             "type": "object",
             "properties": {
-                "payment_info": {
-                    "$ref": "#/definitions/dto.PaymentInfoDto"
+                "revocation": {
+                    "$ref": "#/definitions/dto.TokenRevocationDto"
                 }
             }
         },
-        "dto.PaymentDto": {
+        "dto.RiskAssessmentDto": {
             "type": "object",
             "properties": {
                 "amount": {
@@ -836,40 +3267,78 @@ const docTemplate = `{
                 "attempt_id": {
                     "type": "string"
                 },
-                "order_id": {
+                "card_country": {
                     "type": "string"
                 },
-                "paid_at": {
+                "client_ip": {
                     "type": "string"
                 },
-                "payment_id": {
+                "created_at": {
+                    "type": "string"
+                },
+                "decision": {
+                    "$ref": "#/definitions/models.RiskDecision"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "reasons": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "request_country": {
+                    "type": "string"
+                },
+                "review_status": {
+                    "$ref": "#/definitions/models.ReviewStatus"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "reviewed_by": {
+                    "type": "string"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "dto.PaymentInfoDto": {
+        "dto.RiskReviewResponseDto": {
+            "type": "object",
+            "properties": {
+                "attempt_status": {
+                    "$ref": "#/definitions/models.PaymentStatus"
+                },
+                "review": {
+                    "$ref": "#/definitions/dto.RiskAssessmentDto"
+                }
+            }
+        },
+        "dto.TokenRevocationDto": {
             "type": "object",
             "properties": {
                 "created_at": {
                     "type": "string"
                 },
-                "details": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                "expires_at": {
+                    "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "payment_method": {
-                    "$ref": "#/definitions/models.PaymentMethod"
+                "jti": {
+                    "type": "string"
                 },
-                "user_id": {
+                "reason": {
                     "type": "string"
                 },
-                "version": {
-                    "type": "integer"
+                "revoked_by": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
                 }
             }
         },
@@ -885,6 +3354,10 @@ const docTemplate = `{
                 },
                 "status": {
                     "$ref": "#/definitions/models.PaymentStatus"
+                },
+                "version": {
+                    "description": "Version is the version the change is based on. If-Match takes its\nplace when sent; one of the two is required.",
+                    "type": "integer"
                 }
             }
         },
@@ -894,9 +3367,12 @@ const docTemplate = `{
                 "method": {
                     "$ref": "#/definitions/models.PaymentMethod"
                 },
-                "order_id": {
+                "payable_id": {
                     "type": "string"
                 },
+                "payable_type": {
+                    "$ref": "#/definitions/models.PayableType"
+                },
                 "payment_attempt_id": {
                     "type": "string"
                 },
@@ -905,6 +3381,9 @@ const docTemplate = `{
                 },
                 "status": {
                     "$ref": "#/definitions/models.PaymentStatus"
+                },
+                "version": {
+                    "type": "integer"
                 }
             }
         },
@@ -935,6 +3414,10 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.PaymentMethod"
                         }
                     ]
+                },
+                "version": {
+                    "description": "Version is the version the change is based on. If-Match takes its\nplace when sent; one of the two is required.",
+                    "type": "integer"
                 }
             }
         },
@@ -964,6 +3447,134 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.VoidDocumentRequestDto": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "export.Format": {
+            "type": "string",
+            "enum": [
+                "csv",
+                "xlsx"
+            ],
+            "x-enum-varnames": [
+                "FormatCSV",
+                "FormatXLSX"
+            ]
+        },
+        "export.JobStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "running",
+                "done",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "JobStatusPending",
+                "JobStatusRunning",
+                "JobStatusDone",
+                "JobStatusFailed"
+            ]
+        },
+        "models.AuditAction": {
+            "type": "string",
+            "enum": [
+                "create",
+                "update",
+                "delete"
+            ],
+            "x-enum-varnames": [
+                "AuditActionCreate",
+                "AuditActionUpdate",
+                "AuditActionDelete"
+            ]
+        },
+        "models.DocumentAction": {
+            "type": "string",
+            "enum": [
+                "issued",
+                "reissued",
+                "voided"
+            ],
+            "x-enum-varnames": [
+                "DocumentActionIssued",
+                "DocumentActionReissued",
+                "DocumentActionVoided"
+            ]
+        },
+        "models.DocumentStatus": {
+            "type": "string",
+            "enum": [
+                "issued",
+                "voided"
+            ],
+            "x-enum-varnames": [
+                "DocumentStatusIssued",
+                "DocumentStatusVoided"
+            ]
+        },
+        "models.DocumentType": {
+            "type": "string",
+            "enum": [
+                "receipt",
+                "tax_invoice"
+            ],
+            "x-enum-varnames": [
+                "DocumentTypeReceipt",
+                "DocumentTypeTaxInvoice"
+            ]
+        },
+        "models.LineItemCategory": {
+            "type": "string",
+            "enum": [
+                "medicine",
+                "delivery_fee",
+                "consultation_fee",
+                "discount",
+                "other"
+            ],
+            "x-enum-varnames": [
+                "LineItemCategoryMedicine",
+                "LineItemCategoryDeliveryFee",
+                "LineItemCategoryConsultationFee",
+                "LineItemCategoryDiscount",
+                "LineItemCategoryOther"
+            ]
+        },
+        "models.PayableType": {
+            "type": "string",
+            "enum": [
+                "order",
+                "appointment",
+                "delivery",
+                "deposit"
+            ],
+            "x-enum-varnames": [
+                "PayableTypeOrder",
+                "PayableTypeAppointment",
+                "PayableTypeDelivery",
+                "PayableTypeDeposit"
+            ]
+        },
+        "models.PayerType": {
+            "type": "string",
+            "enum": [
+                "insurer",
+                "government"
+            ],
+            "x-enum-varnames": [
+                "PayerTypeInsurer",
+                "PayerTypeGovernment"
+            ]
+        },
         "models.PaymentMethod": {
             "type": "string",
             "enum": [
@@ -988,10 +3599,63 @@ const docTemplate = `{
                 "PaymentStatusFailed"
             ]
         },
+        "models.ReceivableStatus": {
+            "type": "string",
+            "enum": [
+                "accrued",
+                "invoiced",
+                "settled"
+            ],
+            "x-enum-varnames": [
+                "ReceivableStatusAccrued",
+                "ReceivableStatusInvoiced",
+                "ReceivableStatusSettled"
+            ]
+        },
+        "models.ReviewStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "approved",
+                "denied"
+            ],
+            "x-enum-varnames": [
+                "ReviewStatusPending",
+                "ReviewStatusApproved",
+                "ReviewStatusDenied"
+            ]
+        },
+        "models.RiskDecision": {
+            "type": "string",
+            "enum": [
+                "allow",
+                "review",
+                "block"
+            ],
+            "x-enum-varnames": [
+                "RiskDecisionAllow",
+                "RiskDecisionReview",
+                "RiskDecisionBlock"
+            ]
+        },
         "response.ErrorResponse": {
             "type": "object",
             "properties": {
-                "error": {
+                "code": {
+                    "type": "string",
+                    "example": "bad_request"
+                },
+                "fields": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "invalid request body"
+                },
+                "request_id": {
                     "type": "string"
                 }
             }
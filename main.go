@@ -1,29 +1,42 @@
 package main
 
 import (
-	"bytes"
+	"context"
 	"database/sql"
 	"embed"
-	"encoding/json"
 	"fmt"
-	"io"
 	"log"
+	"net"
 	"os"
-	"reflect"
+	"path/filepath"
+	"strings"
+	"time"
 
-	// "payment-service/cmd"
+	"payment-service/cmd"
+	"payment-service/pkg/apperr"
+	"payment-service/pkg/audit"
 	"payment-service/pkg/clients"
 	"payment-service/pkg/config"
 	dbpkg "payment-service/pkg/db"
+	"payment-service/pkg/events"
+	"payment-service/pkg/export"
 	"payment-service/pkg/handlers"
 	"payment-service/pkg/jwt"
+	"payment-service/pkg/middleware"
+	"payment-service/pkg/models"
+	"payment-service/pkg/payable"
+	"payment-service/pkg/receipt"
 	"payment-service/pkg/repository"
+	"payment-service/pkg/revocation"
+	"payment-service/pkg/risk"
 	"payment-service/pkg/routes"
+	"payment-service/pkg/rpc"
 	service "payment-service/pkg/services"
+	"payment-service/pkg/validation"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
 	"github.com/pressly/goose/v3"
 )
 
@@ -71,7 +84,14 @@ func main() {
 	if err != nil {
 		log.Fatalf("cannot get *sql.DB from gorm: %v", err)
 	}
-	// cmd.InitCmd()
+
+	// Commands such as `payment-service audit verify` run and exit
+	if len(os.Args) > 1 {
+		if err := cmd.Run(os.Args[1:], gormDB); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 
 	// Run migrations on start if enabled
 	if config.Get("MIGRATE_ON_START", "true") == "true" {
@@ -80,63 +100,210 @@ func main() {
 		}
 	}
 
-	userServiceUrl := config.Get("USER_SERVICE_URL", "http://localhost:8000")
-	userClient := clients.NewUserClient(userServiceUrl)
+	// service tokens are signed with their own key, so a user token can
+	// never pass as a service token nor the reverse
+	jwtSecret := config.Get("JWT_SECRET", "secret")
+	serviceTokenSecret := config.Get("SERVICE_TOKEN_SECRET", "")
+	if serviceTokenSecret == "" || serviceTokenSecret == jwtSecret {
+		log.Fatal("SERVICE_TOKEN_SECRET must be set and differ from JWT_SECRET")
+	}
 	jwtService := jwt.NewJwtService(
-		config.Get("JWT_SECRET", "secret"),
+		jwtSecret,
 		config.GetInt("JWT_TTL", 3600),
+		config.Get("SERVICE_NAME", "payment-service"),
+		serviceTokenSecret,
+		config.GetInt("SERVICE_TOKEN_TTL", 60),
 	)
+	jwtService.Issuer = config.Get("JWT_ISSUER", "")
+	jwtService.Audience = config.Get("JWT_AUDIENCE", "")
+	jwtService.Leeway = time.Duration(config.GetInt("JWT_LEEWAY_SEC", 30)) * time.Second
+	// JWT_MAX_LIFETIME_SEC bounds identity provider tokens as well, so that
+	// token revocations can be forgotten once it has passed
+	jwtService.MaxLifetime = time.Duration(config.GetInt("JWT_MAX_LIFETIME_SEC", jwtService.TTL)) * time.Second
+	if jwtService.MaxLifetime < time.Duration(jwtService.TTL)*time.Second {
+		log.Fatal("JWT_MAX_LIFETIME_SEC must not be shorter than JWT_TTL")
+	}
+
+	httpClientConfig := clients.DefaultHttpClientConfig()
+	httpClientConfig.Timeout = time.Duration(config.GetInt("HTTP_CLIENT_TIMEOUT_MS", 5000)) * time.Millisecond
+	httpClientConfig.MaxRetries = config.GetInt("HTTP_CLIENT_MAX_RETRIES", httpClientConfig.MaxRetries)
+	httpClientConfig.BreakerThreshold = config.GetInt("HTTP_CLIENT_BREAKER_THRESHOLD", httpClientConfig.BreakerThreshold)
+	httpClientConfig.BreakerCooldown = time.Duration(config.GetInt("HTTP_CLIENT_BREAKER_COOLDOWN_SEC", 30)) * time.Second
+	httpClientConfig.ServiceTokens = jwtService
+	httpClientConfig.LogBodies = config.Get("HTTP_CLIENT_LOG_BODIES", "false") == "true"
+
+	// JWKS_SOURCE is a file path or an http(s) URL of the identity provider's keys
+	if jwksSource := config.Get("JWKS_SOURCE", ""); jwksSource != "" {
+		keys, err := jwt.NewKeySet(
+			context.Background(),
+			jwksSource,
+			time.Duration(config.GetInt("JWKS_REFRESH_SEC", 300))*time.Second,
+			clients.NewHttpClient("jwks", jwksSource, httpClientConfig),
+		)
+		if err != nil {
+			log.Fatalf("cannot load JWKS: %v", err)
+		}
+		go keys.Run(context.Background())
+		jwtService.Keys = keys
+	}
+
+	userServiceUrl := config.Get("USER_SERVICE_URL", "http://localhost:8000")
+	profileCacheConfig := clients.DefaultProfileCacheConfig()
+	profileCacheConfig.TTL = time.Duration(config.GetInt("PROFILE_CACHE_TTL_SEC", 300)) * time.Second
+	profileCacheConfig.BatchWindow = time.Duration(config.GetInt("PROFILE_CACHE_BATCH_WINDOW_MS", 5)) * time.Millisecond
+	userClient := clients.NewCachedUserClient(
+		clients.NewUserClient(userServiceUrl, httpClientConfig),
+		profileCacheConfig,
+	)
+	orderServiceUrl := config.Get("ORDER_SERVICE_URL", "http://localhost:8002")
+	orderClient := clients.NewOrderClient(orderServiceUrl, httpClientConfig)
+	appointmentServiceUrl := config.Get("APPOINTMENT_SERVICE_URL", "http://localhost:8001")
+	appointmentClient := clients.NewAppointmentClient(appointmentServiceUrl, httpClientConfig)
+
+	// Every change to these tables is recorded in the audit log
+	if err := gormDB.Use(audit.NewPlugin(&models.PaymentInformation{}, &models.PaymentAttempt{}, &models.Payment{}, &models.Refund{})); err != nil {
+		log.Fatalf("cannot register audit plugin: %v", err)
+	}
 
 	// Initialize Payment Service dependencies
-	paymentInformationRepository := repository.NewPaymentInformationRepository(gormDB)
-	paymentAttemptRepository := repository.NewPaymentAttemptRepository(gormDB)
-	paymentRepository := repository.NewPaymentRepository(gormDB)
-
-	paymentService := service.NewPaymentService(
-		gormDB,
-		paymentInformationRepository,
-		paymentAttemptRepository,
-		paymentRepository,
-		userClient,
+	repositories := repository.NewRepositories(gormDB)
+
+	// Revocations are kept as long as the longest-lived token they can deny
+	revocationStore := revocation.NewStore(
+		repositories.TokenRevocations,
+		jwtService.MaxLifetime+jwtService.Leeway,
+		time.Duration(config.GetInt("TOKEN_REVOCATION_REFRESH_SEC", 10))*time.Second,
+	)
+	if err := revocationStore.Load(context.Background()); err != nil {
+		log.Fatalf("cannot load token revocations: %v", err)
+	}
+	go revocationStore.Run(context.Background())
+	jwtService.Revocations = revocationStore
+
+	// Background exports keep their files on local disk until they expire
+	exportJobs, err := export.NewJobs(
+		config.Get("EXPORT_DIR", filepath.Join(os.TempDir(), "payment-exports")),
+		time.Duration(config.GetInt("EXPORT_JOB_TTL_SEC", 86400))*time.Second,
+		config.GetInt("EXPORT_JOB_CONCURRENCY", 2),
+	)
+	if err != nil {
+		log.Fatalf("cannot set up exports: %v", err)
+	}
+
+	// Attempt status changes are pushed to clients watching the attempt
+	attemptEvents := events.NewBroker(
+		config.GetInt("ATTEMPT_EVENT_HISTORY", 16),
+		time.Duration(config.GetInt("ATTEMPT_EVENT_RETENTION_SEC", 3600))*time.Second,
 	)
 
+	// card fingerprints are stored, so their key must outlive, and stay
+	// apart from, any signing secret
+	fingerprintKey := config.Get("RISK_FINGERPRINT_KEY", "")
+	if fingerprintKey == "" || fingerprintKey == jwtSecret || fingerprintKey == serviceTokenSecret {
+		log.Fatal("RISK_FINGERPRINT_KEY must be set and differ from JWT_SECRET and SERVICE_TOKEN_SECRET")
+	}
+	riskConfig := risk.DefaultConfig()
+	riskConfig.FingerprintKey = []byte(fingerprintKey)
+	riskConfig.Window = time.Duration(config.GetInt("RISK_WINDOW_SEC", 3600)) * time.Second
+	riskConfig.UserAttempts = risk.Thresholds{
+		Review: config.GetInt("RISK_USER_ATTEMPTS_REVIEW", riskConfig.UserAttempts.Review),
+		Block:  config.GetInt("RISK_USER_ATTEMPTS_BLOCK", riskConfig.UserAttempts.Block),
+	}
+	riskConfig.CardAttempts = risk.Thresholds{
+		Review: config.GetInt("RISK_CARD_ATTEMPTS_REVIEW", riskConfig.CardAttempts.Review),
+		Block:  config.GetInt("RISK_CARD_ATTEMPTS_BLOCK", riskConfig.CardAttempts.Block),
+	}
+	riskConfig.IPAttempts = risk.Thresholds{
+		Review: config.GetInt("RISK_IP_ATTEMPTS_REVIEW", riskConfig.IPAttempts.Review),
+		Block:  config.GetInt("RISK_IP_ATTEMPTS_BLOCK", riskConfig.IPAttempts.Block),
+	}
+	riskConfig.FailedRatioReview = config.GetFloat("RISK_FAILED_RATIO_REVIEW", riskConfig.FailedRatioReview)
+	riskConfig.FailedRatioBlock = config.GetFloat("RISK_FAILED_RATIO_BLOCK", riskConfig.FailedRatioBlock)
+	riskConfig.FailedRatioMinAttempts = config.GetInt("RISK_FAILED_RATIO_MIN_ATTEMPTS", riskConfig.FailedRatioMinAttempts)
+	riskConfig.NewCardAmount = config.GetFloat("RISK_NEW_CARD_AMOUNT", riskConfig.NewCardAmount)
+	riskConfig.CountryMismatch = models.RiskDecision(config.Get("RISK_COUNTRY_MISMATCH", string(riskConfig.CountryMismatch)))
+
+	// Register a resolver for every kind of payable this service can bill
+	payableRegistry := payable.NewRegistry()
+	payableRegistry.Register(models.PayableTypeOrder, payable.NewOrderResolver(orderClient))
+	payableRegistry.Register(models.PayableTypeAppointment, payable.NewAppointmentResolver(
+		appointmentClient,
+		config.GetFloat("APPOINTMENT_CONSULTATION_FEE", 0),
+	))
+
+	paymentService := service.NewPaymentService(service.Dependencies{
+		UnitOfWork:   repository.NewUnitOfWork(gormDB),
+		Repositories: repositories,
+		Users:        userClient,
+		Payables:     payableRegistry,
+		Renderer:     receipt.NewRenderer(config.Get("RECEIPT_FONT_PATH", "/usr/share/fonts/truetype/tlwg/Garuda.ttf")),
+		Seller: receipt.Seller{
+			Name:       config.Get("SELLER_NAME", ""),
+			TaxID:      config.Get("SELLER_TAX_ID", ""),
+			Address:    config.Get("SELLER_ADDRESS", ""),
+			BranchCode: config.Get("SELLER_BRANCH_CODE", "00000"),
+			VatRate:    config.GetFloat("VAT_RATE", 7),
+		},
+		Revocations:   revocationStore,
+		Risk:          riskConfig,
+		ExportJobs:    exportJobs,
+		AttemptEvents: attemptEvents,
+	})
+
 	// Initialize Handlers
 	paymentHandler := handlers.NewPaymentHandler(paymentService)
-	validate := validator.New()
+	validate, err := validation.New()
+	if err != nil {
+		log.Fatalf("cannot set up validation: %v", err)
+	}
 
+	// Behind a proxy, c.IP() is the proxy unless it tells us the client's
+	// address. PROXY_HEADER is only read from TRUSTED_PROXIES, and has to be
+	// one they overwrite, e.g. X-Real-IP, since clients can send it too.
+	var trustedProxies []string
+	if proxies := config.Get("TRUSTED_PROXIES", ""); proxies != "" {
+		for _, proxy := range strings.Split(proxies, ",") {
+			trustedProxies = append(trustedProxies, strings.TrimSpace(proxy))
+		}
+	}
 	app := fiber.New(fiber.Config{
-		JSONDecoder: func(b []byte, v any) error {
-			dec := json.NewDecoder(bytes.NewReader(b))
-			dec.DisallowUnknownFields()
-			if err := dec.Decode(v); err != nil {
-				return fmt.Errorf("decode: %w", err)
-			}
-			if err := dec.Decode(new(struct{})); err != io.EOF {
-				return fmt.Errorf("decode: trailing data")
-			}
-
-			rv := reflect.ValueOf(v)
-			for rv.Kind() == reflect.Pointer {
-				rv = rv.Elem()
-			}
-
-			if rv.Kind() == reflect.Struct {
-				if err := validate.Struct(v); err != nil {
-					return err
-				}
-			}
-			return nil
-		},
+		JSONDecoder:             validate.DecodeJSON,
+		ErrorHandler:            apperr.ErrorHandler,
+		ProxyHeader:             config.Get("PROXY_HEADER", ""),
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          trustedProxies,
+		EnableIPValidation:      true,
 	})
 
+	app.Use(requestid.New())
+	app.Use(middleware.Language())
+	// Only set behind an edge proxy that overwrites the header
+	if countryHeader := config.Get("CLIENT_COUNTRY_HEADER", ""); countryHeader != "" {
+		app.Use(middleware.ClientCountry(countryHeader))
+	}
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     "http://localhost:3000",
-		AllowHeaders:     "Origin, Content-Type, Accept, Authorization",
+		AllowHeaders:     "Origin, Content-Type, Accept, Authorization, If-Match",
+		ExposeHeaders:    "ETag",
 		AllowCredentials: true,
 	}))
 
 	routes.SetupRoutes(app, paymentHandler, jwtService)
 
+	// Internal services can also reach the payment API over gRPC
+	grpcPort := config.Get("GRPC_PORT", "9000")
+	grpcListener, err := net.Listen("tcp", ":"+grpcPort)
+	if err != nil {
+		log.Fatalf("cannot listen for gRPC: %v", err)
+	}
+	grpcServer := rpc.NewServer(jwtService, paymentService, validate)
+	go func() {
+		fmt.Println("gRPC server is running on port " + grpcPort)
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
 	port := config.Get("APP_PORT", "8000")
 	fmt.Println("Server is running on port " + port)
 	// listen on all interfaces so container port mapping works correctly
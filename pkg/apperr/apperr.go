@@ -2,6 +2,10 @@ package apperr
 
 import (
 	"errors"
+	"strings"
+
+	"payment-service/pkg/i18n"
+	"payment-service/pkg/response"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -15,25 +19,119 @@ const (
 	CodeNotFound
 	CodeConflict
 	CodeInternal
+	CodeBadGateway
+	CodeUnavailable
+	CodeGatewayTimeout
+	CodePreconditionRequired
 )
 
+var codeNames = map[Code]string{
+	CodeBadRequest:           "bad_request",
+	CodeUnauthorized:         "unauthorized",
+	CodeForbidden:            "forbidden",
+	CodeNotFound:             "not_found",
+	CodeConflict:             "conflict",
+	CodeInternal:             "internal",
+	CodeBadGateway:           "bad_gateway",
+	CodeUnavailable:          "unavailable",
+	CodeGatewayTimeout:       "gateway_timeout",
+	CodePreconditionRequired: "precondition_required",
+}
+
+// String is the machine-readable name clients see in error responses.
+func (c Code) String() string {
+	if name, ok := codeNames[c]; ok {
+		return name
+	}
+	return "internal"
+}
+
+// Status is the HTTP status of the code.
+func (c Code) Status() int {
+	switch c {
+	case CodeBadRequest:
+		return fiber.StatusBadRequest
+	case CodeUnauthorized:
+		return fiber.StatusUnauthorized
+	case CodeForbidden:
+		return fiber.StatusForbidden
+	case CodeNotFound:
+		return fiber.StatusNotFound
+	case CodeConflict:
+		return fiber.StatusConflict
+	case CodeBadGateway:
+		return fiber.StatusBadGateway
+	case CodeUnavailable:
+		return fiber.StatusServiceUnavailable
+	case CodeGatewayTimeout:
+		return fiber.StatusGatewayTimeout
+	case CodePreconditionRequired:
+		return fiber.StatusPreconditionRequired
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+// Error is a failure with a code the client sees. Msg and Fields, which
+// maps request fields to what is wrong with them, are put into the
+// client's language when the error is written.
 type Error struct {
 	Code   Code
-	Msg    string
+	Msg    i18n.Text
 	Err    error
-	Fields map[string]any
+	Fields map[string]i18n.Text
 }
 
 func (e *Error) Error() string {
+	msg := e.Msg.In(i18n.English)
 	if e.Err != nil {
-		return e.Msg + ": " + e.Err.Error()
+		return msg + ": " + e.Err.Error()
 	}
-	return e.Msg
+	return msg
 }
 func (e *Error) Unwrap() error { return e.Err }
 
-func New(code Code, msg string, err error) *Error {
-	return &Error{Code: code, Msg: msg, Err: err}
+// New is an error with the catalog message msg, args filled into its verbs.
+func New(code Code, msg i18n.Key, err error, args ...any) *Error {
+	return &Error{Code: code, Msg: i18n.T(msg, args...), Err: err}
+}
+
+// Propagate keeps an error that already carries a Code, such as one mapped
+// from an upstream response, and wraps anything else as CodeInternal.
+func Propagate(err error, msg i18n.Key) *Error {
+	var ae *Error
+	if errors.As(err, &ae) {
+		return ae
+	}
+	return New(CodeInternal, msg, err)
+}
+
+// Invalid reports a request that failed validation, field by field.
+func Invalid(msg i18n.Key, fields map[string]i18n.Text) *Error {
+	return &Error{Code: CodeBadRequest, Msg: i18n.T(msg), Fields: fields}
+}
+
+// BadInput wraps a failure to parse a request body or query. Errors that
+// already carry a code, such as validation failures with their fields, are
+// kept as they are, and query params that fail to convert are listed.
+func BadInput(msg i18n.Key, err error) *Error {
+	var ae *Error
+	if errors.As(err, &ae) {
+		return ae
+	}
+	var multi fiber.MultiError
+	if errors.As(err, &multi) {
+		fields := make(map[string]i18n.Text, len(multi))
+		for key, fieldErr := range multi {
+			fields[key] = i18n.T(i18n.FieldInvalid)
+			var conversion fiber.ConversionError
+			if errors.As(fieldErr, &conversion) && conversion.Type != nil {
+				fields[key] = i18n.T(i18n.FieldWrongType, conversion.Type.String())
+			}
+		}
+		return &Error{Code: CodeBadRequest, Msg: i18n.T(msg), Err: err, Fields: fields}
+	}
+	return New(CodeBadRequest, msg, err)
 }
 
 func IsCode(err error, code Code) bool {
@@ -44,27 +142,48 @@ func IsCode(err error, code Code) bool {
 	return false
 }
 
+// WriteError writes err in the shared error schema, in the language the
+// request asked for. Errors without a Code are internal, and their text is
+// never sent.
 func WriteError(c *fiber.Ctx, err error) error {
+	lang := language(c)
 	var ae *Error
-	status := fiber.StatusInternalServerError
-	msg := "internal error"
+	if !errors.As(err, &ae) {
+		return response.InternalServerError(c, i18n.T(i18n.Internal).In(lang))
+	}
 
-	if errors.As(err, &ae) {
-		msg = ae.Msg
-		switch ae.Code {
-		case CodeBadRequest:
-			status = fiber.StatusBadRequest
-		case CodeUnauthorized:
-			status = fiber.StatusUnauthorized
-		case CodeForbidden:
-			status = fiber.StatusForbidden
-		case CodeNotFound:
-			status = fiber.StatusNotFound
-		case CodeConflict:
-			status = fiber.StatusConflict
-		default:
-			status = fiber.StatusInternalServerError
+	var fields map[string]string
+	if len(ae.Fields) > 0 {
+		fields = make(map[string]string, len(ae.Fields))
+		for field, text := range ae.Fields {
+			fields[field] = text.In(lang)
+		}
+	}
+	return response.Error(c, ae.Code.Status(), ae.Code.String(), ae.Msg.In(lang), fields)
+}
+
+// ErrorHandler is the fiber error handler: it writes errors returned by
+// handlers and middleware, and fiber's own such as unknown routes, in the
+// shared error schema. Fiber's messages are English only, so other
+// languages get the catalog's message for the code instead.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	var fe *fiber.Error
+	if errors.As(err, &fe) {
+		code := response.StatusCode(fe.Code)
+		message := strings.ToLower(fe.Message)
+		if lang := language(c); lang != i18n.English {
+			message = i18n.T(i18n.Key(code)).In(lang)
 		}
+		return response.Error(c, fe.Code, code, message, nil)
+	}
+	return WriteError(c, err)
+}
+
+// language is the language the Language middleware negotiated for the
+// request, English when it did not run.
+func language(c *fiber.Ctx) i18n.Lang {
+	if lang, ok := c.Locals("lang").(i18n.Lang); ok {
+		return lang
 	}
-	return c.Status(status).JSON(fiber.Map{"error": msg})
+	return i18n.English
 }
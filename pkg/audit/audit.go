@@ -0,0 +1,308 @@
+// Package audit writes a hash-chained audit_logs row for every create,
+// update and delete of the tables it watches, in the same transaction as
+// the change itself.
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	contextUtils "payment-service/pkg/context"
+	"payment-service/pkg/models"
+	"payment-service/pkg/redact"
+	"payment-service/pkg/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GenesisHash is the previous hash of the first row in the chain.
+const GenesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// chainLockKey serializes appends so that every row links to the one
+// committed right before it. There is one chain, so the lock is global:
+// once a transaction writes its first audited change, every other
+// transaction writing an audited table waits until it commits or rolls
+// back. That caps audited writes at one transaction at a time, which the
+// payment tables' write rate allows; keep audited transactions short, and
+// never wait on another service while holding one. Spreading the load
+// would mean one chain, and one key, per table.
+const chainLockKey = 7_360_436
+
+const beforeKey = "audit:before"
+
+// Plugin audits the tables of the models it is created with.
+type Plugin struct {
+	models []any
+	tables map[string]bool
+}
+
+func NewPlugin(models ...any) *Plugin {
+	return &Plugin{models: models, tables: make(map[string]bool)}
+}
+
+func (p *Plugin) Name() string {
+	return "audit"
+}
+
+func (p *Plugin) Initialize(db *gorm.DB) error {
+	for _, model := range p.models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return fmt.Errorf("audit: parse %T: %w", model, err)
+		}
+		p.tables[stmt.Schema.Table] = true
+	}
+
+	callbacks := db.Callback()
+	if err := callbacks.Create().After("gorm:create").Before("gorm:commit_or_rollback_transaction").
+		Register("audit:after_create", p.afterCreate); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("audit:before_update", p.loadBefore); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After("gorm:update").Before("gorm:commit_or_rollback_transaction").
+		Register("audit:after_update", p.afterUpdate); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("audit:before_delete", p.loadBefore); err != nil {
+		return err
+	}
+	return callbacks.Delete().After("gorm:delete").Before("gorm:commit_or_rollback_transaction").
+		Register("audit:after_delete", p.afterDelete)
+}
+
+func (p *Plugin) watched(db *gorm.DB) bool {
+	return db.Error == nil && db.Statement.Schema != nil && p.tables[db.Statement.Schema.Table]
+}
+
+func (p *Plugin) afterCreate(db *gorm.DB) {
+	if !p.watched(db) || db.RowsAffected == 0 {
+		return
+	}
+	for _, row := range rows(db.Statement.ReflectValue) {
+		p.append(db, models.AuditActionCreate, row, reflect.Value{}, row)
+	}
+}
+
+func (p *Plugin) afterUpdate(db *gorm.DB) {
+	if !p.watched(db) || db.RowsAffected == 0 {
+		return
+	}
+	for _, old := range beforeRows(db) {
+		current, err := p.find(db, primaryKey(db, old))
+		if err != nil {
+			db.AddError(fmt.Errorf("audit: reload %s: %w", db.Statement.Table, err))
+			return
+		}
+		p.append(db, models.AuditActionUpdate, old, old, current)
+	}
+}
+
+func (p *Plugin) afterDelete(db *gorm.DB) {
+	if !p.watched(db) || db.RowsAffected == 0 {
+		return
+	}
+	for _, old := range beforeRows(db) {
+		p.append(db, models.AuditActionDelete, old, old, reflect.Value{})
+	}
+}
+
+// loadBefore reads the rows an update or delete is about to change, using
+// the statement's own conditions.
+func (p *Plugin) loadBefore(db *gorm.DB) {
+	if !p.watched(db) {
+		return
+	}
+	stmt := db.Statement
+
+	query := db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Table(stmt.Table)
+	conditioned := false
+	if where, ok := stmt.Clauses["WHERE"].Expression.(clause.Where); ok && len(where.Exprs) > 0 {
+		query = query.Clauses(where)
+		conditioned = true
+	}
+	var ids []any
+	for _, row := range rows(stmt.ReflectValue) {
+		if id := primaryKey(db, row); id != nil {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) > 0 {
+		query = query.Where(clause.IN{Column: clause.Column{Name: stmt.Schema.PrioritizedPrimaryField.DBName}, Values: ids})
+		conditioned = true
+	}
+	if !conditioned {
+		// gorm refuses updates and deletes without conditions, so nothing
+		// will change
+		return
+	}
+
+	found := reflect.New(reflect.SliceOf(stmt.Schema.ModelType))
+	if err := query.Find(found.Interface()).Error; err != nil {
+		db.AddError(fmt.Errorf("audit: load %s before change: %w", stmt.Table, err))
+		return
+	}
+	db.InstanceSet(beforeKey, rows(found))
+}
+
+func beforeRows(db *gorm.DB) []reflect.Value {
+	before, _ := db.InstanceGet(beforeKey)
+	loaded, _ := before.([]reflect.Value)
+	return loaded
+}
+
+func (p *Plugin) find(db *gorm.DB, id any) (reflect.Value, error) {
+	stmt := db.Statement
+	found := reflect.New(stmt.Schema.ModelType)
+	err := db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).Table(stmt.Table).
+		Where(clause.Eq{Column: clause.Column{Name: stmt.Schema.PrioritizedPrimaryField.DBName}, Value: id}).
+		Take(found.Interface()).Error
+	return found.Elem(), err
+}
+
+// append links a new row to the chain head under a lock held until the
+// transaction ends. A zero before or after is recorded as null.
+func (p *Plugin) append(db *gorm.DB, action models.AuditAction, row, before, after reflect.Value) {
+	beforeJSON, err := snapshot(before)
+	if err != nil {
+		db.AddError(fmt.Errorf("audit: encode before: %w", err))
+		return
+	}
+	afterJSON, err := snapshot(after)
+	if err != nil {
+		db.AddError(fmt.Errorf("audit: encode after: %w", err))
+		return
+	}
+	entry := newEntry(db.Statement.Context, db.Statement.Table, fmt.Sprint(primaryKey(db, row)), action, beforeJSON, afterJSON)
+
+	tx := db.Session(&gorm.Session{NewDB: true, SkipHooks: true})
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", chainLockKey).Error; err != nil {
+		db.AddError(fmt.Errorf("audit: lock chain: %w", err))
+		return
+	}
+
+	var head models.AuditLog
+	err = tx.Select("hash").Order("seq DESC").Take(&head).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		entry.PrevHash = GenesisHash
+	case err != nil:
+		db.AddError(fmt.Errorf("audit: read chain head: %w", err))
+		return
+	default:
+		entry.PrevHash = head.Hash
+	}
+	entry.Hash = Hash(entry)
+
+	if err := tx.Create(entry).Error; err != nil {
+		db.AddError(fmt.Errorf("audit: write entry: %w", err))
+	}
+}
+
+func newEntry(ctx context.Context, table, entityID string, action models.AuditAction, before, after json.RawMessage) *models.AuditLog {
+	entry := &models.AuditLog{
+		ID:         utils.GenerateUUIDv7(),
+		EntityType: table,
+		EntityID:   entityID,
+		Action:     action,
+		Before:     before,
+		After:      after,
+		// postgres keeps microseconds; hash what will be read back
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+	}
+	if actorID, ok := contextUtils.LookupUserId(ctx); ok {
+		entry.ActorID = &actorID
+	}
+	if role, ok := contextUtils.LookupRole(ctx); ok {
+		entry.ActorRole = &role
+	}
+	if requestID := contextUtils.GetRequestID(ctx); requestID != "" {
+		entry.RequestID = &requestID
+	}
+	if clientIP := contextUtils.GetClientIP(ctx); clientIP != "" {
+		entry.ClientIP = &clientIP
+	}
+	return entry
+}
+
+// Hash is the chain hash of entry: SHA-256 over its previous hash and
+// every recorded field, each length-prefixed so fields cannot run together.
+func Hash(entry *models.AuditLog) string {
+	var b strings.Builder
+	for _, field := range []string{
+		entry.PrevHash,
+		entry.ID.String(),
+		entry.EntityType,
+		entry.EntityID,
+		string(entry.Action),
+		deref(entry.ActorID),
+		deref(entry.ActorRole),
+		deref(entry.RequestID),
+		deref(entry.ClientIP),
+		string(entry.Before),
+		string(entry.After),
+		entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+	} {
+		fmt.Fprintf(&b, "%d:%s;", len(field), field)
+	}
+	sum := sha256.Sum256([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+func snapshot(row reflect.Value) (json.RawMessage, error) {
+	if !row.IsValid() {
+		return nil, nil
+	}
+	return redact.JSON(row.Interface())
+}
+
+// rows flattens a statement value, a struct or a slice of them, into rows.
+func rows(rv reflect.Value) []reflect.Value {
+	for rv.Kind() == reflect.Pointer {
+		rv = rv.Elem()
+	}
+	if rv.Kind() == reflect.Struct {
+		return []reflect.Value{rv}
+	}
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil
+	}
+
+	result := make([]reflect.Value, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		row := rv.Index(i)
+		for row.Kind() == reflect.Pointer {
+			row = row.Elem()
+		}
+		result = append(result, row)
+	}
+	return result
+}
+
+func primaryKey(db *gorm.DB, row reflect.Value) any {
+	field := db.Statement.Schema.PrioritizedPrimaryField
+	if field == nil || !row.IsValid() || row.Kind() != reflect.Struct {
+		return nil
+	}
+	value, zero := field.ValueOf(db.Statement.Context, row)
+	if zero {
+		return nil
+	}
+	return value
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,204 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	contextUtils "payment-service/pkg/context"
+	"payment-service/pkg/models"
+	"payment-service/pkg/utils"
+)
+
+func newTestEntry() *models.AuditLog {
+	actor, role := "user-1", "admin"
+	return &models.AuditLog{
+		ID:         utils.GenerateUUIDv7(),
+		EntityType: "payments",
+		EntityID:   "payment-1",
+		Action:     models.AuditActionUpdate,
+		ActorID:    &actor,
+		ActorRole:  &role,
+		Before:     json.RawMessage(`{"amount":100}`),
+		After:      json.RawMessage(`{"amount":200}`),
+		PrevHash:   GenesisHash,
+		CreatedAt:  time.Date(2026, 10, 19, 9, 0, 0, 123000, time.UTC),
+	}
+}
+
+func TestHash(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(e *models.AuditLog)
+	}{
+		{"previous hash", func(e *models.AuditLog) { e.PrevHash = strings.Repeat("1", 64) }},
+		{"entity", func(e *models.AuditLog) { e.EntityID = "payment-2" }},
+		{"action", func(e *models.AuditLog) { e.Action = models.AuditActionDelete }},
+		{"actor", func(e *models.AuditLog) { e.ActorID = nil }},
+		{"before", func(e *models.AuditLog) { e.Before = json.RawMessage(`{"amount":101}`) }},
+		{"after", func(e *models.AuditLog) { e.After = nil }},
+		{"time", func(e *models.AuditLog) { e.CreatedAt = e.CreatedAt.Add(time.Microsecond) }},
+		{"fields run together", func(e *models.AuditLog) { e.EntityType, e.EntityID = "paymentsp", "ayment-1" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newTestEntry()
+			want := Hash(e)
+			tt.change(e)
+			if Hash(e) == want {
+				t.Fatalf("hash did not change")
+			}
+		})
+	}
+
+	t.Run("ignores time zone, seq and stored hash", func(t *testing.T) {
+		e := newTestEntry()
+		again := *e
+		again.CreatedAt = e.CreatedAt.In(time.FixedZone("ICT", 7*60*60))
+		again.Seq, again.Hash = 42, "stored"
+		if Hash(e) != Hash(&again) {
+			t.Fatalf("hash changed")
+		}
+	})
+}
+
+// chain links entries the way append does, starting from the genesis hash.
+func chain(n int) []models.AuditLog {
+	entries := make([]models.AuditLog, n)
+	prevHash := GenesisHash
+	for i := range entries {
+		entries[i] = *newTestEntry()
+		entries[i].Seq = int64(i + 1)
+		entries[i].PrevHash = prevHash
+		entries[i].Hash = Hash(&entries[i])
+		prevHash = entries[i].Hash
+	}
+	return entries
+}
+
+func TestVerifyBatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		tamper  func(entries []models.AuditLog)
+		checked int
+		seq     int64
+	}{
+		{"intact", func([]models.AuditLog) {}, 3, 0},
+		{"edited row", func(entries []models.AuditLog) { entries[1].After = json.RawMessage(`{"amount":0}`) }, 1, 2},
+		{"deleted row", func(entries []models.AuditLog) { entries[1] = entries[2] }, 1, 3},
+		{"rehashed row", func(entries []models.AuditLog) {
+			entries[0].EntityID = "payment-2"
+			entries[0].Hash = Hash(&entries[0])
+		}, 1, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := chain(3)
+			tt.tamper(entries)
+
+			checked, err := verifyBatch(GenesisHash, entries)
+			if checked != tt.checked {
+				t.Errorf("checked %d rows, want %d", checked, tt.checked)
+			}
+			var chainErr *ChainError
+			switch {
+			case tt.seq == 0 && err != nil:
+				t.Fatalf("unexpected error %v", err)
+			case tt.seq != 0 && (!errors.As(err, &chainErr) || chainErr.Seq != tt.seq):
+				t.Fatalf("got error %v, want a break at seq %d", err, tt.seq)
+			}
+		})
+	}
+
+	t.Run("continues from the previous batch", func(t *testing.T) {
+		entries := chain(4)
+		if _, err := verifyBatch(entries[1].Hash, entries[2:]); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if _, err := verifyBatch(GenesisHash, entries[2:]); err == nil {
+			t.Fatalf("expected a break when starting from genesis")
+		}
+	})
+}
+
+func TestNewEntry(t *testing.T) {
+	t.Run("records the caller", func(t *testing.T) {
+		ctx := context.Background()
+		ctx = context.WithValue(ctx, contextUtils.ContextKeyUserID, "user-1")
+		ctx = context.WithValue(ctx, contextUtils.ContextKeyRole, "patient")
+		ctx = context.WithValue(ctx, contextUtils.ContextKeyRequestID, "request-1")
+		ctx = context.WithValue(ctx, contextUtils.ContextKeyClientIP, "203.0.113.7")
+
+		got := newEntry(ctx, "payments", "payment-1", models.AuditActionCreate, nil, json.RawMessage(`{}`))
+		for name, field := range map[string]*string{
+			"user-1":      got.ActorID,
+			"patient":     got.ActorRole,
+			"request-1":   got.RequestID,
+			"203.0.113.7": got.ClientIP,
+		} {
+			if field == nil || *field != name {
+				t.Errorf("got %v, want %s", field, name)
+			}
+		}
+		if got.CreatedAt.Nanosecond()%int(time.Microsecond) != 0 || got.CreatedAt.Location() != time.UTC {
+			t.Errorf("created at %v is not UTC microseconds", got.CreatedAt)
+		}
+	})
+
+	t.Run("leaves a background change anonymous", func(t *testing.T) {
+		got := newEntry(context.Background(), "payments", "payment-1", models.AuditActionDelete, nil, nil)
+		if got.ActorID != nil || got.ActorRole != nil || got.RequestID != nil || got.ClientIP != nil {
+			t.Fatalf("got %+v", got)
+		}
+	})
+}
+
+func TestSnapshot(t *testing.T) {
+	info := models.PaymentInformation{
+		ID:      utils.GenerateUUIDv7(),
+		Type:    models.PaymentMethodCreditCard,
+		Details: json.RawMessage(`{"card_number":"4111111111111111"}`),
+	}
+	got, err := snapshot(reflect.ValueOf(info))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if strings.Contains(string(got), "4111111111111111") || !strings.Contains(string(got), info.ID.String()) {
+		t.Fatalf("got %s", got)
+	}
+
+	none, err := snapshot(reflect.Value{})
+	if err != nil || none != nil {
+		t.Fatalf("got %s, %v for no row", none, err)
+	}
+}
+
+func TestRows(t *testing.T) {
+	a, b := models.AuditLog{EntityID: "a"}, models.AuditLog{EntityID: "b"}
+	tests := []struct {
+		name  string
+		value any
+		want  []string
+	}{
+		{"struct", a, []string{"a"}},
+		{"pointer", &a, []string{"a"}},
+		{"slice", []models.AuditLog{a, b}, []string{"a", "b"}},
+		{"slice of pointers", &[]*models.AuditLog{&a, &b}, []string{"a", "b"}},
+		{"not a row", "a", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, row := range rows(reflect.ValueOf(tt.value)) {
+				got = append(got, row.Interface().(models.AuditLog).EntityID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
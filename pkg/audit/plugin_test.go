@@ -0,0 +1,153 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	contextUtils "payment-service/pkg/context"
+	"payment-service/pkg/models"
+	"payment-service/pkg/utils"
+
+	"github.com/pressly/goose/v3"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// newTestDB migrates a schema of its own in the Postgres database named by
+// TEST_DATABASE_DSN, in key=value form, and drops it when the test ends.
+// The plugin relies on Postgres locking, so there is no in-memory stand-in.
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+
+	admin, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := "audit_test_" + strings.ReplaceAll(utils.GenerateUUIDv7().String(), "-", "")
+	if err := admin.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		if sqlDB, err := admin.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	db, err := gorm.Open(postgres.Open(dsn+" search_path="+schema+",public"), &gorm.Config{TranslateError: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	goose.SetBaseFS(nil)
+	goose.SetLogger(goose.NopLogger())
+	if err := goose.SetDialect("postgres"); err != nil {
+		t.Fatal(err)
+	}
+	if err := goose.Up(sqlDB, "../db/migrations"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.Use(NewPlugin(&models.PaymentInformation{})); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestPluginChainsChanges(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.WithValue(context.Background(), contextUtils.ContextKeyUserID, "user-1")
+	ctx = context.WithValue(ctx, contextUtils.ContextKeyRole, "admin")
+
+	info := models.PaymentInformation{
+		ID:      utils.GenerateUUIDv7(),
+		UserID:  utils.GenerateUUIDv7(),
+		Type:    models.PaymentMethodCreditCard,
+		Details: []byte(`{"card_number":"4111111111111111"}`),
+		Version: 1,
+	}
+	if err := db.WithContext(ctx).Create(&info).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.WithContext(ctx).Model(&info).Update("version", 2).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.WithContext(ctx).Delete(&info).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// a change that rolls back takes its audit row with it
+	rolledBack := errors.New("roll back")
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		other := info
+		other.ID = utils.GenerateUUIDv7()
+		if err := tx.Create(&other).Error; err != nil {
+			return err
+		}
+		return rolledBack
+	})
+	if !errors.Is(err, rolledBack) {
+		t.Fatalf("got %v, want the rollback", err)
+	}
+
+	var entries []models.AuditLog
+	if err := db.Order("seq").Find(&entries).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("got %d audit rows, want 3", len(entries))
+	}
+	tests := []struct {
+		action models.AuditAction
+		before string
+		after  string
+	}{
+		{models.AuditActionCreate, "", `"version":1`},
+		{models.AuditActionUpdate, `"version":1`, `"version":2`},
+		{models.AuditActionDelete, `"version":2`, ""},
+	}
+	prevHash := GenesisHash
+	for i, tt := range tests {
+		e := entries[i]
+		if e.Action != tt.action || e.EntityType != "payment_informations" || e.EntityID != info.ID.String() {
+			t.Errorf("row %d: got %s of %s %s", i, e.Action, e.EntityType, e.EntityID)
+		}
+		if e.ActorID == nil || *e.ActorID != "user-1" || e.ActorRole == nil || *e.ActorRole != "admin" {
+			t.Errorf("row %d: got actor %v as %v", i, e.ActorID, e.ActorRole)
+		}
+		for _, side := range []struct {
+			got  []byte
+			want string
+		}{{e.Before, tt.before}, {e.After, tt.after}} {
+			switch {
+			case side.want == "" && side.got != nil:
+				t.Errorf("row %d: got %s, want null", i, side.got)
+			case side.want != "" && !strings.Contains(string(side.got), side.want):
+				t.Errorf("row %d: got %s, want %s", i, side.got, side.want)
+			case strings.Contains(string(side.got), "4111111111111111"):
+				t.Errorf("row %d: card number reached the audit log: %s", i, side.got)
+			}
+		}
+		if e.PrevHash != prevHash || e.Hash != Hash(&e) {
+			t.Errorf("row %d is not linked to the one before it", i)
+		}
+		prevHash = e.Hash
+	}
+
+	checked, err := Verify(ctx, db)
+	if err != nil || checked != 3 {
+		t.Fatalf("verified %d rows: %v", checked, err)
+	}
+}
@@ -0,0 +1,64 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+
+	"payment-service/pkg/models"
+
+	"gorm.io/gorm"
+)
+
+const verifyBatchSize = 1000
+
+// ChainError reports the first row where the chain does not hold.
+type ChainError struct {
+	Seq    int64
+	Reason string
+}
+
+func (e *ChainError) Error() string {
+	return fmt.Sprintf("audit chain broken at seq %d: %s", e.Seq, e.Reason)
+}
+
+// Verify walks the whole chain in order and returns how many rows it
+// checked. It stops at the first row whose link or hash does not match.
+func Verify(ctx context.Context, db *gorm.DB) (int, error) {
+	prevHash := GenesisHash
+	var lastSeq int64
+	checked := 0
+
+	for {
+		var batch []models.AuditLog
+		if err := db.WithContext(ctx).Where("seq > ?", lastSeq).Order("seq").Limit(verifyBatchSize).Find(&batch).Error; err != nil {
+			return checked, err
+		}
+		if len(batch) == 0 {
+			return checked, nil
+		}
+
+		n, err := verifyBatch(prevHash, batch)
+		checked += n
+		if err != nil {
+			return checked, err
+		}
+		prevHash = batch[len(batch)-1].Hash
+		lastSeq = batch[len(batch)-1].Seq
+	}
+}
+
+// verifyBatch checks that batch, in seq order, continues the chain from
+// prevHash, and returns how many rows held.
+func verifyBatch(prevHash string, batch []models.AuditLog) (int, error) {
+	for i := range batch {
+		entry := &batch[i]
+		if entry.PrevHash != prevHash {
+			return i, &ChainError{Seq: entry.Seq, Reason: "previous hash does not match the row before it"}
+		}
+		if Hash(entry) != entry.Hash {
+			return i, &ChainError{Seq: entry.Seq, Reason: "row content does not match its hash"}
+		}
+		prevHash = entry.Hash
+	}
+	return len(batch), nil
+}
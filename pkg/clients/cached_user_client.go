@@ -0,0 +1,67 @@
+package clients
+
+import (
+	"context"
+	client_dto "payment-service/pkg/clients/dto"
+)
+
+// CachedUserClient puts a profile cache in front of UserClient's patient
+// and doctor lookups. Every other call goes straight to UserClient.
+type CachedUserClient struct {
+	*UserClient
+	patients *profileLoader[client_dto.GetPatientProfileResponseDto]
+	doctors  *profileLoader[client_dto.GetDoctorProfileResponseDto]
+}
+
+func NewCachedUserClient(userClient *UserClient, cfg ProfileCacheConfig) *CachedUserClient {
+	return &CachedUserClient{
+		UserClient: userClient,
+		patients: newProfileLoader("patient", cfg,
+			func(ctx context.Context, ids []string) ([]client_dto.GetPatientProfileResponseDto, error) {
+				profiles, err := userClient.GetPatientByIds(ctx, ids)
+				if err != nil {
+					return nil, err
+				}
+				return *profiles, nil
+			},
+			func(p client_dto.GetPatientProfileResponseDto) string { return p.ID },
+		),
+		doctors: newProfileLoader("doctor", cfg,
+			func(ctx context.Context, ids []string) ([]client_dto.GetDoctorProfileResponseDto, error) {
+				profiles, err := userClient.GetDoctorByIds(ctx, ids)
+				if err != nil {
+					return nil, err
+				}
+				return *profiles, nil
+			},
+			func(d client_dto.GetDoctorProfileResponseDto) string { return d.ID },
+		),
+	}
+}
+
+func (c *CachedUserClient) GetPatientByIds(ctx context.Context, patientIDs []string) (*[]client_dto.GetPatientProfileResponseDto, error) {
+	profiles, err := c.patients.Load(ctx, patientIDs)
+	if err != nil {
+		return nil, err
+	}
+	return &profiles, nil
+}
+
+func (c *CachedUserClient) GetDoctorByIds(ctx context.Context, doctorIDs []string) (*[]client_dto.GetDoctorProfileResponseDto, error) {
+	profiles, err := c.doctors.Load(ctx, doctorIDs)
+	if err != nil {
+		return nil, err
+	}
+	return &profiles, nil
+}
+
+func (c *CachedUserClient) GetDoctorById(ctx context.Context, doctorID string) (*client_dto.GetDoctorProfileResponseDto, error) {
+	profiles, err := c.doctors.Load(ctx, []string{doctorID})
+	if err != nil {
+		return nil, err
+	}
+	if len(profiles) == 0 {
+		return nil, nil
+	}
+	return &profiles[0], nil
+}
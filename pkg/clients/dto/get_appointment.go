@@ -0,0 +1,8 @@
+package client_dto
+
+type GetAppointmentResponseDto struct {
+	ID        string `json:"id"`
+	PatientID string `json:"patient_id"`
+	DoctorID  string `json:"doctor_id"`
+	Status    string `json:"status"`
+}
@@ -0,0 +1,9 @@
+package client_dto
+
+type GetOrderResponseDto struct {
+	ID          string  `json:"id"`
+	PatientID   string  `json:"patient_id"`
+	DoctorID    *string `json:"doctor_id,omitempty"`
+	TotalAmount float64 `json:"total_amount"`
+	Status      string  `json:"status"`
+}
@@ -0,0 +1,5 @@
+package client_dto
+
+type GetPatientEntitlementResponseDto struct {
+	HealthcareEntitlement string `json:"healthcare_entitlement"`
+}
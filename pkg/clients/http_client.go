@@ -0,0 +1,363 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"payment-service/pkg/apperr"
+	contextUtils "payment-service/pkg/context"
+	"payment-service/pkg/i18n"
+	"payment-service/pkg/redact"
+)
+
+// maxErrorBody caps how much of an upstream error response is read.
+const maxErrorBody = 4 << 10
+
+// errCallTimeout is the cause of a call running out of HttpClientConfig.Timeout,
+// which counts against the upstream, unlike the caller giving up.
+var errCallTimeout = fmt.Errorf("upstream call timed out: %w", context.DeadlineExceeded)
+
+type HttpClientConfig struct {
+	// Timeout bounds a call, retries included, when ctx has no earlier
+	// deadline. Zero takes the default, so no call can hang.
+	Timeout     time.Duration
+	MaxRetries  int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// BreakerThreshold consecutive failures open the breaker for BreakerCooldown
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+	// ServiceTokens authenticates calls made without a user token in ctx,
+	// such as from background jobs and webhooks
+	ServiceTokens ServiceTokenSource
+	// LogBodies logs request and response bodies, redacted, for debugging
+	LogBodies bool
+}
+
+// ServiceTokenSource mints a token addressed to the named upstream.
+type ServiceTokenSource interface {
+	ServiceToken(audience string) (string, error)
+}
+
+func DefaultHttpClientConfig() HttpClientConfig {
+	return HttpClientConfig{
+		Timeout:          5 * time.Second,
+		MaxRetries:       2,
+		BaseBackoff:      100 * time.Millisecond,
+		MaxBackoff:       2 * time.Second,
+		BreakerThreshold: 5,
+		BreakerCooldown:  30 * time.Second,
+	}
+}
+
+// HttpClient is the shared JSON client for calls to other services. Every
+// upstream gets its own instance, and with it its own circuit breaker.
+type HttpClient struct {
+	upstream string
+	baseUrl  string
+	hc       *http.Client
+	cfg      HttpClientConfig
+	breaker  *circuitBreaker
+}
+
+// UpstreamError is the decoded error response of another service.
+type UpstreamError struct {
+	Upstream   string
+	StatusCode int
+	Message    string
+}
+
+func (e *UpstreamError) Error() string {
+	return fmt.Sprintf("%s responded %d: %s", e.Upstream, e.StatusCode, e.Message)
+}
+
+type requestOptions struct {
+	idempotent bool
+	public     bool
+}
+
+type RequestOption func(*requestOptions)
+
+// Idempotent marks a non-GET call, such as a POST lookup, as safe to retry.
+func Idempotent() RequestOption {
+	return func(o *requestOptions) {
+		o.idempotent = true
+	}
+}
+
+// Public sends the call without credentials, for endpoints anyone may
+// read, such as an identity provider's JWKS document.
+func Public() RequestOption {
+	return func(o *requestOptions) {
+		o.public = true
+	}
+}
+
+func NewHttpClient(upstream, baseUrl string, cfg HttpClientConfig) *HttpClient {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultHttpClientConfig().Timeout
+	}
+	return &HttpClient{
+		upstream: upstream,
+		baseUrl:  baseUrl,
+		hc:       &http.Client{},
+		cfg:      cfg,
+		breaker:  newCircuitBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown),
+	}
+}
+
+// Do sends body as JSON and decodes a 2xx response into response. Failures
+// come back as *apperr.Error with a code matching the upstream outcome.
+func (c *HttpClient) Do(ctx context.Context, method, path string, body interface{}, response interface{}, opts ...RequestOption) error {
+	var o requestOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var err error
+	authorize := func(*http.Request) {}
+	if !o.public {
+		if authorize, err = c.authorizer(ctx); err != nil {
+			return err
+		}
+	}
+
+	var payload []byte
+	if body != nil {
+		if payload, err = json.Marshal(body); err != nil {
+			return apperr.New(apperr.CodeInternal, i18n.FailedMarshalRequest, err)
+		}
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, c.cfg.Timeout, errCallTimeout)
+		defer cancel()
+	}
+
+	retries := 0
+	if o.idempotent || isIdempotent(method) {
+		retries = c.cfg.MaxRetries
+	}
+
+	url := c.baseUrl + path
+	for attempt := 0; ; attempt++ {
+		retryable, err := c.once(ctx, method, url, payload, authorize, response)
+		if err == nil || !retryable || attempt >= retries {
+			return err
+		}
+		if waitErr := c.backoff(ctx, attempt); waitErr != nil {
+			return err
+		}
+	}
+}
+
+// authorizer forwards the end user's token when there is one and falls
+// back to a service token otherwise.
+func (c *HttpClient) authorizer(ctx context.Context) (func(*http.Request), error) {
+	if accessToken := contextUtils.GetAccessToken(ctx); accessToken != "" {
+		return func(req *http.Request) {
+			req.AddCookie(&http.Cookie{
+				Name:  "access_token",
+				Value: accessToken,
+			})
+		}, nil
+	}
+
+	if c.cfg.ServiceTokens == nil {
+		return nil, apperr.New(apperr.CodeUnauthorized, i18n.UpstreamMissingToken, nil, c.upstream)
+	}
+	token, err := c.cfg.ServiceTokens.ServiceToken(c.upstream)
+	if err != nil {
+		return nil, apperr.New(apperr.CodeInternal, i18n.UpstreamServiceToken, err, c.upstream)
+	}
+	return func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}, nil
+}
+
+func (c *HttpClient) once(ctx context.Context, method, url string, payload []byte, authorize func(*http.Request), response interface{}) (bool, error) {
+	if !c.breaker.allow() {
+		return false, apperr.New(apperr.CodeUnavailable, i18n.UpstreamUnavailable, nil, c.upstream)
+	}
+	// every path reports exactly one outcome, so a half-open probe is
+	// never left taken
+	report := c.breaker.release
+	defer func() { report() }()
+
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return false, apperr.New(apperr.CodeInternal, i18n.FailedCreateRequest, err)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	authorize(req)
+
+	// URLs and bodies can carry card data and tokens, so only their
+	// redacted form is logged
+	logURL := redact.URL(url)
+	if c.cfg.LogBodies && payload != nil {
+		log.Printf("%s %s %s request body: %s", c.upstream, method, logURL, redact.Body(payload))
+	}
+
+	start := time.Now()
+	resp, err := c.hc.Do(req)
+	if err != nil {
+		report = c.failed(ctx)
+		log.Printf("%s %s %s failed after %s: %v", c.upstream, method, logURL, time.Since(start), redact.String(err.Error()))
+		if errors.Is(err, context.DeadlineExceeded) {
+			return ctx.Err() == nil, apperr.New(apperr.CodeGatewayTimeout, i18n.UpstreamTimeout, err, c.upstream)
+		}
+		return ctx.Err() == nil, apperr.New(apperr.CodeBadGateway, i18n.UpstreamUnreachable, err, c.upstream)
+	}
+	defer resp.Body.Close()
+	log.Printf("%s %s %s -> %d in %s", c.upstream, method, logURL, resp.StatusCode, time.Since(start))
+
+	body := io.Reader(resp.Body)
+	if c.cfg.LogBodies {
+		raw, err := io.ReadAll(resp.Body)
+		if err != nil {
+			report = c.failed(ctx)
+			return ctx.Err() == nil, apperr.New(apperr.CodeBadGateway, i18n.UpstreamBadResponse, err, c.upstream)
+		}
+		log.Printf("%s %s %s response body: %s", c.upstream, method, logURL, redact.Body(raw))
+		body = bytes.NewReader(raw)
+	}
+
+	if resp.StatusCode >= 500 {
+		report = c.breaker.failure
+	} else {
+		report = c.breaker.success
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// the upstream's message is meant for us, not for our callers, so
+		// it is only logged
+		upstreamErr := c.decodeError(resp.StatusCode, body)
+		log.Printf("%s %s %s error: %s", c.upstream, method, logURL, redact.String(upstreamErr.Message))
+		return isRetryableStatus(resp.StatusCode), apperr.New(statusToCode(resp.StatusCode), i18n.UpstreamError, upstreamErr, c.upstream)
+	}
+
+	if response != nil {
+		if err := json.NewDecoder(body).Decode(response); err != nil {
+			return false, apperr.New(apperr.CodeBadGateway, i18n.UpstreamBadResponse, err, c.upstream)
+		}
+	}
+	return false, nil
+}
+
+// failed is the breaker outcome of a call that failed: a failure of the
+// upstream, unless the caller gave up on it first.
+func (c *HttpClient) failed(ctx context.Context) func() {
+	if ctx.Err() != nil && context.Cause(ctx) != errCallTimeout {
+		return c.breaker.release
+	}
+	return c.breaker.failure
+}
+
+// decodeError understands the {"code": "...", "message": "..."} bodies our
+// services send, the older {"error": "..."} ones, and falls back to the raw
+// text.
+func (c *HttpClient) decodeError(statusCode int, body io.Reader) *UpstreamError {
+	raw, _ := io.ReadAll(io.LimitReader(body, maxErrorBody))
+
+	var decoded struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+	message := ""
+	if json.Unmarshal(raw, &decoded) == nil {
+		message = decoded.Error
+		if message == "" {
+			message = decoded.Message
+		}
+	}
+	if message == "" {
+		message = string(bytes.TrimSpace(raw))
+	}
+	if message == "" {
+		message = http.StatusText(statusCode)
+	}
+
+	return &UpstreamError{
+		Upstream:   c.upstream,
+		StatusCode: statusCode,
+		Message:    message,
+	}
+}
+
+// backoff sleeps with full jitter: a random duration up to the capped
+// exponential delay for this attempt.
+func (c *HttpClient) backoff(ctx context.Context, attempt int) error {
+	delay := c.cfg.BaseBackoff << attempt
+	if delay <= 0 || delay > c.cfg.MaxBackoff {
+		delay = c.cfg.MaxBackoff
+	}
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(rand.N(delay) + 1)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func isRetryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+func statusToCode(status int) apperr.Code {
+	switch {
+	case status == http.StatusBadRequest, status == http.StatusUnprocessableEntity:
+		return apperr.CodeBadRequest
+	case status == http.StatusUnauthorized:
+		return apperr.CodeUnauthorized
+	case status == http.StatusForbidden:
+		return apperr.CodeForbidden
+	case status == http.StatusNotFound:
+		return apperr.CodeNotFound
+	case status == http.StatusConflict:
+		return apperr.CodeConflict
+	case status == http.StatusTooManyRequests, status == http.StatusServiceUnavailable:
+		return apperr.CodeUnavailable
+	case status == http.StatusGatewayTimeout:
+		return apperr.CodeGatewayTimeout
+	case status >= 500:
+		return apperr.CodeBadGateway
+	default:
+		return apperr.CodeInternal
+	}
+}
@@ -0,0 +1,210 @@
+package clients
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"payment-service/pkg/apperr"
+	"payment-service/pkg/i18n"
+
+	"github.com/google/uuid"
+)
+
+type staticServiceToken string
+
+func (t staticServiceToken) ServiceToken(string) (string, error) {
+	return string(t), nil
+}
+
+func TestHttpClientNeverLogsCardNumbers(t *testing.T) {
+	const cardNumber = "4111111111111111"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"card_number":"` + cardNumber + `","promptpay_id":"0812345678","status":"ok"}`))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	previous := log.Writer()
+	log.SetOutput(&out)
+	defer log.SetOutput(previous)
+
+	cfg := DefaultHttpClientConfig()
+	cfg.LogBodies = true
+	cfg.ServiceTokens = staticServiceToken("service-token")
+	client := NewHttpClient("test-service", server.URL, cfg)
+
+	body := map[string]string{
+		"card_number": cardNumber,
+		"cvv":         "123",
+		"note":        "paid with 4111-1111-1111-1111",
+	}
+	var response map[string]string
+	err := client.Do(context.Background(), http.MethodPost, "/v1/charges?token=secret-token&pan="+cardNumber, body, &response)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if response["card_number"] != cardNumber {
+		t.Errorf("redaction must not change the decoded response, got %q", response["card_number"])
+	}
+
+	logged := out.String()
+	if logged == "" {
+		t.Fatal("expected the request to be logged")
+	}
+	digits := strings.NewReplacer(" ", "", "-", "").Replace(logged)
+	for _, secret := range []string{cardNumber, "secret-token", "0812345678", `"cvv":"123"`} {
+		if strings.Contains(digits, strings.NewReplacer(" ", "", "-", "").Replace(secret)) {
+			t.Errorf("%s reached the log:\n%s", secret, logged)
+		}
+	}
+}
+
+func TestOrderClientIsBounded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	cfg := DefaultHttpClientConfig()
+	cfg.Timeout = 50 * time.Millisecond
+	cfg.MaxRetries = 0
+	cfg.ServiceTokens = staticServiceToken("service-token")
+	orders := NewOrderClient(server.URL, cfg)
+
+	start := time.Now()
+	_, err := orders.GetOrderByID(context.Background(), uuid.New())
+	if !apperr.IsCode(err, apperr.CodeGatewayTimeout) {
+		t.Fatalf("got %v, want a gateway timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("took %s", elapsed)
+	}
+
+	// the caller's ctx ends the call as well
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := orders.GetOrderByID(ctx, uuid.New()); err == nil {
+		t.Fatal("cancelled call succeeded")
+	}
+}
+
+func TestBreakerIgnoresCallersGivingUp(t *testing.T) {
+	var hang atomic.Bool
+	hang.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hang.Load() {
+			<-r.Context().Done()
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	cfg := DefaultHttpClientConfig()
+	cfg.Timeout = 50 * time.Millisecond
+	cfg.MaxRetries = 0
+	cfg.BreakerThreshold = 1
+	cfg.BreakerCooldown = time.Hour
+	cfg.ServiceTokens = staticServiceToken("service-token")
+	client := NewHttpClient("test-service", server.URL, cfg)
+
+	// the caller's own deadline passes, or the caller cancels
+	deadline, cancelDeadline := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelDeadline()
+	cancelled, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	for _, ctx := range []context.Context{deadline, cancelled} {
+		if err := client.Do(ctx, http.MethodGet, "/", nil, nil); err == nil {
+			t.Fatal("abandoned call succeeded")
+		}
+	}
+	hang.Store(false)
+	if err := client.Do(context.Background(), http.MethodGet, "/", nil, nil); err != nil {
+		t.Fatalf("breaker opened for calls the caller gave up on: %v", err)
+	}
+
+	// running out of our own timeout is the upstream's fault
+	hang.Store(true)
+	if err := client.Do(context.Background(), http.MethodGet, "/", nil, nil); !apperr.IsCode(err, apperr.CodeGatewayTimeout) {
+		t.Fatalf("got %v, want a gateway timeout", err)
+	}
+	hang.Store(false)
+	if err := client.Do(context.Background(), http.MethodGet, "/", nil, nil); !apperr.IsCode(err, apperr.CodeUnavailable) {
+		t.Fatalf("got %v, want the breaker open", err)
+	}
+}
+
+func TestBreakerProbeIsReleased(t *testing.T) {
+	var fail atomic.Bool
+	fail.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	cfg := DefaultHttpClientConfig()
+	cfg.MaxRetries = 0
+	cfg.BreakerThreshold = 1
+	cfg.BreakerCooldown = time.Millisecond
+	cfg.ServiceTokens = staticServiceToken("service-token")
+	client := NewHttpClient("test-service", server.URL, cfg)
+
+	if err := client.Do(context.Background(), http.MethodGet, "/", nil, nil); !apperr.IsCode(err, apperr.CodeBadGateway) {
+		t.Fatalf("got %v, want a bad gateway", err)
+	}
+	time.Sleep(2 * time.Millisecond)
+	fail.Store(false)
+
+	// the probe fails before it is sent, which says nothing of the upstream
+	if err := client.Do(context.Background(), "NOT A METHOD", "/", nil, nil); !apperr.IsCode(err, apperr.CodeInternal) {
+		t.Fatalf("got %v, want an internal error", err)
+	}
+	if err := client.Do(context.Background(), http.MethodGet, "/", nil, nil); err != nil {
+		t.Fatalf("next probe: %v", err)
+	}
+}
+
+func TestUpstreamMessagesAreNotRelayed(t *testing.T) {
+	const detail = "row 42 violates constraint orders_pkey"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"message":"` + detail + `"}`))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	previous := log.Writer()
+	log.SetOutput(&out)
+	defer log.SetOutput(previous)
+
+	cfg := DefaultHttpClientConfig()
+	cfg.ServiceTokens = staticServiceToken("service-token")
+	err := NewHttpClient("test-service", server.URL, cfg).Do(context.Background(), http.MethodGet, "/", nil, nil)
+
+	var appErr *apperr.Error
+	if !errors.As(err, &appErr) || appErr.Code != apperr.CodeConflict {
+		t.Fatalf("got %v, want a conflict", err)
+	}
+	for _, lang := range []i18n.Lang{i18n.English, i18n.Thai} {
+		if msg := appErr.Msg.In(lang); strings.Contains(msg, detail) {
+			t.Errorf("upstream message relayed: %s", msg)
+		}
+	}
+	if !strings.Contains(out.String(), detail) {
+		t.Errorf("upstream message not logged:\n%s", out.String())
+	}
+}
@@ -0,0 +1,28 @@
+package clients
+
+import (
+	"context"
+	"net/http"
+	client_dto "payment-service/pkg/clients/dto"
+
+	"github.com/google/uuid"
+)
+
+type OrderClient struct {
+	http *HttpClient
+}
+
+func NewOrderClient(baseUrl string, cfg HttpClientConfig) *OrderClient {
+	return &OrderClient{
+		http: NewHttpClient("order-service", baseUrl, cfg),
+	}
+}
+
+func (c *OrderClient) GetOrderByID(ctx context.Context, orderID uuid.UUID) (*client_dto.GetOrderResponseDto, error) {
+	var order client_dto.GetOrderResponseDto
+	if err := c.http.Do(ctx, http.MethodGet, "/v1/orders/"+orderID.String(), nil, &order); err != nil {
+		return nil, err
+	}
+
+	return &order, nil
+}
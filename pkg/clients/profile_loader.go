@@ -0,0 +1,236 @@
+package clients
+
+import (
+	"context"
+	"expvar"
+	"sync"
+	"time"
+
+	contextUtils "payment-service/pkg/context"
+)
+
+// profileCacheMetrics is published on /debug/vars style endpoints as
+// "profile_cache", e.g. {"patient_hits": 10, "patient_misses": 2, ...}.
+var profileCacheMetrics = expvar.NewMap("profile_cache")
+
+type ProfileCacheConfig struct {
+	TTL time.Duration
+	// BatchWindow is how long a lookup waits for others to join its batch
+	BatchWindow  time.Duration
+	MaxBatchSize int
+	MaxEntries   int
+}
+
+func DefaultProfileCacheConfig() ProfileCacheConfig {
+	return ProfileCacheConfig{
+		TTL:          5 * time.Minute,
+		BatchWindow:  5 * time.Millisecond,
+		MaxBatchSize: 100,
+		MaxEntries:   10000,
+	}
+}
+
+type cacheEntry[T any] struct {
+	value   T
+	expires time.Time
+}
+
+// pendingLoad is one ID being fetched. Every caller asking for the same ID
+// while it is in flight waits on the same pendingLoad.
+type pendingLoad[T any] struct {
+	done  chan struct{}
+	value T
+	found bool
+	err   error
+}
+
+type loadBatch[T any] struct {
+	ctx    context.Context
+	caller string
+	ids    []string
+	loads  map[string]*pendingLoad[T]
+	timer  *time.Timer
+}
+
+// profileLoader caches profiles by ID, deduplicates concurrent lookups of
+// the same ID and merges lookups of different IDs that arrive within
+// BatchWindow into a single upstream call.
+//
+// The upstream decides what a caller may see from the token it forwards,
+// so batches, in-flight lookups and cache entries all belong to one caller:
+// a profile one user was allowed to read is never handed to another, and
+// one caller's failed lookup never fails another's.
+type profileLoader[T any] struct {
+	name  string
+	cfg   ProfileCacheConfig
+	fetch func(ctx context.Context, ids []string) ([]T, error)
+	idOf  func(T) string
+
+	mu       sync.Mutex
+	cache    map[loadKey]cacheEntry[T]
+	inflight map[loadKey]*pendingLoad[T]
+	batches  map[string]*loadBatch[T]
+}
+
+// loadKey is an ID as looked up by one caller. caller is the access token
+// the lookup is made with, "" for the service's own calls.
+type loadKey struct {
+	caller string
+	id     string
+}
+
+func newProfileLoader[T any](name string, cfg ProfileCacheConfig, fetch func(ctx context.Context, ids []string) ([]T, error), idOf func(T) string) *profileLoader[T] {
+	return &profileLoader[T]{
+		name:     name,
+		cfg:      cfg,
+		fetch:    fetch,
+		idOf:     idOf,
+		cache:    make(map[loadKey]cacheEntry[T]),
+		inflight: make(map[loadKey]*pendingLoad[T]),
+		batches:  make(map[string]*loadBatch[T]),
+	}
+}
+
+// Load returns the profiles found for ids, in the order of ids. IDs the
+// upstream does not know are left out, as the upstream itself does.
+func (l *profileLoader[T]) Load(ctx context.Context, ids []string) ([]T, error) {
+	now := time.Now()
+	caller := contextUtils.GetAccessToken(ctx)
+	found := make(map[string]T, len(ids))
+	waits := make(map[string]*pendingLoad[T])
+
+	l.mu.Lock()
+	for _, id := range ids {
+		if _, ok := found[id]; ok {
+			continue
+		}
+		if _, ok := waits[id]; ok {
+			continue
+		}
+		key := loadKey{caller: caller, id: id}
+		if entry, ok := l.cache[key]; ok && now.Before(entry.expires) {
+			found[id] = entry.value
+			profileCacheMetrics.Add(l.name+"_hits", 1)
+			continue
+		}
+		profileCacheMetrics.Add(l.name+"_misses", 1)
+		if load, ok := l.inflight[key]; ok {
+			waits[id] = load
+			continue
+		}
+		waits[id] = l.enqueue(ctx, key)
+	}
+	l.mu.Unlock()
+
+	for id, load := range waits {
+		select {
+		case <-load.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if load.err != nil {
+			return nil, load.err
+		}
+		if load.found {
+			found[id] = load.value
+		}
+	}
+
+	result := make([]T, 0, len(found))
+	seen := make(map[string]bool, len(found))
+	for _, id := range ids {
+		if value, ok := found[id]; ok && !seen[id] {
+			result = append(result, value)
+			seen[id] = true
+		}
+	}
+	return result, nil
+}
+
+// enqueue adds key to the batch being collected for its caller. Callers
+// hold l.mu.
+func (l *profileLoader[T]) enqueue(ctx context.Context, key loadKey) *pendingLoad[T] {
+	load := &pendingLoad[T]{done: make(chan struct{})}
+	l.inflight[key] = load
+
+	batch, ok := l.batches[key.caller]
+	if !ok {
+		// Every lookup in the batch carries the same token, so the batch
+		// keeps the credentials of whoever opened it but not its
+		// cancellation.
+		batch = &loadBatch[T]{
+			ctx:    context.WithoutCancel(ctx),
+			caller: key.caller,
+			loads:  make(map[string]*pendingLoad[T]),
+		}
+		batch.timer = time.AfterFunc(l.cfg.BatchWindow, func() { l.flush(batch) })
+		l.batches[key.caller] = batch
+	}
+	batch.ids = append(batch.ids, key.id)
+	batch.loads[key.id] = load
+
+	if l.cfg.MaxBatchSize > 0 && len(batch.ids) >= l.cfg.MaxBatchSize {
+		batch.timer.Stop()
+		delete(l.batches, key.caller)
+		go l.run(batch)
+	}
+	return load
+}
+
+func (l *profileLoader[T]) flush(batch *loadBatch[T]) {
+	l.mu.Lock()
+	if l.batches[batch.caller] != batch {
+		// already dispatched for being full
+		l.mu.Unlock()
+		return
+	}
+	delete(l.batches, batch.caller)
+	l.mu.Unlock()
+	l.run(batch)
+}
+
+func (l *profileLoader[T]) run(batch *loadBatch[T]) {
+	profileCacheMetrics.Add(l.name+"_batches", 1)
+	values, err := l.fetch(batch.ctx, batch.ids)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err == nil {
+		expires := time.Now().Add(l.cfg.TTL)
+		l.evict()
+		for _, value := range values {
+			id := l.idOf(value)
+			load, ok := batch.loads[id]
+			if !ok {
+				// not asked for, so not something to vouch for either
+				continue
+			}
+			load.value = value
+			load.found = true
+			l.cache[loadKey{caller: batch.caller, id: id}] = cacheEntry[T]{value: value, expires: expires}
+		}
+	}
+	for id, load := range batch.loads {
+		load.err = err
+		delete(l.inflight, loadKey{caller: batch.caller, id: id})
+		close(load.done)
+	}
+}
+
+// evict drops expired entries once the cache is full, and everything if
+// that is not enough. Callers hold l.mu.
+func (l *profileLoader[T]) evict() {
+	if l.cfg.MaxEntries <= 0 || len(l.cache) < l.cfg.MaxEntries {
+		return
+	}
+	now := time.Now()
+	for key, entry := range l.cache {
+		if !now.Before(entry.expires) {
+			delete(l.cache, key)
+		}
+	}
+	if len(l.cache) >= l.cfg.MaxEntries {
+		clear(l.cache)
+	}
+}
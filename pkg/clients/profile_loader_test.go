@@ -0,0 +1,151 @@
+package clients
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	contextUtils "payment-service/pkg/context"
+)
+
+type testProfile struct {
+	ID     string
+	Caller string
+}
+
+// upstreamCall is one fetch the loader made: who it was made as and the
+// IDs it asked for.
+type upstreamCall struct {
+	caller string
+	ids    []string
+}
+
+type fakeUpstream struct {
+	mu    sync.Mutex
+	calls []upstreamCall
+	// fails makes every lookup made with the token fail
+	fails map[string]bool
+}
+
+func (u *fakeUpstream) fetch(ctx context.Context, ids []string) ([]testProfile, error) {
+	caller := contextUtils.GetAccessToken(ctx)
+	u.mu.Lock()
+	u.calls = append(u.calls, upstreamCall{caller: caller, ids: slices.Sorted(slices.Values(ids))})
+	u.mu.Unlock()
+	if u.fails[caller] {
+		return nil, errors.New("forbidden")
+	}
+	profiles := make([]testProfile, 0, len(ids))
+	for _, id := range ids {
+		profiles = append(profiles, testProfile{ID: id, Caller: caller})
+	}
+	return profiles, nil
+}
+
+func asCaller(token string) context.Context {
+	return context.WithValue(context.Background(), contextUtils.ContextKeyAccessToken, token)
+}
+
+func TestProfileLoader(t *testing.T) {
+	type lookup struct {
+		caller  string
+		ids     []string
+		wantErr bool
+	}
+
+	tests := []struct {
+		name string
+		// each step runs its lookups concurrently, within one batch window
+		steps [][]lookup
+		fails []string
+		calls []upstreamCall
+	}{
+		{"merges one caller's lookups", [][]lookup{{
+			{caller: "token-a", ids: []string{"p1"}},
+			{caller: "token-a", ids: []string{"p2", "p1"}},
+		}}, nil, []upstreamCall{{"token-a", []string{"p1", "p2"}}}},
+		{"batches two callers apart", [][]lookup{{
+			{caller: "token-a", ids: []string{"p1"}},
+			{caller: "token-b", ids: []string{"p1", "p2"}},
+		}}, nil, []upstreamCall{{"token-a", []string{"p1"}}, {"token-b", []string{"p1", "p2"}}}},
+		{"fails only the caller refused", [][]lookup{{
+			{caller: "token-a", ids: []string{"p1"}},
+			{caller: "token-b", ids: []string{"p2"}, wantErr: true},
+		}}, []string{"token-b"}, []upstreamCall{{"token-a", []string{"p1"}}, {"token-b", []string{"p2"}}}},
+		{"caches per caller", [][]lookup{
+			{{caller: "token-a", ids: []string{"p1"}}},
+			{{caller: "token-a", ids: []string{"p1"}}},
+			{{caller: "token-b", ids: []string{"p1"}}},
+		}, nil, []upstreamCall{{"token-a", []string{"p1"}}, {"token-b", []string{"p1"}}}},
+		{"caches nothing for a failed lookup", [][]lookup{
+			{{caller: "token-b", ids: []string{"p1"}, wantErr: true}},
+			{{caller: "token-b", ids: []string{"p1"}, wantErr: true}},
+		}, []string{"token-b"}, []upstreamCall{{"token-b", []string{"p1"}}, {"token-b", []string{"p1"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upstream := &fakeUpstream{fails: make(map[string]bool)}
+			for _, caller := range tt.fails {
+				upstream.fails[caller] = true
+			}
+			loader := newProfileLoader("test", ProfileCacheConfig{
+				TTL:          time.Minute,
+				BatchWindow:  20 * time.Millisecond,
+				MaxBatchSize: 100,
+				MaxEntries:   100,
+			}, upstream.fetch, func(p testProfile) string { return p.ID })
+
+			for _, step := range tt.steps {
+				var wg sync.WaitGroup
+				for _, l := range step {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						got, err := loader.Load(asCaller(l.caller), l.ids)
+						if (err != nil) != l.wantErr {
+							t.Errorf("%s got error %v", l.caller, err)
+							return
+						}
+						for _, profile := range got {
+							if profile.Caller != l.caller {
+								t.Errorf("%s got %s's profile of %s", l.caller, profile.Caller, profile.ID)
+							}
+						}
+						if err == nil && len(got) != len(l.ids)-countRepeats(l.ids) {
+							t.Errorf("%s got %d profiles for %v", l.caller, len(got), l.ids)
+						}
+					}()
+				}
+				wg.Wait()
+			}
+
+			slices.SortStableFunc(upstream.calls, func(a, b upstreamCall) int {
+				return strings.Compare(a.caller, b.caller)
+			})
+			if len(upstream.calls) != len(tt.calls) {
+				t.Fatalf("got calls %v, want %v", upstream.calls, tt.calls)
+			}
+			for i, call := range upstream.calls {
+				if call.caller != tt.calls[i].caller || !slices.Equal(call.ids, tt.calls[i].ids) {
+					t.Fatalf("got calls %v, want %v", upstream.calls, tt.calls)
+				}
+			}
+		})
+	}
+}
+
+func countRepeats(ids []string) int {
+	seen := make(map[string]bool, len(ids))
+	repeats := 0
+	for _, id := range ids {
+		if seen[id] {
+			repeats++
+		}
+		seen[id] = true
+	}
+	return repeats
+}
@@ -1,85 +1,28 @@
 package clients
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"fmt"
 	"net/http"
 	client_dto "payment-service/pkg/clients/dto"
-	contextUtils "payment-service/pkg/context"
 )
 
-type UserClient struct {
-	baseUrl string
-	hc      *http.Client
+// Users is the user service as its callers see it. UserClient and
+// CachedUserClient both implement it.
+type Users interface {
+	GetDoctorByIds(ctx context.Context, doctorIDs []string) (*[]client_dto.GetDoctorProfileResponseDto, error)
+	GetDoctorById(ctx context.Context, doctorID string) (*client_dto.GetDoctorProfileResponseDto, error)
+	GetPatientByIds(ctx context.Context, patientIDs []string) (*[]client_dto.GetPatientProfileResponseDto, error)
+	GetPatientEntitlements(ctx context.Context, patientID string) (*[]client_dto.GetPatientEntitlementResponseDto, error)
 }
 
-func NewUserClient(baseUrl string) *UserClient {
-	return &UserClient{
-		baseUrl: baseUrl,
-		hc: &http.Client{
-			Timeout: http.DefaultClient.Timeout,
-		},
-	}
+type UserClient struct {
+	http *HttpClient
 }
 
-func (c *UserClient) doRequest(ctx context.Context, method, path string, body interface{}, response interface{}) error {
-	accessToken := contextUtils.GetAccessToken(ctx)
-	if accessToken == "" {
-		return fmt.Errorf("access token is empty")
-	}
-
-	url := fmt.Sprintf("%s%s", c.baseUrl, path)
-
-	var req *http.Request
-	var err error
-
-	if body != nil {
-		jsonBody, err := json.Marshal(body)
-		if err != nil {
-			return fmt.Errorf("failed to marshal request body: %w", err)
-		}
-		req, err = http.NewRequest(method, url, bytes.NewBuffer(jsonBody))
-		if err != nil {
-			return fmt.Errorf("failed to create request: %w", err)
-		}
-		req.Header.Set("Content-Type", "application/json")
-	} else {
-		req, err = http.NewRequest(method, url, nil)
-		if err != nil {
-			return fmt.Errorf("failed to create request: %w", err)
-		}
-	}
-
-	req.AddCookie(&http.Cookie{
-		Name:  "access_token",
-		Value: accessToken,
-	})
-
-	fmt.Println("Requesting URL:", url)
-	fmt.Println("With Method:", method)
-	if body != nil {
-		fmt.Println("With Body:", body)
-	}
-
-	resp, err := c.hc.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to execute request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
-	}
-
-	if response != nil {
-		if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
-			return fmt.Errorf("failed to decode response: %w", err)
-		}
+func NewUserClient(baseUrl string, cfg HttpClientConfig) *UserClient {
+	return &UserClient{
+		http: NewHttpClient("user-service", baseUrl, cfg),
 	}
-
-	return nil
 }
 
 func (c *UserClient) GetDoctorByIds(ctx context.Context, doctorID []string) (*[]client_dto.GetDoctorProfileResponseDto, error) {
@@ -88,7 +31,7 @@ func (c *UserClient) GetDoctorByIds(ctx context.Context, doctorID []string) (*[]
 	}
 
 	var doctorProfiles []client_dto.GetDoctorProfileResponseDto
-	if err := c.doRequest(ctx, http.MethodPost, "/v1/doctors", reqBody, &doctorProfiles); err != nil {
+	if err := c.http.Do(ctx, http.MethodPost, "/v1/doctors", reqBody, &doctorProfiles, Idempotent()); err != nil {
 		return nil, err
 	}
 
@@ -97,7 +40,7 @@ func (c *UserClient) GetDoctorByIds(ctx context.Context, doctorID []string) (*[]
 
 func (c *UserClient) GetDoctorById(ctx context.Context, doctorID string) (*client_dto.GetDoctorProfileResponseDto, error) {
 	var doctorProfile client_dto.GetDoctorProfileResponseDto
-	if err := c.doRequest(ctx, http.MethodPost, "/v1/doctors", client_dto.GetDoctorsByIDsRequestDto{DoctorIDs: []string{doctorID}}, &doctorProfile); err != nil {
+	if err := c.http.Do(ctx, http.MethodPost, "/v1/doctors", client_dto.GetDoctorsByIDsRequestDto{DoctorIDs: []string{doctorID}}, &doctorProfile, Idempotent()); err != nil {
 		return nil, err
 	}
 
@@ -110,9 +53,18 @@ func (c *UserClient) GetPatientByIds(ctx context.Context, patientIDs []string) (
 	}
 
 	var patientProfiles []client_dto.GetPatientProfileResponseDto
-	if err := c.doRequest(ctx, http.MethodPost, "/v1/patients", reqBody, &patientProfiles); err != nil {
+	if err := c.http.Do(ctx, http.MethodPost, "/v1/patients", reqBody, &patientProfiles, Idempotent()); err != nil {
 		return nil, err
 	}
 
 	return &patientProfiles, nil
 }
+
+func (c *UserClient) GetPatientEntitlements(ctx context.Context, patientID string) (*[]client_dto.GetPatientEntitlementResponseDto, error) {
+	var entitlements []client_dto.GetPatientEntitlementResponseDto
+	if err := c.http.Do(ctx, http.MethodGet, "/v1/patients/"+patientID+"/healthcare-entitlements", nil, &entitlements); err != nil {
+		return nil, err
+	}
+
+	return &entitlements, nil
+}
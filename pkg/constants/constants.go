@@ -5,6 +5,8 @@ const (
 	RoleAdmin   = "admin"
 	RoleDoctor  = "doctor"
 	RolePatient = "patient"
+	// RoleService is carried by tokens other services mint for internal calls
+	RoleService = "service"
 
 	// Error messages
 	ErrUnuserorized = "unuserorized access"
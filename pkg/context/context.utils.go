@@ -3,9 +3,9 @@ package contextUtils
 import (
 	"context"
 	// "time"
-	"payment-service/pkg/response"
+	"payment-service/pkg/apperr"
+	"payment-service/pkg/i18n"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -15,17 +15,21 @@ const (
 	ContextKeyUserID      contextKey = "userID"
 	ContextKeyRole        contextKey = "role"
 	ContextKeyAccessToken contextKey = "accessToken"
+	ContextKeyRequestID   contextKey = "requestID"
+	ContextKeyClientIP    contextKey = "clientIP"
+	// ContextKeyClientCountry is the country the edge proxy resolved the
+	// client IP to, when it tells us
+	ContextKeyClientCountry contextKey = "clientCountry"
+	ContextKeyLanguage      contextKey = "language"
 )
 
+// WithBody parses the body into Locals("body"). The app's JSON decoder
+// validates it on the way.
 func WithBody[T any]() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var body T
 		if err := c.BodyParser(&body); err != nil {
-			return response.BadRequest(c, "Cannot parse JSON")
-		}
-		validate := validator.New()
-		if err := validate.Struct(body); err != nil {
-			return response.BadRequest(c, err.Error())
+			return apperr.WriteError(c, apperr.BadInput(i18n.InvalidRequestBody, err))
 		}
 		c.Locals("body", body)
 		return c.Next()
@@ -40,8 +44,48 @@ func GetRole(c context.Context) string {
 	return c.Value(ContextKeyRole).(string)
 }
 
+// GetAccessToken returns "" when the context carries no user token, e.g.
+// in background jobs.
 func GetAccessToken(c context.Context) string {
-	return c.Value(ContextKeyAccessToken).(string)
+	token, _ := c.Value(ContextKeyAccessToken).(string)
+	return token
+}
+
+// LookupUserId is GetUserId for code that may run outside a request.
+func LookupUserId(c context.Context) (string, bool) {
+	userID, ok := c.Value(ContextKeyUserID).(string)
+	return userID, ok
+}
+
+// LookupRole is GetRole for code that may run outside a request.
+func LookupRole(c context.Context) (string, bool) {
+	role, ok := c.Value(ContextKeyRole).(string)
+	return role, ok
+}
+
+func GetRequestID(c context.Context) string {
+	requestID, _ := c.Value(ContextKeyRequestID).(string)
+	return requestID
+}
+
+func GetClientIP(c context.Context) string {
+	clientIP, _ := c.Value(ContextKeyClientIP).(string)
+	return clientIP
+}
+
+func GetClientCountry(c context.Context) string {
+	country, _ := c.Value(ContextKeyClientCountry).(string)
+	return country
+}
+
+// GetLanguage is the language the client asked for, English when the
+// context carries none.
+func GetLanguage(c context.Context) i18n.Lang {
+	lang, ok := c.Value(ContextKeyLanguage).(i18n.Lang)
+	if !ok {
+		return i18n.English
+	}
+	return lang
 }
 
 func GetContext(c *fiber.Ctx) context.Context {
@@ -58,6 +102,20 @@ func GetContext(c *fiber.Ctx) context.Context {
 	if s, ok := token.(string); ok {
 		ctx = context.WithValue(ctx, ContextKeyAccessToken, s)
 	}
+	// set by the requestid middleware
+	requestID := c.Locals("requestid")
+	if s, ok := requestID.(string); ok {
+		ctx = context.WithValue(ctx, ContextKeyRequestID, s)
+	}
+	ctx = context.WithValue(ctx, ContextKeyClientIP, c.IP())
+	country := c.Locals("clientCountry")
+	if s, ok := country.(string); ok {
+		ctx = context.WithValue(ctx, ContextKeyClientCountry, s)
+	}
+	// set by the Language middleware
+	if lang, ok := c.Locals("lang").(i18n.Lang); ok {
+		ctx = context.WithValue(ctx, ContextKeyLanguage, lang)
+	}
 
 	return ctx
 }
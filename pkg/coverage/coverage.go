@@ -0,0 +1,171 @@
+package coverage
+
+import (
+	"math"
+	"sort"
+
+	"payment-service/pkg/models"
+	"payment-service/pkg/utils"
+)
+
+// Split is how a charge is divided between the patient and third-party
+// payers. Shares is empty when nothing is covered.
+type Split struct {
+	PatientAmount float64
+	PayerAmount   float64
+	// Entitlement is the healthcare entitlement the shares come from
+	Entitlement string
+	Shares      []Share
+}
+
+// Share is what one payer owes. Rule is the payer's highest-priority rule
+// that covered part of the charge.
+type Share struct {
+	Rule   *models.CoverageRule
+	Amount float64
+}
+
+// Calculate splits amount using the coverage rules of the patient's
+// entitlements. When line items are given each line is covered by the rule
+// for its category, falling back to the entitlement's catch-all rule;
+// otherwise the catch-all rule applies to the whole amount. Rules of one
+// entitlement may name different payers, and each payer gets the share its
+// rules cover. If several entitlements apply, the one covering the most is
+// used.
+func Calculate(amount float64, items []models.PaymentLineItem, rules []models.CoverageRule) Split {
+	total := utils.ToSatang(amount)
+	best := Split{PatientAmount: amount}
+	var bestCovered int64
+
+	for _, entitlementRules := range groupByEntitlement(rules) {
+		shares := coverEntitlement(total, items, entitlementRules)
+		var covered int64
+		for _, share := range shares {
+			covered += share.amount
+		}
+		if covered > bestCovered {
+			bestCovered = covered
+			best = Split{
+				PatientAmount: utils.FromSatang(total - covered),
+				PayerAmount:   utils.FromSatang(covered),
+				Entitlement:   entitlementRules[0].HealthcareEntitlement,
+			}
+			for _, share := range shares {
+				best.Shares = append(best.Shares, Share{Rule: share.rule, Amount: utils.FromSatang(share.amount)})
+			}
+		}
+	}
+	return best
+}
+
+type payer struct {
+	payerType models.PayerType
+	name      string
+}
+
+type share struct {
+	rule   *models.CoverageRule
+	amount int64
+}
+
+// coverEntitlement returns the positive share of each payer, in priority
+// order, together never more than total.
+func coverEntitlement(total int64, items []models.PaymentLineItem, rules []models.CoverageRule) []share {
+	var catchAll *models.CoverageRule
+	byCategory := make(map[models.LineItemCategory]*models.CoverageRule)
+	for i := range rules {
+		if rules[i].Category == nil {
+			catchAll = &rules[i]
+		} else {
+			byCategory[*rules[i].Category] = &rules[i]
+		}
+	}
+
+	coveredByRule := make(map[*models.CoverageRule]int64)
+	if len(items) == 0 {
+		if catchAll != nil {
+			coveredByRule[catchAll] = percentOf(total, catchAll.CoveragePercent)
+		}
+	} else {
+		for i := range items {
+			rule, ok := byCategory[items[i].Category]
+			if !ok {
+				rule = catchAll
+			}
+			if rule == nil {
+				continue
+			}
+			line := utils.ToSatang(items[i].Quantity*items[i].UnitPrice) + utils.ToSatang(items[i].Tax)
+			coveredByRule[rule] += percentOf(line, rule.CoveragePercent)
+		}
+	}
+
+	byPayer := make(map[payer]*share)
+	for rule, amount := range coveredByRule {
+		if rule.MaxCoverage != nil && amount > utils.ToSatang(*rule.MaxCoverage) {
+			amount = utils.ToSatang(*rule.MaxCoverage)
+		}
+		key := payer{rule.PayerType, rule.PayerName}
+		current, ok := byPayer[key]
+		if !ok {
+			current = &share{rule: rule}
+			byPayer[key] = current
+		} else if before(rule, current.rule) {
+			current.rule = rule
+		}
+		current.amount += amount
+	}
+
+	shares := make([]share, 0, len(byPayer))
+	for _, share := range byPayer {
+		shares = append(shares, *share)
+	}
+	sort.Slice(shares, func(i, j int) bool { return before(shares[i].rule, shares[j].rule) })
+
+	// payers are paid in priority order; whatever exceeds the charge comes
+	// off the last ones
+	remaining := total
+	kept := shares[:0]
+	for _, share := range shares {
+		share.amount = min(share.amount, remaining)
+		if share.amount <= 0 {
+			continue
+		}
+		remaining -= share.amount
+		kept = append(kept, share)
+	}
+	return kept
+}
+
+func before(a, b *models.CoverageRule) bool {
+	if a.Priority != b.Priority {
+		return a.Priority < b.Priority
+	}
+	return a.ID.String() < b.ID.String()
+}
+
+// groupByEntitlement orders groups by entitlement name so ties between
+// entitlements resolve the same way on every call.
+func groupByEntitlement(rules []models.CoverageRule) [][]models.CoverageRule {
+	sorted := make([]models.CoverageRule, len(rules))
+	copy(sorted, rules)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].HealthcareEntitlement != sorted[j].HealthcareEntitlement {
+			return sorted[i].HealthcareEntitlement < sorted[j].HealthcareEntitlement
+		}
+		return sorted[i].Priority < sorted[j].Priority
+	})
+
+	var groups [][]models.CoverageRule
+	for i, rule := range sorted {
+		if i == 0 || rule.HealthcareEntitlement != sorted[i-1].HealthcareEntitlement {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], rule)
+	}
+	return groups
+}
+
+func percentOf(satang int64, percent float64) int64 {
+	return int64(math.Round(float64(satang) * percent / 100))
+}
@@ -0,0 +1,78 @@
+package coverage
+
+import (
+	"testing"
+
+	"payment-service/pkg/models"
+	"payment-service/pkg/utils"
+)
+
+func TestCalculate(t *testing.T) {
+	medicine := models.LineItemCategoryMedicine
+	consultation := models.LineItemCategoryConsultationFee
+	maxCoverage := 100.0
+	rule := func(entitlement string, payer string, category *models.LineItemCategory, percent float64, priority int) models.CoverageRule {
+		return models.CoverageRule{
+			ID:                    utils.GenerateUUIDv7(),
+			HealthcareEntitlement: entitlement,
+			PayerType:             models.PayerTypeInsurer,
+			PayerName:             payer,
+			Category:              category,
+			CoveragePercent:       percent,
+			Priority:              priority,
+			Active:                true,
+		}
+	}
+	items := []models.PaymentLineItem{
+		{Quantity: 2, UnitPrice: 300, Category: models.LineItemCategoryMedicine},
+		{Quantity: 1, UnitPrice: 400, Category: models.LineItemCategoryConsultationFee},
+	}
+	discounted := append(items, models.PaymentLineItem{Quantity: 1, UnitPrice: -200, Category: models.LineItemCategoryDiscount})
+	capped := rule("private", "Insurer A", nil, 50, 1)
+	capped.MaxCoverage = &maxCoverage
+
+	tests := []struct {
+		name    string
+		amount  float64
+		items   []models.PaymentLineItem
+		rules   []models.CoverageRule
+		patient float64
+		shares  map[string]float64
+	}{
+		{"nothing covered", 1000, nil, nil, 1000, nil},
+		{"catch-all", 1000, nil, []models.CoverageRule{rule("private", "Insurer A", nil, 80, 1)}, 200, map[string]float64{"Insurer A": 800}},
+		{"capped", 1000, nil, []models.CoverageRule{capped}, 900, map[string]float64{"Insurer A": 100}},
+		{"payer per category", 1000, items, []models.CoverageRule{
+			rule("private", "Insurer A", &medicine, 50, 1),
+			rule("private", "Insurer B", &consultation, 100, 2),
+		}, 300, map[string]float64{"Insurer A": 300, "Insurer B": 400}},
+		{"one payer across categories", 1000, items, []models.CoverageRule{
+			rule("private", "Insurer A", &medicine, 50, 1),
+			rule("private", "Insurer A", nil, 100, 2),
+		}, 300, map[string]float64{"Insurer A": 700}},
+		{"best entitlement", 1000, nil, []models.CoverageRule{
+			rule("civil_servant", "Comptroller", nil, 90, 1),
+			rule("private", "Insurer A", nil, 50, 1),
+		}, 100, map[string]float64{"Comptroller": 900}},
+		{"never more than the charge", 800, discounted, []models.CoverageRule{
+			rule("private", "Insurer A", &medicine, 100, 1),
+			rule("private", "Insurer B", &consultation, 100, 2),
+		}, 0, map[string]float64{"Insurer A": 600, "Insurer B": 200}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Calculate(tt.amount, tt.items, tt.rules)
+			if got.PatientAmount != tt.patient || got.PatientAmount+got.PayerAmount != tt.amount {
+				t.Fatalf("got patient %v, payer %v", got.PatientAmount, got.PayerAmount)
+			}
+			if len(got.Shares) != len(tt.shares) {
+				t.Fatalf("got shares %+v, want %v", got.Shares, tt.shares)
+			}
+			for _, share := range got.Shares {
+				if want, ok := tt.shares[share.Rule.PayerName]; !ok || share.Amount != want {
+					t.Fatalf("%s got %v, want %v", share.Rule.PayerName, share.Amount, want)
+				}
+			}
+		})
+	}
+}
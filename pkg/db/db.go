@@ -21,17 +21,19 @@ type Config struct {
 }
 
 func Open(cfg Config) *gorm.DB {
-	newLogger := logger.New(
+	newLogger := NewRedactingLogger(logger.New(
 		log.New(os.Stdout, "\r\n", log.LstdFlags), // io writer
 		logger.Config{
 			SlowThreshold: time.Second, // Slow SQL threshold
 			LogLevel:      logger.Info, // Log level
 			Colorful:      true,        // Enable color
 		},
-	)
+	))
 	con := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s", cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.Dbname, cfg.Sslmode)
 	db, err := gorm.Open(postgres.Open(con), &gorm.Config{
 		Logger: newLogger,
+		// report unique violations as gorm.ErrDuplicatedKey and so on
+		TranslateError: true,
 	})
 
 	if err != nil {
@@ -0,0 +1,133 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"payment-service/pkg/redact"
+
+	"gorm.io/gorm/logger"
+)
+
+var (
+	// INSERT INTO "t" ("a","b") VALUES ($1,$2),($3,$4)
+	insertColumnsPattern = regexp.MustCompile(`(?is)^\s*INSERT\s+INTO\s+\S+\s*\(([^)]*)\)\s*VALUES`)
+	// "a" = $1, a IN ($2,$3), "t"."a" <> $4 ...
+	comparisonPattern  = regexp.MustCompile(`(?i)"?(\w+)"?\s*(?:=|<>|!=|>=|<=|>|<|\bLIKE\b|\bIN\b)\s*\(?\s*((?:\$\d+\s*,?\s*)+)`)
+	placeholderPattern = regexp.MustCompile(`\$(\d+)`)
+)
+
+// RedactingLogger wraps a gorm logger so that logged SQL never carries
+// sensitive values: parameters bound to sensitive columns and raw byte
+// parameters, such as payment details, are masked, and card numbers are
+// masked wherever they appear.
+type RedactingLogger struct {
+	logger.Interface
+}
+
+func NewRedactingLogger(inner logger.Interface) *RedactingLogger {
+	return &RedactingLogger{Interface: inner}
+}
+
+// LogMode keeps the wrapper when gorm derives a logger at another level.
+func (l *RedactingLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return &RedactingLogger{Interface: l.Interface.LogMode(level)}
+}
+
+func (l *RedactingLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	l.Interface.Info(ctx, "%s", redact.String(fmt.Sprintf(msg, data...)))
+}
+
+func (l *RedactingLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	l.Interface.Warn(ctx, "%s", redact.String(fmt.Sprintf(msg, data...)))
+}
+
+func (l *RedactingLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	l.Interface.Error(ctx, "%s", redact.String(fmt.Sprintf(msg, data...)))
+}
+
+// Trace masks card numbers in the SQL text and in database errors, which
+// can quote the offending value.
+func (l *RedactingLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if err != nil {
+		err = redactedError{err}
+	}
+	l.Interface.Trace(ctx, begin, func() (string, int64) {
+		sql, rows := fc()
+		return redact.String(sql), rows
+	}, err)
+}
+
+// ParamsFilter implements gorm.ParamsFilter, which gorm applies to the
+// parameters before it renders them into the logged SQL.
+func (l *RedactingLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	sensitive := sensitiveParams(sql, len(params))
+	filtered := make([]interface{}, len(params))
+	for i, param := range params {
+		switch value := param.(type) {
+		case []byte:
+			filtered[i] = redact.Mask
+		case string:
+			if sensitive[i] {
+				filtered[i] = redact.Mask
+			} else {
+				filtered[i] = redact.String(value)
+			}
+		default:
+			if sensitive[i] {
+				filtered[i] = redact.Mask
+			} else {
+				filtered[i] = param
+			}
+		}
+	}
+	return sql, filtered
+}
+
+// sensitiveParams works out which parameters are bound to a sensitive
+// column, from an INSERT column list or from comparisons like "col" = $n.
+func sensitiveParams(sql string, count int) []bool {
+	sensitive := make([]bool, count)
+	mark := func(placeholder string) {
+		n, err := strconv.Atoi(placeholder)
+		if err == nil && n >= 1 && n <= count {
+			sensitive[n-1] = true
+		}
+	}
+
+	if match := insertColumnsPattern.FindStringSubmatch(sql); match != nil {
+		columns := strings.Split(match[1], ",")
+		for i := 0; i < count; i++ {
+			column := strings.Trim(strings.TrimSpace(columns[i%len(columns)]), `"`)
+			if redact.IsSensitiveKey(column) {
+				sensitive[i] = true
+			}
+		}
+	}
+
+	for _, match := range comparisonPattern.FindAllStringSubmatch(sql, -1) {
+		if !redact.IsSensitiveKey(match[1]) {
+			continue
+		}
+		for _, placeholder := range placeholderPattern.FindAllStringSubmatch(match[2], -1) {
+			mark(placeholder[1])
+		}
+	}
+	return sensitive
+}
+
+type redactedError struct {
+	err error
+}
+
+func (e redactedError) Error() string {
+	return redact.String(e.err.Error())
+}
+
+func (e redactedError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,97 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"testing"
+
+	"payment-service/pkg/models"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const testCardNumber = "4111111111111111"
+
+// newDryRunDB builds SQL without a database, logging everything to out.
+func newDryRunDB(t *testing.T, out *bytes.Buffer) *gorm.DB {
+	t.Helper()
+	sqlLogger := NewRedactingLogger(logger.New(log.New(out, "", 0), logger.Config{LogLevel: logger.Info}))
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1 port=1"}), &gorm.Config{
+		Logger:                 sqlLogger,
+		DryRun:                 true,
+		SkipDefaultTransaction: true,
+		DisableAutomaticPing:   true,
+	})
+	if err != nil {
+		t.Fatalf("failed to open dry-run database: %v", err)
+	}
+	return db
+}
+
+func TestRedactingLoggerNeverLogsCardNumbers(t *testing.T) {
+	var out bytes.Buffer
+	db := newDryRunDB(t, &out)
+	ctx := context.Background()
+
+	info := &models.PaymentInformation{
+		ID:      uuid.New(),
+		UserID:  uuid.New(),
+		Type:    models.PaymentMethodCreditCard,
+		Details: []byte(`{"card_number":"` + testCardNumber + `","cvv":"123"}`),
+		Version: 1,
+	}
+
+	statements := map[string]func() error{
+		"insert": func() error { return db.WithContext(ctx).Create(info).Error },
+		"update": func() error { return db.WithContext(ctx).Model(info).Updates(info).Error },
+		"where on sensitive column": func() error {
+			return db.WithContext(ctx).Where("card_number = ?", testCardNumber).Find(&[]models.PaymentInformation{}).Error
+		},
+		"card number in free text": func() error {
+			return db.WithContext(ctx).Where("type = ?", "card 4111 1111 1111 1111").Find(&[]models.PaymentInformation{}).Error
+		},
+		"raw sql": func() error {
+			return db.WithContext(ctx).Exec("SELECT ?::text", testCardNumber).Error
+		},
+	}
+
+	for name, run := range statements {
+		t.Run(name, func(t *testing.T) {
+			out.Reset()
+			if err := run(); err != nil {
+				t.Fatalf("statement failed: %v", err)
+			}
+			logged := out.String()
+			if logged == "" {
+				t.Fatal("expected the statement to be logged")
+			}
+			digits := strings.NewReplacer(" ", "", "-", "").Replace(logged)
+			if strings.Contains(digits, testCardNumber) {
+				t.Errorf("card number reached the log:\n%s", logged)
+			}
+			if strings.Contains(logged, `"cvv":"123"`) {
+				t.Errorf("cvv reached the log:\n%s", logged)
+			}
+		})
+	}
+}
+
+func TestRedactingLoggerKeepsOrdinaryParams(t *testing.T) {
+	var out bytes.Buffer
+	db := newDryRunDB(t, &out)
+
+	userID := uuid.New()
+	if err := db.Where("user_id = ? AND type = ?", userID, "promptpay").Find(&[]models.PaymentInformation{}).Error; err != nil {
+		t.Fatalf("statement failed: %v", err)
+	}
+
+	logged := out.String()
+	if !strings.Contains(logged, userID.String()) || !strings.Contains(logged, "promptpay") {
+		t.Errorf("expected ordinary parameters in the log:\n%s", logged)
+	}
+}
@@ -0,0 +1,59 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- payable_type is plain text (not an enum) so new billable kinds only need a
+-- resolver in the application, not another migration.
+ALTER TABLE payment_attempts
+  ADD COLUMN payable_type text,
+  ADD COLUMN payable_id uuid;
+
+UPDATE payment_attempts SET payable_type = 'order', payable_id = order_id;
+
+ALTER TABLE payment_attempts
+  ALTER COLUMN payable_type SET NOT NULL,
+  ALTER COLUMN payable_id SET NOT NULL;
+
+ALTER TABLE payments
+  ADD COLUMN payable_type text,
+  ADD COLUMN payable_id uuid;
+
+UPDATE payments SET payable_type = 'order', payable_id = order_id;
+
+ALTER TABLE payments
+  ALTER COLUMN payable_type SET NOT NULL,
+  ALTER COLUMN payable_id SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_attempts_order;
+DROP INDEX IF EXISTS idx_payments_order;
+
+ALTER TABLE payment_attempts DROP COLUMN order_id;
+ALTER TABLE payments DROP COLUMN order_id;
+
+CREATE INDEX idx_attempts_payable ON payment_attempts(payable_type, payable_id);
+CREATE INDEX idx_payments_payable ON payments(payable_type, payable_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_payments_payable;
+DROP INDEX IF EXISTS idx_attempts_payable;
+
+-- non-order payables have no equivalent in the old schema; their id is kept
+-- in order_id so the rows survive the rollback.
+ALTER TABLE payment_attempts ADD COLUMN order_id uuid;
+UPDATE payment_attempts SET order_id = payable_id;
+ALTER TABLE payment_attempts ALTER COLUMN order_id SET NOT NULL;
+
+ALTER TABLE payments ADD COLUMN order_id uuid;
+UPDATE payments SET order_id = payable_id;
+ALTER TABLE payments ALTER COLUMN order_id SET NOT NULL;
+
+ALTER TABLE payment_attempts DROP COLUMN payable_type, DROP COLUMN payable_id;
+ALTER TABLE payments DROP COLUMN payable_type, DROP COLUMN payable_id;
+
+CREATE INDEX idx_attempts_order ON payment_attempts(order_id);
+CREATE INDEX idx_payments_order ON payments(order_id);
+
+-- +goose StatementEnd
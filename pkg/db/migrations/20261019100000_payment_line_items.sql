@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TYPE line_item_category AS ENUM ('medicine','delivery_fee','consultation_fee','discount','other');
+
+-- Line items are written against an attempt and copied onto the payment
+-- when it is created, so each row belongs to exactly one of the two.
+CREATE TABLE payment_line_items (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  attempt_id uuid REFERENCES payment_attempts(id) ON DELETE CASCADE,
+  payment_id uuid REFERENCES payments(id) ON DELETE CASCADE,
+  description text NOT NULL,
+  quantity numeric(12,2) NOT NULL CHECK (quantity > 0),
+  unit_price numeric(12,2) NOT NULL,
+  tax numeric(12,2) NOT NULL DEFAULT 0 CHECK (tax >= 0),
+  category line_item_category NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  CONSTRAINT line_item_single_owner CHECK (num_nonnulls(attempt_id, payment_id) = 1),
+  CONSTRAINT line_item_discount_sign CHECK (
+    (category = 'discount' AND unit_price <= 0) OR (category <> 'discount' AND unit_price >= 0)
+  )
+);
+
+CREATE INDEX idx_line_items_attempt ON payment_line_items(attempt_id);
+CREATE INDEX idx_line_items_payment ON payment_line_items(payment_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_line_items_payment;
+DROP INDEX IF EXISTS idx_line_items_attempt;
+DROP TABLE IF EXISTS payment_line_items;
+DROP TYPE IF EXISTS line_item_category;
+
+-- +goose StatementEnd
@@ -0,0 +1,67 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TYPE payer_type AS ENUM ('insurer','government');
+CREATE TYPE receivable_status AS ENUM ('accrued','invoiced','settled');
+
+CREATE TABLE coverage_rules (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  healthcare_entitlement text NOT NULL,         -- cross-service to user_service
+  payer_type payer_type NOT NULL,
+  payer_name text NOT NULL,
+  category line_item_category,                  -- NULL covers the whole charge
+  coverage_percent numeric(5,2) NOT NULL CHECK (coverage_percent >= 0 AND coverage_percent <= 100),
+  max_coverage numeric(12,2) CHECK (max_coverage IS NULL OR max_coverage >= 0),
+  priority int NOT NULL DEFAULT 100,
+  active boolean NOT NULL DEFAULT true,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  CONSTRAINT unique_coverage_rule UNIQUE NULLS NOT DISTINCT (healthcare_entitlement, category)
+);
+
+CREATE INDEX idx_coverage_rules_entitlement ON coverage_rules(healthcare_entitlement) WHERE active;
+
+ALTER TABLE payments
+  ADD COLUMN patient_amount numeric(12,2),
+  ADD COLUMN payer_amount numeric(12,2) NOT NULL DEFAULT 0 CHECK (payer_amount >= 0),
+  ADD COLUMN healthcare_entitlement text;
+
+UPDATE payments SET patient_amount = amount;
+
+ALTER TABLE payments
+  ALTER COLUMN patient_amount SET NOT NULL,
+  ADD CONSTRAINT payment_split_total CHECK (patient_amount + payer_amount = amount);
+
+CREATE TABLE payer_receivables (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  payment_id uuid NOT NULL UNIQUE REFERENCES payments(id) ON DELETE CASCADE,
+  payer_type payer_type NOT NULL,
+  payer_name text NOT NULL,
+  healthcare_entitlement text NOT NULL,
+  amount numeric(12,2) NOT NULL CHECK (amount > 0),
+  status receivable_status NOT NULL DEFAULT 'accrued',
+  created_at timestamptz NOT NULL DEFAULT now(),
+  invoiced_at timestamptz
+);
+
+CREATE INDEX idx_receivables_status ON payer_receivables(status, created_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_receivables_status;
+DROP TABLE IF EXISTS payer_receivables;
+
+ALTER TABLE payments
+  DROP CONSTRAINT IF EXISTS payment_split_total,
+  DROP COLUMN IF EXISTS healthcare_entitlement,
+  DROP COLUMN IF EXISTS payer_amount,
+  DROP COLUMN IF EXISTS patient_amount;
+
+DROP INDEX IF EXISTS idx_coverage_rules_entitlement;
+DROP TABLE IF EXISTS coverage_rules;
+DROP TYPE IF EXISTS receivable_status;
+DROP TYPE IF EXISTS payer_type;
+
+-- +goose StatementEnd
@@ -0,0 +1,79 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TYPE document_type AS ENUM ('receipt','tax_invoice');
+CREATE TYPE document_status AS ENUM ('issued','voided');
+CREATE TYPE document_action AS ENUM ('issued','reissued','voided');
+
+-- One counter per branch, document type and year. The counter row is
+-- incremented in the same transaction that inserts the document, so a
+-- rolled back issue never consumes a number.
+CREATE TABLE document_sequences (
+  branch_code text NOT NULL,
+  type document_type NOT NULL,
+  year int NOT NULL,
+  last_number int NOT NULL CHECK (last_number > 0),
+  PRIMARY KEY (branch_code, type, year)
+);
+
+CREATE TABLE payment_documents (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  payment_id uuid NOT NULL REFERENCES payments(id) ON DELETE RESTRICT,
+  type document_type NOT NULL,
+  branch_code text NOT NULL,
+  year int NOT NULL,
+  sequence int NOT NULL CHECK (sequence > 0),
+  number text NOT NULL UNIQUE,
+  status document_status NOT NULL DEFAULT 'issued',
+  seller_name text NOT NULL,
+  seller_tax_id text NOT NULL,
+  seller_address text NOT NULL,
+  buyer_name text NOT NULL,
+  buyer_tax_id text,
+  buyer_address text,
+  buyer_branch text,
+  subtotal numeric(12,2) NOT NULL,
+  vat_rate numeric(5,2) NOT NULL,
+  vat_amount numeric(12,2) NOT NULL,
+  total numeric(12,2) NOT NULL,
+  payer_amount numeric(12,2) NOT NULL DEFAULT 0,
+  patient_amount numeric(12,2) NOT NULL,
+  replaces_document_id uuid REFERENCES payment_documents(id) ON DELETE RESTRICT,
+  void_reason text,
+  issued_at timestamptz NOT NULL DEFAULT now(),
+  voided_at timestamptz,
+  CONSTRAINT unique_document_sequence UNIQUE (branch_code, type, year, sequence),
+  CONSTRAINT tax_invoice_buyer CHECK (type <> 'tax_invoice' OR (buyer_tax_id IS NOT NULL AND buyer_address IS NOT NULL)),
+  CONSTRAINT voided_document CHECK ((status = 'voided') = (voided_at IS NOT NULL))
+);
+
+-- At most one live document of each type per payment
+CREATE UNIQUE INDEX idx_documents_payment_issued ON payment_documents(payment_id, type) WHERE status = 'issued';
+
+CREATE TABLE payment_document_events (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  document_id uuid NOT NULL REFERENCES payment_documents(id) ON DELETE RESTRICT,
+  action document_action NOT NULL,
+  actor_id uuid NOT NULL,                     -- cross-service to user_service
+  actor_role text NOT NULL,
+  reason text,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_document_events_document ON payment_document_events(document_id, created_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_document_events_document;
+DROP TABLE IF EXISTS payment_document_events;
+DROP INDEX IF EXISTS idx_documents_payment_issued;
+DROP TABLE IF EXISTS payment_documents;
+DROP TABLE IF EXISTS document_sequences;
+DROP TYPE IF EXISTS document_action;
+DROP TYPE IF EXISTS document_status;
+DROP TYPE IF EXISTS document_type;
+
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE token_revocations (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  jti text,                                     -- one token
+  user_id uuid,                                 -- every token of a user issued up to created_at
+  reason text,
+  revoked_by uuid NOT NULL,
+  expires_at timestamptz NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  CONSTRAINT token_revocation_target CHECK ((jti IS NULL) <> (user_id IS NULL))
+);
+
+CREATE INDEX idx_token_revocations_expires_at ON token_revocations(expires_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_token_revocations_expires_at;
+DROP TABLE IF EXISTS token_revocations;
+
+-- +goose StatementEnd
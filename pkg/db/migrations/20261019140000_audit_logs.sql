@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TYPE audit_action AS ENUM ('create','update','delete');
+
+-- before and after are json, not jsonb, so they read back byte for byte as
+-- they were hashed.
+CREATE TABLE audit_logs (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  seq bigint GENERATED ALWAYS AS IDENTITY UNIQUE,
+  entity_type text NOT NULL,
+  entity_id text NOT NULL,
+  action audit_action NOT NULL,
+  actor_id text,
+  actor_role text,
+  request_id text,
+  client_ip text,
+  before json,
+  after json,
+  prev_hash text NOT NULL,
+  hash text NOT NULL UNIQUE,
+  created_at timestamptz NOT NULL
+);
+
+CREATE INDEX idx_audit_logs_entity ON audit_logs(entity_type, entity_id);
+CREATE INDEX idx_audit_logs_actor ON audit_logs(actor_id);
+CREATE INDEX idx_audit_logs_request ON audit_logs(request_id);
+CREATE INDEX idx_audit_logs_created_at ON audit_logs(created_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_audit_logs_created_at;
+DROP INDEX IF EXISTS idx_audit_logs_request;
+DROP INDEX IF EXISTS idx_audit_logs_actor;
+DROP INDEX IF EXISTS idx_audit_logs_entity;
+DROP TABLE IF EXISTS audit_logs;
+DROP TYPE IF EXISTS audit_action;
+
+-- +goose StatementEnd
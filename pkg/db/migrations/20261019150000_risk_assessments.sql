@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TYPE risk_decision AS ENUM ('allow','review','block');
+CREATE TYPE review_status AS ENUM ('pending','approved','denied');
+
+CREATE TABLE risk_assessments (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  attempt_id uuid NOT NULL UNIQUE REFERENCES payment_attempts(id) ON DELETE CASCADE,
+  user_id uuid NOT NULL,
+  client_ip text,
+  card_fingerprint text,                        -- keyed hash of the card number
+  card_country text,
+  request_country text,
+  amount numeric(12,2) NOT NULL,
+  decision risk_decision NOT NULL,
+  reasons jsonb NOT NULL DEFAULT '[]',
+  review_status review_status,                  -- set only when decision = 'review'
+  reviewed_by uuid,
+  reviewed_at timestamptz,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+-- velocity rules count recent assessments by user, card and IP
+CREATE INDEX idx_risk_user_created ON risk_assessments(user_id, created_at);
+CREATE INDEX idx_risk_card_created ON risk_assessments(card_fingerprint, created_at) WHERE card_fingerprint IS NOT NULL;
+CREATE INDEX idx_risk_ip_created ON risk_assessments(client_ip, created_at) WHERE client_ip IS NOT NULL;
+CREATE INDEX idx_risk_review_pending ON risk_assessments(created_at) WHERE review_status = 'pending';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_risk_review_pending;
+DROP INDEX IF EXISTS idx_risk_ip_created;
+DROP INDEX IF EXISTS idx_risk_card_created;
+DROP INDEX IF EXISTS idx_risk_user_created;
+DROP TABLE IF EXISTS risk_assessments;
+DROP TYPE IF EXISTS review_status;
+DROP TYPE IF EXISTS risk_decision;
+
+-- +goose StatementEnd
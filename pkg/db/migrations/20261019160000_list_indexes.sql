@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Listings page by id, which the primary keys already cover; these back the
+-- filters. payment_informations(user_id) is covered by unique_payment_profile.
+CREATE INDEX idx_payments_paid_at ON payments(paid_at);
+CREATE INDEX idx_payments_attempt ON payments(attempt_id);
+CREATE INDEX idx_attempts_payment_information ON payment_attempts(payment_information_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_attempts_payment_information;
+DROP INDEX IF EXISTS idx_payments_attempt;
+DROP INDEX IF EXISTS idx_payments_paid_at;
+
+-- +goose StatementEnd
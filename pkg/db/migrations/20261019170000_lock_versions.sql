@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Updates compare and bump these so concurrent edits cannot both win.
+-- payment_informations already has version for this.
+ALTER TABLE payment_attempts ADD COLUMN lock_version int NOT NULL DEFAULT 1 CHECK (lock_version > 0);
+ALTER TABLE payments ADD COLUMN lock_version int NOT NULL DEFAULT 1 CHECK (lock_version > 0);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE payments DROP COLUMN IF EXISTS lock_version;
+ALTER TABLE payment_attempts DROP COLUMN IF EXISTS lock_version;
+
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Rules of one entitlement can name different payers, so a payment has a
+-- receivable per payer rather than one.
+ALTER TABLE payer_receivables
+  DROP CONSTRAINT payer_receivables_payment_id_key,
+  ADD CONSTRAINT unique_receivable_payer UNIQUE (payment_id, payer_type, payer_name);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE payer_receivables
+  DROP CONSTRAINT IF EXISTS unique_receivable_payer,
+  ADD CONSTRAINT payer_receivables_payment_id_key UNIQUE (payment_id);
+
+-- +goose StatementEnd
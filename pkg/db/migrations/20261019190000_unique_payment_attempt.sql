@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- An attempt is settled by one payment at most.
+DROP INDEX IF EXISTS idx_payments_attempt;
+CREATE UNIQUE INDEX unique_payment_attempt ON payments(attempt_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS unique_payment_attempt;
+CREATE INDEX idx_payments_attempt ON payments(attempt_id);
+
+-- +goose StatementEnd
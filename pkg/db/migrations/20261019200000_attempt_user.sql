@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Who made an attempt was only known through its payment information,
+-- which is set to null when the card is deleted. Keep it on the attempt.
+ALTER TABLE payment_attempts ADD COLUMN user_id uuid;
+
+UPDATE payment_attempts AS a
+SET user_id = i.user_id
+FROM payment_informations AS i
+WHERE i.id = a.payment_information_id;
+
+-- attempts whose card is already gone still have their risk assessment
+UPDATE payment_attempts AS a
+SET user_id = r.user_id
+FROM risk_assessments AS r
+WHERE r.attempt_id = a.id AND a.user_id IS NULL;
+
+CREATE INDEX idx_attempts_user ON payment_attempts(user_id, id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_attempts_user;
+ALTER TABLE payment_attempts DROP COLUMN IF EXISTS user_id;
+
+-- +goose StatementEnd
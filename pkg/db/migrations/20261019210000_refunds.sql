@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- A refund gives back part or all of a payment. Refunds are kept for
+-- reconciliation, so a refunded payment cannot be deleted.
+CREATE TABLE refunds (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  payment_id uuid NOT NULL REFERENCES payments(id) ON DELETE RESTRICT,
+  amount numeric(12,2) NOT NULL CHECK (amount > 0),
+  reason text,
+  refunded_by text NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_refunds_payment ON refunds(payment_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS idx_refunds_payment;
+DROP TABLE IF EXISTS refunds;
+
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- A patient saves one method of each type. The version is a lock counter,
+-- so keying on it let a second method of a type in at another version and
+-- made an edit fail when its next version was taken. Fails while duplicate
+-- (user_id, type) rows remain; merge or delete them first.
+ALTER TABLE payment_informations DROP CONSTRAINT unique_payment_profile;
+ALTER TABLE payment_informations ADD CONSTRAINT unique_payment_profile UNIQUE (user_id, type);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE payment_informations DROP CONSTRAINT unique_payment_profile;
+ALTER TABLE payment_informations ADD CONSTRAINT unique_payment_profile UNIQUE (user_id, type, version);
+
+-- +goose StatementEnd
@@ -1,10 +1,16 @@
 package dto
 
+import "payment-service/pkg/models"
+
 type CreatePaymentAttemptRequestDto struct {
-	OrderID       string `json:"order_id" validate:"required"`
-	PaymentInfoID string `json:"payment_info_id" validate:"required"`
+	PayableType   models.PayableType   `json:"payable_type" validate:"required"`
+	PayableID     string               `json:"payable_id" validate:"required"`
+	PaymentInfoID string               `json:"payment_info_id" validate:"required"`
+	LineItems     []LineItemRequestDto `json:"line_items" validate:"omitempty,dive"`
 }
 
+// Status is pending while the attempt waits for a risk review.
 type CreatePaymentAttemptResponseDto struct {
-	PaymentAttemptID string `json:"payment_attempt_id"`
+	PaymentAttemptID string               `json:"payment_attempt_id"`
+	Status           models.PaymentStatus `json:"status"`
 }
@@ -1,14 +1,20 @@
 package dto
 
+import "payment-service/pkg/models"
+
 type CreatePaymentRequestDto struct {
 	PaymentAttemptID string  `json:"payment_attempt_id" validate:"required"`
 	Amount           float64 `json:"amount" validate:"required,gte=0"`
 }
 
 type CreatePaymentResponseDto struct {
-	PaymentID string  `json:"payment_id"`
-	AttemptID string  `json:"attempt_id"`
-	OrderID   string  `json:"order_id"`
-	Amount    float64 `json:"amount"`
-	PaidAt    string  `json:"paid_at"`
+	PaymentID             string             `json:"payment_id"`
+	AttemptID             string             `json:"attempt_id"`
+	PayableType           models.PayableType `json:"payable_type"`
+	PayableID             string             `json:"payable_id"`
+	Amount                float64            `json:"amount"`
+	PatientAmount         float64            `json:"patient_amount"`
+	PayerAmount           float64            `json:"payer_amount"`
+	HealthcareEntitlement string             `json:"healthcare_entitlement,omitempty"`
+	PaidAt                string             `json:"paid_at"`
 }
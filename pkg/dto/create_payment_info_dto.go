@@ -10,6 +10,8 @@ type CreditCardDetails struct {
 	ExpiryMonth    int    `json:"expiry_month" validate:"required,min=1,max=12"`
 	ExpiryYear     int    `json:"expiry_year" validate:"required"`
 	CardHolderName string `json:"card_holder_name" validate:"required"`
+	// IssuerCountry is the ISO 3166 alpha-2 country of the issuing bank
+	IssuerCountry string `json:"issuer_country,omitempty" validate:"omitempty,iso3166_1_alpha2"`
 }
 
 type PromptPayDetails struct {
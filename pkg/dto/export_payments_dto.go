@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"time"
+
+	"payment-service/pkg/export"
+)
+
+// From and To take an RFC 3339 time or a local date; To is exclusive.
+// Columns is a comma separated list of column keys, all columns when empty.
+type ExportPaymentsRequestDto struct {
+	From    string `query:"from" json:"from"`
+	To      string `query:"to" json:"to"`
+	Format  string `query:"format" json:"format"`
+	Columns string `query:"columns" json:"columns"`
+	Locale  string `query:"locale" json:"locale"`
+}
+
+type ExportJobDto struct {
+	ID          string           `json:"id"`
+	Status      export.JobStatus `json:"status"`
+	Format      export.Format    `json:"format"`
+	Filename    string           `json:"filename"`
+	Rows        int              `json:"rows"`
+	CreatedAt   string           `json:"created_at"`
+	CompletedAt string           `json:"completed_at,omitempty"`
+	DownloadURL string           `json:"download_url,omitempty"`
+}
+
+type ExportJobResponseDto struct {
+	Job ExportJobDto `json:"job"`
+}
+
+func ToExportJobDto(job *export.Job, downloadURL string) ExportJobDto {
+	result := ExportJobDto{
+		ID:        job.ID.String(),
+		Status:    job.Status,
+		Format:    job.Format,
+		Filename:  job.Filename,
+		Rows:      job.Rows,
+		CreatedAt: job.CreatedAt.Format(time.RFC3339),
+	}
+	if job.CompletedAt != nil {
+		result.CompletedAt = job.CompletedAt.Format(time.RFC3339)
+	}
+	if job.Status == export.JobStatusDone {
+		result.DownloadURL = downloadURL
+	}
+	return result
+}
@@ -2,8 +2,18 @@ package dto
 
 import "payment-service/pkg/models"
 
+type GetAllPaymentInfosRequestDto struct {
+	UserID string `query:"user_id"`
+	Method string `query:"method"`
+	Sort   string `query:"sort"`
+	Cursor string `query:"cursor"`
+	Limit  int    `query:"limit"`
+}
+
 type GetAllPaymentInfosResponseDto struct {
 	DeliveryInfos []PaymentInfoDto `json:"delivery_infos"`
+	// NextCursor is passed as cursor to fetch the next page; null on the last
+	NextCursor *string `json:"next_cursor"`
 }
 
 func ToPaymentInfoList(info []models.PaymentInformation) []PaymentInfoDto {
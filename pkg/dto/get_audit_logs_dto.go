@@ -0,0 +1,76 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"payment-service/pkg/models"
+)
+
+type GetAuditLogsRequestDto struct {
+	EntityType string `query:"entity_type"`
+	EntityID   string `query:"entity_id"`
+	ActorID    string `query:"actor_id"`
+	Action     string `query:"action"`
+	RequestID  string `query:"request_id"`
+	From       string `query:"from"`
+	To         string `query:"to"`
+	Limit      int    `query:"limit"`
+}
+
+type AuditLogDto struct {
+	ID         string             `json:"id"`
+	Seq        int64              `json:"seq"`
+	EntityType string             `json:"entity_type"`
+	EntityID   string             `json:"entity_id"`
+	Action     models.AuditAction `json:"action"`
+	ActorID    string             `json:"actor_id,omitempty"`
+	ActorRole  string             `json:"actor_role,omitempty"`
+	RequestID  string             `json:"request_id,omitempty"`
+	ClientIP   string             `json:"client_ip,omitempty"`
+	Before     json.RawMessage    `json:"before" swaggertype:"object"`
+	After      json.RawMessage    `json:"after" swaggertype:"object"`
+	PrevHash   string             `json:"prev_hash"`
+	Hash       string             `json:"hash"`
+	CreatedAt  string             `json:"created_at"`
+}
+
+type GetAuditLogsResponseDto struct {
+	Entries []AuditLogDto `json:"entries"`
+}
+
+func ToAuditLogDto(entry *models.AuditLog) AuditLogDto {
+	result := AuditLogDto{
+		ID:         entry.ID.String(),
+		Seq:        entry.Seq,
+		EntityType: entry.EntityType,
+		EntityID:   entry.EntityID,
+		Action:     entry.Action,
+		Before:     entry.Before,
+		After:      entry.After,
+		PrevHash:   entry.PrevHash,
+		Hash:       entry.Hash,
+		CreatedAt:  entry.CreatedAt.Format(time.RFC3339Nano),
+	}
+	if entry.ActorID != nil {
+		result.ActorID = *entry.ActorID
+	}
+	if entry.ActorRole != nil {
+		result.ActorRole = *entry.ActorRole
+	}
+	if entry.RequestID != nil {
+		result.RequestID = *entry.RequestID
+	}
+	if entry.ClientIP != nil {
+		result.ClientIP = *entry.ClientIP
+	}
+	return result
+}
+
+func ToAuditLogDtoList(entries []models.AuditLog) []AuditLogDto {
+	result := make([]AuditLogDto, len(entries))
+	for i := range entries {
+		result[i] = ToAuditLogDto(&entries[i])
+	}
+	return result
+}
@@ -0,0 +1,40 @@
+package dto
+
+import "payment-service/pkg/models"
+
+// RefundStatus tells how much of a payment has been refunded.
+type RefundStatus string
+
+const (
+	RefundStatusNone     RefundStatus = "none"
+	RefundStatusPartial  RefundStatus = "partially_refunded"
+	RefundStatusRefunded RefundStatus = "refunded"
+)
+
+type GetMyPaymentsRequestDto struct {
+	Year   int    `query:"year"`
+	Cursor string `query:"cursor"`
+	Limit  int    `query:"limit"`
+}
+
+type MyPaymentDto struct {
+	PaymentID     string             `json:"payment_id"`
+	PayableType   models.PayableType `json:"payable_type"`
+	PayableID     string             `json:"payable_id"`
+	Amount        float64            `json:"amount"`
+	PatientAmount float64            `json:"patient_amount"`
+	PaidAt        string             `json:"paid_at"`
+	// PaymentMethod is empty when the payment information has been deleted
+	PaymentMethod       models.PaymentMethod `json:"payment_method,omitempty"`
+	MaskedPaymentMethod string               `json:"masked_payment_method,omitempty"`
+	RefundStatus        RefundStatus         `json:"refund_status"`
+	ReceiptURL          string               `json:"receipt_url"`
+	DoctorID            string               `json:"doctor_id,omitempty"`
+	DoctorName          string               `json:"doctor_name,omitempty"`
+}
+
+type GetMyPaymentsResponseDto struct {
+	Payments []MyPaymentDto `json:"payments"`
+	// NextCursor is passed as cursor to fetch the next page; null on the last
+	NextCursor *string `json:"next_cursor"`
+}
@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"time"
+
+	"payment-service/pkg/models"
+)
+
+// OrderPaymentStatus is the payment state of an order, derived from its
+// payments and refunds against the amount due.
+type OrderPaymentStatus string
+
+const (
+	OrderPaymentStatusUnpaid        OrderPaymentStatus = "unpaid"
+	OrderPaymentStatusPartiallyPaid OrderPaymentStatus = "partially_paid"
+	OrderPaymentStatusPaid          OrderPaymentStatus = "paid"
+	OrderPaymentStatusOverpaid      OrderPaymentStatus = "overpaid"
+	OrderPaymentStatusRefunded      OrderPaymentStatus = "refunded"
+)
+
+type PaymentAttemptDto struct {
+	PaymentAttemptID string               `json:"payment_attempt_id"`
+	PaymentInfoID    string               `json:"payment_info_id,omitempty"`
+	Method           models.PaymentMethod `json:"method"`
+	Status           models.PaymentStatus `json:"status"`
+	CreatedAt        string               `json:"created_at"`
+}
+
+type GetOrderPaymentsResponseDto struct {
+	OrderID            string              `json:"order_id"`
+	Status             OrderPaymentStatus  `json:"status"`
+	AmountDue          float64             `json:"amount_due"`
+	TotalPaid          float64             `json:"total_paid"`
+	TotalRefunded      float64             `json:"total_refunded"`
+	OutstandingBalance float64             `json:"outstanding_balance"`
+	Attempts           []PaymentAttemptDto `json:"attempts"`
+	Payments           []PaymentDto        `json:"payments"`
+}
+
+func ToPaymentAttemptDto(attempt *models.PaymentAttempt) PaymentAttemptDto {
+	result := PaymentAttemptDto{
+		PaymentAttemptID: attempt.ID.String(),
+		Method:           attempt.Method,
+		Status:           attempt.Status,
+		CreatedAt:        attempt.CreatedAt.Format(time.RFC3339),
+	}
+	if attempt.PaymentInformationID != nil {
+		result.PaymentInfoID = attempt.PaymentInformationID.String()
+	}
+	return result
+}
+
+func ToPaymentAttemptDtoList(attempts []models.PaymentAttempt) []PaymentAttemptDto {
+	result := make([]PaymentAttemptDto, len(attempts))
+	for i := range attempts {
+		result[i] = ToPaymentAttemptDto(&attempts[i])
+	}
+	return result
+}
@@ -0,0 +1,30 @@
+package dto
+
+import "payment-service/pkg/models"
+
+// PayableStatusResponseDto is what this service knows of a payable's
+// payments. It does not know the amount due, so whether the payable is
+// settled is for its owning service to decide from TotalPaid.
+type PayableStatusResponseDto struct {
+	PayableType         models.PayableType   `json:"payable_type"`
+	PayableID           string               `json:"payable_id"`
+	HasPayments         bool                 `json:"has_payments"`
+	TotalPaid           float64              `json:"total_paid"`
+	PaymentCount        int                  `json:"payment_count"`
+	AttemptCount        int                  `json:"attempt_count"`
+	LatestAttemptStatus models.PaymentStatus `json:"latest_attempt_status,omitempty"`
+}
+
+type PayableRefDto struct {
+	PayableType models.PayableType `json:"payable_type" validate:"required"`
+	PayableID   string             `json:"payable_id" validate:"required,uuid"`
+}
+
+type BatchPayableStatusRequestDto struct {
+	Payables []PayableRefDto `json:"payables" validate:"required,min=1,dive"`
+}
+
+// Statuses follow the order of the requested payables.
+type BatchPayableStatusResponseDto struct {
+	Statuses []PayableStatusResponseDto `json:"statuses"`
+}
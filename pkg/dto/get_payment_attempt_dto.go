@@ -4,8 +4,11 @@ import "payment-service/pkg/models"
 
 type GetPaymentAttemptResponseDto struct {
 	PaymentAttemptID string               `json:"payment_attempt_id"`
-	OrderID          string               `json:"order_id"`
+	PayableType      models.PayableType   `json:"payable_type"`
+	PayableID        string               `json:"payable_id"`
 	PaymentInfoID    string               `json:"payment_info_id,omitempty"`
 	Method           models.PaymentMethod `json:"method"`
 	Status           models.PaymentStatus `json:"status"`
+	Version          int                  `json:"version"`
+	CreatedAt        string               `json:"created_at"`
 }
@@ -7,29 +7,57 @@ import (
 )
 
 type PaymentDto struct {
-	PaymentID string  `json:"payment_id"`
-	AttemptID string  `json:"attempt_id"`
-	OrderID   string  `json:"order_id"`
-	Amount    float64 `json:"amount"`
-	PaidAt    string  `json:"paid_at"`
+	PaymentID             string             `json:"payment_id"`
+	AttemptID             string             `json:"attempt_id"`
+	PayableType           models.PayableType `json:"payable_type"`
+	PayableID             string             `json:"payable_id"`
+	Amount                float64            `json:"amount"`
+	PatientAmount         float64            `json:"patient_amount"`
+	PayerAmount           float64            `json:"payer_amount"`
+	HealthcareEntitlement string             `json:"healthcare_entitlement,omitempty"`
+	PaidAt                string             `json:"paid_at"`
+	Version               int                `json:"version"`
+}
+
+type GetAllPaymentsRequestDto struct {
+	Status   string `query:"status"`
+	Method   string `query:"method"`
+	OrderID  string `query:"order_id"`
+	UserID   string `query:"user_id"`
+	PaidFrom string `query:"paid_from"`
+	PaidTo   string `query:"paid_to"`
+	Sort     string `query:"sort"`
+	Cursor   string `query:"cursor"`
+	Limit    int    `query:"limit"`
 }
 
 type GetAllPaymentsResponseDto struct {
 	Payments []PaymentDto `json:"payments"`
+	// NextCursor is passed as cursor to fetch the next page; null on the last
+	NextCursor *string `json:"next_cursor"`
 }
 
 type GetPaymentByIDResponseDto struct {
-	Payment PaymentDto `json:"payment"`
+	Payment   PaymentDto    `json:"payment"`
+	LineItems []LineItemDto `json:"line_items"`
 }
 
 func ToPaymentDto(payment *models.Payment) PaymentDto {
-	return PaymentDto{
-		PaymentID: payment.ID.String(),
-		AttemptID: payment.AttemptID.String(),
-		OrderID:   payment.OrderID.String(),
-		Amount:    payment.Amount,
-		PaidAt:    payment.PaidAt.Format(time.RFC3339),
+	result := PaymentDto{
+		PaymentID:     payment.ID.String(),
+		AttemptID:     payment.AttemptID.String(),
+		PayableType:   payment.PayableType,
+		PayableID:     payment.PayableID.String(),
+		Amount:        payment.Amount,
+		PatientAmount: payment.PatientAmount,
+		PayerAmount:   payment.PayerAmount,
+		PaidAt:        payment.PaidAt.Format(time.RFC3339),
+		Version:       payment.LockVersion,
 	}
+	if payment.HealthcareEntitlement != nil {
+		result.HealthcareEntitlement = *payment.HealthcareEntitlement
+	}
+	return result
 }
 
 func ToPaymentDtoList(payments []models.Payment) []PaymentDto {
@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"time"
+
+	"payment-service/pkg/models"
+)
+
+type ReceivableDto struct {
+	ID                    string                  `json:"id"`
+	PaymentID             string                  `json:"payment_id"`
+	PayerType             models.PayerType        `json:"payer_type"`
+	PayerName             string                  `json:"payer_name"`
+	HealthcareEntitlement string                  `json:"healthcare_entitlement"`
+	Amount                float64                 `json:"amount"`
+	Status                models.ReceivableStatus `json:"status"`
+	CreatedAt             string                  `json:"created_at"`
+}
+
+type GetReceivablesResponseDto struct {
+	Receivables []ReceivableDto `json:"receivables"`
+}
+
+func ToReceivableDto(receivable *models.PayerReceivable) ReceivableDto {
+	return ReceivableDto{
+		ID:                    receivable.ID.String(),
+		PaymentID:             receivable.PaymentID.String(),
+		PayerType:             receivable.PayerType,
+		PayerName:             receivable.PayerName,
+		HealthcareEntitlement: receivable.HealthcareEntitlement,
+		Amount:                receivable.Amount,
+		Status:                receivable.Status,
+		CreatedAt:             receivable.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func ToReceivableDtoList(receivables []models.PayerReceivable) []ReceivableDto {
+	result := make([]ReceivableDto, len(receivables))
+	for i := range receivables {
+		result[i] = ToReceivableDto(&receivables[i])
+	}
+	return result
+}
@@ -0,0 +1,42 @@
+package dto
+
+import "payment-service/pkg/models"
+
+// Discounts are sent with a negative unit price.
+type LineItemRequestDto struct {
+	Description string                  `json:"description" validate:"required"`
+	Quantity    float64                 `json:"quantity" validate:"gt=0"`
+	UnitPrice   float64                 `json:"unit_price"`
+	Tax         float64                 `json:"tax" validate:"gte=0"`
+	Category    models.LineItemCategory `json:"category" validate:"required,oneof=medicine delivery_fee consultation_fee discount other"`
+}
+
+type LineItemDto struct {
+	ID          string                  `json:"id"`
+	Description string                  `json:"description"`
+	Quantity    float64                 `json:"quantity"`
+	UnitPrice   float64                 `json:"unit_price"`
+	Tax         float64                 `json:"tax"`
+	Category    models.LineItemCategory `json:"category"`
+	Total       float64                 `json:"total"`
+}
+
+func ToLineItemDto(item *models.PaymentLineItem) LineItemDto {
+	return LineItemDto{
+		ID:          item.ID.String(),
+		Description: item.Description,
+		Quantity:    item.Quantity,
+		UnitPrice:   item.UnitPrice,
+		Tax:         item.Tax,
+		Category:    item.Category,
+		Total:       item.Total(),
+	}
+}
+
+func ToLineItemDtoList(items []models.PaymentLineItem) []LineItemDto {
+	result := make([]LineItemDto, len(items))
+	for i := range items {
+		result[i] = ToLineItemDto(&items[i])
+	}
+	return result
+}
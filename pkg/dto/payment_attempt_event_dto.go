@@ -0,0 +1,11 @@
+package dto
+
+import "payment-service/pkg/models"
+
+// PaymentAttemptEventDto is the data of a status event on the attempt
+// event stream.
+type PaymentAttemptEventDto struct {
+	PaymentAttemptID string               `json:"payment_attempt_id"`
+	Status           models.PaymentStatus `json:"status"`
+	At               string               `json:"at"`
+}
@@ -0,0 +1,115 @@
+package dto
+
+import (
+	"time"
+
+	"payment-service/pkg/models"
+)
+
+type IssueTaxInvoiceRequestDto struct {
+	BuyerName    string `json:"buyer_name" validate:"required"`
+	BuyerTaxID   string `json:"buyer_tax_id" validate:"required,numeric,len=13"`
+	BuyerAddress string `json:"buyer_address" validate:"required"`
+	BuyerBranch  string `json:"buyer_branch" validate:"omitempty,numeric,len=5"`
+}
+
+// Buyer fields left empty keep the values of the document being replaced.
+type ReissueDocumentRequestDto struct {
+	Reason       string `json:"reason" validate:"required"`
+	BuyerName    string `json:"buyer_name"`
+	BuyerTaxID   string `json:"buyer_tax_id" validate:"omitempty,numeric,len=13"`
+	BuyerAddress string `json:"buyer_address"`
+	BuyerBranch  string `json:"buyer_branch" validate:"omitempty,numeric,len=5"`
+}
+
+type VoidDocumentRequestDto struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
+type PaymentDocumentEventDto struct {
+	Action    models.DocumentAction `json:"action"`
+	ActorID   string                `json:"actor_id"`
+	ActorRole string                `json:"actor_role"`
+	Reason    string                `json:"reason,omitempty"`
+	CreatedAt string                `json:"created_at"`
+}
+
+type PaymentDocumentDto struct {
+	ID                 string                    `json:"id"`
+	PaymentID          string                    `json:"payment_id"`
+	Type               models.DocumentType       `json:"type"`
+	Number             string                    `json:"number"`
+	Status             models.DocumentStatus     `json:"status"`
+	BuyerName          string                    `json:"buyer_name"`
+	BuyerTaxID         string                    `json:"buyer_tax_id,omitempty"`
+	Subtotal           float64                   `json:"subtotal"`
+	VatAmount          float64                   `json:"vat_amount"`
+	Total              float64                   `json:"total"`
+	ReplacesDocumentID string                    `json:"replaces_document_id,omitempty"`
+	IssuedAt           string                    `json:"issued_at"`
+	VoidedAt           string                    `json:"voided_at,omitempty"`
+	VoidReason         string                    `json:"void_reason,omitempty"`
+	Events             []PaymentDocumentEventDto `json:"events,omitempty"`
+}
+
+type PaymentDocumentResponseDto struct {
+	Document PaymentDocumentDto `json:"document"`
+}
+
+type GetPaymentDocumentsResponseDto struct {
+	Documents []PaymentDocumentDto `json:"documents"`
+}
+
+// PaymentDocumentFileDto is a rendered document ready to be streamed.
+type PaymentDocumentFileDto struct {
+	FileName string
+	Content  []byte
+}
+
+func ToPaymentDocumentDto(doc *models.PaymentDocument) PaymentDocumentDto {
+	result := PaymentDocumentDto{
+		ID:        doc.ID.String(),
+		PaymentID: doc.PaymentID.String(),
+		Type:      doc.Type,
+		Number:    doc.Number,
+		Status:    doc.Status,
+		BuyerName: doc.BuyerName,
+		Subtotal:  doc.Subtotal,
+		VatAmount: doc.VatAmount,
+		Total:     doc.Total,
+		IssuedAt:  doc.IssuedAt.Format(time.RFC3339),
+	}
+	if doc.BuyerTaxID != nil {
+		result.BuyerTaxID = *doc.BuyerTaxID
+	}
+	if doc.ReplacesDocumentID != nil {
+		result.ReplacesDocumentID = doc.ReplacesDocumentID.String()
+	}
+	if doc.VoidedAt != nil {
+		result.VoidedAt = doc.VoidedAt.Format(time.RFC3339)
+	}
+	if doc.VoidReason != nil {
+		result.VoidReason = *doc.VoidReason
+	}
+	for _, event := range doc.Events {
+		e := PaymentDocumentEventDto{
+			Action:    event.Action,
+			ActorID:   event.ActorID.String(),
+			ActorRole: event.ActorRole,
+			CreatedAt: event.CreatedAt.Format(time.RFC3339),
+		}
+		if event.Reason != nil {
+			e.Reason = *event.Reason
+		}
+		result.Events = append(result.Events, e)
+	}
+	return result
+}
+
+func ToPaymentDocumentDtoList(docs []models.PaymentDocument) []PaymentDocumentDto {
+	result := make([]PaymentDocumentDto, len(docs))
+	for i := range docs {
+		result[i] = ToPaymentDocumentDto(&docs[i])
+	}
+	return result
+}
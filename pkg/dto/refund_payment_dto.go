@@ -0,0 +1,20 @@
+package dto
+
+// RefundStatusCompleted is the status of every recorded refund: the
+// service records refunds once the money has been given back.
+const RefundStatusCompleted = "completed"
+
+type RefundPaymentRequestDto struct {
+	PaymentID string  `json:"payment_id" validate:"required"`
+	Amount    float64 `json:"amount" validate:"required,gt=0"`
+	Reason    string  `json:"reason,omitempty"`
+}
+
+type RefundPaymentResponseDto struct {
+	RefundID  string  `json:"refund_id"`
+	PaymentID string  `json:"payment_id"`
+	Amount    float64 `json:"amount"`
+	Status    string  `json:"status"`
+	// Refundable is what is left to refund on the payment
+	Refundable float64 `json:"refundable"`
+}
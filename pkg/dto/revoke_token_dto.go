@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"time"
+
+	"payment-service/pkg/models"
+)
+
+// Exactly one of Jti and UserID is given: revoking by user denies every
+// token that user holds now.
+type RevokeTokenRequestDto struct {
+	Jti    string `json:"jti" validate:"required_without=UserID,excluded_with=UserID"`
+	UserID string `json:"user_id" validate:"required_without=Jti,omitempty,uuid"`
+	Reason string `json:"reason"`
+}
+
+type TokenRevocationDto struct {
+	ID        string `json:"id"`
+	Jti       string `json:"jti,omitempty"`
+	UserID    string `json:"user_id,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+	RevokedBy string `json:"revoked_by"`
+	ExpiresAt string `json:"expires_at"`
+	CreatedAt string `json:"created_at"`
+}
+
+type RevokeTokenResponseDto struct {
+	Revocation TokenRevocationDto `json:"revocation"`
+}
+
+func ToTokenRevocationDto(revocation *models.TokenRevocation) TokenRevocationDto {
+	result := TokenRevocationDto{
+		ID:        revocation.ID.String(),
+		RevokedBy: revocation.RevokedBy.String(),
+		ExpiresAt: revocation.ExpiresAt.Format(time.RFC3339),
+		CreatedAt: revocation.CreatedAt.Format(time.RFC3339),
+	}
+	if revocation.Jti != nil {
+		result.Jti = *revocation.Jti
+	}
+	if revocation.UserID != nil {
+		result.UserID = revocation.UserID.String()
+	}
+	if revocation.Reason != nil {
+		result.Reason = *revocation.Reason
+	}
+	return result
+}
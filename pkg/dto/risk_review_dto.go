@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"time"
+
+	"payment-service/pkg/models"
+)
+
+type RiskAssessmentDto struct {
+	ID             string              `json:"id"`
+	AttemptID      string              `json:"attempt_id"`
+	UserID         string              `json:"user_id"`
+	ClientIP       string              `json:"client_ip,omitempty"`
+	CardCountry    string              `json:"card_country,omitempty"`
+	RequestCountry string              `json:"request_country,omitempty"`
+	Amount         float64             `json:"amount"`
+	Decision       models.RiskDecision `json:"decision"`
+	Reasons        []string            `json:"reasons"`
+	ReviewStatus   models.ReviewStatus `json:"review_status,omitempty"`
+	ReviewedBy     string              `json:"reviewed_by,omitempty"`
+	ReviewedAt     string              `json:"reviewed_at,omitempty"`
+	CreatedAt      string              `json:"created_at"`
+}
+
+type GetRiskReviewsResponseDto struct {
+	Reviews []RiskAssessmentDto `json:"reviews"`
+}
+
+type RiskReviewResponseDto struct {
+	Review        RiskAssessmentDto    `json:"review"`
+	AttemptStatus models.PaymentStatus `json:"attempt_status"`
+}
+
+// ToRiskAssessmentDto leaves out the card fingerprint, which only matters
+// for matching attempts.
+func ToRiskAssessmentDto(assessment *models.RiskAssessment) RiskAssessmentDto {
+	result := RiskAssessmentDto{
+		ID:        assessment.ID.String(),
+		AttemptID: assessment.AttemptID.String(),
+		UserID:    assessment.UserID.String(),
+		Amount:    assessment.Amount,
+		Decision:  assessment.Decision,
+		Reasons:   assessment.Reasons,
+		CreatedAt: assessment.CreatedAt.Format(time.RFC3339),
+	}
+	if assessment.ClientIP != nil {
+		result.ClientIP = *assessment.ClientIP
+	}
+	if assessment.CardCountry != nil {
+		result.CardCountry = *assessment.CardCountry
+	}
+	if assessment.RequestCountry != nil {
+		result.RequestCountry = *assessment.RequestCountry
+	}
+	if assessment.ReviewStatus != nil {
+		result.ReviewStatus = *assessment.ReviewStatus
+	}
+	if assessment.ReviewedBy != nil {
+		result.ReviewedBy = assessment.ReviewedBy.String()
+	}
+	if assessment.ReviewedAt != nil {
+		result.ReviewedAt = assessment.ReviewedAt.Format(time.RFC3339)
+	}
+	return result
+}
+
+func ToRiskAssessmentDtoList(assessments []models.RiskAssessment) []RiskAssessmentDto {
+	result := make([]RiskAssessmentDto, len(assessments))
+	for i := range assessments {
+		result[i] = ToRiskAssessmentDto(&assessments[i])
+	}
+	return result
+}
@@ -5,12 +5,20 @@ import "payment-service/pkg/models"
 type UpdatePaymentAttemptRequestDto struct {
 	PaymentAttemptID string               `json:"payment_attempt_id" validate:"required"`
 	Status           models.PaymentStatus `json:"status" validate:"required"`
+	// Version is the version the change is based on. If-Match takes its
+	// place when sent; one of the two is required.
+	Version int `json:"version,omitempty"`
+	// AnyVersion is set by "If-Match: *" and applies the change to the
+	// current version.
+	AnyVersion bool `json:"-"`
 }
 
 type UpdatePaymentAttemptResponseDto struct {
 	PaymentAttemptID string               `json:"payment_attempt_id"`
-	OrderID          string               `json:"order_id"`
+	PayableType      models.PayableType   `json:"payable_type"`
+	PayableID        string               `json:"payable_id"`
 	PaymentInfoID    string               `json:"payment_info_id,omitempty"`
 	Method           models.PaymentMethod `json:"method"`
 	Status           models.PaymentStatus `json:"status"`
+	Version          int                  `json:"version"`
 }
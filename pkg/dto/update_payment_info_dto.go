@@ -10,6 +10,12 @@ type UpdatePaymentInfoRequestDto struct {
 	ID            string               `json:"id" validate:"required"`
 	PaymentMethod models.PaymentMethod `json:"payment_method" validate:"required,oneof=credit_card promptpay"`
 	Details       []byte               `json:"details" validate:"required"`
+	// Version is the version the change is based on. If-Match takes its
+	// place when sent; one of the two is required.
+	Version int `json:"version,omitempty"`
+	// AnyVersion is set by "If-Match: *" and applies the change to the
+	// current version.
+	AnyVersion bool `json:"-"`
 }
 
 type UpdatePaymentInfoResponseDto struct {
@@ -0,0 +1,179 @@
+// Package events passes payment attempt status changes from the requests
+// that make them to the clients watching the attempt. It is in-process:
+// clients only see changes made by the instance they are connected to.
+package events
+
+import (
+	"sync"
+	"time"
+
+	"payment-service/pkg/models"
+
+	"github.com/google/uuid"
+)
+
+// AttemptEvent is one status change of an attempt. IDs increase across all
+// attempts, so a client can resume after the last one it saw.
+type AttemptEvent struct {
+	ID        uint64
+	AttemptID uuid.UUID
+	Status    models.PaymentStatus
+	At        time.Time
+}
+
+// Terminal reports whether the attempt can change no further.
+func (e AttemptEvent) Terminal() bool {
+	return IsTerminal(e.Status)
+}
+
+// IsTerminal reports whether an attempt in status can change no further.
+func IsTerminal(status models.PaymentStatus) bool {
+	return status == models.PaymentStatusSuccess || status == models.PaymentStatusFailed
+}
+
+// subscriptionBuffer is how many events a watcher may fall behind before
+// it is dropped; it then resumes from its last event.
+const subscriptionBuffer = 8
+
+// Broker fans attempt events out to subscribers and keeps the recent
+// events of each attempt for watchers that reconnect.
+type Broker struct {
+	// historySize is how many events are kept per attempt
+	historySize int
+	// retention is how long the events of an attempt are kept after its
+	// last change
+	retention time.Duration
+
+	mu       sync.Mutex
+	lastID   uint64
+	history  map[uuid.UUID][]AttemptEvent
+	subs     map[uuid.UUID]map[*Subscription]struct{}
+	prunedAt time.Time
+}
+
+func NewBroker(historySize int, retention time.Duration) *Broker {
+	return &Broker{
+		historySize: historySize,
+		retention:   retention,
+		// IDs start from the clock so they keep increasing across restarts
+		// and IDs from before one are never taken for new ones
+		lastID:   uint64(time.Now().UnixNano()),
+		history:  make(map[uuid.UUID][]AttemptEvent),
+		subs:     make(map[uuid.UUID]map[*Subscription]struct{}),
+		prunedAt: time.Now(),
+	}
+}
+
+// Subscription receives the events of one attempt on C until it is closed,
+// either by Close or by the broker when the subscriber falls behind.
+type Subscription struct {
+	C         <-chan AttemptEvent
+	c         chan AttemptEvent
+	attemptID uuid.UUID
+	broker    *Broker
+}
+
+// Publish records a status change of an attempt and sends it to everyone
+// watching the attempt.
+func (b *Broker) Publish(attemptID uuid.UUID, status models.PaymentStatus) AttemptEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	event := AttemptEvent{ID: b.lastID, AttemptID: attemptID, Status: status, At: time.Now().UTC()}
+
+	history := append(b.history[attemptID], event)
+	if len(history) > b.historySize {
+		history = history[len(history)-b.historySize:]
+	}
+	b.history[attemptID] = history
+
+	for sub := range b.subs[attemptID] {
+		select {
+		case sub.c <- event:
+		default:
+			b.remove(sub)
+		}
+	}
+
+	if event.At.Sub(b.prunedAt) >= b.retention {
+		b.prune(event.At)
+	}
+	return event
+}
+
+// Resume tells a watcher where it stands when it starts.
+type Resume struct {
+	// Seen is the last event the client saw, when the broker still has it
+	Seen *AttemptEvent
+	// Missed are the events after Seen
+	Missed []AttemptEvent
+	// Latest is the attempt's newest event, when the broker has one
+	Latest *AttemptEvent
+}
+
+// Subscribe starts watching an attempt. after is the ID of the last event
+// the client saw, 0 for a new watcher. Without Seen in the result the
+// client cannot be caught up from the events kept here and has to be told
+// the current status instead.
+func (b *Broker) Subscribe(attemptID uuid.UUID, after uint64) (Resume, *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := make(chan AttemptEvent, subscriptionBuffer)
+	sub := &Subscription{C: c, c: c, attemptID: attemptID, broker: b}
+	if b.subs[attemptID] == nil {
+		b.subs[attemptID] = make(map[*Subscription]struct{})
+	}
+	b.subs[attemptID][sub] = struct{}{}
+
+	var resume Resume
+	history := b.history[attemptID]
+	if len(history) == 0 {
+		return resume, sub
+	}
+	latest := history[len(history)-1]
+	resume.Latest = &latest
+	for i, event := range history {
+		if event.ID == after {
+			resume.Seen = &event
+			resume.Missed = append([]AttemptEvent(nil), history[i+1:]...)
+			break
+		}
+	}
+	return resume, sub
+}
+
+// Close stops the subscription. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	s.broker.remove(s)
+}
+
+// remove must be called with b.mu held.
+func (b *Broker) remove(sub *Subscription) {
+	subs, ok := b.subs[sub.attemptID]
+	if !ok {
+		return
+	}
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+	delete(subs, sub)
+	close(sub.c)
+	if len(subs) == 0 {
+		delete(b.subs, sub.attemptID)
+	}
+}
+
+// prune forgets the events of attempts that have not changed for the
+// retention period. It must be called with b.mu held.
+func (b *Broker) prune(now time.Time) {
+	for attemptID, history := range b.history {
+		if now.Sub(history[len(history)-1].At) >= b.retention {
+			delete(b.history, attemptID)
+		}
+	}
+	b.prunedAt = now
+}
@@ -0,0 +1,98 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"payment-service/pkg/models"
+
+	"github.com/google/uuid"
+)
+
+func TestSubscriberReceivesOnlyItsAttempt(t *testing.T) {
+	broker := NewBroker(16, time.Hour)
+	watched, other := uuid.New(), uuid.New()
+
+	_, sub := broker.Subscribe(watched, 0)
+	defer sub.Close()
+
+	broker.Publish(other, models.PaymentStatusSuccess)
+	published := broker.Publish(watched, models.PaymentStatusSuccess)
+
+	select {
+	case event := <-sub.C:
+		if event != published {
+			t.Errorf("got %+v, want %+v", event, published)
+		}
+	default:
+		t.Fatal("expected the watched attempt's event")
+	}
+	select {
+	case event := <-sub.C:
+		t.Errorf("unexpected event %+v", event)
+	default:
+	}
+}
+
+func TestResumeAfterLastEvent(t *testing.T) {
+	broker := NewBroker(2, time.Hour)
+	attemptID := uuid.New()
+
+	first := broker.Publish(attemptID, models.PaymentStatusPending)
+	second := broker.Publish(attemptID, models.PaymentStatusPending)
+	third := broker.Publish(attemptID, models.PaymentStatusSuccess)
+
+	resume, sub := broker.Subscribe(attemptID, second.ID)
+	sub.Close()
+	if resume.Seen == nil || resume.Seen.ID != second.ID {
+		t.Fatalf("expected to resume after %d, got %+v", second.ID, resume.Seen)
+	}
+	if len(resume.Missed) != 1 || resume.Missed[0] != third {
+		t.Errorf("got missed %+v, want only %+v", resume.Missed, third)
+	}
+
+	// the first event no longer fits in the history
+	resume, sub = broker.Subscribe(attemptID, first.ID)
+	sub.Close()
+	if resume.Seen != nil || resume.Missed != nil {
+		t.Errorf("resumed from a dropped event: %+v", resume)
+	}
+	if resume.Latest == nil || *resume.Latest != third {
+		t.Errorf("got latest %+v, want %+v", resume.Latest, third)
+	}
+}
+
+func TestSlowSubscriberIsDropped(t *testing.T) {
+	broker := NewBroker(16, time.Hour)
+	attemptID := uuid.New()
+
+	_, sub := broker.Subscribe(attemptID, 0)
+	for range subscriptionBuffer + 1 {
+		broker.Publish(attemptID, models.PaymentStatusPending)
+	}
+
+	received := 0
+	for range sub.C {
+		received++
+	}
+	if received != subscriptionBuffer {
+		t.Errorf("got %d events before the subscription closed, want %d", received, subscriptionBuffer)
+	}
+	// closing again must not panic
+	sub.Close()
+}
+
+func TestPruneForgetsIdleAttempts(t *testing.T) {
+	broker := NewBroker(16, time.Millisecond)
+	idle, busy := uuid.New(), uuid.New()
+
+	seen := broker.Publish(idle, models.PaymentStatusSuccess)
+	time.Sleep(2 * time.Millisecond)
+	broker.Publish(busy, models.PaymentStatusPending)
+
+	resume, sub := broker.Subscribe(idle, seen.ID)
+	sub.Close()
+	if resume.Seen != nil || resume.Latest != nil {
+		t.Errorf("idle attempt still has events: %+v", resume)
+	}
+}
@@ -0,0 +1,39 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// utf8BOM makes Excel open the file as UTF-8, so Thai text survives.
+const utf8BOM = "\xef\xbb\xbf"
+
+type csvWriter struct {
+	w       io.Writer
+	csv     *csv.Writer
+	started bool
+	record  []string
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: w, csv: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) WriteRow(cells []Cell) error {
+	if !c.started {
+		c.started = true
+		if _, err := io.WriteString(c.w, utf8BOM); err != nil {
+			return err
+		}
+	}
+	c.record = c.record[:0]
+	for _, cell := range cells {
+		c.record = append(c.record, cell.Value)
+	}
+	return c.csv.Write(c.record)
+}
+
+func (c *csvWriter) Close() error {
+	c.csv.Flush()
+	return c.csv.Error()
+}
@@ -0,0 +1,66 @@
+// Package export writes tabular data as CSV or XLSX, one row at a time, so
+// that exports of any size stream without being held in memory.
+package export
+
+import (
+	"fmt"
+	"io"
+)
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case "", FormatCSV:
+		return FormatCSV, nil
+	case FormatXLSX:
+		return FormatXLSX, nil
+	default:
+		return "", fmt.Errorf("unsupported export format %q", s)
+	}
+}
+
+func (f Format) ContentType() string {
+	if f == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Cell is one value of a row. Numeric cells hold a plain decimal number and
+// stay numbers in a spreadsheet; everything else is text.
+type Cell struct {
+	Value   string
+	Numeric bool
+}
+
+func Text(s string) Cell {
+	return Cell{Value: s}
+}
+
+func Number(s string) Cell {
+	return Cell{Value: s, Numeric: true}
+}
+
+// Writer writes rows in order. Close finishes the file but leaves the
+// underlying writer open.
+type Writer interface {
+	WriteRow(cells []Cell) error
+	Close() error
+}
+
+func NewWriter(format Format, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w), nil
+	case FormatXLSX:
+		return newXLSXWriter(w)
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
@@ -0,0 +1,175 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"payment-service/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+type JobStatus string
+
+const (
+	JobStatusPending JobStatus = "pending"
+	JobStatusRunning JobStatus = "running"
+	JobStatusDone    JobStatus = "done"
+	JobStatusFailed  JobStatus = "failed"
+)
+
+// RunFunc writes an export to w and returns how many rows it wrote.
+type RunFunc func(ctx context.Context, w io.Writer) (int, error)
+
+// Job is a snapshot of a background export.
+type Job struct {
+	ID          uuid.UUID
+	Format      Format
+	Filename    string
+	RequestedBy string
+	Status      JobStatus
+	Rows        int
+	CreatedAt   time.Time
+	CompletedAt *time.Time
+	path        string
+}
+
+// Jobs runs exports in the background and keeps the finished files on
+// local disk for ttl. Jobs live in memory, so a download has to reach the
+// instance that ran the export, and jobs do not survive a restart.
+type Jobs struct {
+	dir   string
+	ttl   time.Duration
+	slots chan struct{}
+
+	mu   sync.Mutex
+	jobs map[uuid.UUID]*Job
+}
+
+// NewJobs keeps files in dir and runs at most concurrency exports at once;
+// the rest wait their turn as pending.
+func NewJobs(dir string, ttl time.Duration, concurrency int) (*Jobs, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create export dir: %w", err)
+	}
+	return &Jobs{
+		dir:   dir,
+		ttl:   ttl,
+		slots: make(chan struct{}, concurrency),
+		jobs:  make(map[uuid.UUID]*Job),
+	}, nil
+}
+
+// Start queues run and returns at once. The job keeps the values of ctx but
+// not its cancellation, so it outlives the request that started it.
+func (j *Jobs) Start(ctx context.Context, requestedBy string, format Format, filename string, run RunFunc) Job {
+	j.sweep()
+
+	id := utils.GenerateUUIDv7()
+	job := &Job{
+		ID:          id,
+		Format:      format,
+		Filename:    filename,
+		RequestedBy: requestedBy,
+		Status:      JobStatusPending,
+		CreatedAt:   time.Now(),
+		path:        filepath.Join(j.dir, id.String()+"."+string(format)),
+	}
+	j.mu.Lock()
+	j.jobs[id] = job
+	snapshot := *job
+	j.mu.Unlock()
+
+	go j.run(context.WithoutCancel(ctx), job, run)
+	return snapshot
+}
+
+func (j *Jobs) Get(id uuid.UUID) (Job, bool) {
+	j.sweep()
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	job, ok := j.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return *job, true
+}
+
+// Open returns the file of a finished job.
+func (j *Jobs) Open(job Job) (*os.File, error) {
+	if job.Status != JobStatusDone {
+		return nil, fmt.Errorf("export %s is %s", job.ID, job.Status)
+	}
+	return os.Open(job.path)
+}
+
+func (j *Jobs) run(ctx context.Context, job *Job, run RunFunc) {
+	j.slots <- struct{}{}
+	defer func() { <-j.slots }()
+	j.update(job, func() { job.Status = JobStatusRunning })
+
+	rows, err := j.write(ctx, job.path, run)
+	now := time.Now()
+	if err != nil {
+		log.Printf("export %s failed: %v", job.ID, err)
+		os.Remove(job.path)
+		j.update(job, func() {
+			job.Status = JobStatusFailed
+			job.CompletedAt = &now
+		})
+		return
+	}
+	j.update(job, func() {
+		job.Status = JobStatusDone
+		job.Rows = rows
+		job.CompletedAt = &now
+	})
+}
+
+func (j *Jobs) write(ctx context.Context, path string, run RunFunc) (rows int, err error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	return run(ctx, f)
+}
+
+func (j *Jobs) update(job *Job, fn func()) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	fn()
+}
+
+// sweep forgets jobs that finished more than ttl ago and removes their files.
+func (j *Jobs) sweep() {
+	cutoff := time.Now().Add(-j.ttl)
+
+	j.mu.Lock()
+	var expired []string
+	for id, job := range j.jobs {
+		if job.CompletedAt != nil && job.CompletedAt.Before(cutoff) {
+			expired = append(expired, job.path)
+			delete(j.jobs, id)
+		}
+	}
+	j.mu.Unlock()
+
+	for _, path := range expired {
+		os.Remove(path)
+	}
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"payment-service/pkg/apperr"
+	contextUtils "payment-service/pkg/context"
+	"payment-service/pkg/dto"
+	"payment-service/pkg/events"
+	"payment-service/pkg/i18n"
+	"payment-service/pkg/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// eventHeartbeat is how often an idle event stream gets a comment, so
+// proxies do not close it and clients notice when it is gone.
+const eventHeartbeat = 15 * time.Second
+
+// StreamPaymentAttemptEvents godoc
+// @Summary Watch payment attempt status
+// @Description Server-Sent Events stream of a payment attempt's status. The first status event carries the current status, or the events missed since Last-Event-ID when the client reconnects. Later changes follow as they happen, with a comment every 15 seconds while idle. The stream ends after success or failed; a client that reconnects after seeing either gets 204, which stops EventSource from retrying.
+// @Tags payment-attempt
+// @Produce text/event-stream
+// @Param id path string true "Payment attempt ID"
+// @Param Last-Event-ID header string false "ID of the last event received, to resume after it"
+// @Success 200 {object} dto.PaymentAttemptEventDto "status events"
+// @Success 204 "The client has already seen the final status"
+// @Failure 400 {object} response.ErrorResponse "Invalid payment attempt ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 404 {object} response.ErrorResponse "Payment attempt not found"
+// @Failure 500 {object} response.ErrorResponse "Failed to retrieve payment attempt"
+// @Router /api/payment/v1/attempt/{id}/events [get]
+// @Security ApiKeyAuth
+func (h *PaymentHandler) StreamPaymentAttemptEvents(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if id == "" {
+		return apperr.WriteError(c, apperr.New(apperr.CodeBadRequest, i18n.MissingAttemptID, nil))
+	}
+
+	ctx := contextUtils.GetContext(c)
+	watch, err := h.paymentService.WatchPaymentAttempt(ctx, id, c.Get("Last-Event-ID"))
+	if err != nil {
+		return apperr.WriteError(c, err)
+	}
+
+	resume := watch.Resume
+	if resume.Seen != nil && len(resume.Missed) == 0 && resume.Seen.Terminal() {
+		watch.Subscription.Close()
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	// keep nginx from buffering the stream
+	c.Set("X-Accel-Buffering", "no")
+
+	// As with exports, the body is written after the handler returns; a
+	// client that goes away fails the next write or heartbeat.
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer watch.Subscription.Close()
+
+		if resume.Seen != nil {
+			for _, event := range resume.Missed {
+				if writeAttemptEvent(w, event) != nil || event.Terminal() {
+					return
+				}
+			}
+		} else {
+			current := events.AttemptEvent{
+				AttemptID: utils.StringToUUIDv7(watch.Attempt.PaymentAttemptID),
+				Status:    watch.Attempt.Status,
+				At:        time.Now().UTC(),
+			}
+			// the current status keeps the ID of the event that set it, so
+			// a client that reconnects resumes from it
+			if resume.Latest != nil && resume.Latest.Status == current.Status {
+				current = *resume.Latest
+			}
+			if writeAttemptEvent(w, current) != nil || current.Terminal() {
+				return
+			}
+		}
+
+		heartbeat := time.NewTicker(eventHeartbeat)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case event, ok := <-watch.Subscription.C:
+				// closed when this client fell behind; it resumes on reconnect
+				if !ok {
+					return
+				}
+				if writeAttemptEvent(w, event) != nil || event.Terminal() {
+					return
+				}
+			case <-heartbeat.C:
+				if _, err := w.WriteString(": heartbeat\n\n"); err != nil {
+					return
+				}
+				if err := w.Flush(); err != nil {
+					return
+				}
+			}
+		}
+	})
+	return nil
+}
+
+// writeAttemptEvent writes one status event and flushes it to the client.
+// Events with no ID are written without one.
+func writeAttemptEvent(w *bufio.Writer, event events.AttemptEvent) error {
+	data, err := json.Marshal(dto.PaymentAttemptEventDto{
+		PaymentAttemptID: event.AttemptID.String(),
+		Status:           event.Status,
+		At:               event.At.Format(time.RFC3339),
+	})
+	if err != nil {
+		return err
+	}
+	if event.ID != 0 {
+		if _, err := fmt.Fprintf(w, "id: %d\n", event.ID); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(w, "event: status\ndata: %s\n\n", data); err != nil {
+		return err
+	}
+	return w.Flush()
+}
@@ -4,6 +4,7 @@ import (
 	"payment-service/pkg/apperr"
 	contextUtils "payment-service/pkg/context"
 	"payment-service/pkg/dto"
+	"payment-service/pkg/i18n"
 	"payment-service/pkg/response"
 
 	"github.com/gofiber/fiber/v2"
@@ -11,7 +12,7 @@ import (
 
 // CreatePayment godoc
 // @Summary Create payment
-// @Description Create a payment record for a successful payment attempt
+// @Description Create a payment record for a successful payment attempt. The amount must equal what the payable is due.
 // @Tags payments
 // @Accept json
 // @Produce json
@@ -20,13 +21,14 @@ import (
 // @Failure 400 {object} response.ErrorResponse "Invalid request body or business rule violation"
 // @Failure 401 {object} response.ErrorResponse "Unauthorized"
 // @Failure 404 {object} response.ErrorResponse "Payment attempt not found"
+// @Failure 409 {object} response.ErrorResponse "Payment attempt already paid"
 // @Failure 500 {object} response.ErrorResponse "Failed to create payment"
 // @Router /api/payment/v1/ [post]
 // @Security ApiKeyAuth
 func (h *PaymentHandler) CreatePayment(c *fiber.Ctx) error {
 	var body dto.CreatePaymentRequestDto
 	if err := c.BodyParser(&body); err != nil {
-		return response.BadRequest(c, "Invalid request body "+err.Error())
+		return apperr.WriteError(c, apperr.BadInput(i18n.InvalidRequestBody, err))
 	}
 
 	ctx := contextUtils.GetContext(c)
@@ -40,18 +42,34 @@ func (h *PaymentHandler) CreatePayment(c *fiber.Ctx) error {
 
 // GetAllPayments godoc
 // @Summary List payments
-// @Description Retrieve all payment records
+// @Description Retrieve one page of payment records, newest first by default (admin only)
 // @Tags payments
 // @Accept json
 // @Produce json
+// @Param status query string false "Attempt status: pending, success or failed"
+// @Param method query string false "Payment method: credit_card or promptpay"
+// @Param order_id query string false "Order ID"
+// @Param user_id query string false "ID of the paying patient"
+// @Param paid_from query string false "Earliest paid_at, RFC 3339"
+// @Param paid_to query string false "Time before which payments were made, RFC 3339"
+// @Param sort query string false "asc or desc (default desc)"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param limit query int false "Maximum payments to return (default 50, at most 200)"
 // @Success 200 {object} dto.GetAllPaymentsResponseDto "Payments retrieved successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid filter or cursor"
 // @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden"
 // @Failure 500 {object} response.ErrorResponse "Failed to retrieve payments"
 // @Router /api/payment/v1/ [get]
 // @Security ApiKeyAuth
 func (h *PaymentHandler) GetAllPayments(c *fiber.Ctx) error {
+	var query dto.GetAllPaymentsRequestDto
+	if err := c.QueryParser(&query); err != nil {
+		return apperr.WriteError(c, apperr.BadInput(i18n.InvalidQuery, err))
+	}
+
 	ctx := contextUtils.GetContext(c)
-	res, err := h.paymentService.GetAllPayments(ctx)
+	res, err := h.paymentService.GetAllPayments(ctx, query)
 	if err != nil {
 		return apperr.WriteError(c, err)
 	}
@@ -67,6 +85,7 @@ func (h *PaymentHandler) GetAllPayments(c *fiber.Ctx) error {
 // @Produce json
 // @Param id path string true "Payment ID"
 // @Success 200 {object} dto.GetPaymentByIDResponseDto "Payment retrieved successfully"
+// @Header 200 {string} ETag "Version of the payment"
 // @Failure 400 {object} response.ErrorResponse "Invalid payment ID"
 // @Failure 401 {object} response.ErrorResponse "Unauthorized"
 // @Failure 404 {object} response.ErrorResponse "Payment not found"
@@ -76,7 +95,7 @@ func (h *PaymentHandler) GetAllPayments(c *fiber.Ctx) error {
 func (h *PaymentHandler) GetPaymentByID(c *fiber.Ctx) error {
 	id := c.Params("id")
 	if id == "" {
-		return response.BadRequest(c, "Missing payment ID")
+		return apperr.WriteError(c, apperr.New(apperr.CodeBadRequest, i18n.MissingPaymentID, nil))
 	}
 
 	ctx := contextUtils.GetContext(c)
@@ -85,6 +104,7 @@ func (h *PaymentHandler) GetPaymentByID(c *fiber.Ctx) error {
 		return apperr.WriteError(c, err)
 	}
 
+	setETag(c, res.Payment.Version)
 	return response.OK(c, res)
 }
 
@@ -96,7 +116,7 @@ func (h *PaymentHandler) GetPaymentByID(c *fiber.Ctx) error {
 // @Produce json
 // @Param payment_attempt body dto.CreatePaymentAttemptRequestDto true "Payment attempt data"
 // @Success 201 {object} dto.CreatePaymentAttemptResponseDto "Payment attempt created successfully"
-// @Failure 400 {object} response.ErrorResponse "Invalid request body or identifiers"
+// @Failure 400 {object} response.ErrorResponse "Invalid request body or identifiers, or line items that do not add up to the amount due"
 // @Failure 401 {object} response.ErrorResponse "Unauthorized"
 // @Failure 403 {object} response.ErrorResponse "Forbidden"
 // @Failure 404 {object} response.ErrorResponse "Payment information not found"
@@ -106,7 +126,7 @@ func (h *PaymentHandler) GetPaymentByID(c *fiber.Ctx) error {
 func (h *PaymentHandler) CreatePaymentAttempt(c *fiber.Ctx) error {
 	var body dto.CreatePaymentAttemptRequestDto
 	if err := c.BodyParser(&body); err != nil {
-		return response.BadRequest(c, "Invalid request body "+err.Error())
+		return apperr.WriteError(c, apperr.BadInput(i18n.InvalidRequestBody, err))
 	}
 	ctx := contextUtils.GetContext(c)
 
@@ -126,6 +146,7 @@ func (h *PaymentHandler) CreatePaymentAttempt(c *fiber.Ctx) error {
 // @Produce json
 // @Param id path string true "Payment attempt ID"
 // @Success 200 {object} dto.GetPaymentAttemptResponseDto "Payment attempt retrieved successfully"
+// @Header 200 {string} ETag "Version of the attempt, for If-Match"
 // @Failure 400 {object} response.ErrorResponse "Invalid payment attempt ID"
 // @Failure 401 {object} response.ErrorResponse "Unauthorized"
 // @Failure 404 {object} response.ErrorResponse "Payment attempt not found"
@@ -135,9 +156,7 @@ func (h *PaymentHandler) CreatePaymentAttempt(c *fiber.Ctx) error {
 func (h *PaymentHandler) GetPaymentAttempt(c *fiber.Ctx) error {
 	id := c.Params("id")
 	if id == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Missing payment attempt ID",
-		})
+		return apperr.WriteError(c, apperr.New(apperr.CodeBadRequest, i18n.MissingAttemptID, nil))
 	}
 
 	ctx := contextUtils.GetContext(c)
@@ -146,6 +165,7 @@ func (h *PaymentHandler) GetPaymentAttempt(c *fiber.Ctx) error {
 		return apperr.WriteError(c, err)
 	}
 
+	setETag(c, res.Version)
 	return c.Status(fiber.StatusOK).JSON(res)
 }
 
@@ -156,19 +176,31 @@ func (h *PaymentHandler) GetPaymentAttempt(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param payment_attempt body dto.UpdatePaymentAttemptRequestDto true "Payment attempt status update payload"
+// @Param If-Match header string false "ETag of the version the change is based on, or * for the current version; required unless the body has version"
 // @Success 200 {object} dto.UpdatePaymentAttemptResponseDto "Payment attempt updated successfully"
+// @Header 200 {string} ETag "New version of the attempt"
 // @Failure 400 {object} response.ErrorResponse "Invalid request body or identifiers"
 // @Failure 401 {object} response.ErrorResponse "Unauthorized"
 // @Failure 404 {object} response.ErrorResponse "Payment attempt not found"
+// @Failure 409 {object} response.ErrorResponse "Payment attempt was changed by someone else, already has a payment or is held for risk review"
+// @Failure 428 {object} response.ErrorResponse "Neither If-Match nor version was sent"
 // @Failure 500 {object} response.ErrorResponse "Failed to update payment attempt"
 // @Router /api/payment/v1/attempt [patch]
 // @Security ApiKeyAuth
 func (h *PaymentHandler) UpdatePaymentAttempt(c *fiber.Ctx) error {
 	var body dto.UpdatePaymentAttemptRequestDto
 	if err := c.BodyParser(&body); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body " + err.Error(),
-		})
+		return apperr.WriteError(c, apperr.BadInput(i18n.InvalidRequestBody, err))
+	}
+	version, err := ifMatch(c)
+	if err != nil {
+		return apperr.WriteError(c, err)
+	}
+	switch {
+	case version == anyVersion:
+		body.AnyVersion = true
+	case version != 0:
+		body.Version = version
 	}
 
 	ctx := contextUtils.GetContext(c)
@@ -177,5 +209,6 @@ func (h *PaymentHandler) UpdatePaymentAttempt(c *fiber.Ctx) error {
 		return apperr.WriteError(c, err)
 	}
 
+	setETag(c, res.Version)
 	return c.Status(fiber.StatusOK).JSON(res)
 }
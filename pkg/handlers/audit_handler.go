@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"payment-service/pkg/apperr"
+	contextUtils "payment-service/pkg/context"
+	"payment-service/pkg/dto"
+	"payment-service/pkg/i18n"
+	"payment-service/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetAuditLogs godoc
+// @Summary Query audit log
+// @Description List audit log entries for payment info, attempts and payments, newest first (admin only)
+// @Tags audit
+// @Accept json
+// @Produce json
+// @Param entity_type query string false "Table name, e.g. payments"
+// @Param entity_id query string false "ID of the changed row"
+// @Param actor_id query string false "User who made the change"
+// @Param action query string false "create, update or delete"
+// @Param request_id query string false "Request ID"
+// @Param from query string false "Earliest time, RFC 3339"
+// @Param to query string false "Time before which entries were written, RFC 3339"
+// @Param limit query int false "Maximum entries to return (default 100, at most 1000)"
+// @Success 200 {object} dto.GetAuditLogsResponseDto "Audit log retrieved successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid filter"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden"
+// @Failure 500 {object} response.ErrorResponse "Failed to retrieve audit log"
+// @Router /api/payment/v1/audit [get]
+// @Security ApiKeyAuth
+func (h *PaymentHandler) GetAuditLogs(c *fiber.Ctx) error {
+	var query dto.GetAuditLogsRequestDto
+	if err := c.QueryParser(&query); err != nil {
+		return apperr.WriteError(c, apperr.BadInput(i18n.InvalidQuery, err))
+	}
+
+	ctx := contextUtils.GetContext(c)
+	res, err := h.paymentService.GetAuditLogs(ctx, query)
+	if err != nil {
+		return apperr.WriteError(c, err)
+	}
+
+	return response.OK(c, res)
+}
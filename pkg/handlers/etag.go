@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+
+	"payment-service/pkg/apperr"
+	"payment-service/pkg/i18n"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// An ETag is the row version in quotes, so a client can send it back in
+// If-Match to update only the version it read.
+
+func setETag(c *fiber.Ctx, version int) {
+	c.Set(fiber.HeaderETag, strconv.Quote(strconv.Itoa(version)))
+}
+
+// anyVersion is what ifMatch returns for "If-Match: *", which matches
+// whatever version the row has as long as it exists.
+const anyVersion = -1
+
+// ifMatch returns the version in the If-Match header, anyVersion for *,
+// or 0 when there is none. A weak ETag is taken as a strong one, since
+// versions are exact.
+func ifMatch(c *fiber.Ctx) (int, error) {
+	header := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if header == "" {
+		return 0, nil
+	}
+	if header == "*" {
+		return anyVersion, nil
+	}
+	quoted := strings.TrimPrefix(header, "W/")
+	unquoted, err := strconv.Unquote(quoted)
+	if err != nil || !strings.HasPrefix(quoted, `"`) {
+		return 0, apperr.New(apperr.CodeBadRequest, i18n.InvalidIfMatch, nil)
+	}
+	version, err := strconv.Atoi(unquoted)
+	if err != nil || version < 1 {
+		return 0, apperr.New(apperr.CodeBadRequest, i18n.InvalidIfMatch, nil)
+	}
+	return version, nil
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"log"
+	"payment-service/pkg/apperr"
+	contextUtils "payment-service/pkg/context"
+	"payment-service/pkg/dto"
+	"payment-service/pkg/i18n"
+	"payment-service/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ExportPayments godoc
+// @Summary Export payments
+// @Description Stream the payments made in a period as CSV or XLSX for reconciliation (admin only). Rows are read from the database as they are written, oldest first.
+// @Tags exports
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param from query string true "Start of the period, RFC 3339 or YYYY-MM-DD"
+// @Param to query string true "End of the period, exclusive, RFC 3339 or YYYY-MM-DD"
+// @Param format query string false "csv or xlsx (default csv)"
+// @Param columns query string false "Comma separated columns: payment_id, paid_at, payable_type, payable_id, attempt_id, user_id, method, amount, patient_amount, payer_amount, healthcare_entitlement (default all)"
+// @Param locale query string false "en or th, the Accept-Language when absent; th writes amounts with thousands separators and Buddhist era dates"
+// @Success 200 {file} file "Export file"
+// @Failure 400 {object} response.ErrorResponse "Invalid period, format, column or locale"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden"
+// @Router /api/payment/v1/exports/payments [get]
+// @Security ApiKeyAuth
+func (h *PaymentHandler) ExportPayments(c *fiber.Ctx) error {
+	var query dto.ExportPaymentsRequestDto
+	if err := c.QueryParser(&query); err != nil {
+		return apperr.WriteError(c, apperr.BadInput(i18n.InvalidQuery, err))
+	}
+
+	ctx := contextUtils.GetContext(c)
+	prepared, err := h.paymentService.PreparePaymentExport(ctx, query)
+	if err != nil {
+		return apperr.WriteError(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, prepared.Format.ContentType())
+	c.Attachment(prepared.Filename)
+	// The body is written after the handler returns, so the stream cannot
+	// depend on the request context; a client that goes away fails the
+	// next write instead. Errors after the first byte cannot change the
+	// status, so they cut the file short and are logged.
+	streamCtx := context.WithoutCancel(ctx)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if _, err := prepared.Run(streamCtx, w); err != nil {
+			log.Printf("export %s failed: %v", prepared.Filename, err)
+			return
+		}
+		if err := w.Flush(); err != nil {
+			log.Printf("export %s failed: %v", prepared.Filename, err)
+		}
+	})
+	return nil
+}
+
+// StartPaymentExportJob godoc
+// @Summary Start a payment export job
+// @Description Export payments in the background, for periods too large to download at once (admin only). Poll the job until it is done, then fetch its download_url.
+// @Tags exports
+// @Accept json
+// @Produce json
+// @Param export body dto.ExportPaymentsRequestDto true "Period, format, columns and locale, as for the streaming export"
+// @Success 202 {object} dto.ExportJobResponseDto "Export started"
+// @Failure 400 {object} response.ErrorResponse "Invalid period, format, column or locale"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden"
+// @Router /api/payment/v1/exports/payments/jobs [post]
+// @Security ApiKeyAuth
+func (h *PaymentHandler) StartPaymentExportJob(c *fiber.Ctx) error {
+	var body dto.ExportPaymentsRequestDto
+	if err := c.BodyParser(&body); err != nil {
+		return apperr.WriteError(c, apperr.BadInput(i18n.InvalidRequestBody, err))
+	}
+
+	ctx := contextUtils.GetContext(c)
+	res, err := h.paymentService.StartPaymentExportJob(ctx, body)
+	if err != nil {
+		return apperr.WriteError(c, err)
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(res)
+}
+
+// GetExportJob godoc
+// @Summary Get an export job
+// @Description Report the status of a background export and, once done, its download link (admin only)
+// @Tags exports
+// @Produce json
+// @Param jobId path string true "Export job ID"
+// @Success 200 {object} dto.ExportJobResponseDto "Export job retrieved successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid job ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden"
+// @Failure 404 {object} response.ErrorResponse "Export job not found"
+// @Router /api/payment/v1/exports/jobs/{jobId} [get]
+// @Security ApiKeyAuth
+func (h *PaymentHandler) GetExportJob(c *fiber.Ctx) error {
+	ctx := contextUtils.GetContext(c)
+	res, err := h.paymentService.GetExportJob(ctx, c.Params("jobId"))
+	if err != nil {
+		return apperr.WriteError(c, err)
+	}
+
+	return response.OK(c, res)
+}
+
+// DownloadExportJob godoc
+// @Summary Download an export
+// @Description Download the file of a finished background export (admin only)
+// @Tags exports
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param jobId path string true "Export job ID"
+// @Success 200 {file} file "Export file"
+// @Failure 400 {object} response.ErrorResponse "Invalid job ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden"
+// @Failure 404 {object} response.ErrorResponse "Export job not found or expired"
+// @Failure 409 {object} response.ErrorResponse "Export is not done"
+// @Failure 500 {object} response.ErrorResponse "Failed to open export file"
+// @Router /api/payment/v1/exports/jobs/{jobId}/download [get]
+// @Security ApiKeyAuth
+func (h *PaymentHandler) DownloadExportJob(c *fiber.Ctx) error {
+	ctx := contextUtils.GetContext(c)
+	res, err := h.paymentService.OpenExportJobFile(ctx, c.Params("jobId"))
+	if err != nil {
+		return apperr.WriteError(c, err)
+	}
+
+	size := -1
+	if info, err := res.File.Stat(); err == nil {
+		size = int(info.Size())
+	}
+	c.Set(fiber.HeaderContentType, res.ContentType)
+	c.Attachment(res.Filename)
+	// the stream is closed once it has been sent
+	return c.Status(fiber.StatusOK).SendStream(res.File, size)
+}
@@ -4,6 +4,7 @@ import (
 	"payment-service/pkg/apperr"
 	contextUtils "payment-service/pkg/context"
 	"payment-service/pkg/dto"
+	"payment-service/pkg/i18n"
 	"payment-service/pkg/response"
 	service "payment-service/pkg/services"
 
@@ -31,13 +32,14 @@ func NewPaymentHandler(paymentService *service.PaymentService) *PaymentHandler {
 // @Failure 400 {object} response.ErrorResponse "Invalid request body"
 // @Failure 401 {object} response.ErrorResponse "Unauthorized"
 // @Failure 403 {object} response.ErrorResponse "Forbidden"
+// @Failure 409 {object} response.ErrorResponse "A payment method of this type is already saved"
 // @Failure 500 {object} response.ErrorResponse "Failed to create payment information"
 // @Router /api/payment/v1/info [post]
 // @Security ApiKeyAuth
 func (h *PaymentHandler) CreatePaymentInfo(c *fiber.Ctx) error {
 	var body dto.CreatePaymentInfoRequestDto
 	if err := c.BodyParser(&body); err != nil {
-		return response.BadRequest(c, "Invalid request body "+err.Error())
+		return apperr.WriteError(c, apperr.BadInput(i18n.InvalidRequestBody, err))
 	}
 	ctx := contextUtils.GetContext(c)
 
@@ -57,6 +59,7 @@ func (h *PaymentHandler) CreatePaymentInfo(c *fiber.Ctx) error {
 // @Produce json
 // @Param id path string true "Payment information ID"
 // @Success 200 {object} dto.GetPaymentInfoByIDResponseDto "Payment information retrieved successfully"
+// @Header 200 {string} ETag "Version of the payment information, for If-Match"
 // @Failure 400 {object} response.ErrorResponse "Invalid payment information ID"
 // @Failure 401 {object} response.ErrorResponse "Unauthorized"
 // @Failure 404 {object} response.ErrorResponse "Payment information not found"
@@ -66,9 +69,7 @@ func (h *PaymentHandler) CreatePaymentInfo(c *fiber.Ctx) error {
 func (h *PaymentHandler) GetPaymentInfo(c *fiber.Ctx) error {
 	id := c.Params("id")
 	if id == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Missing payment information ID",
-		})
+		return apperr.WriteError(c, apperr.New(apperr.CodeBadRequest, i18n.MissingPaymentInfoID, nil))
 	}
 
 	ctx := contextUtils.GetContext(c)
@@ -77,6 +78,7 @@ func (h *PaymentHandler) GetPaymentInfo(c *fiber.Ctx) error {
 		return apperr.WriteError(c, err)
 	}
 
+	setETag(c, res.PaymentInfo.Version)
 	return c.Status(fiber.StatusOK).JSON(res)
 }
 
@@ -96,9 +98,7 @@ func (h *PaymentHandler) GetPaymentInfo(c *fiber.Ctx) error {
 func (h *PaymentHandler) GetPaymentInfoByMethod(c *fiber.Ctx) error {
 	method := c.Query("method")
 	if method == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Missing payment method",
-		})
+		return apperr.WriteError(c, apperr.New(apperr.CodeBadRequest, i18n.MissingPaymentMethod, nil))
 	}
 
 	ctx := contextUtils.GetContext(c)
@@ -111,18 +111,30 @@ func (h *PaymentHandler) GetPaymentInfoByMethod(c *fiber.Ctx) error {
 
 // GetAllPaymentInfos godoc
 // @Summary List payment information
-// @Description Retrieve all payment information records for the authenticated patient
+// @Description Retrieve one page of payment information records, newest first by default (admin only)
 // @Tags payment-info
 // @Accept json
 // @Produce json
+// @Param user_id query string false "Owner of the payment information"
+// @Param method query string false "Payment method: credit_card or promptpay"
+// @Param sort query string false "asc or desc (default desc)"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param limit query int false "Maximum records to return (default 50, at most 200)"
 // @Success 200 {object} dto.GetAllPaymentInfosResponseDto "Payment information retrieved successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid filter or cursor"
 // @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden"
 // @Failure 500 {object} response.ErrorResponse "Failed to retrieve payment information"
 // @Router /api/payment/v1/info [get]
 // @Security ApiKeyAuth
 func (h *PaymentHandler) GetAllPaymentInfos(c *fiber.Ctx) error {
+	var query dto.GetAllPaymentInfosRequestDto
+	if err := c.QueryParser(&query); err != nil {
+		return apperr.WriteError(c, apperr.BadInput(i18n.InvalidQuery, err))
+	}
+
 	ctx := contextUtils.GetContext(c)
-	res, err := h.paymentService.GetAllPaymentInfos(ctx)
+	res, err := h.paymentService.GetAllPaymentInfos(ctx, query)
 	if err != nil {
 		return apperr.WriteError(c, err)
 	}
@@ -137,19 +149,31 @@ func (h *PaymentHandler) GetAllPaymentInfos(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param payment_info body dto.UpdatePaymentInfoRequestDto true "Payment information to update"
+// @Param If-Match header string false "ETag of the version the change is based on, or * for the current version; required unless the body has version"
 // @Success 200 {object} dto.UpdatePaymentInfoResponseDto "Payment information updated successfully"
+// @Header 200 {string} ETag "New version of the payment information"
 // @Failure 400 {object} response.ErrorResponse "Invalid request body"
 // @Failure 401 {object} response.ErrorResponse "Unauthorized"
 // @Failure 404 {object} response.ErrorResponse "Payment information not found"
+// @Failure 409 {object} response.ErrorResponse "Payment information was changed by someone else, or a method of the new type is already saved"
+// @Failure 428 {object} response.ErrorResponse "Neither If-Match nor version was sent"
 // @Failure 500 {object} response.ErrorResponse "Failed to update payment information"
 // @Router /api/payment/v1/info [put]
 // @Security ApiKeyAuth
 func (h *PaymentHandler) UpdatePaymentInfo(c *fiber.Ctx) error {
 	var body dto.UpdatePaymentInfoRequestDto
 	if err := c.BodyParser(&body); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body " + err.Error(),
-		})
+		return apperr.WriteError(c, apperr.BadInput(i18n.InvalidRequestBody, err))
+	}
+	version, err := ifMatch(c)
+	if err != nil {
+		return apperr.WriteError(c, err)
+	}
+	switch {
+	case version == anyVersion:
+		body.AnyVersion = true
+	case version != 0:
+		body.Version = version
 	}
 
 	ctx := contextUtils.GetContext(c)
@@ -158,6 +182,7 @@ func (h *PaymentHandler) UpdatePaymentInfo(c *fiber.Ctx) error {
 		return apperr.WriteError(c, err)
 	}
 
+	setETag(c, res.Version)
 	return c.Status(fiber.StatusOK).JSON(res)
 }
 
@@ -178,9 +203,7 @@ func (h *PaymentHandler) UpdatePaymentInfo(c *fiber.Ctx) error {
 func (h *PaymentHandler) DeletePaymentInfo(c *fiber.Ctx) error {
 	var body dto.DeletePaymentInfoRequestDto
 	if err := c.BodyParser(&body); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request body " + err.Error(),
-		})
+		return apperr.WriteError(c, apperr.BadInput(i18n.InvalidRequestBody, err))
 	}
 
 	ctx := contextUtils.GetContext(c)
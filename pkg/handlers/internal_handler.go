@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"payment-service/pkg/apperr"
+	contextUtils "payment-service/pkg/context"
+	"payment-service/pkg/dto"
+	"payment-service/pkg/i18n"
+	"payment-service/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetPayableStatus godoc
+// @Summary Get payable payment status (internal)
+// @Description Report the payments made on a payable such as an order or appointment, for its owning service to compare with the amount due; requires a service token
+// @Tags internal
+// @Accept json
+// @Produce json
+// @Param payableType path string true "Payable type (order, appointment, delivery, deposit)"
+// @Param payableId path string true "Payable ID"
+// @Success 200 {object} dto.PayableStatusResponseDto "Payment status retrieved successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid payable"
+// @Failure 401 {object} response.ErrorResponse "Missing or invalid service token"
+// @Failure 500 {object} response.ErrorResponse "Failed to retrieve payment status"
+// @Router /internal/v1/payments/{payableType}/{payableId}/status [get]
+// @Security ApiKeyAuth
+func (h *PaymentHandler) GetPayableStatus(c *fiber.Ctx) error {
+	ctx := contextUtils.GetContext(c)
+	res, err := h.paymentService.GetPayableStatus(ctx, c.Params("payableType"), c.Params("payableId"))
+	if err != nil {
+		return apperr.WriteError(c, err)
+	}
+
+	return response.OK(c, res)
+}
+
+// GetPayableStatuses godoc
+// @Summary Get payment status of many payables (internal)
+// @Description Report the payment state of up to 100 orders or appointments in one call, in the order requested; requires a service token
+// @Tags internal
+// @Accept json
+// @Produce json
+// @Param payables body dto.BatchPayableStatusRequestDto true "Payables to look up"
+// @Success 200 {object} dto.BatchPayableStatusResponseDto "Payment statuses retrieved successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid or too many payables"
+// @Failure 401 {object} response.ErrorResponse "Missing or invalid service token"
+// @Failure 500 {object} response.ErrorResponse "Failed to retrieve payment status"
+// @Router /internal/v1/payments/status:batch [post]
+// @Security ApiKeyAuth
+func (h *PaymentHandler) GetPayableStatuses(c *fiber.Ctx) error {
+	var body dto.BatchPayableStatusRequestDto
+	if err := c.BodyParser(&body); err != nil {
+		return apperr.WriteError(c, apperr.BadInput(i18n.InvalidRequestBody, err))
+	}
+
+	ctx := contextUtils.GetContext(c)
+	res, err := h.paymentService.GetPayableStatuses(ctx, body)
+	if err != nil {
+		return apperr.WriteError(c, err)
+	}
+
+	return response.OK(c, res)
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"payment-service/pkg/apperr"
+	contextUtils "payment-service/pkg/context"
+	"payment-service/pkg/dto"
+	"payment-service/pkg/i18n"
+	"payment-service/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetMyPayments godoc
+// @Summary List my payments
+// @Description List the authenticated patient's payments, newest first, with the order or appointment, the masked payment method, the refund status, a receipt link and the doctor
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Param year query int false "Only payments made in this year, Asia/Bangkok time"
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param limit query int false "Maximum payments to return (default 50, at most 200)"
+// @Success 200 {object} dto.GetMyPaymentsResponseDto "Payments retrieved successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid year or cursor"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden"
+// @Failure 500 {object} response.ErrorResponse "Failed to retrieve payments"
+// @Router /api/payment/v1/me/payments [get]
+// @Security ApiKeyAuth
+func (h *PaymentHandler) GetMyPayments(c *fiber.Ctx) error {
+	var query dto.GetMyPaymentsRequestDto
+	if err := c.QueryParser(&query); err != nil {
+		return apperr.WriteError(c, apperr.BadInput(i18n.InvalidQuery, err))
+	}
+
+	ctx := contextUtils.GetContext(c)
+	res, err := h.paymentService.GetMyPayments(ctx, query)
+	if err != nil {
+		return apperr.WriteError(c, err)
+	}
+
+	return response.OK(c, res)
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"payment-service/pkg/apperr"
+	contextUtils "payment-service/pkg/context"
+	"payment-service/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetOrderPayments godoc
+// @Summary Get order payments
+// @Description List every attempt and payment of an order with the total paid, total refunded, outstanding balance and derived payment status (unpaid, partially_paid, paid, overpaid, refunded). Patients see their own orders; admins, and services on the internal route, see any order.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param orderId path string true "Order ID"
+// @Success 200 {object} dto.GetOrderPaymentsResponseDto "Order payments retrieved successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid order ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 404 {object} response.ErrorResponse "Order not found or belongs to another user"
+// @Failure 500 {object} response.ErrorResponse "Failed to retrieve order payments"
+// @Router /api/payment/v1/orders/{orderId} [get]
+// @Router /internal/v1/orders/{orderId} [get]
+// @Security ApiKeyAuth
+func (h *PaymentHandler) GetOrderPayments(c *fiber.Ctx) error {
+	ctx := contextUtils.GetContext(c)
+	res, err := h.paymentService.GetOrderPayments(ctx, c.Params("orderId"))
+	if err != nil {
+		return apperr.WriteError(c, err)
+	}
+
+	return response.OK(c, res)
+}
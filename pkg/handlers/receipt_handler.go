@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"payment-service/pkg/apperr"
+	contextUtils "payment-service/pkg/context"
+	"payment-service/pkg/dto"
+	"payment-service/pkg/i18n"
+	"payment-service/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetPaymentReceipt godoc
+// @Summary Download payment receipt
+// @Description Render the issued receipt or tax invoice of a payment as PDF
+// @Tags payment-documents
+// @Produce application/pdf
+// @Param id path string true "Payment ID"
+// @Param type query string false "Document type (receipt, tax_invoice); defaults to receipt"
+// @Param Accept-Language header string false "en or th; receipts are printed in it, tax invoices always in Thai with English"
+// @Success 200 {file} file "Rendered document"
+// @Failure 400 {object} response.ErrorResponse "Invalid payment ID or document type"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden"
+// @Failure 404 {object} response.ErrorResponse "Payment not found or document not issued"
+// @Failure 500 {object} response.ErrorResponse "Failed to render document"
+// @Router /api/payment/v1/{id}/receipt [get]
+// @Security ApiKeyAuth
+func (h *PaymentHandler) GetPaymentReceipt(c *fiber.Ctx) error {
+	ctx := contextUtils.GetContext(c)
+	res, err := h.paymentService.GetPaymentDocumentFile(ctx, c.Params("id"), c.Query("type"))
+	if err != nil {
+		return apperr.WriteError(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, "application/pdf")
+	c.Set(fiber.HeaderContentDisposition, `inline; filename="`+res.FileName+`"`)
+	return c.Status(fiber.StatusOK).Send(res.Content)
+}
+
+// IssueReceipt godoc
+// @Summary Issue receipt
+// @Description Issue the receipt of a payment, made out to the patient
+// @Tags payment-documents
+// @Produce json
+// @Param id path string true "Payment ID"
+// @Success 201 {object} dto.PaymentDocumentResponseDto "Receipt issued successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid payment ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden"
+// @Failure 404 {object} response.ErrorResponse "Payment or patient profile not found"
+// @Failure 409 {object} response.ErrorResponse "Receipt already issued"
+// @Failure 500 {object} response.ErrorResponse "Failed to issue receipt"
+// @Router /api/payment/v1/{id}/receipt [post]
+// @Security ApiKeyAuth
+func (h *PaymentHandler) IssueReceipt(c *fiber.Ctx) error {
+	ctx := contextUtils.GetContext(c)
+	res, err := h.paymentService.IssueReceipt(ctx, c.Params("id"))
+	if err != nil {
+		return apperr.WriteError(c, err)
+	}
+
+	return response.Created(c, res)
+}
+
+// IssueTaxInvoice godoc
+// @Summary Issue tax invoice
+// @Description Issue a Thai tax invoice for a payment with the buyer's tax details
+// @Tags payment-documents
+// @Accept json
+// @Produce json
+// @Param id path string true "Payment ID"
+// @Param tax_invoice body dto.IssueTaxInvoiceRequestDto true "Buyer details"
+// @Success 201 {object} dto.PaymentDocumentResponseDto "Tax invoice issued successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid request body"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden"
+// @Failure 404 {object} response.ErrorResponse "Payment not found"
+// @Failure 409 {object} response.ErrorResponse "Tax invoice already issued"
+// @Failure 500 {object} response.ErrorResponse "Failed to issue tax invoice"
+// @Router /api/payment/v1/{id}/tax-invoice [post]
+// @Security ApiKeyAuth
+func (h *PaymentHandler) IssueTaxInvoice(c *fiber.Ctx) error {
+	var body dto.IssueTaxInvoiceRequestDto
+	if err := c.BodyParser(&body); err != nil {
+		return apperr.WriteError(c, apperr.BadInput(i18n.InvalidRequestBody, err))
+	}
+
+	ctx := contextUtils.GetContext(c)
+	res, err := h.paymentService.IssueTaxInvoice(ctx, c.Params("id"), body)
+	if err != nil {
+		return apperr.WriteError(c, err)
+	}
+
+	return response.Created(c, res)
+}
+
+// GetPaymentDocuments godoc
+// @Summary List payment documents
+// @Description Retrieve every receipt and tax invoice of a payment, including voided ones, with their audit trail
+// @Tags payment-documents
+// @Accept json
+// @Produce json
+// @Param id path string true "Payment ID"
+// @Success 200 {object} dto.GetPaymentDocumentsResponseDto "Documents retrieved successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid payment ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden"
+// @Failure 404 {object} response.ErrorResponse "Payment not found"
+// @Failure 500 {object} response.ErrorResponse "Failed to retrieve documents"
+// @Router /api/payment/v1/{id}/documents [get]
+// @Security ApiKeyAuth
+func (h *PaymentHandler) GetPaymentDocuments(c *fiber.Ctx) error {
+	ctx := contextUtils.GetContext(c)
+	res, err := h.paymentService.GetPaymentDocuments(ctx, c.Params("id"))
+	if err != nil {
+		return apperr.WriteError(c, err)
+	}
+
+	return response.OK(c, res)
+}
+
+// ReissueDocument godoc
+// @Summary Reissue document
+// @Description Void a receipt or tax invoice and issue a corrected copy under a new number (admin only)
+// @Tags payment-documents
+// @Accept json
+// @Produce json
+// @Param documentId path string true "Document ID"
+// @Param reissue body dto.ReissueDocumentRequestDto true "Reason and buyer corrections"
+// @Success 201 {object} dto.PaymentDocumentResponseDto "Document reissued successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid request body"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden"
+// @Failure 404 {object} response.ErrorResponse "Document not found"
+// @Failure 409 {object} response.ErrorResponse "Document already voided"
+// @Failure 500 {object} response.ErrorResponse "Failed to reissue document"
+// @Router /api/payment/v1/documents/{documentId}/reissue [post]
+// @Security ApiKeyAuth
+func (h *PaymentHandler) ReissueDocument(c *fiber.Ctx) error {
+	var body dto.ReissueDocumentRequestDto
+	if err := c.BodyParser(&body); err != nil {
+		return apperr.WriteError(c, apperr.BadInput(i18n.InvalidRequestBody, err))
+	}
+
+	ctx := contextUtils.GetContext(c)
+	res, err := h.paymentService.ReissueDocument(ctx, c.Params("documentId"), body)
+	if err != nil {
+		return apperr.WriteError(c, err)
+	}
+
+	return response.Created(c, res)
+}
+
+// VoidDocument godoc
+// @Summary Void document
+// @Description Void a receipt or tax invoice; its number is never reused (admin only)
+// @Tags payment-documents
+// @Accept json
+// @Produce json
+// @Param documentId path string true "Document ID"
+// @Param void body dto.VoidDocumentRequestDto true "Void reason"
+// @Success 200 {object} dto.PaymentDocumentResponseDto "Document voided successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid request body"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden"
+// @Failure 404 {object} response.ErrorResponse "Document not found"
+// @Failure 409 {object} response.ErrorResponse "Document already voided"
+// @Failure 500 {object} response.ErrorResponse "Failed to void document"
+// @Router /api/payment/v1/documents/{documentId}/void [post]
+// @Security ApiKeyAuth
+func (h *PaymentHandler) VoidDocument(c *fiber.Ctx) error {
+	var body dto.VoidDocumentRequestDto
+	if err := c.BodyParser(&body); err != nil {
+		return apperr.WriteError(c, apperr.BadInput(i18n.InvalidRequestBody, err))
+	}
+
+	ctx := contextUtils.GetContext(c)
+	res, err := h.paymentService.VoidDocument(ctx, c.Params("documentId"), body)
+	if err != nil {
+		return apperr.WriteError(c, err)
+	}
+
+	return response.OK(c, res)
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"payment-service/pkg/apperr"
+	contextUtils "payment-service/pkg/context"
+	"payment-service/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetReceivables godoc
+// @Summary List payer receivables
+// @Description Retrieve the insurer and government shares of payments, by status, for invoicing
+// @Tags receivables
+// @Accept json
+// @Produce json
+// @Param status query string false "Receivable status (accrued, invoiced, settled); defaults to accrued"
+// @Success 200 {object} dto.GetReceivablesResponseDto "Receivables retrieved successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid receivable status"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden"
+// @Failure 500 {object} response.ErrorResponse "Failed to retrieve receivables"
+// @Router /api/payment/v1/receivables [get]
+// @Security ApiKeyAuth
+func (h *PaymentHandler) GetReceivables(c *fiber.Ctx) error {
+	ctx := contextUtils.GetContext(c)
+	res, err := h.paymentService.GetReceivables(ctx, c.Query("status"))
+	if err != nil {
+		return apperr.WriteError(c, err)
+	}
+
+	return response.OK(c, res)
+}
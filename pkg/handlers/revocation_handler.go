@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"payment-service/pkg/apperr"
+	contextUtils "payment-service/pkg/context"
+	"payment-service/pkg/dto"
+	"payment-service/pkg/i18n"
+	"payment-service/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RevokeToken godoc
+// @Summary Revoke tokens
+// @Description Revoke one token by its jti, or every current token of a user, e.g. after logout or suspension (admin only)
+// @Tags tokens
+// @Accept json
+// @Produce json
+// @Param revocation body dto.RevokeTokenRequestDto true "Token or user to revoke"
+// @Success 201 {object} dto.RevokeTokenResponseDto "Token revoked successfully"
+// @Failure 400 {object} response.ErrorResponse "Invalid request body"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden"
+// @Failure 500 {object} response.ErrorResponse "Failed to revoke token"
+// @Router /api/payment/v1/tokens/revoke [post]
+// @Security ApiKeyAuth
+func (h *PaymentHandler) RevokeToken(c *fiber.Ctx) error {
+	var body dto.RevokeTokenRequestDto
+	if err := c.BodyParser(&body); err != nil {
+		return apperr.WriteError(c, apperr.BadInput(i18n.InvalidRequestBody, err))
+	}
+
+	ctx := contextUtils.GetContext(c)
+	res, err := h.paymentService.RevokeToken(ctx, body)
+	if err != nil {
+		return apperr.WriteError(c, err)
+	}
+
+	return response.Created(c, res)
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"payment-service/pkg/apperr"
+	contextUtils "payment-service/pkg/context"
+	"payment-service/pkg/response"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetRiskReviews godoc
+// @Summary List risk review queue
+// @Description List payment attempts held for review by the risk checks, oldest first (admin only)
+// @Tags risk
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.GetRiskReviewsResponseDto "Risk reviews retrieved successfully"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden"
+// @Failure 500 {object} response.ErrorResponse "Failed to retrieve risk reviews"
+// @Router /api/payment/v1/risk/reviews [get]
+// @Security ApiKeyAuth
+func (h *PaymentHandler) GetRiskReviews(c *fiber.Ctx) error {
+	ctx := contextUtils.GetContext(c)
+	res, err := h.paymentService.GetRiskReviews(ctx)
+	if err != nil {
+		return apperr.WriteError(c, err)
+	}
+
+	return response.OK(c, res)
+}
+
+// ApproveRiskReview godoc
+// @Summary Approve risk review
+// @Description Let a payment attempt held for review go ahead (admin only)
+// @Tags risk
+// @Accept json
+// @Produce json
+// @Param reviewId path string true "Risk review ID"
+// @Success 200 {object} dto.RiskReviewResponseDto "Risk review approved"
+// @Failure 400 {object} response.ErrorResponse "Invalid risk review ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden"
+// @Failure 404 {object} response.ErrorResponse "Risk review not found"
+// @Failure 409 {object} response.ErrorResponse "Risk review already closed"
+// @Failure 500 {object} response.ErrorResponse "Failed to close risk review"
+// @Router /api/payment/v1/risk/reviews/{reviewId}/approve [post]
+// @Security ApiKeyAuth
+func (h *PaymentHandler) ApproveRiskReview(c *fiber.Ctx) error {
+	ctx := contextUtils.GetContext(c)
+	res, err := h.paymentService.ApproveRiskReview(ctx, c.Params("reviewId"))
+	if err != nil {
+		return apperr.WriteError(c, err)
+	}
+
+	return response.OK(c, res)
+}
+
+// DenyRiskReview godoc
+// @Summary Deny risk review
+// @Description Fail a payment attempt held for review (admin only)
+// @Tags risk
+// @Accept json
+// @Produce json
+// @Param reviewId path string true "Risk review ID"
+// @Success 200 {object} dto.RiskReviewResponseDto "Risk review denied"
+// @Failure 400 {object} response.ErrorResponse "Invalid risk review ID"
+// @Failure 401 {object} response.ErrorResponse "Unauthorized"
+// @Failure 403 {object} response.ErrorResponse "Forbidden"
+// @Failure 404 {object} response.ErrorResponse "Risk review not found"
+// @Failure 409 {object} response.ErrorResponse "Risk review already closed"
+// @Failure 500 {object} response.ErrorResponse "Failed to close risk review"
+// @Router /api/payment/v1/risk/reviews/{reviewId}/deny [post]
+// @Security ApiKeyAuth
+func (h *PaymentHandler) DenyRiskReview(c *fiber.Ctx) error {
+	ctx := contextUtils.GetContext(c)
+	res, err := h.paymentService.DenyRiskReview(ctx, c.Params("reviewId"))
+	if err != nil {
+		return apperr.WriteError(c, err)
+	}
+
+	return response.OK(c, res)
+}
@@ -0,0 +1,186 @@
+package i18n
+
+var english = catalog{
+	BadRequest:       "bad request",
+	Unauthorized:     "unauthorized",
+	Forbidden:        "forbidden",
+	NotFound:         "not found",
+	MethodNotAllowed: "method not allowed",
+	Conflict:         "conflict",
+	PayloadTooLarge:  "request body is too large",
+	TooManyRequests:  "too many requests",
+	Internal:         "internal error",
+	BadGateway:       "bad gateway",
+	Unavailable:      "service unavailable",
+	GatewayTimeout:   "gateway timeout",
+
+	InvalidRequestBody: "invalid request body",
+	InvalidQuery:       "invalid query",
+	BodyNotJSON:        "request body is not valid JSON",
+	BodyNotSingleValue: "request body must be a single JSON value",
+	FieldInvalid:       "is invalid",
+	FieldWrongType:     "must be of type %s",
+	FieldUnknown:       "is not a known field",
+	FieldString:        "must be a string",
+	FieldBool:          "must be true or false",
+	FieldInteger:       "must be a whole number",
+	FieldNumber:        "must be a number",
+	FieldArray:         "must be an array",
+	FieldObject:        "must be an object",
+	FieldFailedCheck:   "failed the %s check",
+
+	MissingToken:        "missing or malformed JWT",
+	InvalidToken:        "invalid token",
+	TokenRevoked:        "token has been revoked",
+	MissingServiceToken: "missing or malformed service token",
+	InvalidServiceToken: "invalid service token",
+	InsufficientRole:    "insufficient role",
+
+	MissingPaymentID:        "missing payment ID",
+	MissingAttemptID:        "missing payment attempt ID",
+	MissingPaymentInfoID:    "missing payment information ID",
+	MissingPaymentMethod:    "missing payment method",
+	AttemptIDRequired:       "attempt ID is required",
+	StatusRequired:          "status is required",
+	PayableTypeRequired:     "payable type is required",
+	PayablesRequired:        "at least one payable is required",
+	TooManyPayables:         "at most %d payables can be looked up at once",
+	InvalidPaymentID:        "invalid payment ID",
+	InvalidAttemptID:        "invalid payment attempt ID",
+	InvalidPaymentInfoID:    "invalid payment information ID",
+	InvalidPayableID:        "invalid payable ID",
+	InvalidPayableIDOf:      "invalid payable ID %s",
+	InvalidOrderID:          "invalid order ID",
+	InvalidDocumentID:       "invalid document ID",
+	InvalidRiskReviewID:     "invalid risk review ID",
+	InvalidExportJobID:      "invalid export job ID",
+	InvalidUserID:           "invalid user ID",
+	InvalidField:            "invalid %s",
+	InvalidCursor:           "invalid cursor",
+	InvalidSort:             "sort must be asc or desc",
+	InvalidTime:             "%s must be an RFC 3339 time",
+	InvalidTimeOrDate:       "%s must be an RFC 3339 time or a YYYY-MM-DD date",
+	InvalidPeriod:           "from must be before to",
+	InvalidYear:             "invalid year",
+	InvalidPaymentMethod:    "invalid payment method",
+	InvalidPaymentDetails:   "invalid payment details",
+	InvalidPaymentStatus:    "invalid payment status",
+	InvalidReceivableStatus: "invalid receivable status",
+	InvalidDocumentType:     "invalid document type",
+	InvalidAuditAction:      "invalid audit action",
+	InvalidExportFormat:     "format must be csv or xlsx",
+	InvalidExportLocale:     "locale must be en or th",
+	UnknownExportColumn:     "unknown export column %s",
+	InvalidRevocation:       "exactly one of jti and user_id is required",
+	InvalidIfMatch:          "If-Match must be an ETag returned by this API",
+	VersionRequired:         "send If-Match or version with the version the change is based on",
+	UnsupportedPayableType:  "unsupported payable type",
+	UnsupportedPayableOf:    "unsupported payable type %s",
+
+	NegativeAmount:        "amount must be greater than or equal to zero",
+	AmountMismatch:        "amount does not match the amount due",
+	NegativeUnitPrice:     "only discount line items may have a negative unit price",
+	NegativeLineItems:     "line items must not add up to a negative amount",
+	LineItemsMismatch:     "line items do not add up to the charged amount",
+	AttemptNotSuccessful:  "payment can only be created for successful attempts",
+	AttemptAlreadyPaid:    "the payment attempt has already been paid",
+	AttemptDeclined:       "payment attempt was declined",
+	PayableNotOwned:       "payable does not belong to the current user",
+	PaymentNotOwned:       "payment does not belong to the current user",
+	PaymentInfoNotOwned:   "payment information does not belong to the current user",
+	ReceiptExists:         "a receipt has already been issued for this payment",
+	ReceiptNotIssued:      "no receipt has been issued for this payment",
+	TaxInvoiceExists:      "a tax invoice has already been issued for this payment",
+	TaxInvoiceNotIssued:   "no tax invoice has been issued for this payment",
+	DocumentAlreadyVoided: "document has already been voided",
+	RiskReviewClosed:      "risk review has already been closed",
+	ExportNotReady:        "export is %s",
+	AttemptChanged:        "payment attempt was changed by someone else; reload it and try again",
+	AttemptInReview:       "payment attempt is held for risk review; approve or deny the review instead",
+	PaymentInfoChanged:    "payment information was changed by someone else; reload it and try again",
+	PaymentMethodSaved:    "a payment method of this type is already saved; update it instead",
+	RefundNotPositive:     "refund amount must be greater than zero",
+	RefundExceedsPaid:     "refunds cannot add up to more than the payment",
+
+	PaymentNotFound:        "payment not found",
+	OrderNotFound:          "order not found",
+	AttemptNotFound:        "payment attempt not found",
+	PaymentInfoNotFound:    "payment information not found",
+	DocumentNotFound:       "document not found",
+	PatientProfileNotFound: "patient profile not found",
+	RiskReviewNotFound:     "risk review not found",
+	ExportJobNotFound:      "export job not found",
+	ExportExpired:          "export file has expired",
+
+	FailedCreatePayment:          "failed to create payment",
+	FailedCreateRefund:           "failed to create refund",
+	FailedRetrieveRefunds:        "failed to retrieve refunds",
+	FailedCreateAttempt:          "failed to create payment attempt",
+	FailedCreatePaymentInfo:      "failed to create payment information",
+	FailedUpdateAttempt:          "failed to update payment attempt",
+	FailedUpdatePaymentInfo:      "failed to update payment information",
+	FailedDeletePaymentInfo:      "failed to delete payment information",
+	FailedRetrievePayment:        "failed to retrieve payment",
+	FailedRetrievePayments:       "failed to retrieve payments",
+	FailedRetrievePaymentStatus:  "failed to retrieve payment status",
+	FailedRetrieveAttempt:        "failed to retrieve payment attempt",
+	FailedRetrieveAttempts:       "failed to retrieve payment attempts",
+	FailedRetrievePaymentInfo:    "failed to retrieve payment information",
+	FailedRetrieveLineItems:      "failed to retrieve line items",
+	FailedRetrieveDocument:       "failed to retrieve document",
+	FailedRetrieveDocuments:      "failed to retrieve documents",
+	FailedRetrieveAuditLog:       "failed to retrieve audit log",
+	FailedRetrieveCoverageRules:  "failed to retrieve coverage rules",
+	FailedRetrieveReceivables:    "failed to retrieve receivables",
+	FailedRetrieveRiskReview:     "failed to retrieve risk review",
+	FailedRetrieveRiskReviews:    "failed to retrieve risk reviews",
+	FailedRetrieveEntitlements:   "failed to retrieve healthcare entitlements",
+	FailedRetrievePatientProfile: "failed to retrieve patient profile",
+	FailedRetrieveDoctors:        "failed to retrieve doctor profiles",
+	FailedResolvePayable:         "failed to resolve payable",
+	FailedResolveOrder:           "failed to resolve order",
+	FailedIssueReceipt:           "failed to issue receipt",
+	FailedIssueTaxInvoice:        "failed to issue tax invoice",
+	FailedReissueDocument:        "failed to reissue document",
+	FailedVoidDocument:           "failed to void document",
+	FailedRenderDocument:         "failed to render document",
+	FailedOpenExport:             "failed to open export file",
+	FailedRevokeToken:            "failed to revoke token",
+	FailedCloseRiskReview:        "failed to close risk review",
+	FailedCountUserAttempts:      "failed to count user attempts",
+	FailedCountCardAttempts:      "failed to count card attempts",
+	FailedCountClientAttempts:    "failed to count attempts from client",
+	FailedLookUpCard:             "failed to look up card",
+	FailedLockRiskSubjects:       "failed to lock the attempt for risk checks",
+	FailedCreateRequest:          "failed to create request",
+	FailedMarshalRequest:         "failed to marshal request body",
+	UpstreamError:                "%s rejected the request",
+	UpstreamMissingToken:         "missing access token for %s",
+	UpstreamServiceToken:         "failed to mint service token for %s",
+	UpstreamUnavailable:          "%s is unavailable",
+	UpstreamTimeout:              "%s timed out",
+	UpstreamUnreachable:          "failed to reach %s",
+	UpstreamBadResponse:          "failed to read %s response",
+
+	DocReceipt:       "Receipt",
+	DocTaxInvoice:    "Tax Invoice / Receipt",
+	DocTaxID:         "Tax ID",
+	DocBranch:        "Branch",
+	DocHeadOffice:    "Head office",
+	DocNumber:        "No.",
+	DocDate:          "Date",
+	DocReplaces:      "Replaces a previously issued document",
+	DocBuyer:         "Buyer",
+	DocAddress:       "Address",
+	DocDescription:   "Description",
+	DocQuantity:      "Qty",
+	DocUnitPrice:     "Unit price",
+	DocAmount:        "Amount",
+	DocServiceCharge: "Service charge",
+	DocSubtotal:      "Subtotal",
+	DocVat:           "VAT %s%%",
+	DocTotal:         "Total",
+	DocCoveredBy:     "Covered by payer",
+	DocPaidByPatient: "Paid by patient",
+	DocVoid:          "VOID",
+}
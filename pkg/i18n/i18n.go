@@ -0,0 +1,73 @@
+// Package i18n holds what the service says to people, in every language it
+// speaks, and picks the language a request asked for.
+package i18n
+
+import "fmt"
+
+type Lang string
+
+const (
+	English Lang = "en"
+	Thai    Lang = "th"
+)
+
+// Supported lists the languages in order of preference when a request
+// states none; it is what Accept-Language is negotiated against.
+var Supported = []string{string(English), string(Thai)}
+
+// Parse returns the language named by s, or English for any it does not
+// speak.
+func Parse(s string) Lang {
+	if Lang(s) == Thai {
+		return Thai
+	}
+	return English
+}
+
+// Key names a message in the catalogs.
+type Key string
+
+type catalog map[Key]string
+
+var catalogs = map[Lang]catalog{
+	English: english,
+	Thai:    thai,
+}
+
+// Text is something said to a client, put into the client's language only
+// when the response is written.
+type Text interface {
+	In(lang Lang) string
+}
+
+type message struct {
+	key  Key
+	args []any
+}
+
+// T is the catalog message for key, with args filled into its verbs.
+func T(key Key, args ...any) Text {
+	return message{key: key, args: args}
+}
+
+// In falls back to the English message when lang has no translation, and
+// to the key itself when no catalog has it.
+func (m message) In(lang Lang) string {
+	format, ok := catalogs[lang][m.key]
+	if !ok {
+		format, ok = english[m.key]
+	}
+	if !ok {
+		return string(m.key)
+	}
+	if len(m.args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, m.args...)
+}
+
+// Raw is text that reads the same in every language, such as a message
+// relayed from another service.
+type Raw string
+
+func (r Raw) In(Lang) string { return string(r) }
@@ -0,0 +1,43 @@
+package i18n
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+)
+
+var verbs = regexp.MustCompile(`%[-+# 0]*[0-9]*(\.[0-9]+)?[a-zA-Z%]`)
+
+// TestCatalogsMatch keeps every catalog saying the same things: each key
+// in every language, with the same verbs to fill in.
+func TestCatalogsMatch(t *testing.T) {
+	for lang, messages := range catalogs {
+		for key, format := range english {
+			translated, ok := messages[key]
+			if !ok {
+				t.Errorf("%s has no message for %s", lang, key)
+				continue
+			}
+			if want, got := verbs.FindAllString(format, -1), verbs.FindAllString(translated, -1); !slices.Equal(want, got) {
+				t.Errorf("%s message for %s has verbs %v, English has %v", lang, key, got, want)
+			}
+		}
+		for key := range messages {
+			if _, ok := english[key]; !ok {
+				t.Errorf("%s has a message for %s, which English lacks", lang, key)
+			}
+		}
+	}
+}
+
+func TestFallback(t *testing.T) {
+	if got := T(PaymentNotFound).In(Lang("fr")); got != english[PaymentNotFound] {
+		t.Errorf("unsupported language got %q, want the English message", got)
+	}
+	if got := T(Key("no_such_message")).In(Thai); got != "no_such_message" {
+		t.Errorf("unknown key got %q, want the key", got)
+	}
+	if got := T(TooManyPayables, 100).In(Thai); got != "ค้นหารายการที่ต้องชำระได้ครั้งละไม่เกิน 100 รายการ" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,209 @@
+package i18n
+
+// Messages for errors that carry nothing more specific than their code,
+// keyed by the code itself.
+const (
+	BadRequest       Key = "bad_request"
+	Unauthorized     Key = "unauthorized"
+	Forbidden        Key = "forbidden"
+	NotFound         Key = "not_found"
+	MethodNotAllowed Key = "method_not_allowed"
+	Conflict         Key = "conflict"
+	PayloadTooLarge  Key = "payload_too_large"
+	TooManyRequests  Key = "too_many_requests"
+	Internal         Key = "internal"
+	BadGateway       Key = "bad_gateway"
+	Unavailable      Key = "unavailable"
+	GatewayTimeout   Key = "gateway_timeout"
+)
+
+// Request parsing and validation.
+const (
+	InvalidRequestBody Key = "invalid_request_body"
+	InvalidQuery       Key = "invalid_query"
+	BodyNotJSON        Key = "body_not_json"
+	BodyNotSingleValue Key = "body_not_single_value"
+	FieldInvalid       Key = "field_invalid"
+	FieldWrongType     Key = "field_wrong_type"
+	FieldUnknown       Key = "field_unknown"
+	FieldString        Key = "field_string"
+	FieldBool          Key = "field_bool"
+	FieldInteger       Key = "field_integer"
+	FieldNumber        Key = "field_number"
+	FieldArray         Key = "field_array"
+	FieldObject        Key = "field_object"
+	FieldFailedCheck   Key = "field_failed_check"
+)
+
+// Authentication and authorization.
+const (
+	MissingToken        Key = "missing_token"
+	InvalidToken        Key = "invalid_token"
+	TokenRevoked        Key = "token_revoked"
+	MissingServiceToken Key = "missing_service_token"
+	InvalidServiceToken Key = "invalid_service_token"
+	InsufficientRole    Key = "insufficient_role"
+)
+
+// Malformed or missing request parameters.
+const (
+	MissingPaymentID        Key = "missing_payment_id"
+	MissingAttemptID        Key = "missing_attempt_id"
+	MissingPaymentInfoID    Key = "missing_payment_info_id"
+	MissingPaymentMethod    Key = "missing_payment_method"
+	AttemptIDRequired       Key = "attempt_id_required"
+	StatusRequired          Key = "status_required"
+	PayableTypeRequired     Key = "payable_type_required"
+	PayablesRequired        Key = "payables_required"
+	TooManyPayables         Key = "too_many_payables"
+	InvalidPaymentID        Key = "invalid_payment_id"
+	InvalidAttemptID        Key = "invalid_attempt_id"
+	InvalidPaymentInfoID    Key = "invalid_payment_info_id"
+	InvalidPayableID        Key = "invalid_payable_id"
+	InvalidPayableIDOf      Key = "invalid_payable_id_of"
+	InvalidOrderID          Key = "invalid_order_id"
+	InvalidDocumentID       Key = "invalid_document_id"
+	InvalidRiskReviewID     Key = "invalid_risk_review_id"
+	InvalidExportJobID      Key = "invalid_export_job_id"
+	InvalidUserID           Key = "invalid_user_id"
+	InvalidField            Key = "invalid_field"
+	InvalidCursor           Key = "invalid_cursor"
+	InvalidSort             Key = "invalid_sort"
+	InvalidTime             Key = "invalid_time"
+	InvalidTimeOrDate       Key = "invalid_time_or_date"
+	InvalidPeriod           Key = "invalid_period"
+	InvalidYear             Key = "invalid_year"
+	InvalidPaymentMethod    Key = "invalid_payment_method"
+	InvalidPaymentDetails   Key = "invalid_payment_details"
+	InvalidPaymentStatus    Key = "invalid_payment_status"
+	InvalidReceivableStatus Key = "invalid_receivable_status"
+	InvalidDocumentType     Key = "invalid_document_type"
+	InvalidAuditAction      Key = "invalid_audit_action"
+	InvalidExportFormat     Key = "invalid_export_format"
+	InvalidExportLocale     Key = "invalid_export_locale"
+	UnknownExportColumn     Key = "unknown_export_column"
+	InvalidRevocation       Key = "invalid_revocation"
+	InvalidIfMatch          Key = "invalid_if_match"
+	VersionRequired         Key = "version_required"
+	UnsupportedPayableType  Key = "unsupported_payable_type"
+	UnsupportedPayableOf    Key = "unsupported_payable_type_of"
+)
+
+// Payment rules.
+const (
+	NegativeAmount        Key = "negative_amount"
+	AmountMismatch        Key = "amount_mismatch"
+	NegativeUnitPrice     Key = "negative_unit_price"
+	NegativeLineItems     Key = "negative_line_items"
+	LineItemsMismatch     Key = "line_items_mismatch"
+	AttemptNotSuccessful  Key = "attempt_not_successful"
+	AttemptAlreadyPaid    Key = "attempt_already_paid"
+	AttemptDeclined       Key = "attempt_declined"
+	PayableNotOwned       Key = "payable_not_owned"
+	PaymentNotOwned       Key = "payment_not_owned"
+	PaymentInfoNotOwned   Key = "payment_info_not_owned"
+	ReceiptExists         Key = "receipt_exists"
+	ReceiptNotIssued      Key = "receipt_not_issued"
+	TaxInvoiceExists      Key = "tax_invoice_exists"
+	TaxInvoiceNotIssued   Key = "tax_invoice_not_issued"
+	DocumentAlreadyVoided Key = "document_already_voided"
+	RiskReviewClosed      Key = "risk_review_closed"
+	ExportNotReady        Key = "export_not_ready"
+	AttemptChanged        Key = "attempt_changed"
+	AttemptInReview       Key = "attempt_in_review"
+	PaymentInfoChanged    Key = "payment_info_changed"
+	PaymentMethodSaved    Key = "payment_method_saved"
+	RefundNotPositive     Key = "refund_not_positive"
+	RefundExceedsPaid     Key = "refund_exceeds_paid"
+)
+
+// Things that could not be found.
+const (
+	PaymentNotFound        Key = "payment_not_found"
+	OrderNotFound          Key = "order_not_found"
+	AttemptNotFound        Key = "attempt_not_found"
+	PaymentInfoNotFound    Key = "payment_info_not_found"
+	DocumentNotFound       Key = "document_not_found"
+	PatientProfileNotFound Key = "patient_profile_not_found"
+	RiskReviewNotFound     Key = "risk_review_not_found"
+	ExportJobNotFound      Key = "export_job_not_found"
+	ExportExpired          Key = "export_expired"
+)
+
+// Failures on our side or upstream.
+const (
+	FailedCreatePayment          Key = "failed_create_payment"
+	FailedCreateRefund           Key = "failed_create_refund"
+	FailedRetrieveRefunds        Key = "failed_retrieve_refunds"
+	FailedCreateAttempt          Key = "failed_create_attempt"
+	FailedCreatePaymentInfo      Key = "failed_create_payment_info"
+	FailedUpdateAttempt          Key = "failed_update_attempt"
+	FailedUpdatePaymentInfo      Key = "failed_update_payment_info"
+	FailedDeletePaymentInfo      Key = "failed_delete_payment_info"
+	FailedRetrievePayment        Key = "failed_retrieve_payment"
+	FailedRetrievePayments       Key = "failed_retrieve_payments"
+	FailedRetrievePaymentStatus  Key = "failed_retrieve_payment_status"
+	FailedRetrieveAttempt        Key = "failed_retrieve_attempt"
+	FailedRetrieveAttempts       Key = "failed_retrieve_attempts"
+	FailedRetrievePaymentInfo    Key = "failed_retrieve_payment_info"
+	FailedRetrieveLineItems      Key = "failed_retrieve_line_items"
+	FailedRetrieveDocument       Key = "failed_retrieve_document"
+	FailedRetrieveDocuments      Key = "failed_retrieve_documents"
+	FailedRetrieveAuditLog       Key = "failed_retrieve_audit_log"
+	FailedRetrieveCoverageRules  Key = "failed_retrieve_coverage_rules"
+	FailedRetrieveReceivables    Key = "failed_retrieve_receivables"
+	FailedRetrieveRiskReview     Key = "failed_retrieve_risk_review"
+	FailedRetrieveRiskReviews    Key = "failed_retrieve_risk_reviews"
+	FailedRetrieveEntitlements   Key = "failed_retrieve_entitlements"
+	FailedRetrievePatientProfile Key = "failed_retrieve_patient_profile"
+	FailedRetrieveDoctors        Key = "failed_retrieve_doctors"
+	FailedResolvePayable         Key = "failed_resolve_payable"
+	FailedResolveOrder           Key = "failed_resolve_order"
+	FailedIssueReceipt           Key = "failed_issue_receipt"
+	FailedIssueTaxInvoice        Key = "failed_issue_tax_invoice"
+	FailedReissueDocument        Key = "failed_reissue_document"
+	FailedVoidDocument           Key = "failed_void_document"
+	FailedRenderDocument         Key = "failed_render_document"
+	FailedOpenExport             Key = "failed_open_export"
+	FailedRevokeToken            Key = "failed_revoke_token"
+	FailedCloseRiskReview        Key = "failed_close_risk_review"
+	FailedCountUserAttempts      Key = "failed_count_user_attempts"
+	FailedCountCardAttempts      Key = "failed_count_card_attempts"
+	FailedCountClientAttempts    Key = "failed_count_client_attempts"
+	FailedLookUpCard             Key = "failed_look_up_card"
+	FailedLockRiskSubjects       Key = "failed_lock_risk_subjects"
+	FailedCreateRequest          Key = "failed_create_request"
+	FailedMarshalRequest         Key = "failed_marshal_request"
+	UpstreamError                Key = "upstream_error"
+	UpstreamMissingToken         Key = "upstream_missing_token"
+	UpstreamServiceToken         Key = "upstream_service_token"
+	UpstreamUnavailable          Key = "upstream_unavailable"
+	UpstreamTimeout              Key = "upstream_timeout"
+	UpstreamUnreachable          Key = "upstream_unreachable"
+	UpstreamBadResponse          Key = "upstream_bad_response"
+)
+
+// Labels printed on receipts and tax invoices.
+const (
+	DocReceipt       Key = "doc_receipt"
+	DocTaxInvoice    Key = "doc_tax_invoice"
+	DocTaxID         Key = "doc_tax_id"
+	DocBranch        Key = "doc_branch"
+	DocHeadOffice    Key = "doc_head_office"
+	DocNumber        Key = "doc_number"
+	DocDate          Key = "doc_date"
+	DocReplaces      Key = "doc_replaces"
+	DocBuyer         Key = "doc_buyer"
+	DocAddress       Key = "doc_address"
+	DocDescription   Key = "doc_description"
+	DocQuantity      Key = "doc_quantity"
+	DocUnitPrice     Key = "doc_unit_price"
+	DocAmount        Key = "doc_amount"
+	DocServiceCharge Key = "doc_service_charge"
+	DocSubtotal      Key = "doc_subtotal"
+	DocVat           Key = "doc_vat"
+	DocTotal         Key = "doc_total"
+	DocCoveredBy     Key = "doc_covered_by"
+	DocPaidByPatient Key = "doc_paid_by_patient"
+	DocVoid          Key = "doc_void"
+)
@@ -0,0 +1,186 @@
+package i18n
+
+var thai = catalog{
+	BadRequest:       "คำขอไม่ถูกต้อง",
+	Unauthorized:     "กรุณาเข้าสู่ระบบ",
+	Forbidden:        "ไม่มีสิทธิ์เข้าถึง",
+	NotFound:         "ไม่พบสิ่งที่ร้องขอ",
+	MethodNotAllowed: "ไม่รองรับเมธอดนี้",
+	Conflict:         "ข้อมูลขัดแย้งกับสถานะปัจจุบัน",
+	PayloadTooLarge:  "ข้อมูลคำขอมีขนาดใหญ่เกินไป",
+	TooManyRequests:  "มีคำขอมากเกินไป กรุณาลองใหม่ภายหลัง",
+	Internal:         "เกิดข้อผิดพลาดภายในระบบ",
+	BadGateway:       "บริการที่เกี่ยวข้องตอบกลับไม่ถูกต้อง",
+	Unavailable:      "บริการไม่พร้อมใช้งานชั่วคราว",
+	GatewayTimeout:   "บริการที่เกี่ยวข้องไม่ตอบสนองภายในเวลาที่กำหนด",
+
+	InvalidRequestBody: "ข้อมูลคำขอไม่ถูกต้อง",
+	InvalidQuery:       "พารามิเตอร์ของคำขอไม่ถูกต้อง",
+	BodyNotJSON:        "ข้อมูลคำขอไม่ใช่ JSON ที่ถูกต้อง",
+	BodyNotSingleValue: "ข้อมูลคำขอต้องเป็นค่า JSON เพียงค่าเดียว",
+	FieldInvalid:       "ไม่ถูกต้อง",
+	FieldWrongType:     "ต้องเป็นชนิด %s",
+	FieldUnknown:       "ไม่ใช่ฟิลด์ที่รู้จัก",
+	FieldString:        "ต้องเป็นข้อความ",
+	FieldBool:          "ต้องเป็น true หรือ false",
+	FieldInteger:       "ต้องเป็นจำนวนเต็ม",
+	FieldNumber:        "ต้องเป็นตัวเลข",
+	FieldArray:         "ต้องเป็นอาร์เรย์",
+	FieldObject:        "ต้องเป็นออบเจ็กต์",
+	FieldFailedCheck:   "ไม่ผ่านการตรวจสอบ %s",
+
+	MissingToken:        "ไม่พบโทเค็นเข้าสู่ระบบหรือรูปแบบไม่ถูกต้อง",
+	InvalidToken:        "โทเค็นไม่ถูกต้อง",
+	TokenRevoked:        "โทเค็นถูกเพิกถอนแล้ว",
+	MissingServiceToken: "ไม่พบโทเค็นบริการหรือรูปแบบไม่ถูกต้อง",
+	InvalidServiceToken: "โทเค็นบริการไม่ถูกต้อง",
+	InsufficientRole:    "บทบาทของคุณไม่มีสิทธิ์ดำเนินการนี้",
+
+	MissingPaymentID:        "ไม่ได้ระบุรหัสการชำระเงิน",
+	MissingAttemptID:        "ไม่ได้ระบุรหัสรายการชำระเงิน",
+	MissingPaymentInfoID:    "ไม่ได้ระบุรหัสข้อมูลการชำระเงิน",
+	MissingPaymentMethod:    "ไม่ได้ระบุวิธีการชำระเงิน",
+	AttemptIDRequired:       "ต้องระบุรหัสรายการชำระเงิน",
+	StatusRequired:          "ต้องระบุสถานะ",
+	PayableTypeRequired:     "ต้องระบุประเภทรายการที่ต้องชำระ",
+	PayablesRequired:        "ต้องระบุรายการที่ต้องชำระอย่างน้อยหนึ่งรายการ",
+	TooManyPayables:         "ค้นหารายการที่ต้องชำระได้ครั้งละไม่เกิน %d รายการ",
+	InvalidPaymentID:        "รหัสการชำระเงินไม่ถูกต้อง",
+	InvalidAttemptID:        "รหัสรายการชำระเงินไม่ถูกต้อง",
+	InvalidPaymentInfoID:    "รหัสข้อมูลการชำระเงินไม่ถูกต้อง",
+	InvalidPayableID:        "รหัสรายการที่ต้องชำระไม่ถูกต้อง",
+	InvalidPayableIDOf:      "รหัสรายการที่ต้องชำระ %s ไม่ถูกต้อง",
+	InvalidOrderID:          "รหัสคำสั่งซื้อไม่ถูกต้อง",
+	InvalidDocumentID:       "รหัสเอกสารไม่ถูกต้อง",
+	InvalidRiskReviewID:     "รหัสรายการตรวจสอบความเสี่ยงไม่ถูกต้อง",
+	InvalidExportJobID:      "รหัสงานส่งออกข้อมูลไม่ถูกต้อง",
+	InvalidUserID:           "รหัสผู้ใช้ไม่ถูกต้อง",
+	InvalidField:            "%s ไม่ถูกต้อง",
+	InvalidCursor:           "ตำแหน่งหน้าถัดไป (cursor) ไม่ถูกต้อง",
+	InvalidSort:             "sort ต้องเป็น asc หรือ desc",
+	InvalidTime:             "%s ต้องเป็นเวลาในรูปแบบ RFC 3339",
+	InvalidTimeOrDate:       "%s ต้องเป็นเวลาในรูปแบบ RFC 3339 หรือวันที่ในรูปแบบ YYYY-MM-DD",
+	InvalidPeriod:           "from ต้องมาก่อน to",
+	InvalidYear:             "ปีไม่ถูกต้อง",
+	InvalidPaymentMethod:    "วิธีการชำระเงินไม่ถูกต้อง",
+	InvalidPaymentDetails:   "รายละเอียดการชำระเงินไม่ถูกต้อง",
+	InvalidPaymentStatus:    "สถานะการชำระเงินไม่ถูกต้อง",
+	InvalidReceivableStatus: "สถานะยอดค้างรับไม่ถูกต้อง",
+	InvalidDocumentType:     "ประเภทเอกสารไม่ถูกต้อง",
+	InvalidAuditAction:      "ประเภทการดำเนินการในบันทึกการตรวจสอบไม่ถูกต้อง",
+	InvalidExportFormat:     "format ต้องเป็น csv หรือ xlsx",
+	InvalidExportLocale:     "locale ต้องเป็น en หรือ th",
+	UnknownExportColumn:     "ไม่รู้จักคอลัมน์ %s",
+	InvalidRevocation:       "ต้องระบุ jti หรือ user_id อย่างใดอย่างหนึ่งเท่านั้น",
+	InvalidIfMatch:          "If-Match ต้องเป็น ETag ที่ได้รับจาก API นี้",
+	VersionRequired:         "กรุณาส่ง If-Match หรือ version ของข้อมูลที่ใช้เป็นฐานในการแก้ไข",
+	UnsupportedPayableType:  "ไม่รองรับประเภทรายการที่ต้องชำระนี้",
+	UnsupportedPayableOf:    "ไม่รองรับประเภทรายการที่ต้องชำระ %s",
+
+	NegativeAmount:        "จำนวนเงินต้องไม่ติดลบ",
+	AmountMismatch:        "จำนวนเงินไม่ตรงกับยอดที่ต้องชำระ",
+	NegativeUnitPrice:     "เฉพาะรายการส่วนลดเท่านั้นที่มีราคาต่อหน่วยติดลบได้",
+	NegativeLineItems:     "ยอดรวมของรายการต้องไม่ติดลบ",
+	LineItemsMismatch:     "ยอดรวมของรายการไม่ตรงกับจำนวนเงินที่เรียกเก็บ",
+	AttemptNotSuccessful:  "สร้างการชำระเงินได้เฉพาะจากรายการชำระเงินที่สำเร็จแล้วเท่านั้น",
+	AttemptAlreadyPaid:    "รายการชำระเงินนี้ได้รับการชำระแล้ว",
+	AttemptDeclined:       "รายการชำระเงินถูกปฏิเสธ",
+	PayableNotOwned:       "รายการที่ต้องชำระนี้ไม่ใช่ของผู้ใช้ปัจจุบัน",
+	PaymentNotOwned:       "การชำระเงินนี้ไม่ใช่ของผู้ใช้ปัจจุบัน",
+	PaymentInfoNotOwned:   "ข้อมูลการชำระเงินนี้ไม่ใช่ของผู้ใช้ปัจจุบัน",
+	ReceiptExists:         "ได้ออกใบเสร็จรับเงินสำหรับการชำระเงินนี้แล้ว",
+	ReceiptNotIssued:      "ยังไม่ได้ออกใบเสร็จรับเงินสำหรับการชำระเงินนี้",
+	TaxInvoiceExists:      "ได้ออกใบกำกับภาษีสำหรับการชำระเงินนี้แล้ว",
+	TaxInvoiceNotIssued:   "ยังไม่ได้ออกใบกำกับภาษีสำหรับการชำระเงินนี้",
+	DocumentAlreadyVoided: "เอกสารนี้ถูกยกเลิกไปแล้ว",
+	RiskReviewClosed:      "รายการตรวจสอบความเสี่ยงนี้ปิดไปแล้ว",
+	ExportNotReady:        "การส่งออกข้อมูลยังไม่พร้อม (สถานะ %s)",
+	AttemptChanged:        "รายการชำระเงินถูกแก้ไขโดยผู้อื่น กรุณาโหลดใหม่แล้วลองอีกครั้ง",
+	AttemptInReview:       "รายการชำระเงินนี้รอการตรวจสอบความเสี่ยง กรุณาอนุมัติหรือปฏิเสธผลการตรวจสอบแทน",
+	PaymentInfoChanged:    "ข้อมูลการชำระเงินถูกแก้ไขโดยผู้อื่น กรุณาโหลดใหม่แล้วลองอีกครั้ง",
+	PaymentMethodSaved:    "มีการบันทึกวิธีชำระเงินประเภทนี้ไว้แล้ว กรุณาแก้ไขรายการเดิมแทน",
+	RefundNotPositive:     "จำนวนเงินคืนต้องมากกว่าศูนย์",
+	RefundExceedsPaid:     "ยอดเงินคืนรวมต้องไม่เกินยอดที่ชำระ",
+
+	PaymentNotFound:        "ไม่พบการชำระเงิน",
+	OrderNotFound:          "ไม่พบคำสั่งซื้อ",
+	AttemptNotFound:        "ไม่พบรายการชำระเงิน",
+	PaymentInfoNotFound:    "ไม่พบข้อมูลการชำระเงิน",
+	DocumentNotFound:       "ไม่พบเอกสาร",
+	PatientProfileNotFound: "ไม่พบข้อมูลผู้ป่วย",
+	RiskReviewNotFound:     "ไม่พบรายการตรวจสอบความเสี่ยง",
+	ExportJobNotFound:      "ไม่พบงานส่งออกข้อมูล",
+	ExportExpired:          "ไฟล์ส่งออกข้อมูลหมดอายุแล้ว",
+
+	FailedCreatePayment:          "ไม่สามารถสร้างการชำระเงินได้",
+	FailedCreateRefund:           "ไม่สามารถบันทึกการคืนเงินได้",
+	FailedRetrieveRefunds:        "ไม่สามารถดึงข้อมูลการคืนเงินได้",
+	FailedCreateAttempt:          "ไม่สามารถสร้างรายการชำระเงินได้",
+	FailedCreatePaymentInfo:      "ไม่สามารถบันทึกข้อมูลการชำระเงินได้",
+	FailedUpdateAttempt:          "ไม่สามารถปรับปรุงรายการชำระเงินได้",
+	FailedUpdatePaymentInfo:      "ไม่สามารถปรับปรุงข้อมูลการชำระเงินได้",
+	FailedDeletePaymentInfo:      "ไม่สามารถลบข้อมูลการชำระเงินได้",
+	FailedRetrievePayment:        "ไม่สามารถดึงข้อมูลการชำระเงินได้",
+	FailedRetrievePayments:       "ไม่สามารถดึงรายการการชำระเงินได้",
+	FailedRetrievePaymentStatus:  "ไม่สามารถดึงสถานะการชำระเงินได้",
+	FailedRetrieveAttempt:        "ไม่สามารถดึงรายการชำระเงินได้",
+	FailedRetrieveAttempts:       "ไม่สามารถดึงรายการชำระเงินทั้งหมดได้",
+	FailedRetrievePaymentInfo:    "ไม่สามารถดึงข้อมูลวิธีการชำระเงินได้",
+	FailedRetrieveLineItems:      "ไม่สามารถดึงรายการค่าใช้จ่ายได้",
+	FailedRetrieveDocument:       "ไม่สามารถดึงเอกสารได้",
+	FailedRetrieveDocuments:      "ไม่สามารถดึงรายการเอกสารได้",
+	FailedRetrieveAuditLog:       "ไม่สามารถดึงบันทึกการตรวจสอบได้",
+	FailedRetrieveCoverageRules:  "ไม่สามารถดึงกฎความคุ้มครองได้",
+	FailedRetrieveReceivables:    "ไม่สามารถดึงรายการยอดค้างรับได้",
+	FailedRetrieveRiskReview:     "ไม่สามารถดึงรายการตรวจสอบความเสี่ยงได้",
+	FailedRetrieveRiskReviews:    "ไม่สามารถดึงรายการตรวจสอบความเสี่ยงทั้งหมดได้",
+	FailedRetrieveEntitlements:   "ไม่สามารถดึงข้อมูลสิทธิการรักษาพยาบาลได้",
+	FailedRetrievePatientProfile: "ไม่สามารถดึงข้อมูลผู้ป่วยได้",
+	FailedRetrieveDoctors:        "ไม่สามารถดึงข้อมูลแพทย์ได้",
+	FailedResolvePayable:         "ไม่สามารถค้นหารายการที่ต้องชำระได้",
+	FailedResolveOrder:           "ไม่สามารถค้นหาคำสั่งซื้อได้",
+	FailedIssueReceipt:           "ไม่สามารถออกใบเสร็จรับเงินได้",
+	FailedIssueTaxInvoice:        "ไม่สามารถออกใบกำกับภาษีได้",
+	FailedReissueDocument:        "ไม่สามารถออกเอกสารฉบับแทนได้",
+	FailedVoidDocument:           "ไม่สามารถยกเลิกเอกสารได้",
+	FailedRenderDocument:         "ไม่สามารถสร้างไฟล์เอกสารได้",
+	FailedOpenExport:             "ไม่สามารถเปิดไฟล์ส่งออกข้อมูลได้",
+	FailedRevokeToken:            "ไม่สามารถเพิกถอนโทเค็นได้",
+	FailedCloseRiskReview:        "ไม่สามารถปิดรายการตรวจสอบความเสี่ยงได้",
+	FailedCountUserAttempts:      "ไม่สามารถนับรายการชำระเงินของผู้ใช้ได้",
+	FailedCountCardAttempts:      "ไม่สามารถนับรายการชำระเงินของบัตรได้",
+	FailedCountClientAttempts:    "ไม่สามารถนับรายการชำระเงินจากอุปกรณ์นี้ได้",
+	FailedLookUpCard:             "ไม่สามารถค้นหาข้อมูลบัตรได้",
+	FailedLockRiskSubjects:       "ไม่สามารถล็อกรายการชำระเงินเพื่อตรวจสอบความเสี่ยงได้",
+	FailedCreateRequest:          "ไม่สามารถสร้างคำขอได้",
+	FailedMarshalRequest:         "ไม่สามารถเตรียมข้อมูลคำขอได้",
+	UpstreamError:                "%s ปฏิเสธคำขอ",
+	UpstreamMissingToken:         "ไม่มีโทเค็นสำหรับเรียก %s",
+	UpstreamServiceToken:         "ไม่สามารถออกโทเค็นบริการสำหรับ %s ได้",
+	UpstreamUnavailable:          "%s ไม่พร้อมใช้งานชั่วคราว",
+	UpstreamTimeout:              "%s ไม่ตอบสนองภายในเวลาที่กำหนด",
+	UpstreamUnreachable:          "ไม่สามารถติดต่อ %s ได้",
+	UpstreamBadResponse:          "ไม่สามารถอ่านคำตอบจาก %s ได้",
+
+	DocReceipt:       "ใบเสร็จรับเงิน",
+	DocTaxInvoice:    "ใบกำกับภาษี / ใบเสร็จรับเงิน",
+	DocTaxID:         "เลขประจำตัวผู้เสียภาษี",
+	DocBranch:        "สาขา",
+	DocHeadOffice:    "สำนักงานใหญ่",
+	DocNumber:        "เลขที่",
+	DocDate:          "วันที่",
+	DocReplaces:      "ออกแทนฉบับเดิม",
+	DocBuyer:         "ผู้ซื้อ",
+	DocAddress:       "ที่อยู่",
+	DocDescription:   "รายการ",
+	DocQuantity:      "จำนวน",
+	DocUnitPrice:     "ราคา",
+	DocAmount:        "จำนวนเงิน",
+	DocServiceCharge: "ค่าบริการ",
+	DocSubtotal:      "มูลค่าก่อนภาษี",
+	DocVat:           "ภาษีมูลค่าเพิ่ม %s%%",
+	DocTotal:         "รวมทั้งสิ้น",
+	DocCoveredBy:     "ส่วนที่สิทธิการรักษาคุ้มครอง",
+	DocPaidByPatient: "ผู้ป่วยชำระ",
+	DocVoid:          "ยกเลิก",
+}
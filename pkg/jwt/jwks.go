@@ -0,0 +1,211 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"payment-service/pkg/clients"
+)
+
+var ErrUnknownKey = errors.New("no key in the key set matches the token")
+
+// minUnknownKidRefresh stops tokens with made-up key IDs from making every
+// request fetch the key set again.
+const minUnknownKidRefresh = 30 * time.Second
+
+// KeySet holds the public keys of a JWKS document, read from a file or
+// fetched from an http(s) URL with the shared client. Run reloads it in the
+// background, so verifying a token never waits on the identity provider.
+type KeySet struct {
+	source  string
+	refresh time.Duration
+	client  *clients.HttpClient
+	// unknownKid asks Run for an early reload, as when the provider has
+	// rotated to a key we have not loaded yet
+	unknownKid chan struct{}
+
+	mu       sync.RWMutex
+	keys     map[string]crypto.PublicKey
+	loadedAt time.Time
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+// NewKeySet loads the key set once so that a bad source fails at startup.
+// An http(s) source is fetched through client.
+func NewKeySet(ctx context.Context, source string, refresh time.Duration, client *clients.HttpClient) (*KeySet, error) {
+	ks := &KeySet{
+		source:     source,
+		refresh:    refresh,
+		client:     client,
+		unknownKid: make(chan struct{}, 1),
+	}
+	if err := ks.load(ctx); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+// Key returns the public key with the given key ID. It only reads what is
+// loaded; an unknown ID asks Run to reload.
+func (ks *KeySet) Key(kid string) (crypto.PublicKey, error) {
+	ks.mu.RLock()
+	key, ok := ks.keys[kid]
+	ks.mu.RUnlock()
+
+	if !ok {
+		select {
+		case ks.unknownKid <- struct{}{}:
+		default:
+		}
+		return nil, ErrUnknownKey
+	}
+	return key, nil
+}
+
+// Run reloads the key set every refresh interval, and early when a token
+// names an unknown key, until ctx is done. A failed reload keeps serving
+// the keys already loaded.
+func (ks *KeySet) Run(ctx context.Context) {
+	ticker := time.NewTicker(ks.refresh)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-ks.unknownKid:
+			ks.mu.RLock()
+			age := time.Since(ks.loadedAt)
+			ks.mu.RUnlock()
+			if age < minUnknownKidRefresh {
+				continue
+			}
+		}
+		if err := ks.load(ctx); err != nil {
+			log.Printf("jwks: refresh from %s failed: %v", ks.source, err)
+		}
+	}
+}
+
+func (ks *KeySet) load(ctx context.Context) error {
+	var doc jsonWebKeySet
+	if err := ks.read(ctx, &doc); err != nil {
+		return err
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(doc.Keys))
+	for _, jwk := range doc.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			return fmt.Errorf("jwks: key %q: %w", jwk.Kid, err)
+		}
+		keys[jwk.Kid] = key
+	}
+
+	ks.mu.Lock()
+	ks.keys = keys
+	ks.loadedAt = time.Now()
+	ks.mu.Unlock()
+	return nil
+}
+
+func (ks *KeySet) read(ctx context.Context, doc *jsonWebKeySet) error {
+	if !strings.HasPrefix(ks.source, "http://") && !strings.HasPrefix(ks.source, "https://") {
+		raw, err := os.ReadFile(ks.source)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(raw, doc); err != nil {
+			return fmt.Errorf("jwks: decode %s: %w", ks.source, err)
+		}
+		return nil
+	}
+
+	if err := ks.client.Do(ctx, http.MethodGet, "", nil, doc, clients.Public()); err != nil {
+		return fmt.Errorf("jwks: fetch %s: %w", ks.source, err)
+	}
+	return nil
+}
+
+func (jwk jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("rsa exponent out of range")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on the curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
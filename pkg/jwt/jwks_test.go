@@ -0,0 +1,280 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"payment-service/pkg/clients"
+	"payment-service/pkg/constants"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwksServer serves the public halves of keys, by key ID, as a JWKS
+// document and counts how often it is fetched.
+type jwksServer struct {
+	*httptest.Server
+	fetches atomic.Int32
+
+	mu   sync.Mutex
+	keys map[string]crypto.Signer
+}
+
+func newJWKSServer(t *testing.T, keys map[string]crypto.Signer) *jwksServer {
+	t.Helper()
+	s := &jwksServer{keys: keys}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.fetches.Add(1)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		var doc jsonWebKeySet
+		for kid, key := range s.keys {
+			doc.Keys = append(doc.Keys, toJWK(kid, key.Public()))
+		}
+		json.NewEncoder(w).Encode(doc)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *jwksServer) rotate(keys map[string]crypto.Signer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+}
+
+func toJWK(kid string, public crypto.PublicKey) jsonWebKey {
+	encode := func(n *big.Int) string { return base64.RawURLEncoding.EncodeToString(n.Bytes()) }
+	switch key := public.(type) {
+	case *rsa.PublicKey:
+		return jsonWebKey{Kty: "RSA", Kid: kid, Use: "sig", N: encode(key.N), E: encode(big.NewInt(int64(key.E)))}
+	case *ecdsa.PublicKey:
+		return jsonWebKey{Kty: "EC", Kid: kid, Use: "sig", Crv: "P-256", X: encode(key.X), Y: encode(key.Y)}
+	}
+	panic("unsupported key")
+}
+
+func newRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func newECKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func newTestKeySet(t *testing.T, source string) *KeySet {
+	t.Helper()
+	ks, err := NewKeySet(context.Background(), source, time.Hour, clients.NewHttpClient("jwks", source, clients.DefaultHttpClientConfig()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ks
+}
+
+// signAs signs claims the way an identity provider would.
+func signAs(t *testing.T, method jwt.SigningMethod, kid string, key crypto.Signer, claims JwtClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func userClaims(iss string, aud []string, exp time.Time) JwtClaims {
+	return JwtClaims{
+		UserID: userID,
+		Role:   constants.RolePatient,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    iss,
+			Audience:  aud,
+			ExpiresAt: jwt.NewNumericDate(exp),
+		},
+	}
+}
+
+func TestParseAsymmetric(t *testing.T) {
+	rsaKey, ecKey := newRSAKey(t), newECKey(t)
+	server := newJWKSServer(t, map[string]crypto.Signer{"rsa": rsaKey, "ec": ecKey})
+	s := newTestService()
+	s.Keys = newTestKeySet(t, server.URL)
+	claims := userClaims("", nil, time.Now().Add(time.Minute))
+
+	tests := []struct {
+		name  string
+		token string
+		ok    bool
+	}{
+		{"RS256", signAs(t, jwt.SigningMethodRS256, "rsa", rsaKey, claims), true},
+		{"ES256", signAs(t, jwt.SigningMethodES256, "ec", ecKey, claims), true},
+		{"RS256 by another key", signAs(t, jwt.SigningMethodRS256, "rsa", newRSAKey(t), claims), false},
+		{"ES256 under the RSA key's ID", signAs(t, jwt.SigningMethodES256, "rsa", ecKey, claims), false},
+		{"unknown key ID", signAs(t, jwt.SigningMethodRS256, "other", rsaKey, claims), false},
+		{"RS384", signAs(t, jwt.SigningMethodRS384, "rsa", rsaKey, claims), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Parse(tt.token)
+			if tt.ok && (err != nil || got.UserID != userID) {
+				t.Fatalf("got %v, %v", got, err)
+			}
+			if !tt.ok && err == nil {
+				t.Fatal("token accepted")
+			}
+		})
+	}
+
+	t.Run("only HS256 without a key set", func(t *testing.T) {
+		if _, err := newTestService().Parse(tests[0].token); err == nil {
+			t.Fatal("RS256 token accepted")
+		}
+	})
+}
+
+func TestParseIssuerAndAudience(t *testing.T) {
+	key := newRSAKey(t)
+	s := newTestService()
+	s.Keys = newTestKeySet(t, newJWKSServer(t, map[string]crypto.Signer{"rsa": key}).URL)
+	s.Issuer, s.Audience = "https://idp.example", "payment-service"
+	exp := time.Now().Add(time.Minute)
+
+	tests := []struct {
+		name   string
+		claims JwtClaims
+		ok     bool
+	}{
+		{"matching", userClaims("https://idp.example", []string{"payment-service"}, exp), true},
+		{"one of several audiences", userClaims("https://idp.example", []string{"order-service", "payment-service"}, exp), true},
+		{"other issuer", userClaims("https://evil.example", []string{"payment-service"}, exp), false},
+		{"no issuer", userClaims("", []string{"payment-service"}, exp), false},
+		{"other audience", userClaims("https://idp.example", []string{"order-service"}, exp), false},
+		{"no audience", userClaims("https://idp.example", nil, exp), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.Parse(signAs(t, jwt.SigningMethodRS256, "rsa", key, tt.claims))
+			if (err == nil) != tt.ok {
+				t.Fatalf("got %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+}
+
+func TestParseLeeway(t *testing.T) {
+	key := newECKey(t)
+	s := newTestService()
+	s.Keys = newTestKeySet(t, newJWKSServer(t, map[string]crypto.Signer{"ec": key}).URL)
+	s.Leeway = 30 * time.Second
+	now := time.Now()
+
+	notYet := userClaims("", nil, now.Add(time.Minute))
+	notYet.NotBefore = jwt.NewNumericDate(now.Add(10 * time.Second))
+	tooEarly := userClaims("", nil, now.Add(time.Minute))
+	tooEarly.NotBefore = jwt.NewNumericDate(now.Add(time.Minute))
+
+	tests := []struct {
+		name   string
+		claims JwtClaims
+		ok     bool
+	}{
+		{"expired within the leeway", userClaims("", nil, now.Add(-10*time.Second)), true},
+		{"expired beyond the leeway", userClaims("", nil, now.Add(-time.Minute)), false},
+		{"not yet valid within the leeway", notYet, true},
+		{"not yet valid beyond the leeway", tooEarly, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.Parse(signAs(t, jwt.SigningMethodES256, "ec", key, tt.claims))
+			if (err == nil) != tt.ok {
+				t.Fatalf("got %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+}
+
+func TestKeySetRefreshesInTheBackground(t *testing.T) {
+	oldKey, newKey := newRSAKey(t), newRSAKey(t)
+	server := newJWKSServer(t, map[string]crypto.Signer{"old": oldKey})
+	ks := newTestKeySet(t, server.URL)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go ks.Run(ctx)
+
+	server.rotate(map[string]crypto.Signer{"old": oldKey, "new": newKey})
+
+	// a key set loaded moments ago is not fetched again for a made-up key ID
+	if _, err := ks.Key("new"); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("got %v, want an unknown key", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if fetches := server.fetches.Load(); fetches != 1 {
+		t.Fatalf("fetched %d times, want once", fetches)
+	}
+
+	ks.mu.Lock()
+	ks.loadedAt = time.Now().Add(-time.Minute)
+	ks.mu.Unlock()
+	if _, err := ks.Key("new"); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("got %v, want an unknown key until the reload", err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, err := ks.Key("new"); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("rotated key never loaded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// the source going away keeps the loaded keys
+	server.Close()
+	ks.mu.Lock()
+	ks.loadedAt = time.Now().Add(-time.Minute)
+	ks.mu.Unlock()
+	ks.Key("missing")
+	time.Sleep(50 * time.Millisecond)
+	if _, err := ks.Key("old"); err != nil {
+		t.Fatalf("lost the loaded keys: %v", err)
+	}
+}
+
+func TestNewKeySetFailsOnABadSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"keys":[{"kty":"EC","kid":"bad","crv":"P-256","x":"AQ","y":"AQ"}]}`))
+	}))
+	defer server.Close()
+
+	for _, source := range []string{server.URL, "/does/not/exist.json"} {
+		if _, err := NewKeySet(context.Background(), source, time.Hour, clients.NewHttpClient("jwks", source, clients.DefaultHttpClientConfig())); err == nil {
+			t.Errorf("%s: loaded", source)
+		}
+	}
+}
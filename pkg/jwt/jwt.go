@@ -1,26 +1,59 @@
 package jwt
 
 import (
+	"errors"
 	"time"
 
+	"payment-service/pkg/constants"
+	"payment-service/pkg/utils"
+
 	"github.com/golang-jwt/jwt/v5"
 )
 
 type JwtService struct {
 	SecretKey []byte
 	TTL       int
+	// ServiceName identifies this service as issuer and audience of
+	// service tokens
+	ServiceName string
+	// ServiceKey signs and verifies service tokens. It differs from
+	// SecretKey, so neither kind of token verifies as the other.
+	ServiceKey []byte
+	ServiceTTL int
+	// Issuer and Audience, when set, are required in user tokens
+	Issuer   string
+	Audience string
+	Leeway   time.Duration
+	// MaxLifetime, when set, rejects user tokens meant to live longer from
+	// iat to exp, so a revocation kept that long outlives what it denies
+	MaxLifetime time.Duration
+	// Keys verifies RS256 and ES256 tokens; without it only HS256 is accepted
+	Keys *KeySet
+	// Revocations, when set, rejects user tokens revoked before they expire
+	Revocations RevocationChecker
+}
+
+// RevocationChecker reports whether a token was revoked by its jti or
+// because every token of its user was.
+type RevocationChecker interface {
+	IsRevoked(claims *JwtClaims) bool
 }
 
+var ErrTokenRevoked = errors.New("token has been revoked")
+
 type JwtClaims struct {
 	UserID string `json:"user_id"`
 	Role   string `json:"role"`
 	jwt.RegisteredClaims
 }
 
-func NewJwtService(secretKey string, ttl int) *JwtService {
+func NewJwtService(secretKey string, ttl int, serviceName string, serviceKey string, serviceTTL int) *JwtService {
 	return &JwtService{
-		SecretKey: []byte(secretKey),
-		TTL:       ttl,
+		SecretKey:   []byte(secretKey),
+		TTL:         ttl,
+		ServiceName: serviceName,
+		ServiceKey:  []byte(serviceKey),
+		ServiceTTL:  serviceTTL,
 	}
 }
 
@@ -31,28 +64,106 @@ func (s *JwtService) GenerateToken(userID, role string) (string, error) {
 		UserID: userID,
 		Role:   role,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        utils.GenerateUUIDv7().String(),
+			Issuer:    s.Issuer,
 			IssuedAt:  jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(now.Add(time.Duration(s.TTL) * time.Second)),
 		},
 	}
+	if s.Audience != "" {
+		claims.Audience = jwt.ClaimStrings{s.Audience}
+	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
 	return token.SignedString(s.SecretKey)
 }
 
+// ServiceToken mints a short-lived token for calling the service named by
+// audience on behalf of this service rather than of a user.
+func (s *JwtService) ServiceToken(audience string) (string, error) {
+	now := time.Now()
+	claims := JwtClaims{
+		UserID: s.ServiceName,
+		Role:   constants.RoleService,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        utils.GenerateUUIDv7().String(),
+			Issuer:    s.ServiceName,
+			Subject:   s.ServiceName,
+			Audience:  jwt.ClaimStrings{audience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Duration(s.ServiceTTL) * time.Second)),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+
+	return token.SignedString(s.ServiceKey)
+}
+
+// Parse accepts only user tokens; service tokens go through
+// ParseServiceToken.
 func (s *JwtService) Parse(tokenString string) (*JwtClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &JwtClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return s.SecretKey, nil
-	})
+	methods := []string{jwt.SigningMethodHS256.Alg()}
+	if s.Keys != nil {
+		methods = append(methods, jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg())
+	}
+
+	options := []jwt.ParserOption{jwt.WithValidMethods(methods), jwt.WithLeeway(s.Leeway)}
+	if s.Issuer != "" {
+		options = append(options, jwt.WithIssuer(s.Issuer))
+	}
+	if s.Audience != "" {
+		options = append(options, jwt.WithAudience(s.Audience))
+	}
+	if s.MaxLifetime > 0 {
+		options = append(options, jwt.WithExpirationRequired())
+	}
+
+	token, err := jwt.ParseWithClaims(tokenString, &JwtClaims{}, s.userTokenKey, options...)
 
 	if err != nil {
 		return nil, err
 	}
 
-	if claims, ok := token.Claims.(*JwtClaims); ok && token.Valid {
-		return claims, nil
+	claims, ok := token.Claims.(*JwtClaims)
+	if !ok || !token.Valid || claims.Role == constants.RoleService {
+		return nil, jwt.ErrTokenInvalidClaims
+	}
+	if s.MaxLifetime > 0 && (claims.IssuedAt == nil || claims.ExpiresAt.Sub(claims.IssuedAt.Time) > s.MaxLifetime) {
+		return nil, jwt.ErrTokenInvalidClaims
+	}
+
+	if s.Revocations != nil && s.Revocations.IsRevoked(claims) {
+		return nil, ErrTokenRevoked
+	}
+	return claims, nil
+}
+
+// userTokenKey picks the verification key by algorithm, so the shared
+// secret is never used to check an asymmetric token or the reverse.
+func (s *JwtService) userTokenKey(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		return s.SecretKey, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	return s.Keys.Key(kid)
+}
+
+// ParseServiceToken accepts only service tokens addressed to this service.
+func (s *JwtService) ParseServiceToken(tokenString string) (*JwtClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &JwtClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return s.ServiceKey, nil
+	}, jwt.WithAudience(s.ServiceName), jwt.WithExpirationRequired(), jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, jwt.ErrTokenInvalidClaims
+	claims, ok := token.Claims.(*JwtClaims)
+	if !ok || !token.Valid || claims.Role != constants.RoleService {
+		return nil, jwt.ErrTokenInvalidClaims
+	}
+	return claims, nil
 }
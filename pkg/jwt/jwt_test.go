@@ -0,0 +1,142 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+
+	"payment-service/pkg/constants"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const userID = "0192a5f4-7c3e-7000-8000-000000000001"
+
+func newTestService() *JwtService {
+	return NewJwtService("secret", 60, "payment-service", "service-secret", 60)
+}
+
+// sign signs claims with key, for tokens the service itself would never mint.
+func sign(t *testing.T, claims JwtClaims, key []byte) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestParseRejectsServiceTokens(t *testing.T) {
+	for _, audience := range []string{"", "payment-service"} {
+		t.Run("audience "+audience, func(t *testing.T) {
+			s := newTestService()
+			s.Audience = audience
+
+			serviceToken, err := s.ServiceToken("payment-service")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := s.Parse(serviceToken); err == nil {
+				t.Errorf("service token accepted as a user token")
+			}
+
+			// a service role signed with the user secret is forged
+			forged := sign(t, JwtClaims{
+				UserID: "order-service",
+				Role:   constants.RoleService,
+				RegisteredClaims: jwt.RegisteredClaims{
+					Audience:  jwt.ClaimStrings{"payment-service"},
+					ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+				},
+			}, s.SecretKey)
+			if _, err := s.Parse(forged); err == nil {
+				t.Errorf("user-signed service role accepted by Parse")
+			}
+			if _, err := s.ParseServiceToken(forged); err == nil {
+				t.Errorf("user-signed service role accepted by ParseServiceToken")
+			}
+
+			userToken, err := s.GenerateToken(userID, constants.RoleAdmin)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if claims, err := s.Parse(userToken); err != nil || claims.UserID != userID {
+				t.Errorf("user token: got %v, %v", claims, err)
+			}
+		})
+	}
+}
+
+func TestParseServiceToken(t *testing.T) {
+	s := newTestService()
+
+	own, err := s.ServiceToken("payment-service")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims, err := s.ParseServiceToken(own); err != nil || claims.Role != constants.RoleService {
+		t.Errorf("own service token: got %v, %v", claims, err)
+	}
+
+	other, err := s.ServiceToken("order-service")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.ParseServiceToken(other); err == nil {
+		t.Errorf("token for another service accepted")
+	}
+
+	user, err := s.GenerateToken(userID, constants.RoleAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.ParseServiceToken(user); err == nil {
+		t.Errorf("user token accepted as a service token")
+	}
+}
+
+func TestParseMaxLifetime(t *testing.T) {
+	s := newTestService()
+	s.MaxLifetime = time.Hour
+	now := time.Now()
+	lifetime := func(issued, expires time.Time) JwtClaims {
+		claims := JwtClaims{UserID: userID, Role: constants.RolePatient}
+		if !issued.IsZero() {
+			claims.IssuedAt = jwt.NewNumericDate(issued)
+		}
+		if !expires.IsZero() {
+			claims.ExpiresAt = jwt.NewNumericDate(expires)
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name   string
+		claims JwtClaims
+		ok     bool
+	}{
+		{"within", lifetime(now, now.Add(time.Hour)), true},
+		{"longer", lifetime(now, now.Add(time.Hour+time.Second)), false},
+		{"issued long ago", lifetime(now.Add(-2*time.Hour), now.Add(time.Minute)), false},
+		{"no iat", lifetime(time.Time{}, now.Add(time.Minute)), false},
+		{"no exp", lifetime(now, time.Time{}), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.Parse(sign(t, tt.claims, s.SecretKey))
+			if (err == nil) != tt.ok {
+				t.Fatalf("got %v, want ok %v", err, tt.ok)
+			}
+		})
+	}
+
+	t.Run("own tokens", func(t *testing.T) {
+		s.TTL = int(s.MaxLifetime / time.Second)
+		token, err := s.GenerateToken(userID, constants.RolePatient)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.Parse(token); err != nil {
+			t.Fatalf("got %v", err)
+		}
+	})
+}
@@ -1,26 +1,36 @@
 package middleware
 
 import (
+	"errors"
+	"slices"
+	"strings"
+
+	"payment-service/pkg/apperr"
+	"payment-service/pkg/i18n"
 	"payment-service/pkg/jwt"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+// JwtMiddleware authenticates users by a bearer token, as mobile clients
+// and server callers send it, or by the access_token cookie browsers carry.
 func JwtMiddleware(jwtService *jwt.JwtService) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 
-		token := c.Cookies("access_token")
+		token, found := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+		if !found || token == "" {
+			token = c.Cookies("access_token")
+		}
 		if token == "" {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": "Missing or malformed JWT",
-			})
+			return unauthorized(c, i18n.MissingToken)
 		}
 
 		claims, err := jwtService.Parse(token)
+		if errors.Is(err, jwt.ErrTokenRevoked) {
+			return unauthorized(c, i18n.TokenRevoked)
+		}
 		if err != nil {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": "Invalid token",
-			})
+			return unauthorized(c, i18n.InvalidToken)
 		}
 
 		c.Locals("userID", claims.UserID)
@@ -30,3 +40,64 @@ func JwtMiddleware(jwtService *jwt.JwtService) fiber.Handler {
 		return c.Next()
 	}
 }
+
+// ServiceTokenMiddleware guards internal routes: only a bearer service token
+// addressed to this service is accepted, never a user's token.
+func ServiceTokenMiddleware(jwtService *jwt.JwtService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		auth := c.Get(fiber.HeaderAuthorization)
+		token, found := strings.CutPrefix(auth, "Bearer ")
+		if !found || token == "" {
+			return unauthorized(c, i18n.MissingServiceToken)
+		}
+
+		claims, err := jwtService.ParseServiceToken(token)
+		if err != nil {
+			return unauthorized(c, i18n.InvalidServiceToken)
+		}
+
+		c.Locals("userID", claims.Subject)
+		c.Locals("role", claims.Role)
+
+		return c.Next()
+	}
+}
+
+// RequireRole lets the request through only when the role set by the
+// authentication middleware is one of roles.
+func RequireRole(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		role, _ := c.Locals("role").(string)
+		if slices.Contains(roles, role) {
+			return c.Next()
+		}
+		return apperr.WriteError(c, apperr.New(apperr.CodeForbidden, i18n.InsufficientRole, nil))
+	}
+}
+
+// ClientCountry takes the client's country from a header set by the edge
+// proxy, such as CF-IPCountry. Only use it behind a proxy that overwrites
+// the header, since clients can send it themselves.
+func ClientCountry(header string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if country := strings.TrimSpace(c.Get(header)); country != "" {
+			c.Locals("clientCountry", strings.ToUpper(country))
+		}
+		return c.Next()
+	}
+}
+
+// Language picks the response language from Accept-Language, English when
+// the client names neither English nor Thai.
+func Language() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Vary(fiber.HeaderAcceptLanguage)
+		lang := c.AcceptsLanguages(i18n.Supported...)
+		c.Locals("lang", i18n.Parse(lang))
+		return c.Next()
+	}
+}
+
+func unauthorized(c *fiber.Ctx, msg i18n.Key) error {
+	return apperr.WriteError(c, apperr.New(apperr.CodeUnauthorized, msg, nil))
+}
@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"payment-service/pkg/constants"
+	"payment-service/pkg/jwt"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const userID = "0192a5f4-7c3e-7000-8000-000000000001"
+
+// newTestApp answers with the user ID the middleware authenticated.
+func newTestApp(auth fiber.Handler) *fiber.App {
+	app := fiber.New()
+	app.Get("/", auth, func(c *fiber.Ctx) error {
+		userID, _ := c.Locals("userID").(string)
+		return c.SendString(userID)
+	})
+	return app
+}
+
+func call(t *testing.T, app *fiber.App, authorization, cookie string) (int, string) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if authorization != "" {
+		req.Header.Set(fiber.HeaderAuthorization, authorization)
+	}
+	if cookie != "" {
+		req.AddCookie(&http.Cookie{Name: "access_token", Value: cookie})
+	}
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
+
+func TestJwtMiddleware(t *testing.T) {
+	jwtService := jwt.NewJwtService("secret", 60, "payment-service", "service-secret", 60)
+	app := newTestApp(JwtMiddleware(jwtService))
+	token, err := jwtService.GenerateToken(userID, constants.RolePatient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serviceToken, err := jwtService.ServiceToken("payment-service")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+		cookie        string
+		status        int
+	}{
+		{"bearer header", "Bearer " + token, "", http.StatusOK},
+		{"cookie", "", token, http.StatusOK},
+		{"header wins over the cookie", "Bearer " + token, "stale", http.StatusOK},
+		{"empty bearer falls back to the cookie", "Bearer ", token, http.StatusOK},
+		{"other scheme falls back to the cookie", "Basic dXNlcjpwYXNz", token, http.StatusOK},
+		{"bearer without a space", "Bearer" + token, "", http.StatusUnauthorized},
+		{"no credentials", "", "", http.StatusUnauthorized},
+		{"garbage", "Bearer not-a-token", "", http.StatusUnauthorized},
+		{"service token", "Bearer " + serviceToken, "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := call(t, app, tt.authorization, tt.cookie)
+			if status != tt.status {
+				t.Fatalf("got %d, want %d", status, tt.status)
+			}
+			if status == http.StatusOK && body != userID {
+				t.Fatalf("authenticated %q", body)
+			}
+		})
+	}
+}
+
+func TestServiceTokenMiddleware(t *testing.T) {
+	jwtService := jwt.NewJwtService("secret", 60, "payment-service", "service-secret", 60)
+	app := newTestApp(ServiceTokenMiddleware(jwtService))
+	serviceToken, err := jwtService.ServiceToken("payment-service")
+	if err != nil {
+		t.Fatal(err)
+	}
+	userToken, err := jwtService.GenerateToken(userID, constants.RoleAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+		cookie        string
+		status        int
+	}{
+		{"bearer service token", "Bearer " + serviceToken, "", http.StatusOK},
+		{"service token in the cookie", "", serviceToken, http.StatusUnauthorized},
+		{"other scheme", "Token " + serviceToken, "", http.StatusUnauthorized},
+		{"user token", "Bearer " + userToken, "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := call(t, app, tt.authorization, tt.cookie)
+			if status != tt.status {
+				t.Fatalf("got %d, want %d", status, tt.status)
+			}
+			if status == http.StatusOK && body != "payment-service" {
+				t.Fatalf("authenticated %q", body)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditAction string
+
+const (
+	AuditActionCreate AuditAction = "create"
+	AuditActionUpdate AuditAction = "update"
+	AuditActionDelete AuditAction = "delete"
+)
+
+func (a AuditAction) Value() (driver.Value, error) {
+	return string(a), nil
+}
+
+func (a *AuditAction) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	*a = AuditAction(value.(string))
+	return nil
+}
+
+// AuditLog records one changed row. Hash covers every other field and
+// PrevHash, the hash of the row before it, so editing or deleting any row
+// breaks the chain from there on.
+type AuditLog struct {
+	ID         uuid.UUID       `db:"id" json:"id"`
+	Seq        int64           `db:"seq" json:"seq" gorm:"->"`
+	EntityType string          `db:"entity_type" json:"entity_type"`
+	EntityID   string          `db:"entity_id" json:"entity_id"`
+	Action     AuditAction     `db:"action" json:"action"`
+	ActorID    *string         `db:"actor_id" json:"actor_id"`
+	ActorRole  *string         `db:"actor_role" json:"actor_role"`
+	RequestID  *string         `db:"request_id" json:"request_id"`
+	ClientIP   *string         `db:"client_ip" json:"client_ip"`
+	Before     json.RawMessage `db:"before" json:"before"`
+	After      json.RawMessage `db:"after" json:"after"`
+	PrevHash   string          `db:"prev_hash" json:"prev_hash"`
+	Hash       string          `db:"hash" json:"hash"`
+	CreatedAt  time.Time       `db:"created_at" json:"created_at"`
+}
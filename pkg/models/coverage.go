@@ -0,0 +1,84 @@
+package models
+
+import (
+	"database/sql/driver"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PayerType represents the payer_type enum
+type PayerType string
+
+const (
+	PayerTypeInsurer    PayerType = "insurer"
+	PayerTypeGovernment PayerType = "government"
+)
+
+// Value implements the driver.Valuer interface
+func (pt PayerType) Value() (driver.Value, error) {
+	return string(pt), nil
+}
+
+// Scan implements the sql.Scanner interface
+func (pt *PayerType) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	*pt = PayerType(value.(string))
+	return nil
+}
+
+// ReceivableStatus represents the receivable_status enum
+type ReceivableStatus string
+
+const (
+	ReceivableStatusAccrued  ReceivableStatus = "accrued"
+	ReceivableStatusInvoiced ReceivableStatus = "invoiced"
+	ReceivableStatusSettled  ReceivableStatus = "settled"
+)
+
+// Value implements the driver.Valuer interface
+func (rs ReceivableStatus) Value() (driver.Value, error) {
+	return string(rs), nil
+}
+
+// Scan implements the sql.Scanner interface
+func (rs *ReceivableStatus) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	*rs = ReceivableStatus(value.(string))
+	return nil
+}
+
+// CoverageRule represents the coverage_rules table. A rule without a
+// category applies to the whole charge, or to line items no other rule of
+// the same entitlement covers.
+type CoverageRule struct {
+	ID                    uuid.UUID         `db:"id" json:"id"`
+	HealthcareEntitlement string            `db:"healthcare_entitlement" json:"healthcare_entitlement"`
+	PayerType             PayerType         `db:"payer_type" json:"payer_type"`
+	PayerName             string            `db:"payer_name" json:"payer_name"`
+	Category              *LineItemCategory `db:"category" json:"category"`
+	CoveragePercent       float64           `db:"coverage_percent" json:"coverage_percent"`
+	MaxCoverage           *float64          `db:"max_coverage" json:"max_coverage"`
+	Priority              int               `db:"priority" json:"priority"`
+	Active                bool              `db:"active" json:"active"`
+	CreatedAt             time.Time         `db:"created_at" json:"created_at"`
+}
+
+// PayerReceivable represents the payer_receivables table: the share of a
+// payment owed by an insurer or government scheme, invoiced later. A
+// payment has one per payer.
+type PayerReceivable struct {
+	ID                    uuid.UUID        `db:"id" json:"id"`
+	PaymentID             uuid.UUID        `db:"payment_id" json:"payment_id"`
+	PayerType             PayerType        `db:"payer_type" json:"payer_type"`
+	PayerName             string           `db:"payer_name" json:"payer_name"`
+	HealthcareEntitlement string           `db:"healthcare_entitlement" json:"healthcare_entitlement"`
+	Amount                float64          `db:"amount" json:"amount"`
+	Status                ReceivableStatus `db:"status" json:"status"`
+	CreatedAt             time.Time        `db:"created_at" json:"created_at"`
+	InvoicedAt            *time.Time       `db:"invoiced_at" json:"invoiced_at"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"database/sql/driver"
+)
+
+// PayableType identifies the kind of billable entity a payment attempt or a
+// payment settles. It is stored as plain text so new billable things can be
+// introduced by registering a resolver, without a schema change.
+type PayableType string
+
+const (
+	PayableTypeOrder       PayableType = "order"
+	PayableTypeAppointment PayableType = "appointment"
+	PayableTypeDelivery    PayableType = "delivery"
+	PayableTypeDeposit     PayableType = "deposit"
+)
+
+// Value implements the driver.Valuer interface
+func (pt PayableType) Value() (driver.Value, error) {
+	return string(pt), nil
+}
+
+// Scan implements the sql.Scanner interface
+func (pt *PayableType) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	*pt = PayableType(value.(string))
+	return nil
+}
@@ -8,9 +8,19 @@ import (
 
 // Payment represents the payments table
 type Payment struct {
-	ID        uuid.UUID `db:"id" json:"id"`
-	AttemptID uuid.UUID `db:"attempt_id" json:"attempt_id"`
-	Amount    float64   `db:"amount" json:"amount"`
-	OrderID   uuid.UUID `db:"order_id" json:"order_id"`
-	PaidAt    time.Time `db:"paid_at" json:"paid_at"`
+	ID          uuid.UUID   `db:"id" json:"id"`
+	AttemptID   uuid.UUID   `db:"attempt_id" json:"attempt_id"`
+	Amount      float64     `db:"amount" json:"amount"`
+	PayableType PayableType `db:"payable_type" json:"payable_type"`
+	PayableID   uuid.UUID   `db:"payable_id" json:"payable_id"`
+	PaidAt      time.Time   `db:"paid_at" json:"paid_at"`
+	LockVersion int         `db:"lock_version" json:"lock_version" gorm:"default:1"`
+
+	// PatientAmount and PayerAmount always add up to Amount
+	PatientAmount         float64 `db:"patient_amount" json:"patient_amount"`
+	PayerAmount           float64 `db:"payer_amount" json:"payer_amount"`
+	HealthcareEntitlement *string `db:"healthcare_entitlement" json:"healthcare_entitlement"`
+
+	LineItems   []PaymentLineItem `gorm:"foreignKey:PaymentID" json:"line_items,omitempty"`
+	Receivables []PayerReceivable `gorm:"foreignKey:PaymentID" json:"receivables,omitempty"`
 }
@@ -32,10 +32,18 @@ func (ps *PaymentStatus) Scan(value interface{}) error {
 
 // PaymentAttempt represents the payment_attempts table
 type PaymentAttempt struct {
-	ID                   uuid.UUID     `db:"id" json:"id"`
-	OrderID              uuid.UUID     `db:"order_id" json:"order_id"`
-	PaymentInformationID *uuid.UUID    `db:"payment_information_id" json:"payment_information_id"`
-	Method               PaymentMethod `db:"method" json:"method"`
-	Status               PaymentStatus `db:"status" json:"status"`
-	CreatedAt            time.Time     `db:"created_at" json:"created_at"`
+	ID                   uuid.UUID   `db:"id" json:"id"`
+	PayableType          PayableType `db:"payable_type" json:"payable_type"`
+	PayableID            uuid.UUID   `db:"payable_id" json:"payable_id"`
+	PaymentInformationID *uuid.UUID  `db:"payment_information_id" json:"payment_information_id"`
+	// UserID is who made the attempt. It outlives the payment information,
+	// and is nil only for old attempts it could not be backfilled for.
+	UserID      *uuid.UUID    `db:"user_id" json:"user_id"`
+	Method      PaymentMethod `db:"method" json:"method"`
+	Status      PaymentStatus `db:"status" json:"status"`
+	LockVersion int           `db:"lock_version" json:"lock_version" gorm:"default:1"`
+	CreatedAt   time.Time     `db:"created_at" json:"created_at"`
+
+	LineItems []PaymentLineItem `gorm:"foreignKey:AttemptID" json:"line_items,omitempty"`
+	Risk      *RiskAssessment   `gorm:"foreignKey:AttemptID" json:"risk,omitempty"`
 }
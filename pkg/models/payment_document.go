@@ -0,0 +1,131 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DocumentType represents the document_type enum
+type DocumentType string
+
+const (
+	DocumentTypeReceipt    DocumentType = "receipt"
+	DocumentTypeTaxInvoice DocumentType = "tax_invoice"
+)
+
+// Value implements the driver.Valuer interface
+func (dt DocumentType) Value() (driver.Value, error) {
+	return string(dt), nil
+}
+
+// Scan implements the sql.Scanner interface
+func (dt *DocumentType) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	*dt = DocumentType(value.(string))
+	return nil
+}
+
+// DocumentStatus represents the document_status enum
+type DocumentStatus string
+
+const (
+	DocumentStatusIssued DocumentStatus = "issued"
+	DocumentStatusVoided DocumentStatus = "voided"
+)
+
+// Value implements the driver.Valuer interface
+func (ds DocumentStatus) Value() (driver.Value, error) {
+	return string(ds), nil
+}
+
+// Scan implements the sql.Scanner interface
+func (ds *DocumentStatus) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	*ds = DocumentStatus(value.(string))
+	return nil
+}
+
+// DocumentAction represents the document_action enum
+type DocumentAction string
+
+const (
+	DocumentActionIssued   DocumentAction = "issued"
+	DocumentActionReissued DocumentAction = "reissued"
+	DocumentActionVoided   DocumentAction = "voided"
+)
+
+// Value implements the driver.Valuer interface
+func (da DocumentAction) Value() (driver.Value, error) {
+	return string(da), nil
+}
+
+// Scan implements the sql.Scanner interface
+func (da *DocumentAction) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	*da = DocumentAction(value.(string))
+	return nil
+}
+
+// PaymentDocument represents the payment_documents table. Seller, buyer and
+// amounts are snapshotted at issue time so a document always reprints the
+// same way; corrections are made by reissuing under a new number.
+type PaymentDocument struct {
+	ID                 uuid.UUID      `db:"id" json:"id"`
+	PaymentID          uuid.UUID      `db:"payment_id" json:"payment_id"`
+	Type               DocumentType   `db:"type" json:"type"`
+	BranchCode         string         `db:"branch_code" json:"branch_code"`
+	Year               int            `db:"year" json:"year"`
+	Sequence           int            `db:"sequence" json:"sequence"`
+	Number             string         `db:"number" json:"number"`
+	Status             DocumentStatus `db:"status" json:"status"`
+	SellerName         string         `db:"seller_name" json:"seller_name"`
+	SellerTaxID        string         `db:"seller_tax_id" json:"seller_tax_id"`
+	SellerAddress      string         `db:"seller_address" json:"seller_address"`
+	BuyerName          string         `db:"buyer_name" json:"buyer_name"`
+	BuyerTaxID         *string        `db:"buyer_tax_id" json:"buyer_tax_id"`
+	BuyerAddress       *string        `db:"buyer_address" json:"buyer_address"`
+	BuyerBranch        *string        `db:"buyer_branch" json:"buyer_branch"`
+	Subtotal           float64        `db:"subtotal" json:"subtotal"`
+	VatRate            float64        `db:"vat_rate" json:"vat_rate"`
+	VatAmount          float64        `db:"vat_amount" json:"vat_amount"`
+	Total              float64        `db:"total" json:"total"`
+	PayerAmount        float64        `db:"payer_amount" json:"payer_amount"`
+	PatientAmount      float64        `db:"patient_amount" json:"patient_amount"`
+	ReplacesDocumentID *uuid.UUID     `db:"replaces_document_id" json:"replaces_document_id"`
+	VoidReason         *string        `db:"void_reason" json:"void_reason"`
+	IssuedAt           time.Time      `db:"issued_at" json:"issued_at"`
+	VoidedAt           *time.Time     `db:"voided_at" json:"voided_at"`
+
+	Events []PaymentDocumentEvent `gorm:"foreignKey:DocumentID" json:"events,omitempty"`
+}
+
+// PaymentDocumentEvent represents the payment_document_events table, the
+// audit trail of who issued, reissued or voided a document and why.
+type PaymentDocumentEvent struct {
+	ID         uuid.UUID      `db:"id" json:"id"`
+	DocumentID uuid.UUID      `db:"document_id" json:"document_id"`
+	Action     DocumentAction `db:"action" json:"action"`
+	ActorID    uuid.UUID      `db:"actor_id" json:"actor_id"`
+	ActorRole  string         `db:"actor_role" json:"actor_role"`
+	Reason     *string        `db:"reason" json:"reason"`
+	CreatedAt  time.Time      `db:"created_at" json:"created_at"`
+}
+
+// DocumentNumber formats the printed document number, e.g.
+// TX-00000-2026-000042 for the 42nd tax invoice of head office in 2026.
+func DocumentNumber(docType DocumentType, branchCode string, year, sequence int) string {
+	prefix := "RC"
+	if docType == DocumentTypeTaxInvoice {
+		prefix = "TX"
+	}
+	return fmt.Sprintf("%s-%s-%d-%06d", prefix, branchCode, year, sequence)
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"database/sql/driver"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LineItemCategory represents the line_item_category enum
+type LineItemCategory string
+
+const (
+	LineItemCategoryMedicine        LineItemCategory = "medicine"
+	LineItemCategoryDeliveryFee     LineItemCategory = "delivery_fee"
+	LineItemCategoryConsultationFee LineItemCategory = "consultation_fee"
+	LineItemCategoryDiscount        LineItemCategory = "discount"
+	LineItemCategoryOther           LineItemCategory = "other"
+)
+
+// Value implements the driver.Valuer interface
+func (c LineItemCategory) Value() (driver.Value, error) {
+	return string(c), nil
+}
+
+// Scan implements the sql.Scanner interface
+func (c *LineItemCategory) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	*c = LineItemCategory(value.(string))
+	return nil
+}
+
+// PaymentLineItem represents the payment_line_items table. A line item
+// belongs either to an attempt or, once copied, to a payment.
+// Discounts are stored with a negative unit price.
+type PaymentLineItem struct {
+	ID          uuid.UUID        `db:"id" json:"id"`
+	AttemptID   *uuid.UUID       `db:"attempt_id" json:"attempt_id"`
+	PaymentID   *uuid.UUID       `db:"payment_id" json:"payment_id"`
+	Description string           `db:"description" json:"description"`
+	Quantity    float64          `db:"quantity" json:"quantity"`
+	UnitPrice   float64          `db:"unit_price" json:"unit_price"`
+	Tax         float64          `db:"tax" json:"tax"`
+	Category    LineItemCategory `db:"category" json:"category"`
+	CreatedAt   time.Time        `db:"created_at" json:"created_at"`
+}
+
+// Total is the amount the line contributes to the charge, tax included.
+func (li *PaymentLineItem) Total() float64 {
+	return li.Quantity*li.UnitPrice + li.Tax
+}
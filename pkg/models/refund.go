@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Refund gives back part or all of a payment. The refunds of a payment
+// never add up to more than its amount. RefundedBy is the user or service
+// that asked for it.
+type Refund struct {
+	ID         uuid.UUID `db:"id" json:"id"`
+	PaymentID  uuid.UUID `db:"payment_id" json:"payment_id"`
+	Amount     float64   `db:"amount" json:"amount"`
+	Reason     *string   `db:"reason" json:"reason"`
+	RefundedBy string    `db:"refunded_by" json:"refunded_by"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"database/sql/driver"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RiskDecision represents the risk_decision enum
+type RiskDecision string
+
+const (
+	RiskDecisionAllow  RiskDecision = "allow"
+	RiskDecisionReview RiskDecision = "review"
+	RiskDecisionBlock  RiskDecision = "block"
+)
+
+// Value implements the driver.Valuer interface
+func (d RiskDecision) Value() (driver.Value, error) {
+	return string(d), nil
+}
+
+// Scan implements the sql.Scanner interface
+func (d *RiskDecision) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	*d = RiskDecision(value.(string))
+	return nil
+}
+
+// ReviewStatus represents the review_status enum
+type ReviewStatus string
+
+const (
+	ReviewStatusPending  ReviewStatus = "pending"
+	ReviewStatusApproved ReviewStatus = "approved"
+	ReviewStatusDenied   ReviewStatus = "denied"
+)
+
+// Value implements the driver.Valuer interface
+func (s ReviewStatus) Value() (driver.Value, error) {
+	return string(s), nil
+}
+
+// Scan implements the sql.Scanner interface
+func (s *ReviewStatus) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	*s = ReviewStatus(value.(string))
+	return nil
+}
+
+// RiskAssessment records the signals and decision of the risk checks for
+// one attempt. Attempts sent to review wait in the admin queue with
+// ReviewStatus pending.
+type RiskAssessment struct {
+	ID              uuid.UUID     `db:"id" json:"id"`
+	AttemptID       uuid.UUID     `db:"attempt_id" json:"attempt_id"`
+	UserID          uuid.UUID     `db:"user_id" json:"user_id"`
+	ClientIP        *string       `db:"client_ip" json:"client_ip"`
+	CardFingerprint *string       `db:"card_fingerprint" json:"card_fingerprint"`
+	CardCountry     *string       `db:"card_country" json:"card_country"`
+	RequestCountry  *string       `db:"request_country" json:"request_country"`
+	Amount          float64       `db:"amount" json:"amount"`
+	Decision        RiskDecision  `db:"decision" json:"decision"`
+	Reasons         []string      `db:"reasons" json:"reasons" gorm:"serializer:json"`
+	ReviewStatus    *ReviewStatus `db:"review_status" json:"review_status"`
+	ReviewedBy      *uuid.UUID    `db:"reviewed_by" json:"reviewed_by"`
+	ReviewedAt      *time.Time    `db:"reviewed_at" json:"reviewed_at"`
+	CreatedAt       time.Time     `db:"created_at" json:"created_at"`
+}
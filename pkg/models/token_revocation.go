@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TokenRevocation denies either one token, by its jti, or every token of a
+// user issued up to CreatedAt. It can be dropped once ExpiresAt has passed,
+// since every token it covers has expired by then.
+type TokenRevocation struct {
+	ID        uuid.UUID  `db:"id" json:"id"`
+	Jti       *string    `db:"jti" json:"jti"`
+	UserID    *uuid.UUID `db:"user_id" json:"user_id"`
+	Reason    *string    `db:"reason" json:"reason"`
+	RevokedBy uuid.UUID  `db:"revoked_by" json:"revoked_by"`
+	ExpiresAt time.Time  `db:"expires_at" json:"expires_at"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+}
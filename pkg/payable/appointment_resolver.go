@@ -0,0 +1,37 @@
+package payable
+
+import (
+	"context"
+	"fmt"
+
+	"payment-service/pkg/clients"
+	"payment-service/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// NewAppointmentResolver resolves appointments through the appointment
+// service. Appointments carry no price of their own, so the amount due is
+// the flat consultation fee.
+func NewAppointmentResolver(appointmentClient *clients.AppointmentClient, consultationFee float64) Resolver {
+	return ResolverFunc(func(ctx context.Context, id uuid.UUID) (*Payable, error) {
+		appointment, err := appointmentClient.GetAppointmentByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		ownerID := utils.StringToUUIDv7(appointment.PatientID)
+		if ownerID == uuid.Nil {
+			return nil, fmt.Errorf("appointment %s has invalid patient id %q", id, appointment.PatientID)
+		}
+
+		resolved := &Payable{
+			OwnerID:   ownerID,
+			AmountDue: consultationFee,
+		}
+		if doctorID := utils.StringToUUIDv7(appointment.DoctorID); doctorID != uuid.Nil {
+			resolved.DoctorID = &doctorID
+		}
+		return resolved, nil
+	})
+}
@@ -0,0 +1,38 @@
+package payable
+
+import (
+	"context"
+	"fmt"
+
+	"payment-service/pkg/clients"
+	"payment-service/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// NewOrderResolver resolves orders through the order service; the amount due
+// is the order total and the owner is the ordering patient.
+func NewOrderResolver(orderClient *clients.OrderClient) Resolver {
+	return ResolverFunc(func(ctx context.Context, id uuid.UUID) (*Payable, error) {
+		order, err := orderClient.GetOrderByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		ownerID := utils.StringToUUIDv7(order.PatientID)
+		if ownerID == uuid.Nil {
+			return nil, fmt.Errorf("order %s has invalid patient id %q", id, order.PatientID)
+		}
+
+		resolved := &Payable{
+			OwnerID:   ownerID,
+			AmountDue: order.TotalAmount,
+		}
+		if order.DoctorID != nil {
+			if doctorID := utils.StringToUUIDv7(*order.DoctorID); doctorID != uuid.Nil {
+				resolved.DoctorID = &doctorID
+			}
+		}
+		return resolved, nil
+	})
+}
@@ -0,0 +1,113 @@
+package payable
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"payment-service/pkg/models"
+
+	"github.com/google/uuid"
+)
+
+var ErrUnsupportedType = errors.New("unsupported payable type")
+
+// Payable is what a resolver knows about a billable entity: who owns it,
+// how much is due on it and, when there is one, the doctor it is with.
+type Payable struct {
+	Type      models.PayableType
+	ID        uuid.UUID
+	OwnerID   uuid.UUID
+	AmountDue float64
+	DoctorID  *uuid.UUID
+}
+
+// Resolver looks up a single kind of payable, usually in the service that
+// owns it.
+type Resolver interface {
+	Resolve(ctx context.Context, id uuid.UUID) (*Payable, error)
+}
+
+// ResolverFunc adapts a plain function to the Resolver interface.
+type ResolverFunc func(ctx context.Context, id uuid.UUID) (*Payable, error)
+
+func (f ResolverFunc) Resolve(ctx context.Context, id uuid.UUID) (*Payable, error) {
+	return f(ctx, id)
+}
+
+// Registry maps payable types to their resolvers. Types without a resolver
+// are rejected, so a new billable thing only needs a Register call.
+type Registry struct {
+	mu        sync.RWMutex
+	resolvers map[models.PayableType]Resolver
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		resolvers: make(map[models.PayableType]Resolver),
+	}
+}
+
+func (r *Registry) Register(payableType models.PayableType, resolver Resolver) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.resolvers[payableType] = resolver
+}
+
+func (r *Registry) Supports(payableType models.PayableType) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.resolvers[payableType]
+	return ok
+}
+
+func (r *Registry) Resolve(ctx context.Context, payableType models.PayableType, id uuid.UUID) (*Payable, error) {
+	r.mu.RLock()
+	resolver, ok := r.resolvers[payableType]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, ErrUnsupportedType
+	}
+
+	p, err := resolver.Resolve(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	p.Type = payableType
+	p.ID = id
+	return p, nil
+}
+
+// resolveConcurrency bounds how many payables ResolveMany looks up at once.
+const resolveConcurrency = 8
+
+// ResolveMany resolves ids of one type concurrently. It returns what it
+// found and, separately, the error for each ID that failed, so one missing
+// payable does not lose the others.
+func (r *Registry) ResolveMany(ctx context.Context, payableType models.PayableType, ids []uuid.UUID) (map[uuid.UUID]*Payable, map[uuid.UUID]error) {
+	found := make(map[uuid.UUID]*Payable, len(ids))
+	failed := make(map[uuid.UUID]error)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, resolveConcurrency)
+	for _, id := range ids {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			p, err := r.Resolve(ctx, payableType, id)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed[id] = err
+				return
+			}
+			found[id] = p
+		}()
+	}
+	wg.Wait()
+	return found, failed
+}
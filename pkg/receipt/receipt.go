@@ -0,0 +1,210 @@
+package receipt
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"payment-service/pkg/i18n"
+	"payment-service/pkg/models"
+
+	"github.com/go-pdf/fpdf"
+)
+
+const fontFamily = "receipt"
+
+// Bangkok is the zone documents are dated in, whatever zone the server runs
+// in. Thailand keeps no daylight saving time.
+var Bangkok = time.FixedZone("Asia/Bangkok", 7*60*60)
+
+// Seller is the issuing business as printed on every document.
+type Seller struct {
+	Name       string
+	TaxID      string
+	Address    string
+	BranchCode string
+	VatRate    float64
+}
+
+// Renderer draws receipts and tax invoices as PDF. Thai labels need a
+// TrueType font with Thai glyphs, e.g. one from fonts-thai-tlwg.
+type Renderer struct {
+	fontPath string
+}
+
+func NewRenderer(fontPath string) *Renderer {
+	return &Renderer{
+		fontPath: fontPath,
+	}
+}
+
+// Render draws a document in lang. Tax invoices are always in Thai with
+// English alongside, since the Revenue Code wants them in Thai whoever the
+// buyer is.
+func (r *Renderer) Render(doc *models.PaymentDocument, items []models.PaymentLineItem, lang i18n.Lang) ([]byte, error) {
+	font, err := os.ReadFile(r.fontPath)
+	if err != nil {
+		return nil, fmt.Errorf("load receipt font %q: %w", r.fontPath, err)
+	}
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle(doc.Number, true)
+	pdf.AddUTF8FontFromBytes(fontFamily, "", font)
+	if err := pdf.Error(); err != nil {
+		return nil, fmt.Errorf("load receipt font %q: %w", r.fontPath, err)
+	}
+	pdf.AddPage()
+
+	l := labels{lang}
+	title := l.text(i18n.DocReceipt)
+	if doc.Type == models.DocumentTypeTaxInvoice {
+		l = labels{i18n.Thai, i18n.English}
+		title = l.join("  ", i18n.DocTaxInvoice)
+	}
+	pdf.SetFont(fontFamily, "", 16)
+	pdf.CellFormat(0, 10, title, "", 1, "C", false, 0, "")
+
+	pdf.SetFont(fontFamily, "", 11)
+	line := func(text string) {
+		pdf.CellFormat(0, 6, text, "", 1, "L", false, 0, "")
+	}
+
+	line(doc.SellerName)
+	line(doc.SellerAddress)
+	line(l.text(i18n.DocTaxID) + ": " + doc.SellerTaxID)
+	line(l.text(i18n.DocBranch) + ": " + l.branch(doc.BranchCode))
+	pdf.Ln(3)
+
+	line(l.text(i18n.DocNumber) + ": " + doc.Number)
+	line(l.text(i18n.DocDate) + ": " + l.date(doc.IssuedAt))
+	if doc.ReplacesDocumentID != nil {
+		line(l.text(i18n.DocReplaces))
+	}
+	pdf.Ln(3)
+
+	line(l.text(i18n.DocBuyer) + ": " + doc.BuyerName)
+	if doc.BuyerTaxID != nil {
+		line(l.text(i18n.DocTaxID) + ": " + *doc.BuyerTaxID)
+	}
+	if doc.BuyerAddress != nil {
+		line(l.text(i18n.DocAddress) + ": " + *doc.BuyerAddress)
+	}
+	if doc.BuyerBranch != nil {
+		line(l.text(i18n.DocBranch) + ": " + l.branch(*doc.BuyerBranch))
+	}
+	pdf.Ln(4)
+
+	widths := []float64{90, 20, 35, 45}
+	header := []string{l.text(i18n.DocDescription), l.text(i18n.DocQuantity), l.text(i18n.DocUnitPrice), l.text(i18n.DocAmount)}
+	for i, h := range header {
+		pdf.CellFormat(widths[i], 8, h, "1", 0, "C", false, 0, "")
+	}
+	pdf.Ln(-1)
+
+	if len(items) == 0 {
+		pdf.CellFormat(widths[0], 7, l.text(i18n.DocServiceCharge), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[1], 7, "1", "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[2], 7, FormatAmount(doc.Subtotal), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[3], 7, FormatAmount(doc.Subtotal), "1", 1, "R", false, 0, "")
+	}
+	for _, item := range items {
+		pdf.CellFormat(widths[0], 7, item.Description, "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[1], 7, trimQuantity(item.Quantity), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[2], 7, FormatAmount(item.UnitPrice), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[3], 7, FormatAmount(item.Quantity*item.UnitPrice), "1", 1, "R", false, 0, "")
+	}
+	pdf.Ln(2)
+
+	total := func(label string, amount float64) {
+		pdf.CellFormat(widths[0]+widths[1]+widths[2], 7, label, "", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[3], 7, FormatAmount(amount), "", 1, "R", false, 0, "")
+	}
+	total(l.text(i18n.DocSubtotal), doc.Subtotal)
+	total(l.text(i18n.DocVat, trimQuantity(doc.VatRate)), doc.VatAmount)
+	total(l.text(i18n.DocTotal), doc.Total)
+	if doc.PayerAmount > 0 {
+		total(l.text(i18n.DocCoveredBy), doc.PayerAmount)
+		total(l.text(i18n.DocPaidByPatient), doc.PatientAmount)
+	}
+
+	if doc.Status == models.DocumentStatusVoided {
+		pdf.Ln(8)
+		pdf.SetFont(fontFamily, "", 28)
+		pdf.SetTextColor(200, 0, 0)
+		pdf.CellFormat(0, 14, l.text(i18n.DocVoid), "", 1, "C", false, 0, "")
+		if doc.VoidReason != nil {
+			pdf.SetFont(fontFamily, "", 11)
+			pdf.CellFormat(0, 6, *doc.VoidReason, "", 1, "C", false, 0, "")
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("render %s: %w", doc.Number, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// labels puts the printed labels in each of its languages, side by side.
+type labels []i18n.Lang
+
+func (l labels) text(key i18n.Key, args ...any) string {
+	return l.join(" / ", key, args...)
+}
+
+func (l labels) join(sep string, key i18n.Key, args ...any) string {
+	texts := make([]string, len(l))
+	for i, lang := range l {
+		texts[i] = i18n.T(key, args...).In(lang)
+	}
+	return strings.Join(texts, sep)
+}
+
+// date prints a day in the Buddhist era for Thai readers and the Common
+// Era for others, both when the document is bilingual.
+func (l labels) date(t time.Time) string {
+	t = t.In(Bangkok)
+	day := t.Format("02/01/")
+	buddhistYear := strconv.Itoa(t.Year() + 543)
+	switch {
+	case len(l) > 1:
+		return fmt.Sprintf("%s%d (พ.ศ. %s)", day, t.Year(), buddhistYear)
+	case l[0] == i18n.Thai:
+		return day + buddhistYear
+	default:
+		return day + strconv.Itoa(t.Year())
+	}
+}
+
+func (l labels) branch(code string) string {
+	if code == "00000" {
+		return code + " (" + l.text(i18n.DocHeadOffice) + ")"
+	}
+	return code
+}
+
+// FormatAmount prints an amount the way Thai documents do: two decimals
+// with comma thousands separators.
+func FormatAmount(amount float64) string {
+	s := fmt.Sprintf("%.2f", amount)
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	intPart, frac := s[:len(s)-3], s[len(s)-3:]
+	var b strings.Builder
+	for i, r := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+	return sign + b.String() + frac
+}
+
+func trimQuantity(q float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", q), "0"), ".")
+}
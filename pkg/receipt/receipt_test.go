@@ -0,0 +1,30 @@
+package receipt
+
+import (
+	"testing"
+	"time"
+
+	"payment-service/pkg/i18n"
+)
+
+func TestDateIsInBangkok(t *testing.T) {
+	// 06:30 on New Year's Day in Bangkok is still the old year in UTC
+	issuedAt := time.Date(2025, time.December, 31, 23, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		labels labels
+		want   string
+	}{
+		{"english", labels{i18n.English}, "01/01/2026"},
+		{"thai", labels{i18n.Thai}, "01/01/2569"},
+		{"bilingual", labels{i18n.Thai, i18n.English}, "01/01/2026 (พ.ศ. 2569)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.labels.date(issuedAt); got != tt.want {
+				t.Fatalf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
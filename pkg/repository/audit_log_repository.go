@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"payment-service/pkg/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AuditLogFilter narrows an audit log query; zero fields do not filter.
+type AuditLogFilter struct {
+	EntityType string
+	EntityID   string
+	ActorID    string
+	Action     models.AuditAction
+	RequestID  string
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+}
+
+// Audit rows are written by the audit plugin, never through this
+// repository, so it only reads.
+type AuditLogRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditLogRepository(db *gorm.DB) *AuditLogRepository {
+	return &AuditLogRepository{
+		db: db,
+	}
+}
+
+// Find returns matching rows, newest first.
+func (r *AuditLogRepository) Find(ctx context.Context, filter AuditLogFilter) ([]models.AuditLog, error) {
+	query := r.db.WithContext(ctx)
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.ActorID != "" {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.RequestID != "" {
+		query = query.Where("request_id = ?", filter.RequestID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	var entries []models.AuditLog
+	if err := query.Order("seq DESC").Limit(filter.Limit).Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
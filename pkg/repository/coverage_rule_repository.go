@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"payment-service/pkg/models"
+
+	"gorm.io/gorm"
+)
+
+type CoverageRuleRepository struct {
+	db *gorm.DB
+}
+
+func NewCoverageRuleRepository(db *gorm.DB) *CoverageRuleRepository {
+	return &CoverageRuleRepository{
+		db: db,
+	}
+}
+
+func (r *CoverageRuleRepository) FindActiveByEntitlements(ctx context.Context, entitlements []string) ([]models.CoverageRule, error) {
+	var rules []models.CoverageRule
+	if len(entitlements) == 0 {
+		return rules, nil
+	}
+	if err := r.db.WithContext(ctx).Where("active AND healthcare_entitlement IN ?", entitlements).Order("priority, id").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
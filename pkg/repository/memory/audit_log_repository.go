@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"payment-service/pkg/models"
+	"payment-service/pkg/repository"
+
+	"gorm.io/gorm"
+)
+
+// Audit rows are written by the audit plugin, which has no in-memory
+// counterpart; Create stands in for it when seeding tests.
+type AuditLogRepository struct {
+	db *DB
+}
+
+func NewAuditLogRepository(db *DB) *AuditLogRepository {
+	return &AuditLogRepository{
+		db: db,
+	}
+}
+
+// Create appends an entry and assigns its seq.
+func (r *AuditLogRepository) Create(ctx context.Context, entry *models.AuditLog) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	newID(&entry.ID)
+	now(&entry.CreatedAt)
+	for _, other := range r.db.auditLogs {
+		if other.ID == entry.ID || (entry.Hash != "" && other.Hash == entry.Hash) {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	entry.Seq = int64(len(r.db.auditLogs)) + 1
+	r.db.auditLogs = append(r.db.auditLogs, *entry)
+	return nil
+}
+
+// Find returns matching rows, newest first.
+func (r *AuditLogRepository) Find(ctx context.Context, filter repository.AuditLogFilter) ([]models.AuditLog, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var entries []models.AuditLog
+	for i := len(r.db.auditLogs) - 1; i >= 0; i-- {
+		if filter.Limit > 0 && len(entries) == filter.Limit {
+			break
+		}
+		entry := r.db.auditLogs[i]
+		if filter.EntityType != "" && entry.EntityType != filter.EntityType {
+			continue
+		}
+		if filter.EntityID != "" && entry.EntityID != filter.EntityID {
+			continue
+		}
+		if filter.ActorID != "" && (entry.ActorID == nil || *entry.ActorID != filter.ActorID) {
+			continue
+		}
+		if filter.Action != "" && entry.Action != filter.Action {
+			continue
+		}
+		if filter.RequestID != "" && (entry.RequestID == nil || *entry.RequestID != filter.RequestID) {
+			continue
+		}
+		if filter.From != nil && entry.CreatedAt.Before(*filter.From) {
+			continue
+		}
+		if filter.To != nil && !entry.CreatedAt.Before(*filter.To) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
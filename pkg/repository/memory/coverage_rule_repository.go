@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+	"payment-service/pkg/models"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+// Coverage rules are maintained outside this service; Create stands in for
+// that when seeding tests.
+type CoverageRuleRepository struct {
+	db *DB
+}
+
+func NewCoverageRuleRepository(db *DB) *CoverageRuleRepository {
+	return &CoverageRuleRepository{
+		db: db,
+	}
+}
+
+func (r *CoverageRuleRepository) Create(ctx context.Context, rule *models.CoverageRule) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	newID(&rule.ID)
+	now(&rule.CreatedAt)
+	if _, ok := r.db.coverageRules[rule.ID]; ok {
+		return gorm.ErrDuplicatedKey
+	}
+	// unique_coverage_rule treats a null category as a value
+	for _, other := range r.db.coverageRules {
+		if other.HealthcareEntitlement == rule.HealthcareEntitlement && sameCategory(other.Category, rule.Category) {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	r.db.coverageRules[rule.ID] = *rule
+	return nil
+}
+
+func (r *CoverageRuleRepository) FindActiveByEntitlements(ctx context.Context, entitlements []string) ([]models.CoverageRule, error) {
+	wanted := make(map[string]bool, len(entitlements))
+	for _, entitlement := range entitlements {
+		wanted[entitlement] = true
+	}
+
+	r.db.mu.RLock()
+	rules := rows(r.db.coverageRules, func(rule *models.CoverageRule) bool {
+		return rule.Active && wanted[rule.HealthcareEntitlement]
+	})
+	r.db.mu.RUnlock()
+
+	sort.SliceStable(rules, func(i, j int) bool { return rules[i].Priority < rules[j].Priority })
+	return rules, nil
+}
+
+func sameCategory(a, b *models.LineItemCategory) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
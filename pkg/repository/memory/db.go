@@ -0,0 +1,172 @@
+// Package memory implements the repository interfaces over in-memory
+// tables, for tests that should not need Postgres. The tables enforce the
+// constraints of the schema the service relies on: primary and unique
+// keys, foreign keys, what happens to referencing rows on delete and the
+// checks between columns. Violations return gorm.ErrDuplicatedKey,
+// gorm.ErrForeignKeyViolated and gorm.ErrCheckConstraintViolated, the
+// errors gorm translates the Postgres ones to.
+package memory
+
+import (
+	"bytes"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"payment-service/pkg/models"
+	"payment-service/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// DB holds the tables every repository of one New shares, so constraints
+// can span tables as they do in the database. It is safe for concurrent
+// use; each repository call is atomic.
+type DB struct {
+	mu sync.RWMutex
+	tables
+
+	// txMu runs units of work one at a time
+	txMu sync.Mutex
+}
+
+type tables struct {
+	paymentInfos   map[uuid.UUID]models.PaymentInformation
+	attempts       map[uuid.UUID]models.PaymentAttempt
+	payments       map[uuid.UUID]models.Payment
+	refunds        map[uuid.UUID]models.Refund
+	lineItems      map[uuid.UUID]models.PaymentLineItem
+	coverageRules  map[uuid.UUID]models.CoverageRule
+	receivables    map[uuid.UUID]models.PayerReceivable
+	documents      map[uuid.UUID]models.PaymentDocument
+	documentEvents map[uuid.UUID]models.PaymentDocumentEvent
+	sequences      map[sequenceKey]int
+	auditLogs      []models.AuditLog
+	assessments    map[uuid.UUID]models.RiskAssessment
+	revocations    map[uuid.UUID]models.TokenRevocation
+}
+
+// sequenceKey is the primary key of document_sequences.
+type sequenceKey struct {
+	branchCode string
+	docType    models.DocumentType
+	year       int
+}
+
+func New() *DB {
+	return &DB{
+		tables: tables{
+			paymentInfos:   make(map[uuid.UUID]models.PaymentInformation),
+			attempts:       make(map[uuid.UUID]models.PaymentAttempt),
+			payments:       make(map[uuid.UUID]models.Payment),
+			refunds:        make(map[uuid.UUID]models.Refund),
+			lineItems:      make(map[uuid.UUID]models.PaymentLineItem),
+			coverageRules:  make(map[uuid.UUID]models.CoverageRule),
+			receivables:    make(map[uuid.UUID]models.PayerReceivable),
+			documents:      make(map[uuid.UUID]models.PaymentDocument),
+			documentEvents: make(map[uuid.UUID]models.PaymentDocumentEvent),
+			sequences:      make(map[sequenceKey]int),
+			assessments:    make(map[uuid.UUID]models.RiskAssessment),
+			revocations:    make(map[uuid.UUID]models.TokenRevocation),
+		},
+	}
+}
+
+// copy returns tables holding the same rows. Stored rows are replaced,
+// never changed in place, so the rows themselves can be shared.
+func (t *tables) copy() tables {
+	return tables{
+		paymentInfos:   maps.Clone(t.paymentInfos),
+		attempts:       maps.Clone(t.attempts),
+		payments:       maps.Clone(t.payments),
+		refunds:        maps.Clone(t.refunds),
+		lineItems:      maps.Clone(t.lineItems),
+		coverageRules:  maps.Clone(t.coverageRules),
+		receivables:    maps.Clone(t.receivables),
+		documents:      maps.Clone(t.documents),
+		documentEvents: maps.Clone(t.documentEvents),
+		sequences:      maps.Clone(t.sequences),
+		auditLogs:      slices.Clone(t.auditLogs),
+		assessments:    maps.Clone(t.assessments),
+		revocations:    maps.Clone(t.revocations),
+	}
+}
+
+// newID and now fill the columns the schema defaults.
+func newID(id *uuid.UUID) {
+	if *id == uuid.Nil {
+		*id = utils.GenerateUUIDv7()
+	}
+}
+
+func now(t *time.Time) {
+	if t.IsZero() {
+		*t = time.Now()
+	}
+}
+
+// rows returns the values of table ordered by id, which for UUIDv7 is the
+// order they were created in. Listings ordered by another column and then
+// id sort these stably.
+func rows[T any](table map[uuid.UUID]T, match func(*T) bool) []T {
+	ids := make([]uuid.UUID, 0, len(table))
+	for id := range table {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return bytes.Compare(ids[i][:], ids[j][:]) < 0 })
+
+	var result []T
+	for _, id := range ids {
+		row := table[id]
+		if match == nil || match(&row) {
+			result = append(result, row)
+		}
+	}
+	return result
+}
+
+// The tables keep rows without their associations, and rows handed out
+// share no slices with the stored ones.
+
+func cloneBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	return append([]byte(nil), b...)
+}
+
+func cloneStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	return append([]string(nil), s...)
+}
+
+func clonePaymentInfo(info models.PaymentInformation) models.PaymentInformation {
+	info.Details = cloneBytes(info.Details)
+	return info
+}
+
+func cloneAttempt(attempt models.PaymentAttempt) models.PaymentAttempt {
+	attempt.LineItems = nil
+	attempt.Risk = nil
+	return attempt
+}
+
+func clonePayment(payment models.Payment) models.Payment {
+	payment.LineItems = nil
+	payment.Receivables = nil
+	return payment
+}
+
+func cloneAssessment(assessment models.RiskAssessment) models.RiskAssessment {
+	assessment.Reasons = cloneStrings(assessment.Reasons)
+	return assessment
+}
+
+func cloneDocument(doc models.PaymentDocument) models.PaymentDocument {
+	doc.Events = nil
+	return doc
+}
@@ -0,0 +1,188 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"payment-service/pkg/models"
+	"payment-service/pkg/repository"
+	"payment-service/pkg/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func TestConstraints(t *testing.T) {
+	ctx := context.Background()
+	userID := utils.GenerateUUIDv7()
+
+	tests := []struct {
+		name string
+		run  func(db *DB) error
+		want error
+	}{
+		{"unique payment profile", func(db *DB) error {
+			infos := NewPaymentInformationRepository(db)
+			if err := infos.Create(ctx, &models.PaymentInformation{UserID: userID, Type: models.PaymentMethodCreditCard}); err != nil {
+				return err
+			}
+			return infos.Create(ctx, &models.PaymentInformation{UserID: userID, Type: models.PaymentMethodCreditCard})
+		}, gorm.ErrDuplicatedKey},
+		{"attempt needs its payment information", func(db *DB) error {
+			missing := utils.GenerateUUIDv7()
+			return NewPaymentAttemptRepository(db).Create(ctx, &models.PaymentAttempt{PaymentInformationID: &missing})
+		}, gorm.ErrForeignKeyViolated},
+		{"payment needs its attempt", func(db *DB) error {
+			return NewPaymentRepository(db).Create(ctx, &models.Payment{AttemptID: utils.GenerateUUIDv7()})
+		}, gorm.ErrForeignKeyViolated},
+		{"payment amount is not negative", func(db *DB) error {
+			attempt := &models.PaymentAttempt{}
+			if err := NewPaymentAttemptRepository(db).Create(ctx, attempt); err != nil {
+				return err
+			}
+			return NewPaymentRepository(db).Create(ctx, &models.Payment{AttemptID: attempt.ID, Amount: -1})
+		}, gorm.ErrCheckConstraintViolated},
+		{"revocation names a jti or a user", func(db *DB) error {
+			jti := "token"
+			return NewTokenRevocationRepository(db).Create(ctx, &models.TokenRevocation{Jti: &jti, UserID: &userID})
+		}, gorm.ErrCheckConstraintViolated},
+		{"documents restrict deleting payments", func(db *DB) error {
+			attempt := &models.PaymentAttempt{}
+			if err := NewPaymentAttemptRepository(db).Create(ctx, attempt); err != nil {
+				return err
+			}
+			payment := &models.Payment{AttemptID: attempt.ID}
+			if err := NewPaymentRepository(db).Create(ctx, payment); err != nil {
+				return err
+			}
+			doc := &models.PaymentDocument{PaymentID: payment.ID, Type: models.DocumentTypeReceipt, BranchCode: "00000", Year: 2026}
+			if err := NewPaymentDocumentRepository(db).Issue(ctx, doc, &models.PaymentDocumentEvent{Action: models.DocumentActionIssued}); err != nil {
+				return err
+			}
+			return NewPaymentAttemptRepository(db).Delete(ctx, attempt.ID)
+		}, gorm.ErrForeignKeyViolated},
+		{"refund needs its payment", func(db *DB) error {
+			return NewRefundRepository(db).Create(ctx, &models.Refund{PaymentID: utils.GenerateUUIDv7(), Amount: 1})
+		}, gorm.ErrForeignKeyViolated},
+		{"refund amount is positive", func(db *DB) error {
+			attempt := &models.PaymentAttempt{}
+			if err := NewPaymentAttemptRepository(db).Create(ctx, attempt); err != nil {
+				return err
+			}
+			payment := &models.Payment{AttemptID: attempt.ID, Amount: 100}
+			if err := NewPaymentRepository(db).Create(ctx, payment); err != nil {
+				return err
+			}
+			return NewRefundRepository(db).Create(ctx, &models.Refund{PaymentID: payment.ID})
+		}, gorm.ErrCheckConstraintViolated},
+		{"refunds restrict deleting payments", func(db *DB) error {
+			attempt := &models.PaymentAttempt{}
+			if err := NewPaymentAttemptRepository(db).Create(ctx, attempt); err != nil {
+				return err
+			}
+			payment := &models.Payment{AttemptID: attempt.ID, Amount: 100}
+			if err := NewPaymentRepository(db).Create(ctx, payment); err != nil {
+				return err
+			}
+			if err := NewRefundRepository(db).Create(ctx, &models.Refund{PaymentID: payment.ID, Amount: 100}); err != nil {
+				return err
+			}
+			return NewPaymentRepository(db).Delete(ctx, payment.ID)
+		}, gorm.ErrForeignKeyViolated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.run(New()); !errors.Is(err, tt.want) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeletingPaymentInformationKeepsAttempts(t *testing.T) {
+	ctx := context.Background()
+	db := New()
+	infos := NewPaymentInformationRepository(db)
+	attempts := NewPaymentAttemptRepository(db)
+
+	info := &models.PaymentInformation{UserID: utils.GenerateUUIDv7(), Type: models.PaymentMethodPromptPay}
+	if err := infos.Create(ctx, info); err != nil {
+		t.Fatal(err)
+	}
+	attempt := &models.PaymentAttempt{PaymentInformationID: &info.ID}
+	if err := attempts.Create(ctx, attempt); err != nil {
+		t.Fatal(err)
+	}
+	if err := infos.Delete(ctx, info.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := attempts.FindByID(ctx, attempt.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.PaymentInformationID != nil {
+		t.Fatalf("attempt still points at %s", got.PaymentInformationID)
+	}
+	if _, err := infos.FindByID(ctx, info.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("got %v, want not found", err)
+	}
+}
+
+func TestRowsAreCopies(t *testing.T) {
+	ctx := context.Background()
+	db := New()
+	infos := NewPaymentInformationRepository(db)
+
+	info := &models.PaymentInformation{UserID: utils.GenerateUUIDv7(), Type: models.PaymentMethodPromptPay, Details: []byte(`{}`)}
+	if err := infos.Create(ctx, info); err != nil {
+		t.Fatal(err)
+	}
+	info.Details[0] = 'x'
+	info.UserID = uuid.Nil
+
+	got, err := infos.FindByID(ctx, info.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got.Details) != `{}` || got.UserID == uuid.Nil {
+		t.Fatalf("stored row changed with the caller's copy: %+v", got)
+	}
+}
+
+func TestUpdateChecksVersion(t *testing.T) {
+	ctx := context.Background()
+	db := New()
+	attempts := NewPaymentAttemptRepository(db)
+
+	info := &models.PaymentInformation{UserID: utils.GenerateUUIDv7(), Type: models.PaymentMethodPromptPay}
+	if err := NewPaymentInformationRepository(db).Create(ctx, info); err != nil {
+		t.Fatal(err)
+	}
+	attempt := &models.PaymentAttempt{PaymentInformationID: &info.ID}
+	if err := attempts.Create(ctx, attempt); err != nil {
+		t.Fatal(err)
+	}
+	stale := *attempt
+
+	// clearing a column is written like any other change
+	attempt.PaymentInformationID = nil
+	if err := attempts.Update(ctx, attempt); err != nil {
+		t.Fatal(err)
+	}
+	if attempt.LockVersion != 2 {
+		t.Fatalf("got version %d, want 2", attempt.LockVersion)
+	}
+	if err := attempts.Update(ctx, &stale); !errors.Is(err, repository.ErrVersionConflict) {
+		t.Fatalf("got %v, want a version conflict", err)
+	}
+
+	got, err := attempts.FindByID(ctx, attempt.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.PaymentInformationID != nil || got.LockVersion != 2 {
+		t.Fatalf("got %+v", got)
+	}
+}
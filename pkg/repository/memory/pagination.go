@@ -0,0 +1,39 @@
+package memory
+
+import (
+	"bytes"
+	"sort"
+
+	"payment-service/pkg/repository"
+
+	"github.com/google/uuid"
+)
+
+// pageOf cuts page out of rows, in any order, the way the Postgres
+// repositories page by id in SQL.
+func pageOf[T any](page repository.KeysetPage, rows []T, id func(*T) uuid.UUID) ([]T, *uuid.UUID) {
+	selected := make([]T, 0, len(rows))
+	for i := range rows {
+		rowID := id(&rows[i])
+		if page.After != nil {
+			cmp := bytes.Compare(rowID[:], page.After[:])
+			if (page.Descending && cmp >= 0) || (!page.Descending && cmp <= 0) {
+				continue
+			}
+		}
+		selected = append(selected, rows[i])
+	}
+	sort.Slice(selected, func(i, j int) bool {
+		a, b := id(&selected[i]), id(&selected[j])
+		if page.Descending {
+			return bytes.Compare(a[:], b[:]) > 0
+		}
+		return bytes.Compare(a[:], b[:]) < 0
+	})
+	if len(selected) <= page.Limit {
+		return selected, nil
+	}
+	selected = selected[:page.Limit]
+	next := id(&selected[len(selected)-1])
+	return selected, &next
+}
@@ -0,0 +1,29 @@
+package memory
+
+import (
+	"context"
+	"payment-service/pkg/models"
+	"sort"
+)
+
+// Receivables are written with the payment they belong to.
+type PayerReceivableRepository struct {
+	db *DB
+}
+
+func NewPayerReceivableRepository(db *DB) *PayerReceivableRepository {
+	return &PayerReceivableRepository{
+		db: db,
+	}
+}
+
+func (r *PayerReceivableRepository) FindByStatus(ctx context.Context, status models.ReceivableStatus) ([]models.PayerReceivable, error) {
+	r.db.mu.RLock()
+	receivables := rows(r.db.receivables, func(receivable *models.PayerReceivable) bool {
+		return receivable.Status == status
+	})
+	r.db.mu.RUnlock()
+
+	sort.SliceStable(receivables, func(i, j int) bool { return receivables[i].CreatedAt.Before(receivables[j].CreatedAt) })
+	return receivables, nil
+}
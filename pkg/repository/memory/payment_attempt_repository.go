@@ -0,0 +1,194 @@
+package memory
+
+import (
+	"context"
+	"payment-service/pkg/models"
+	"payment-service/pkg/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PaymentAttemptRepository struct {
+	db *DB
+}
+
+func NewPaymentAttemptRepository(db *DB) *PaymentAttemptRepository {
+	return &PaymentAttemptRepository{
+		db: db,
+	}
+}
+
+// Create inserts the attempt with its line items and risk assessment, as
+// gorm does with associations.
+func (r *PaymentAttemptRepository) Create(ctx context.Context, attempt *models.PaymentAttempt) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	newID(&attempt.ID)
+	now(&attempt.CreatedAt)
+	if attempt.Status == "" {
+		attempt.Status = models.PaymentStatusPending
+	}
+	if attempt.LockVersion == 0 {
+		attempt.LockVersion = 1
+	}
+	if _, ok := r.db.attempts[attempt.ID]; ok {
+		return gorm.ErrDuplicatedKey
+	}
+	if attempt.PaymentInformationID != nil {
+		if _, ok := r.db.paymentInfos[*attempt.PaymentInformationID]; !ok {
+			return gorm.ErrForeignKeyViolated
+		}
+	}
+
+	for i := range attempt.LineItems {
+		attempt.LineItems[i].AttemptID = &attempt.ID
+	}
+	if err := r.db.checkLineItems(attempt.LineItems); err != nil {
+		return err
+	}
+	if attempt.Risk != nil {
+		attempt.Risk.AttemptID = attempt.ID
+		newID(&attempt.Risk.ID)
+		now(&attempt.Risk.CreatedAt)
+		if _, ok := r.db.assessments[attempt.Risk.ID]; ok {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+
+	r.db.attempts[attempt.ID] = cloneAttempt(*attempt)
+	r.db.insertLineItems(attempt.LineItems)
+	if attempt.Risk != nil {
+		r.db.assessments[attempt.Risk.ID] = cloneAssessment(*attempt.Risk)
+	}
+	return nil
+}
+
+func (r *PaymentAttemptRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.PaymentAttempt, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	attempt, ok := r.db.attempts[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &attempt, nil
+}
+
+// FindByIDForUpdate needs no lock of its own: units of work run one at a
+// time.
+func (r *PaymentAttemptRepository) FindByIDForUpdate(ctx context.Context, id uuid.UUID) (*models.PaymentAttempt, error) {
+	return r.FindByID(ctx, id)
+}
+
+func (r *PaymentAttemptRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]models.PaymentAttempt, error) {
+	wanted := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	return r.find(func(a *models.PaymentAttempt) bool { return wanted[a.ID] }), nil
+}
+
+func (r *PaymentAttemptRepository) FindByPayable(ctx context.Context, payableType models.PayableType, payableID uuid.UUID) ([]models.PaymentAttempt, error) {
+	return r.find(func(a *models.PaymentAttempt) bool {
+		return a.PayableType == payableType && a.PayableID == payableID
+	}), nil
+}
+
+func (r *PaymentAttemptRepository) FindByPayableAndStatus(ctx context.Context, payableType models.PayableType, payableID uuid.UUID, status models.PaymentStatus) ([]models.PaymentAttempt, error) {
+	return r.find(func(a *models.PaymentAttempt) bool {
+		return a.PayableType == payableType && a.PayableID == payableID && a.Status == status
+	}), nil
+}
+
+func (r *PaymentAttemptRepository) FindByOrderID(ctx context.Context, orderID uuid.UUID) ([]models.PaymentAttempt, error) {
+	return r.FindByPayable(ctx, models.PayableTypeOrder, orderID)
+}
+
+func (r *PaymentAttemptRepository) FindByOrderIDAndStatus(ctx context.Context, orderID uuid.UUID, status models.PaymentStatus) ([]models.PaymentAttempt, error) {
+	return r.FindByPayableAndStatus(ctx, models.PayableTypeOrder, orderID, status)
+}
+
+func (r *PaymentAttemptRepository) FindAll(ctx context.Context) ([]models.PaymentAttempt, error) {
+	return r.find(nil), nil
+}
+
+func (r *PaymentAttemptRepository) Update(ctx context.Context, attempt *models.PaymentAttempt) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	stored, ok := r.db.attempts[attempt.ID]
+	if !ok || stored.LockVersion != attempt.LockVersion {
+		return repository.ErrVersionConflict
+	}
+	if attempt.PaymentInformationID != nil {
+		if _, ok := r.db.paymentInfos[*attempt.PaymentInformationID]; !ok {
+			return gorm.ErrForeignKeyViolated
+		}
+	}
+	attempt.LockVersion++
+	r.db.attempts[attempt.ID] = cloneAttempt(*attempt)
+	return nil
+}
+
+func (r *PaymentAttemptRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	return r.db.deleteAttempts(func(a *models.PaymentAttempt) bool { return a.ID == id })
+}
+
+func (r *PaymentAttemptRepository) DeleteByPayable(ctx context.Context, payableType models.PayableType, payableID uuid.UUID) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	return r.db.deleteAttempts(func(a *models.PaymentAttempt) bool {
+		return a.PayableType == payableType && a.PayableID == payableID
+	})
+}
+
+func (r *PaymentAttemptRepository) find(match func(*models.PaymentAttempt) bool) []models.PaymentAttempt {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	return rows(r.db.attempts, match)
+}
+
+// deleteAttempts deletes the matching attempts with everything that
+// cascades from them: their line items, risk assessments and payments.
+// Like the statement it stands for, it deletes nothing when any of it is
+// still referenced.
+func (db *DB) deleteAttempts(match func(*models.PaymentAttempt) bool) error {
+	attemptIDs := make(map[uuid.UUID]bool)
+	for id, attempt := range db.attempts {
+		if match(&attempt) {
+			attemptIDs[id] = true
+		}
+	}
+	paymentIDs := make(map[uuid.UUID]bool)
+	for id, payment := range db.payments {
+		if attemptIDs[payment.AttemptID] {
+			paymentIDs[id] = true
+		}
+	}
+	if err := db.checkPaymentsUnreferenced(paymentIDs); err != nil {
+		return err
+	}
+
+	db.removePayments(paymentIDs)
+	for id := range attemptIDs {
+		delete(db.attempts, id)
+	}
+	for id, item := range db.lineItems {
+		if item.AttemptID != nil && attemptIDs[*item.AttemptID] {
+			delete(db.lineItems, id)
+		}
+	}
+	for id, assessment := range db.assessments {
+		if attemptIDs[assessment.AttemptID] {
+			delete(db.assessments, id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package memory
+
+import (
+	"context"
+	"payment-service/pkg/models"
+	"payment-service/pkg/repository"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PaymentDocumentRepository struct {
+	db *DB
+}
+
+func NewPaymentDocumentRepository(db *DB) *PaymentDocumentRepository {
+	return &PaymentDocumentRepository{
+		db: db,
+	}
+}
+
+// Issue numbers and stores a new document together with its audit event.
+func (r *PaymentDocumentRepository) Issue(ctx context.Context, doc *models.PaymentDocument, event *models.PaymentDocumentEvent) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	return r.db.issueDocument(doc, event)
+}
+
+// Void marks an issued document as voided. Its number stays consumed.
+func (r *PaymentDocumentRepository) Void(ctx context.Context, doc *models.PaymentDocument, event *models.PaymentDocumentEvent) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	_, err := r.db.voidDocument(doc, event)
+	return err
+}
+
+// Reissue voids old and issues replacement under a new number atomically.
+func (r *PaymentDocumentRepository) Reissue(ctx context.Context, old *models.PaymentDocument, voidEvent *models.PaymentDocumentEvent, replacement *models.PaymentDocument, issueEvent *models.PaymentDocumentEvent) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	undo, err := r.db.voidDocument(old, voidEvent)
+	if err != nil {
+		return err
+	}
+	replacement.ReplacesDocumentID = &old.ID
+	if err := r.db.issueDocument(replacement, issueEvent); err != nil {
+		undo()
+		return err
+	}
+	return nil
+}
+
+func (r *PaymentDocumentRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.PaymentDocument, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	doc, ok := r.db.documents[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	doc.Events = r.db.eventsOf(doc.ID)
+	return &doc, nil
+}
+
+func (r *PaymentDocumentRepository) FindByPaymentID(ctx context.Context, paymentID uuid.UUID) ([]models.PaymentDocument, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	docs := rows(r.db.documents, func(doc *models.PaymentDocument) bool { return doc.PaymentID == paymentID })
+	sort.SliceStable(docs, func(i, j int) bool { return docs[i].IssuedAt.Before(docs[j].IssuedAt) })
+	for i := range docs {
+		docs[i].Events = r.db.eventsOf(docs[i].ID)
+	}
+	return docs, nil
+}
+
+func (r *PaymentDocumentRepository) FindIssued(ctx context.Context, paymentID uuid.UUID, docType models.DocumentType) (*models.PaymentDocument, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	if doc, ok := r.db.issuedDocument(paymentID, docType); ok {
+		return &doc, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// issueDocument takes the next number of the document's series and
+// inserts it and its event, or nothing when a constraint fails, so a
+// failed issue does not consume a number.
+func (db *DB) issueDocument(doc *models.PaymentDocument, event *models.PaymentDocumentEvent) error {
+	key := sequenceKey{branchCode: doc.BranchCode, docType: doc.Type, year: doc.Year}
+	sequence := db.sequences[key] + 1
+
+	newID(&doc.ID)
+	now(&doc.IssuedAt)
+	doc.Sequence = sequence
+	doc.Number = models.DocumentNumber(doc.Type, doc.BranchCode, doc.Year, sequence)
+	doc.Status = models.DocumentStatusIssued
+	if _, ok := db.documents[doc.ID]; ok {
+		return gorm.ErrDuplicatedKey
+	}
+	if _, ok := db.payments[doc.PaymentID]; !ok {
+		return gorm.ErrForeignKeyViolated
+	}
+	if doc.ReplacesDocumentID != nil {
+		if _, ok := db.documents[*doc.ReplacesDocumentID]; !ok {
+			return gorm.ErrForeignKeyViolated
+		}
+	}
+	if doc.Type == models.DocumentTypeTaxInvoice && (doc.BuyerTaxID == nil || doc.BuyerAddress == nil) {
+		return gorm.ErrCheckConstraintViolated
+	}
+	for _, other := range db.documents {
+		if other.Number == doc.Number ||
+			(other.BranchCode == doc.BranchCode && other.Type == doc.Type && other.Year == doc.Year && other.Sequence == doc.Sequence) {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	// at most one live document of each type per payment
+	if _, ok := db.issuedDocument(doc.PaymentID, doc.Type); ok {
+		return gorm.ErrDuplicatedKey
+	}
+
+	event.DocumentID = doc.ID
+	newID(&event.ID)
+	now(&event.CreatedAt)
+	if _, ok := db.documentEvents[event.ID]; ok {
+		return gorm.ErrDuplicatedKey
+	}
+
+	db.sequences[key] = sequence
+	db.documents[doc.ID] = cloneDocument(*doc)
+	db.documentEvents[event.ID] = *event
+	return nil
+}
+
+// voidDocument voids an issued document and records event. The returned
+// func undoes both, for a caller whose next step fails.
+func (db *DB) voidDocument(doc *models.PaymentDocument, event *models.PaymentDocumentEvent) (undo func(), err error) {
+	stored, ok := db.documents[doc.ID]
+	if !ok || stored.Status != models.DocumentStatusIssued {
+		return nil, repository.ErrDocumentNotIssued
+	}
+	event.DocumentID = doc.ID
+	newID(&event.ID)
+	now(&event.CreatedAt)
+	if _, ok := db.documentEvents[event.ID]; ok {
+		return nil, gorm.ErrDuplicatedKey
+	}
+
+	voidedAt := time.Now().UTC()
+	voided := stored
+	voided.Status = models.DocumentStatusVoided
+	voided.VoidedAt = &voidedAt
+	voided.VoidReason = event.Reason
+	db.documents[doc.ID] = voided
+	db.documentEvents[event.ID] = *event
+
+	doc.Status = voided.Status
+	doc.VoidedAt = voided.VoidedAt
+	doc.VoidReason = voided.VoidReason
+	return func() {
+		db.documents[doc.ID] = stored
+		delete(db.documentEvents, event.ID)
+	}, nil
+}
+
+func (db *DB) issuedDocument(paymentID uuid.UUID, docType models.DocumentType) (models.PaymentDocument, bool) {
+	for _, doc := range db.documents {
+		if doc.PaymentID == paymentID && doc.Type == docType && doc.Status == models.DocumentStatusIssued {
+			return doc, true
+		}
+	}
+	return models.PaymentDocument{}, false
+}
+
+// eventsOf returns the events of a document in the order they happened.
+func (db *DB) eventsOf(documentID uuid.UUID) []models.PaymentDocumentEvent {
+	events := rows(db.documentEvents, func(event *models.PaymentDocumentEvent) bool {
+		return event.DocumentID == documentID
+	})
+	sort.SliceStable(events, func(i, j int) bool { return events[i].CreatedAt.Before(events[j].CreatedAt) })
+	return events
+}
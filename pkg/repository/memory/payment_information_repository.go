@@ -0,0 +1,156 @@
+package memory
+
+import (
+	"context"
+	"payment-service/pkg/models"
+	"payment-service/pkg/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PaymentInformationRepository struct {
+	db *DB
+}
+
+func NewPaymentInformationRepository(db *DB) *PaymentInformationRepository {
+	return &PaymentInformationRepository{
+		db: db,
+	}
+}
+
+func (r *PaymentInformationRepository) Create(ctx context.Context, paymentInfo *models.PaymentInformation) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	newID(&paymentInfo.ID)
+	now(&paymentInfo.CreatedAt)
+	if paymentInfo.Version == 0 {
+		paymentInfo.Version = 1
+	}
+	if _, ok := r.db.paymentInfos[paymentInfo.ID]; ok {
+		return gorm.ErrDuplicatedKey
+	}
+	if r.db.paymentProfileTaken(paymentInfo) {
+		return gorm.ErrDuplicatedKey
+	}
+	r.db.paymentInfos[paymentInfo.ID] = clonePaymentInfo(*paymentInfo)
+	return nil
+}
+
+func (r *PaymentInformationRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.PaymentInformation, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	paymentInfo, ok := r.db.paymentInfos[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	paymentInfo = clonePaymentInfo(paymentInfo)
+	return &paymentInfo, nil
+}
+
+func (r *PaymentInformationRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]models.PaymentInformation, error) {
+	wanted := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	return r.find(func(p *models.PaymentInformation) bool { return wanted[p.ID] }), nil
+}
+
+func (r *PaymentInformationRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]models.PaymentInformation, error) {
+	return r.find(func(p *models.PaymentInformation) bool { return p.UserID == userID }), nil
+}
+
+func (r *PaymentInformationRepository) FindByUserIDAndType(ctx context.Context, userID uuid.UUID, paymentMethod string) ([]models.PaymentInformation, error) {
+	return r.find(func(p *models.PaymentInformation) bool {
+		return p.UserID == userID && string(p.Type) == paymentMethod
+	}), nil
+}
+
+func (r *PaymentInformationRepository) FindPage(ctx context.Context, filter repository.PaymentInformationFilter, page repository.KeysetPage) ([]models.PaymentInformation, *uuid.UUID, error) {
+	paymentInfos := r.find(func(p *models.PaymentInformation) bool {
+		if filter.UserID != nil && p.UserID != *filter.UserID {
+			return false
+		}
+		return filter.Method == "" || p.Type == filter.Method
+	})
+	paymentInfos, next := pageOf(page, paymentInfos, func(p *models.PaymentInformation) uuid.UUID { return p.ID })
+	return paymentInfos, next, nil
+}
+
+func (r *PaymentInformationRepository) Update(ctx context.Context, paymentInfo *models.PaymentInformation) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	stored, ok := r.db.paymentInfos[paymentInfo.ID]
+	if !ok || stored.Version != paymentInfo.Version {
+		return repository.ErrVersionConflict
+	}
+	updated := clonePaymentInfo(*paymentInfo)
+	updated.Version++
+	if r.db.paymentProfileTaken(&updated) {
+		return gorm.ErrDuplicatedKey
+	}
+	r.db.paymentInfos[paymentInfo.ID] = updated
+	paymentInfo.Version = updated.Version
+	return nil
+}
+
+func (r *PaymentInformationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	r.db.deletePaymentInfo(id)
+	return nil
+}
+
+func (r *PaymentInformationRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	for id, paymentInfo := range r.db.paymentInfos {
+		if paymentInfo.UserID == userID {
+			r.db.deletePaymentInfo(id)
+		}
+	}
+	return nil
+}
+
+func (r *PaymentInformationRepository) find(match func(*models.PaymentInformation) bool) []models.PaymentInformation {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	paymentInfos := rows(r.db.paymentInfos, match)
+	for i := range paymentInfos {
+		paymentInfos[i] = clonePaymentInfo(paymentInfos[i])
+	}
+	return paymentInfos
+}
+
+// paymentProfileTaken checks unique_payment_profile (user_id, type)
+// against every other row.
+func (db *DB) paymentProfileTaken(paymentInfo *models.PaymentInformation) bool {
+	for id, other := range db.paymentInfos {
+		if id != paymentInfo.ID && other.UserID == paymentInfo.UserID &&
+			other.Type == paymentInfo.Type {
+			return true
+		}
+	}
+	return false
+}
+
+// deletePaymentInfo deletes a row; attempts made with it keep a null
+// payment_information_id, as fk_attempt_profile is ON DELETE SET NULL.
+func (db *DB) deletePaymentInfo(id uuid.UUID) {
+	if _, ok := db.paymentInfos[id]; !ok {
+		return
+	}
+	delete(db.paymentInfos, id)
+	for attemptID, attempt := range db.attempts {
+		if attempt.PaymentInformationID != nil && *attempt.PaymentInformationID == id {
+			attempt.PaymentInformationID = nil
+			db.attempts[attemptID] = attempt
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"context"
+	"payment-service/pkg/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Line items are written with the attempt or payment that owns them.
+type PaymentLineItemRepository struct {
+	db *DB
+}
+
+func NewPaymentLineItemRepository(db *DB) *PaymentLineItemRepository {
+	return &PaymentLineItemRepository{
+		db: db,
+	}
+}
+
+func (r *PaymentLineItemRepository) FindByAttemptID(ctx context.Context, attemptID uuid.UUID) ([]models.PaymentLineItem, error) {
+	return r.find(func(item *models.PaymentLineItem) bool {
+		return item.AttemptID != nil && *item.AttemptID == attemptID
+	}), nil
+}
+
+func (r *PaymentLineItemRepository) FindByPaymentID(ctx context.Context, paymentID uuid.UUID) ([]models.PaymentLineItem, error) {
+	return r.find(func(item *models.PaymentLineItem) bool {
+		return item.PaymentID != nil && *item.PaymentID == paymentID
+	}), nil
+}
+
+func (r *PaymentLineItemRepository) find(match func(*models.PaymentLineItem) bool) []models.PaymentLineItem {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	return rows(r.db.lineItems, match)
+}
+
+// checkLineItems fills the defaults of items about to be inserted and
+// checks their keys. Each item belongs to exactly one attempt or payment.
+func (db *DB) checkLineItems(items []models.PaymentLineItem) error {
+	seen := make(map[uuid.UUID]bool, len(items))
+	for i := range items {
+		item := &items[i]
+		newID(&item.ID)
+		now(&item.CreatedAt)
+		if _, ok := db.lineItems[item.ID]; ok || seen[item.ID] {
+			return gorm.ErrDuplicatedKey
+		}
+		seen[item.ID] = true
+		if (item.AttemptID == nil) == (item.PaymentID == nil) {
+			return gorm.ErrCheckConstraintViolated
+		}
+	}
+	return nil
+}
+
+func (db *DB) insertLineItems(items []models.PaymentLineItem) {
+	for _, item := range items {
+		db.lineItems[item.ID] = item
+	}
+}
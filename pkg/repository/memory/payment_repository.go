@@ -0,0 +1,344 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"payment-service/pkg/models"
+	"payment-service/pkg/repository"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PaymentRepository struct {
+	db *DB
+}
+
+func NewPaymentRepository(db *DB) *PaymentRepository {
+	return &PaymentRepository{
+		db: db,
+	}
+}
+
+// Create inserts the payment with its line items and receivables, as gorm
+// does with associations.
+func (r *PaymentRepository) Create(ctx context.Context, payment *models.Payment) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	newID(&payment.ID)
+	now(&payment.PaidAt)
+	if payment.LockVersion == 0 {
+		payment.LockVersion = 1
+	}
+	if _, ok := r.db.payments[payment.ID]; ok {
+		return gorm.ErrDuplicatedKey
+	}
+	if _, ok := r.db.attempts[payment.AttemptID]; !ok {
+		return gorm.ErrForeignKeyViolated
+	}
+	// unique_payment_attempt
+	for _, existing := range r.db.payments {
+		if existing.AttemptID == payment.AttemptID {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	if payment.Amount < 0 {
+		return gorm.ErrCheckConstraintViolated
+	}
+
+	for i := range payment.LineItems {
+		payment.LineItems[i].PaymentID = &payment.ID
+	}
+	if err := r.db.checkLineItems(payment.LineItems); err != nil {
+		return err
+	}
+	payers := make(map[string]bool)
+	for i := range payment.Receivables {
+		receivable := &payment.Receivables[i]
+		receivable.PaymentID = payment.ID
+		newID(&receivable.ID)
+		now(&receivable.CreatedAt)
+		if _, ok := r.db.receivables[receivable.ID]; ok {
+			return gorm.ErrDuplicatedKey
+		}
+		// unique_receivable_payer
+		payer := string(receivable.PayerType) + "/" + receivable.PayerName
+		if payers[payer] {
+			return gorm.ErrDuplicatedKey
+		}
+		payers[payer] = true
+	}
+
+	r.db.payments[payment.ID] = clonePayment(*payment)
+	r.db.insertLineItems(payment.LineItems)
+	for _, receivable := range payment.Receivables {
+		r.db.receivables[receivable.ID] = receivable
+	}
+	return nil
+}
+
+func (r *PaymentRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Payment, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	payment, ok := r.db.payments[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &payment, nil
+}
+
+// FindByIDForUpdate needs no lock of its own: units of work run one at a
+// time.
+func (r *PaymentRepository) FindByIDForUpdate(ctx context.Context, id uuid.UUID) (*models.Payment, error) {
+	return r.FindByID(ctx, id)
+}
+
+func (r *PaymentRepository) FindByPayable(ctx context.Context, payableType models.PayableType, payableID uuid.UUID) ([]models.Payment, error) {
+	return r.find(func(p *models.Payment) bool {
+		return p.PayableType == payableType && p.PayableID == payableID
+	}), nil
+}
+
+func (r *PaymentRepository) FindByOrderID(ctx context.Context, orderID uuid.UUID) ([]models.Payment, error) {
+	return r.FindByPayable(ctx, models.PayableTypeOrder, orderID)
+}
+
+func (r *PaymentRepository) FindByAttemptID(ctx context.Context, attemptID uuid.UUID) ([]models.Payment, error) {
+	return r.find(func(p *models.Payment) bool { return p.AttemptID == attemptID }), nil
+}
+
+func (r *PaymentRepository) FindPage(ctx context.Context, filter repository.PaymentFilter, page repository.KeysetPage) ([]models.Payment, *uuid.UUID, error) {
+	r.db.mu.RLock()
+	payments := rows(r.db.payments, func(p *models.Payment) bool {
+		if filter.Status != "" || filter.Method != "" || filter.UserID != nil {
+			attempt, ok := r.db.attempts[p.AttemptID]
+			if !ok {
+				return false
+			}
+			if filter.Status != "" && attempt.Status != filter.Status {
+				return false
+			}
+			if filter.Method != "" && attempt.Method != filter.Method {
+				return false
+			}
+			if filter.UserID != nil && (attempt.UserID == nil || *attempt.UserID != *filter.UserID) {
+				return false
+			}
+		}
+		if filter.PayableType != "" && p.PayableType != filter.PayableType {
+			return false
+		}
+		if filter.PayableID != nil && p.PayableID != *filter.PayableID {
+			return false
+		}
+		if filter.PaidFrom != nil && p.PaidAt.Before(*filter.PaidFrom) {
+			return false
+		}
+		return filter.PaidTo == nil || p.PaidAt.Before(*filter.PaidTo)
+	})
+	r.db.mu.RUnlock()
+
+	payments, next := pageOf(page, payments, func(p *models.Payment) uuid.UUID { return p.ID })
+	return payments, next, nil
+}
+
+func (r *PaymentRepository) SummarizeByPayables(ctx context.Context, refs []repository.PayableRef) ([]repository.PayableSummary, error) {
+	if len(refs) == 0 {
+		return nil, nil
+	}
+	wanted := make(map[repository.PayableRef]bool, len(refs))
+	for _, ref := range refs {
+		wanted[ref] = true
+	}
+
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	summaries := make(map[repository.PayableRef]*repository.PayableSummary)
+	summaryOf := func(ref repository.PayableRef) *repository.PayableSummary {
+		summary, ok := summaries[ref]
+		if !ok {
+			summary = &repository.PayableSummary{PayableType: ref.Type, PayableID: ref.ID}
+			summaries[ref] = summary
+		}
+		return summary
+	}
+
+	latest := make(map[repository.PayableRef]models.PaymentAttempt)
+	for _, attempt := range r.db.attempts {
+		ref := repository.PayableRef{Type: attempt.PayableType, ID: attempt.PayableID}
+		if !wanted[ref] {
+			continue
+		}
+		summaryOf(ref).AttemptCount++
+		current, ok := latest[ref]
+		if !ok || attempt.CreatedAt.After(current.CreatedAt) ||
+			(attempt.CreatedAt.Equal(current.CreatedAt) && bytes.Compare(attempt.ID[:], current.ID[:]) > 0) {
+			latest[ref] = attempt
+		}
+	}
+	for ref, attempt := range latest {
+		summaries[ref].LatestAttemptStatus = attempt.Status
+	}
+	for _, payment := range r.db.payments {
+		ref := repository.PayableRef{Type: payment.PayableType, ID: payment.PayableID}
+		if !wanted[ref] {
+			continue
+		}
+		summary := summaryOf(ref)
+		summary.PaymentCount++
+		summary.TotalPaid += payment.Amount
+	}
+
+	result := make([]repository.PayableSummary, 0, len(summaries))
+	for _, ref := range refs {
+		if summary, ok := summaries[ref]; ok {
+			result = append(result, *summary)
+			delete(summaries, ref)
+		}
+	}
+	return result, nil
+}
+
+// StreamForExport reads the matching rows up front and calls fn without
+// holding the tables, so fn may use other repositories.
+func (r *PaymentRepository) StreamForExport(ctx context.Context, from, to time.Time, fn func(row *repository.PaymentExportRow) error) error {
+	r.db.mu.RLock()
+	var exportRows []repository.PaymentExportRow
+	for _, payment := range r.db.payments {
+		if payment.PaidAt.Before(from) || !payment.PaidAt.Before(to) {
+			continue
+		}
+		attempt, ok := r.db.attempts[payment.AttemptID]
+		if !ok {
+			continue
+		}
+		row := repository.PaymentExportRow{
+			ID:                    payment.ID,
+			AttemptID:             payment.AttemptID,
+			PayableType:           payment.PayableType,
+			PayableID:             payment.PayableID,
+			UserID:                attempt.UserID,
+			Method:                attempt.Method,
+			Amount:                payment.Amount,
+			PatientAmount:         payment.PatientAmount,
+			PayerAmount:           payment.PayerAmount,
+			HealthcareEntitlement: payment.HealthcareEntitlement,
+			PaidAt:                payment.PaidAt,
+		}
+		exportRows = append(exportRows, row)
+	}
+	r.db.mu.RUnlock()
+
+	sort.Slice(exportRows, func(i, j int) bool {
+		a, b := exportRows[i], exportRows[j]
+		if !a.PaidAt.Equal(b.PaidAt) {
+			return a.PaidAt.Before(b.PaidAt)
+		}
+		return bytes.Compare(a.ID[:], b.ID[:]) < 0
+	})
+	for i := range exportRows {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(&exportRows[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *PaymentRepository) Update(ctx context.Context, payment *models.Payment) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	stored, ok := r.db.payments[payment.ID]
+	if !ok || stored.LockVersion != payment.LockVersion {
+		return repository.ErrVersionConflict
+	}
+	if _, ok := r.db.attempts[payment.AttemptID]; !ok {
+		return gorm.ErrForeignKeyViolated
+	}
+	if payment.Amount < 0 {
+		return gorm.ErrCheckConstraintViolated
+	}
+	payment.LockVersion++
+	r.db.payments[payment.ID] = clonePayment(*payment)
+	return nil
+}
+
+func (r *PaymentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	return r.db.deletePayments(func(p *models.Payment) bool { return p.ID == id })
+}
+
+func (r *PaymentRepository) DeleteByPayable(ctx context.Context, payableType models.PayableType, payableID uuid.UUID) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	return r.db.deletePayments(func(p *models.Payment) bool {
+		return p.PayableType == payableType && p.PayableID == payableID
+	})
+}
+
+func (r *PaymentRepository) find(match func(*models.Payment) bool) []models.Payment {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	return rows(r.db.payments, match)
+}
+
+func (db *DB) deletePayments(match func(*models.Payment) bool) error {
+	ids := make(map[uuid.UUID]bool)
+	for id, payment := range db.payments {
+		if match(&payment) {
+			ids[id] = true
+		}
+	}
+	if err := db.checkPaymentsUnreferenced(ids); err != nil {
+		return err
+	}
+	db.removePayments(ids)
+	return nil
+}
+
+// checkPaymentsUnreferenced fails when a document was issued for one of
+// the payments or one was refunded; documents are kept for the tax
+// authority and refunds for reconciliation, and both restrict the delete.
+func (db *DB) checkPaymentsUnreferenced(ids map[uuid.UUID]bool) error {
+	for _, doc := range db.documents {
+		if ids[doc.PaymentID] {
+			return gorm.ErrForeignKeyViolated
+		}
+	}
+	for _, refund := range db.refunds {
+		if ids[refund.PaymentID] {
+			return gorm.ErrForeignKeyViolated
+		}
+	}
+	return nil
+}
+
+// removePayments deletes payments with their line items and receivables,
+// which cascade.
+func (db *DB) removePayments(ids map[uuid.UUID]bool) {
+	for id := range ids {
+		delete(db.payments, id)
+	}
+	for id, item := range db.lineItems {
+		if item.PaymentID != nil && ids[*item.PaymentID] {
+			delete(db.lineItems, id)
+		}
+	}
+	for id, receivable := range db.receivables {
+		if ids[receivable.PaymentID] {
+			delete(db.receivables, id)
+		}
+	}
+}
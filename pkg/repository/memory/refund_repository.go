@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"context"
+	"payment-service/pkg/models"
+	"sort"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RefundRepository struct {
+	db *DB
+}
+
+func NewRefundRepository(db *DB) *RefundRepository {
+	return &RefundRepository{
+		db: db,
+	}
+}
+
+func (r *RefundRepository) Create(ctx context.Context, refund *models.Refund) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	newID(&refund.ID)
+	now(&refund.CreatedAt)
+	if _, ok := r.db.refunds[refund.ID]; ok {
+		return gorm.ErrDuplicatedKey
+	}
+	if _, ok := r.db.payments[refund.PaymentID]; !ok {
+		return gorm.ErrForeignKeyViolated
+	}
+	if refund.Amount <= 0 {
+		return gorm.ErrCheckConstraintViolated
+	}
+	r.db.refunds[refund.ID] = *refund
+	return nil
+}
+
+func (r *RefundRepository) FindByPaymentIDs(ctx context.Context, paymentIDs []uuid.UUID) ([]models.Refund, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	wanted := make(map[uuid.UUID]bool, len(paymentIDs))
+	for _, id := range paymentIDs {
+		wanted[id] = true
+	}
+	refunds := rows(r.db.refunds, func(refund *models.Refund) bool { return wanted[refund.PaymentID] })
+	sort.SliceStable(refunds, func(i, j int) bool { return refunds[i].CreatedAt.Before(refunds[j].CreatedAt) })
+	return refunds, nil
+}
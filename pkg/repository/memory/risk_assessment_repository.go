@@ -0,0 +1,143 @@
+package memory
+
+import (
+	"context"
+	"payment-service/pkg/models"
+	"payment-service/pkg/repository"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Assessments are written with the attempt they belong to.
+type RiskAssessmentRepository struct {
+	db *DB
+}
+
+func NewRiskAssessmentRepository(db *DB) *RiskAssessmentRepository {
+	return &RiskAssessmentRepository{
+		db: db,
+	}
+}
+
+// Lock has nothing to do: units of work over the memory tables already run
+// one at a time.
+func (r *RiskAssessmentRepository) Lock(ctx context.Context, subjects ...string) error {
+	return nil
+}
+
+func (r *RiskAssessmentRepository) CountByUserSince(ctx context.Context, userID uuid.UUID, since time.Time) (total int, failed int, blocked int, err error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, assessment := range r.db.assessments {
+		if assessment.UserID != userID || !assessment.CreatedAt.After(since) {
+			continue
+		}
+		attempt, ok := r.db.attempts[assessment.AttemptID]
+		if !ok {
+			continue
+		}
+		total++
+		switch {
+		case assessment.Decision == models.RiskDecisionBlock:
+			blocked++
+		case attempt.Status == models.PaymentStatusFailed:
+			failed++
+		}
+	}
+	return total, failed, blocked, nil
+}
+
+func (r *RiskAssessmentRepository) CountByCardSince(ctx context.Context, fingerprint string, since time.Time) (int, error) {
+	return r.count(func(a *models.RiskAssessment) bool {
+		return a.CardFingerprint != nil && *a.CardFingerprint == fingerprint && a.CreatedAt.After(since)
+	}), nil
+}
+
+func (r *RiskAssessmentRepository) CountByIPSince(ctx context.Context, clientIP string, since time.Time) (int, error) {
+	return r.count(func(a *models.RiskAssessment) bool {
+		return a.ClientIP != nil && *a.ClientIP == clientIP && a.CreatedAt.After(since)
+	}), nil
+}
+
+// CardSeen reports whether any earlier attempt used the card.
+func (r *RiskAssessmentRepository) CardSeen(ctx context.Context, fingerprint string) (bool, error) {
+	return r.count(func(a *models.RiskAssessment) bool {
+		return a.CardFingerprint != nil && *a.CardFingerprint == fingerprint
+	}) > 0, nil
+}
+
+// ReviewPending reports whether the attempt is held for a review that no
+// admin has closed yet.
+func (r *RiskAssessmentRepository) ReviewPending(ctx context.Context, attemptID uuid.UUID) (bool, error) {
+	return r.count(func(a *models.RiskAssessment) bool {
+		return a.AttemptID == attemptID && a.ReviewStatus != nil && *a.ReviewStatus == models.ReviewStatusPending
+	}) > 0, nil
+}
+
+func (r *RiskAssessmentRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.RiskAssessment, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	assessment, ok := r.db.assessments[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	assessment = cloneAssessment(assessment)
+	return &assessment, nil
+}
+
+// FindPendingReviews returns the review queue, oldest first.
+func (r *RiskAssessmentRepository) FindPendingReviews(ctx context.Context) ([]models.RiskAssessment, error) {
+	r.db.mu.RLock()
+	assessments := rows(r.db.assessments, func(a *models.RiskAssessment) bool {
+		return a.ReviewStatus != nil && *a.ReviewStatus == models.ReviewStatusPending
+	})
+	r.db.mu.RUnlock()
+
+	for i := range assessments {
+		assessments[i] = cloneAssessment(assessments[i])
+	}
+	sort.SliceStable(assessments, func(i, j int) bool { return assessments[i].CreatedAt.Before(assessments[j].CreatedAt) })
+	return assessments, nil
+}
+
+// Review closes a pending review and moves its attempt to attemptStatus in
+// one step. It returns repository.ErrReviewNotPending when another admin
+// got there first.
+func (r *RiskAssessmentRepository) Review(ctx context.Context, assessment *models.RiskAssessment, attemptStatus models.PaymentStatus) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	stored, ok := r.db.assessments[assessment.ID]
+	if !ok || stored.ReviewStatus == nil || *stored.ReviewStatus != models.ReviewStatusPending {
+		return repository.ErrReviewNotPending
+	}
+	stored.ReviewStatus = assessment.ReviewStatus
+	stored.ReviewedBy = assessment.ReviewedBy
+	stored.ReviewedAt = assessment.ReviewedAt
+	r.db.assessments[assessment.ID] = stored
+
+	if attempt, ok := r.db.attempts[assessment.AttemptID]; ok {
+		attempt.Status = attemptStatus
+		attempt.LockVersion++
+		r.db.attempts[attempt.ID] = attempt
+	}
+	return nil
+}
+
+func (r *RiskAssessmentRepository) count(match func(*models.RiskAssessment) bool) int {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	count := 0
+	for _, assessment := range r.db.assessments {
+		if match(&assessment) {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"context"
+	"payment-service/pkg/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TokenRevocationRepository struct {
+	db *DB
+}
+
+func NewTokenRevocationRepository(db *DB) *TokenRevocationRepository {
+	return &TokenRevocationRepository{
+		db: db,
+	}
+}
+
+func (r *TokenRevocationRepository) Create(ctx context.Context, revocation *models.TokenRevocation) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	newID(&revocation.ID)
+	now(&revocation.CreatedAt)
+	if _, ok := r.db.revocations[revocation.ID]; ok {
+		return gorm.ErrDuplicatedKey
+	}
+	if (revocation.Jti == nil) == (revocation.UserID == nil) {
+		return gorm.ErrCheckConstraintViolated
+	}
+	r.db.revocations[revocation.ID] = *revocation
+	return nil
+}
+
+func (r *TokenRevocationRepository) FindActive(ctx context.Context, now time.Time) ([]models.TokenRevocation, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	return rows(r.db.revocations, func(revocation *models.TokenRevocation) bool {
+		return revocation.ExpiresAt.After(now)
+	}), nil
+}
+
+func (r *TokenRevocationRepository) DeleteExpired(ctx context.Context, now time.Time) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	for id, revocation := range r.db.revocations {
+		if !revocation.ExpiresAt.After(now) {
+			delete(r.db.revocations, id)
+		}
+	}
+	return nil
+}
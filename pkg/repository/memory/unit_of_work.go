@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"context"
+
+	"payment-service/pkg/repository"
+)
+
+// UnitOfWork runs fn over a copy of the tables and swaps the copy in when fn
+// succeeds. Units of work run one at a time; changes made outside one while
+// it runs are lost when it commits.
+type UnitOfWork struct {
+	db *DB
+}
+
+func NewUnitOfWork(db *DB) *UnitOfWork {
+	return &UnitOfWork{
+		db: db,
+	}
+}
+
+// txKey finds the copy a unit of work works on in the context it passes to
+// fn.
+type txKey struct {
+	unitOfWork *UnitOfWork
+}
+
+func (u *UnitOfWork) Do(ctx context.Context, fn func(ctx context.Context, repos repository.Repositories) error) error {
+	if tx, ok := ctx.Value(txKey{u}).(*DB); ok {
+		return savepoint(ctx, tx, fn)
+	}
+
+	u.db.txMu.Lock()
+	defer u.db.txMu.Unlock()
+
+	u.db.mu.RLock()
+	tx := &DB{tables: u.db.copy()}
+	u.db.mu.RUnlock()
+
+	if err := fn(context.WithValue(ctx, txKey{u}, tx), NewRepositories(tx)); err != nil {
+		return err
+	}
+
+	u.db.mu.Lock()
+	u.db.tables = tx.tables
+	u.db.mu.Unlock()
+	return nil
+}
+
+// savepoint runs a nested unit of work on tx and puts its tables back when
+// fn fails or panics.
+func savepoint(ctx context.Context, tx *DB, fn func(ctx context.Context, repos repository.Repositories) error) (err error) {
+	tx.mu.RLock()
+	saved := tx.copy()
+	tx.mu.RUnlock()
+
+	rollback := func() {
+		tx.mu.Lock()
+		tx.tables = saved
+		tx.mu.Unlock()
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			rollback()
+			panic(r)
+		}
+	}()
+
+	if err = fn(ctx, NewRepositories(tx)); err != nil {
+		rollback()
+	}
+	return err
+}
+
+// NewRepositories creates every repository over db.
+func NewRepositories(db *DB) repository.Repositories {
+	return repository.Repositories{
+		PaymentInformations: NewPaymentInformationRepository(db),
+		PaymentAttempts:     NewPaymentAttemptRepository(db),
+		Payments:            NewPaymentRepository(db),
+		Refunds:             NewRefundRepository(db),
+		PaymentLineItems:    NewPaymentLineItemRepository(db),
+		CoverageRules:       NewCoverageRuleRepository(db),
+		PayerReceivables:    NewPayerReceivableRepository(db),
+		PaymentDocuments:    NewPaymentDocumentRepository(db),
+		AuditLogs:           NewAuditLogRepository(db),
+		RiskAssessments:     NewRiskAssessmentRepository(db),
+		TokenRevocations:    NewTokenRevocationRepository(db),
+	}
+}
@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"payment-service/pkg/models"
+	"payment-service/pkg/repository"
+	"payment-service/pkg/utils"
+)
+
+func TestUnitOfWork(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		name string
+		run  func(u *UnitOfWork, create func(repos repository.Repositories) error) error
+		want int
+	}{
+		{"commits", func(u *UnitOfWork, create func(repos repository.Repositories) error) error {
+			return u.Do(context.Background(), func(ctx context.Context, repos repository.Repositories) error {
+				return create(repos)
+			})
+		}, 1},
+		{"rolls back on error", func(u *UnitOfWork, create func(repos repository.Repositories) error) error {
+			return u.Do(context.Background(), func(ctx context.Context, repos repository.Repositories) error {
+				if err := create(repos); err != nil {
+					return err
+				}
+				return errFailed
+			})
+		}, 0},
+		{"rolls back on panic", func(u *UnitOfWork, create func(repos repository.Repositories) error) (err error) {
+			defer func() {
+				if recover() == nil {
+					err = errors.New("panic not passed on")
+				}
+			}()
+			return u.Do(context.Background(), func(ctx context.Context, repos repository.Repositories) error {
+				if err := create(repos); err != nil {
+					return err
+				}
+				panic(errFailed)
+			})
+		}, 0},
+		{"nested failure keeps the outer work", func(u *UnitOfWork, create func(repos repository.Repositories) error) error {
+			return u.Do(context.Background(), func(ctx context.Context, repos repository.Repositories) error {
+				if err := create(repos); err != nil {
+					return err
+				}
+				err := u.Do(ctx, func(ctx context.Context, repos repository.Repositories) error {
+					if err := create(repos); err != nil {
+						return err
+					}
+					return errFailed
+				})
+				if !errors.Is(err, errFailed) {
+					return errors.New("nested error not returned")
+				}
+				return nil
+			})
+		}, 1},
+		{"nested work commits with the outer", func(u *UnitOfWork, create func(repos repository.Repositories) error) error {
+			return u.Do(context.Background(), func(ctx context.Context, repos repository.Repositories) error {
+				return u.Do(ctx, func(ctx context.Context, repos repository.Repositories) error {
+					return create(repos)
+				})
+			})
+		}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := New()
+			create := func(repos repository.Repositories) error {
+				return repos.PaymentAttempts.Create(context.Background(), &models.PaymentAttempt{PayableID: utils.GenerateUUIDv7()})
+			}
+			if err := tt.run(NewUnitOfWork(db), create); err != nil && !errors.Is(err, errFailed) {
+				t.Fatal(err)
+			}
+			if got := len(db.attempts); got != tt.want {
+				t.Fatalf("got %d attempts, want %d", got, tt.want)
+			}
+		})
+	}
+}
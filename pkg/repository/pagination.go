@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// KeysetPage selects up to Limit rows after the row with id After. IDs are
+// UUIDv7, which sort by creation time, so paging by id is paging by time and
+// stays fast however deep the client goes.
+type KeysetPage struct {
+	After      *uuid.UUID
+	Descending bool
+	Limit      int
+}
+
+// apply orders query by column and asks for one row more than the page, so
+// that page can tell whether another follows.
+func (p KeysetPage) apply(query *gorm.DB, column string) *gorm.DB {
+	if p.Descending {
+		if p.After != nil {
+			query = query.Where(column+" < ?", *p.After)
+		}
+		query = query.Order(column + " DESC")
+	} else {
+		if p.After != nil {
+			query = query.Where(column+" > ?", *p.After)
+		}
+		query = query.Order(column + " ASC")
+	}
+	return query.Limit(p.Limit + 1)
+}
+
+// trim cuts the extra row fetched by apply and returns the cursor of the
+// next page, or nil on the last page.
+func trim[T any](p KeysetPage, rows []T, id func(*T) uuid.UUID) ([]T, *uuid.UUID) {
+	if len(rows) <= p.Limit {
+		return rows, nil
+	}
+	rows = rows[:p.Limit]
+	next := id(&rows[len(rows)-1])
+	return rows, &next
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"payment-service/pkg/models"
+
+	"gorm.io/gorm"
+)
+
+type PayerReceivableRepository struct {
+	db *gorm.DB
+}
+
+func NewPayerReceivableRepository(db *gorm.DB) *PayerReceivableRepository {
+	return &PayerReceivableRepository{
+		db: db,
+	}
+}
+
+func (r *PayerReceivableRepository) FindByStatus(ctx context.Context, status models.ReceivableStatus) ([]models.PayerReceivable, error) {
+	var receivables []models.PayerReceivable
+	if err := r.db.WithContext(ctx).Where("status = ?", status).Order("created_at, id").Find(&receivables).Error; err != nil {
+		return nil, err
+	}
+	return receivables, nil
+}
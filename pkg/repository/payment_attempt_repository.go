@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PaymentAttemptRepository struct {
@@ -18,28 +19,6 @@ func NewPaymentAttemptRepository(db *gorm.DB) *PaymentAttemptRepository {
 	}
 }
 
-func (r *PaymentAttemptRepository) Transaction(ctx context.Context, fn func(repo *PaymentAttemptRepository) (interface{}, error)) (interface{}, error) {
-	tx := r.db.Begin()
-	if tx.Error != nil {
-		return nil, tx.Error
-	}
-	repoWithTx := r.withTx(tx)
-
-	result, err := fn(repoWithTx)
-	if err != nil {
-		tx.Rollback()
-		return nil, err
-	}
-	if err := tx.Commit().Error; err != nil {
-		return nil, err
-	}
-	return result, nil
-}
-
-func (r *PaymentAttemptRepository) withTx(tx *gorm.DB) *PaymentAttemptRepository {
-	return &PaymentAttemptRepository{db: tx}
-}
-
 func (r *PaymentAttemptRepository) Create(ctx context.Context, attempt *models.PaymentAttempt) error {
 	return r.db.WithContext(ctx).Create(attempt).Error
 }
@@ -55,22 +34,49 @@ func (r *PaymentAttemptRepository) FindByID(ctx context.Context, id uuid.UUID) (
 	return &attempt, nil
 }
 
-func (r *PaymentAttemptRepository) FindByOrderID(ctx context.Context, orderID uuid.UUID) ([]models.PaymentAttempt, error) {
+// FindByIDForUpdate reads an attempt and locks it until the transaction
+// ends, so nothing else changes it in the meantime. Outside a unit of work
+// the lock is released right away.
+func (r *PaymentAttemptRepository) FindByIDForUpdate(ctx context.Context, id uuid.UUID) (*models.PaymentAttempt, error) {
+	var attempt models.PaymentAttempt
+	if err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&attempt).Error; err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+func (r *PaymentAttemptRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]models.PaymentAttempt, error) {
 	var attempts []models.PaymentAttempt
-	if err := r.db.WithContext(ctx).Where("order_id = ?", orderID).Find(&attempts).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&attempts).Error; err != nil {
 		return nil, err
 	}
 	return attempts, nil
 }
 
-func (r *PaymentAttemptRepository) FindByOrderIDAndStatus(ctx context.Context, orderID uuid.UUID, status models.PaymentStatus) ([]models.PaymentAttempt, error) {
+func (r *PaymentAttemptRepository) FindByPayable(ctx context.Context, payableType models.PayableType, payableID uuid.UUID) ([]models.PaymentAttempt, error) {
+	var attempts []models.PaymentAttempt
+	if err := r.db.WithContext(ctx).Where("payable_type = ? AND payable_id = ?", payableType, payableID).Find(&attempts).Error; err != nil {
+		return nil, err
+	}
+	return attempts, nil
+}
+
+func (r *PaymentAttemptRepository) FindByPayableAndStatus(ctx context.Context, payableType models.PayableType, payableID uuid.UUID, status models.PaymentStatus) ([]models.PaymentAttempt, error) {
 	var attempts []models.PaymentAttempt
-	if err := r.db.WithContext(ctx).Where("order_id = ? AND status = ?", orderID, status).Find(&attempts).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("payable_type = ? AND payable_id = ? AND status = ?", payableType, payableID, status).Find(&attempts).Error; err != nil {
 		return nil, err
 	}
 	return attempts, nil
 }
 
+func (r *PaymentAttemptRepository) FindByOrderID(ctx context.Context, orderID uuid.UUID) ([]models.PaymentAttempt, error) {
+	return r.FindByPayable(ctx, models.PayableTypeOrder, orderID)
+}
+
+func (r *PaymentAttemptRepository) FindByOrderIDAndStatus(ctx context.Context, orderID uuid.UUID, status models.PaymentStatus) ([]models.PaymentAttempt, error) {
+	return r.FindByPayableAndStatus(ctx, models.PayableTypeOrder, orderID, status)
+}
+
 func (r *PaymentAttemptRepository) FindAll(ctx context.Context) ([]models.PaymentAttempt, error) {
 	var attempts []models.PaymentAttempt
 	if err := r.db.WithContext(ctx).Find(&attempts).Error; err != nil {
@@ -80,13 +86,30 @@ func (r *PaymentAttemptRepository) FindAll(ctx context.Context) ([]models.Paymen
 }
 
 func (r *PaymentAttemptRepository) Update(ctx context.Context, attempt *models.PaymentAttempt) error {
-	return r.db.WithContext(ctx).Model(attempt).Updates(attempt).Error
+	result := r.db.WithContext(ctx).Model(&models.PaymentAttempt{}).
+		Where("id = ? AND lock_version = ?", attempt.ID, attempt.LockVersion).
+		Updates(map[string]interface{}{
+			"payable_type":           attempt.PayableType,
+			"payable_id":             attempt.PayableID,
+			"payment_information_id": attempt.PaymentInformationID,
+			"method":                 attempt.Method,
+			"status":                 attempt.Status,
+			"lock_version":           attempt.LockVersion + 1,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	attempt.LockVersion++
+	return nil
 }
 
 func (r *PaymentAttemptRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.PaymentAttempt{}).Error
 }
 
-func (r *PaymentAttemptRepository) DeleteByOrderID(ctx context.Context, orderID uuid.UUID) error {
-	return r.db.WithContext(ctx).Where("order_id = ?", orderID).Delete(&models.PaymentAttempt{}).Error
+func (r *PaymentAttemptRepository) DeleteByPayable(ctx context.Context, payableType models.PayableType, payableID uuid.UUID) error {
+	return r.db.WithContext(ctx).Where("payable_type = ? AND payable_id = ?", payableType, payableID).Delete(&models.PaymentAttempt{}).Error
 }
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"payment-service/pkg/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrDocumentNotIssued = errors.New("document is not in issued state")
+
+type PaymentDocumentRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentDocumentRepository(db *gorm.DB) *PaymentDocumentRepository {
+	return &PaymentDocumentRepository{
+		db: db,
+	}
+}
+
+// Issue numbers and stores a new document together with its audit event.
+func (r *PaymentDocumentRepository) Issue(ctx context.Context, doc *models.PaymentDocument, event *models.PaymentDocumentEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return issueDocument(tx, doc, event)
+	})
+}
+
+// Void marks an issued document as voided. Its number stays consumed.
+func (r *PaymentDocumentRepository) Void(ctx context.Context, doc *models.PaymentDocument, event *models.PaymentDocumentEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return voidDocument(tx, doc, event)
+	})
+}
+
+// Reissue voids old and issues replacement under a new number atomically.
+func (r *PaymentDocumentRepository) Reissue(ctx context.Context, old *models.PaymentDocument, voidEvent *models.PaymentDocumentEvent, replacement *models.PaymentDocument, issueEvent *models.PaymentDocumentEvent) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := voidDocument(tx, old, voidEvent); err != nil {
+			return err
+		}
+		replacement.ReplacesDocumentID = &old.ID
+		return issueDocument(tx, replacement, issueEvent)
+	})
+}
+
+func (r *PaymentDocumentRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.PaymentDocument, error) {
+	var doc models.PaymentDocument
+	if err := r.db.WithContext(ctx).Preload("Events", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at, id")
+	}).Where("id = ?", id).First(&doc).Error; err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+func (r *PaymentDocumentRepository) FindByPaymentID(ctx context.Context, paymentID uuid.UUID) ([]models.PaymentDocument, error) {
+	var docs []models.PaymentDocument
+	if err := r.db.WithContext(ctx).Preload("Events", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at, id")
+	}).Where("payment_id = ?", paymentID).Order("issued_at, id").Find(&docs).Error; err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+func (r *PaymentDocumentRepository) FindIssued(ctx context.Context, paymentID uuid.UUID, docType models.DocumentType) (*models.PaymentDocument, error) {
+	var doc models.PaymentDocument
+	if err := r.db.WithContext(ctx).Where("payment_id = ? AND type = ? AND status = ?", paymentID, docType, models.DocumentStatusIssued).First(&doc).Error; err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+func issueDocument(tx *gorm.DB, doc *models.PaymentDocument, event *models.PaymentDocumentEvent) error {
+	// The upsert row-locks the counter until commit, serialising concurrent
+	// issues on the same series without leaving gaps on rollback.
+	var sequence int
+	if err := tx.Raw(`
+		INSERT INTO document_sequences (branch_code, type, year, last_number)
+		VALUES (?, ?, ?, 1)
+		ON CONFLICT (branch_code, type, year)
+		DO UPDATE SET last_number = document_sequences.last_number + 1
+		RETURNING last_number`,
+		doc.BranchCode, doc.Type, doc.Year,
+	).Scan(&sequence).Error; err != nil {
+		return err
+	}
+
+	doc.Sequence = sequence
+	doc.Number = models.DocumentNumber(doc.Type, doc.BranchCode, doc.Year, sequence)
+	doc.Status = models.DocumentStatusIssued
+	if err := tx.Omit("Events").Create(doc).Error; err != nil {
+		return err
+	}
+
+	event.DocumentID = doc.ID
+	return tx.Create(event).Error
+}
+
+func voidDocument(tx *gorm.DB, doc *models.PaymentDocument, event *models.PaymentDocumentEvent) error {
+	now := time.Now().UTC()
+	result := tx.Model(&models.PaymentDocument{}).
+		Where("id = ? AND status = ?", doc.ID, models.DocumentStatusIssued).
+		Updates(map[string]interface{}{
+			"status":      models.DocumentStatusVoided,
+			"voided_at":   now,
+			"void_reason": event.Reason,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrDocumentNotIssued
+	}
+
+	doc.Status = models.DocumentStatusVoided
+	doc.VoidedAt = &now
+	doc.VoidReason = event.Reason
+	event.DocumentID = doc.ID
+	return tx.Create(event).Error
+}
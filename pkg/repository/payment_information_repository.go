@@ -8,6 +8,13 @@ import (
 	"gorm.io/gorm"
 )
 
+// PaymentInformationFilter narrows a payment information listing; zero
+// fields do not filter.
+type PaymentInformationFilter struct {
+	UserID *uuid.UUID
+	Method models.PaymentMethod
+}
+
 type PaymentInformationRepository struct {
 	db *gorm.DB
 }
@@ -18,28 +25,6 @@ func NewPaymentInformationRepository(db *gorm.DB) *PaymentInformationRepository
 	}
 }
 
-func (r *PaymentInformationRepository) Transaction(ctx context.Context, fn func(repo *PaymentInformationRepository) (interface{}, error)) (interface{}, error) {
-	tx := r.db.Begin()
-	if tx.Error != nil {
-		return nil, tx.Error
-	}
-	repoWithTx := r.withTx(tx)
-
-	result, err := fn(repoWithTx)
-	if err != nil {
-		tx.Rollback()
-		return nil, err
-	}
-	if err := tx.Commit().Error; err != nil {
-		return nil, err
-	}
-	return result, nil
-}
-
-func (r *PaymentInformationRepository) withTx(tx *gorm.DB) *PaymentInformationRepository {
-	return &PaymentInformationRepository{db: tx}
-}
-
 func (r *PaymentInformationRepository) Create(ctx context.Context, paymentInfo *models.PaymentInformation) error {
 	return r.db.WithContext(ctx).Create(paymentInfo).Error
 }
@@ -55,6 +40,14 @@ func (r *PaymentInformationRepository) FindByID(ctx context.Context, id uuid.UUI
 	return &paymentInfo, nil
 }
 
+func (r *PaymentInformationRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]models.PaymentInformation, error) {
+	var paymentInfos []models.PaymentInformation
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&paymentInfos).Error; err != nil {
+		return nil, err
+	}
+	return paymentInfos, nil
+}
+
 func (r *PaymentInformationRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]models.PaymentInformation, error) {
 	var paymentInfos []models.PaymentInformation
 	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&paymentInfos).Error; err != nil {
@@ -71,16 +64,42 @@ func (r *PaymentInformationRepository) FindByUserIDAndType(ctx context.Context,
 	return paymentInfos, nil
 }
 
-func (r *PaymentInformationRepository) FindAll(ctx context.Context) ([]models.PaymentInformation, error) {
+// FindPage returns one page of matching payment information and the cursor
+// of the next page, nil when there is none.
+func (r *PaymentInformationRepository) FindPage(ctx context.Context, filter PaymentInformationFilter, page KeysetPage) ([]models.PaymentInformation, *uuid.UUID, error) {
+	query := r.db.WithContext(ctx)
+	if filter.UserID != nil {
+		query = query.Where("user_id = ?", *filter.UserID)
+	}
+	if filter.Method != "" {
+		query = query.Where("type = ?", filter.Method)
+	}
+
 	var paymentInfos []models.PaymentInformation
-	if err := r.db.WithContext(ctx).Find(&paymentInfos).Error; err != nil {
-		return nil, err
+	if err := page.apply(query, "id").Find(&paymentInfos).Error; err != nil {
+		return nil, nil, err
 	}
-	return paymentInfos, nil
+	paymentInfos, next := trim(page, paymentInfos, func(p *models.PaymentInformation) uuid.UUID { return p.ID })
+	return paymentInfos, next, nil
 }
 
 func (r *PaymentInformationRepository) Update(ctx context.Context, paymentInfo *models.PaymentInformation) error {
-	return r.db.WithContext(ctx).Model(paymentInfo).Updates(paymentInfo).Error
+	result := r.db.WithContext(ctx).Model(&models.PaymentInformation{}).
+		Where("id = ? AND version = ?", paymentInfo.ID, paymentInfo.Version).
+		Updates(map[string]interface{}{
+			"user_id": paymentInfo.UserID,
+			"type":    paymentInfo.Type,
+			"details": paymentInfo.Details,
+			"version": paymentInfo.Version + 1,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	paymentInfo.Version++
+	return nil
 }
 
 func (r *PaymentInformationRepository) Delete(ctx context.Context, id uuid.UUID) error {
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"payment-service/pkg/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PaymentLineItemRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentLineItemRepository(db *gorm.DB) *PaymentLineItemRepository {
+	return &PaymentLineItemRepository{
+		db: db,
+	}
+}
+
+func (r *PaymentLineItemRepository) FindByAttemptID(ctx context.Context, attemptID uuid.UUID) ([]models.PaymentLineItem, error) {
+	var items []models.PaymentLineItem
+	if err := r.db.WithContext(ctx).Where("attempt_id = ?", attemptID).Order("id").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *PaymentLineItemRepository) FindByPaymentID(ctx context.Context, paymentID uuid.UUID) ([]models.PaymentLineItem, error) {
+	var items []models.PaymentLineItem
+	if err := r.db.WithContext(ctx).Where("payment_id = ?", paymentID).Order("id").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
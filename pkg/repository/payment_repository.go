@@ -3,11 +3,57 @@ package repository
 import (
 	"context"
 	"payment-service/pkg/models"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// PaymentFilter narrows a payment listing; zero fields do not filter.
+// Status, Method and UserID are those of the attempt the payment settled.
+type PaymentFilter struct {
+	Status      models.PaymentStatus
+	Method      models.PaymentMethod
+	PayableType models.PayableType
+	PayableID   *uuid.UUID
+	UserID      *uuid.UUID
+	PaidFrom    *time.Time
+	PaidTo      *time.Time
+}
+
+// PayableRef names one payable.
+type PayableRef struct {
+	Type models.PayableType
+	ID   uuid.UUID
+}
+
+// PayableSummary aggregates the attempts and payments of one payable.
+type PayableSummary struct {
+	PayableType         models.PayableType
+	PayableID           uuid.UUID
+	AttemptCount        int
+	LatestAttemptStatus models.PaymentStatus
+	PaymentCount        int
+	TotalPaid           float64
+}
+
+// PaymentExportRow is a payment joined with what finance reconciles it
+// against. UserID is nil for old attempts made before it was recorded.
+type PaymentExportRow struct {
+	ID                    uuid.UUID
+	AttemptID             uuid.UUID
+	PayableType           models.PayableType
+	PayableID             uuid.UUID
+	UserID                *uuid.UUID
+	Method                models.PaymentMethod
+	Amount                float64
+	PatientAmount         float64
+	PayerAmount           float64
+	HealthcareEntitlement *string
+	PaidAt                time.Time
+}
+
 type PaymentRepository struct {
 	db *gorm.DB
 }
@@ -18,28 +64,6 @@ func NewPaymentRepository(db *gorm.DB) *PaymentRepository {
 	}
 }
 
-func (r *PaymentRepository) Transaction(ctx context.Context, fn func(repo *PaymentRepository) (interface{}, error)) (interface{}, error) {
-	tx := r.db.Begin()
-	if tx.Error != nil {
-		return nil, tx.Error
-	}
-	repoWithTx := r.withTx(tx)
-
-	result, err := fn(repoWithTx)
-	if err != nil {
-		tx.Rollback()
-		return nil, err
-	}
-	if err := tx.Commit().Error; err != nil {
-		return nil, err
-	}
-	return result, nil
-}
-
-func (r *PaymentRepository) withTx(tx *gorm.DB) *PaymentRepository {
-	return &PaymentRepository{db: tx}
-}
-
 func (r *PaymentRepository) Create(ctx context.Context, payment *models.Payment) error {
 	return r.db.WithContext(ctx).Create(payment).Error
 }
@@ -55,14 +79,29 @@ func (r *PaymentRepository) FindByID(ctx context.Context, id uuid.UUID) (*models
 	return &payment, nil
 }
 
-func (r *PaymentRepository) FindByOrderID(ctx context.Context, orderID uuid.UUID) ([]models.Payment, error) {
+// FindByIDForUpdate reads a payment and locks it until the transaction
+// ends, so nothing else changes or refunds it in the meantime. Outside a
+// unit of work the lock is released right away.
+func (r *PaymentRepository) FindByIDForUpdate(ctx context.Context, id uuid.UUID) (*models.Payment, error) {
+	var payment models.Payment
+	if err := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&payment).Error; err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+func (r *PaymentRepository) FindByPayable(ctx context.Context, payableType models.PayableType, payableID uuid.UUID) ([]models.Payment, error) {
 	var payments []models.Payment
-	if err := r.db.WithContext(ctx).Where("order_id = ?", orderID).Find(&payments).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("payable_type = ? AND payable_id = ?", payableType, payableID).Find(&payments).Error; err != nil {
 		return nil, err
 	}
 	return payments, nil
 }
 
+func (r *PaymentRepository) FindByOrderID(ctx context.Context, orderID uuid.UUID) ([]models.Payment, error) {
+	return r.FindByPayable(ctx, models.PayableTypeOrder, orderID)
+}
+
 func (r *PaymentRepository) FindByAttemptID(ctx context.Context, attemptID uuid.UUID) ([]models.Payment, error) {
 	var payments []models.Payment
 	if err := r.db.WithContext(ctx).Where("attempt_id = ?", attemptID).Find(&payments).Error; err != nil {
@@ -71,22 +110,142 @@ func (r *PaymentRepository) FindByAttemptID(ctx context.Context, attemptID uuid.
 	return payments, nil
 }
 
-func (r *PaymentRepository) FindAll(ctx context.Context) ([]models.Payment, error) {
+// FindPage returns one page of matching payments and the cursor of the next
+// page, nil when there is none.
+func (r *PaymentRepository) FindPage(ctx context.Context, filter PaymentFilter, page KeysetPage) ([]models.Payment, *uuid.UUID, error) {
+	query := r.db.WithContext(ctx).Model(&models.Payment{})
+	if filter.Status != "" || filter.Method != "" || filter.UserID != nil {
+		attempts := r.db.Table("payment_attempts").Select("1").Where("payment_attempts.id = payments.attempt_id")
+		if filter.Status != "" {
+			attempts = attempts.Where("payment_attempts.status = ?", filter.Status)
+		}
+		if filter.Method != "" {
+			attempts = attempts.Where("payment_attempts.method = ?", filter.Method)
+		}
+		if filter.UserID != nil {
+			attempts = attempts.Where("payment_attempts.user_id = ?", *filter.UserID)
+		}
+		query = query.Where("EXISTS (?)", attempts)
+	}
+	if filter.PayableType != "" {
+		query = query.Where("payments.payable_type = ?", filter.PayableType)
+	}
+	if filter.PayableID != nil {
+		query = query.Where("payments.payable_id = ?", *filter.PayableID)
+	}
+	if filter.PaidFrom != nil {
+		query = query.Where("payments.paid_at >= ?", *filter.PaidFrom)
+	}
+	if filter.PaidTo != nil {
+		query = query.Where("payments.paid_at < ?", *filter.PaidTo)
+	}
+
 	var payments []models.Payment
-	if err := r.db.WithContext(ctx).Find(&payments).Error; err != nil {
+	if err := page.apply(query, "payments.id").Find(&payments).Error; err != nil {
+		return nil, nil, err
+	}
+	payments, next := trim(page, payments, func(p *models.Payment) uuid.UUID { return p.ID })
+	return payments, next, nil
+}
+
+// SummarizeByPayables aggregates attempts and payments of many payables in
+// one grouped statement. Payables with neither are left out.
+func (r *PaymentRepository) SummarizeByPayables(ctx context.Context, refs []PayableRef) ([]PayableSummary, error) {
+	if len(refs) == 0 {
+		return nil, nil
+	}
+	keys := make([][]interface{}, len(refs))
+	for i, ref := range refs {
+		keys[i] = []interface{}{string(ref.Type), ref.ID}
+	}
+
+	var summaries []PayableSummary
+	err := r.db.WithContext(ctx).Raw(`
+SELECT
+  COALESCE(a.payable_type, p.payable_type) AS payable_type,
+  COALESCE(a.payable_id, p.payable_id) AS payable_id,
+  COALESCE(a.attempt_count, 0) AS attempt_count,
+  COALESCE(a.latest_attempt_status, '') AS latest_attempt_status,
+  COALESCE(p.payment_count, 0) AS payment_count,
+  COALESCE(p.total_paid, 0) AS total_paid
+FROM (
+  SELECT payable_type, payable_id, count(*) AS attempt_count,
+    (array_agg(status::text ORDER BY created_at DESC, id DESC))[1] AS latest_attempt_status
+  FROM payment_attempts
+  WHERE (payable_type, payable_id) IN ?
+  GROUP BY payable_type, payable_id
+) a
+FULL JOIN (
+  SELECT payable_type, payable_id, count(*) AS payment_count, sum(amount) AS total_paid
+  FROM payments
+  WHERE (payable_type, payable_id) IN ?
+  GROUP BY payable_type, payable_id
+) p ON p.payable_type = a.payable_type AND p.payable_id = a.payable_id`, keys, keys).Scan(&summaries).Error
+	if err != nil {
 		return nil, err
 	}
-	return payments, nil
+	return summaries, nil
+}
+
+// StreamForExport calls fn for each payment made in [from, to), oldest
+// first, reading rows from the database as it goes rather than all at once.
+// An error from fn stops the stream and is returned.
+func (r *PaymentRepository) StreamForExport(ctx context.Context, from, to time.Time, fn func(row *PaymentExportRow) error) error {
+	rows, err := r.db.WithContext(ctx).Table("payments").
+		Select(`payments.id, payments.attempt_id, payments.payable_type, payments.payable_id,
+  payment_attempts.user_id, payment_attempts.method, payments.amount, payments.patient_amount,
+  payments.payer_amount, payments.healthcare_entitlement, payments.paid_at`).
+		Joins("JOIN payment_attempts ON payment_attempts.id = payments.attempt_id").
+		Where("payments.paid_at >= ? AND payments.paid_at < ?", from, to).
+		Order("payments.paid_at, payments.id").
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row PaymentExportRow
+		if err := r.db.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
 }
 
+// Update writes the payment row only; line items and the receivable keep
+// their own rows.
 func (r *PaymentRepository) Update(ctx context.Context, payment *models.Payment) error {
-	return r.db.WithContext(ctx).Model(payment).Updates(payment).Error
+	result := r.db.WithContext(ctx).Model(&models.Payment{}).
+		Where("id = ? AND lock_version = ?", payment.ID, payment.LockVersion).
+		Updates(map[string]interface{}{
+			"attempt_id":             payment.AttemptID,
+			"amount":                 payment.Amount,
+			"payable_type":           payment.PayableType,
+			"payable_id":             payment.PayableID,
+			"paid_at":                payment.PaidAt,
+			"patient_amount":         payment.PatientAmount,
+			"payer_amount":           payment.PayerAmount,
+			"healthcare_entitlement": payment.HealthcareEntitlement,
+			"lock_version":           payment.LockVersion + 1,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	payment.LockVersion++
+	return nil
 }
 
 func (r *PaymentRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.Payment{}).Error
 }
 
-func (r *PaymentRepository) DeleteByOrderID(ctx context.Context, orderID uuid.UUID) error {
-	return r.db.WithContext(ctx).Where("order_id = ?", orderID).Delete(&models.Payment{}).Error
+func (r *PaymentRepository) DeleteByPayable(ctx context.Context, payableType models.PayableType, payableID uuid.UUID) error {
+	return r.db.WithContext(ctx).Where("payable_type = ? AND payable_id = ?", payableType, payableID).Delete(&models.Payment{}).Error
 }
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"payment-service/pkg/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RefundRepository struct {
+	db *gorm.DB
+}
+
+func NewRefundRepository(db *gorm.DB) *RefundRepository {
+	return &RefundRepository{
+		db: db,
+	}
+}
+
+func (r *RefundRepository) Create(ctx context.Context, refund *models.Refund) error {
+	return r.db.WithContext(ctx).Create(refund).Error
+}
+
+// FindByPaymentIDs returns the refunds of the payments, oldest first.
+func (r *RefundRepository) FindByPaymentIDs(ctx context.Context, paymentIDs []uuid.UUID) ([]models.Refund, error) {
+	var refunds []models.Refund
+	if len(paymentIDs) == 0 {
+		return refunds, nil
+	}
+	if err := r.db.WithContext(ctx).Where("payment_id IN ?", paymentIDs).Order("created_at, id").Find(&refunds).Error; err != nil {
+		return nil, err
+	}
+	return refunds, nil
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"payment-service/pkg/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// The interfaces below are what the service layer needs from each table.
+// The gorm repositories in this package implement them against Postgres;
+// package memory implements them in memory for tests. Lookups of a single
+// row return gorm.ErrRecordNotFound when there is none.
+//
+// Update writes every column it owns, zero values included, but only while
+// the row still has the version the caller read. It then bumps the version
+// on the row and the model, or returns ErrVersionConflict when someone else
+// changed or deleted the row first.
+
+var ErrVersionConflict = errors.New("row was changed by someone else")
+
+type PaymentInformations interface {
+	Create(ctx context.Context, paymentInfo *models.PaymentInformation) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.PaymentInformation, error)
+	FindByIDs(ctx context.Context, ids []uuid.UUID) ([]models.PaymentInformation, error)
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]models.PaymentInformation, error)
+	FindByUserIDAndType(ctx context.Context, userID uuid.UUID, paymentMethod string) ([]models.PaymentInformation, error)
+	FindPage(ctx context.Context, filter PaymentInformationFilter, page KeysetPage) ([]models.PaymentInformation, *uuid.UUID, error)
+	Update(ctx context.Context, paymentInfo *models.PaymentInformation) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
+}
+
+type PaymentAttempts interface {
+	Create(ctx context.Context, attempt *models.PaymentAttempt) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.PaymentAttempt, error)
+	FindByIDForUpdate(ctx context.Context, id uuid.UUID) (*models.PaymentAttempt, error)
+	FindByIDs(ctx context.Context, ids []uuid.UUID) ([]models.PaymentAttempt, error)
+	FindByPayable(ctx context.Context, payableType models.PayableType, payableID uuid.UUID) ([]models.PaymentAttempt, error)
+	FindByPayableAndStatus(ctx context.Context, payableType models.PayableType, payableID uuid.UUID, status models.PaymentStatus) ([]models.PaymentAttempt, error)
+	FindByOrderID(ctx context.Context, orderID uuid.UUID) ([]models.PaymentAttempt, error)
+	FindByOrderIDAndStatus(ctx context.Context, orderID uuid.UUID, status models.PaymentStatus) ([]models.PaymentAttempt, error)
+	FindAll(ctx context.Context) ([]models.PaymentAttempt, error)
+	Update(ctx context.Context, attempt *models.PaymentAttempt) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	DeleteByPayable(ctx context.Context, payableType models.PayableType, payableID uuid.UUID) error
+}
+
+type Payments interface {
+	Create(ctx context.Context, payment *models.Payment) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.Payment, error)
+	FindByIDForUpdate(ctx context.Context, id uuid.UUID) (*models.Payment, error)
+	FindByPayable(ctx context.Context, payableType models.PayableType, payableID uuid.UUID) ([]models.Payment, error)
+	FindByOrderID(ctx context.Context, orderID uuid.UUID) ([]models.Payment, error)
+	FindByAttemptID(ctx context.Context, attemptID uuid.UUID) ([]models.Payment, error)
+	FindPage(ctx context.Context, filter PaymentFilter, page KeysetPage) ([]models.Payment, *uuid.UUID, error)
+	SummarizeByPayables(ctx context.Context, refs []PayableRef) ([]PayableSummary, error)
+	StreamForExport(ctx context.Context, from, to time.Time, fn func(row *PaymentExportRow) error) error
+	Update(ctx context.Context, payment *models.Payment) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	DeleteByPayable(ctx context.Context, payableType models.PayableType, payableID uuid.UUID) error
+}
+
+type Refunds interface {
+	Create(ctx context.Context, refund *models.Refund) error
+	FindByPaymentIDs(ctx context.Context, paymentIDs []uuid.UUID) ([]models.Refund, error)
+}
+
+type PaymentLineItems interface {
+	FindByAttemptID(ctx context.Context, attemptID uuid.UUID) ([]models.PaymentLineItem, error)
+	FindByPaymentID(ctx context.Context, paymentID uuid.UUID) ([]models.PaymentLineItem, error)
+}
+
+type CoverageRules interface {
+	FindActiveByEntitlements(ctx context.Context, entitlements []string) ([]models.CoverageRule, error)
+}
+
+type PayerReceivables interface {
+	FindByStatus(ctx context.Context, status models.ReceivableStatus) ([]models.PayerReceivable, error)
+}
+
+type PaymentDocuments interface {
+	Issue(ctx context.Context, doc *models.PaymentDocument, event *models.PaymentDocumentEvent) error
+	Void(ctx context.Context, doc *models.PaymentDocument, event *models.PaymentDocumentEvent) error
+	Reissue(ctx context.Context, old *models.PaymentDocument, voidEvent *models.PaymentDocumentEvent, replacement *models.PaymentDocument, issueEvent *models.PaymentDocumentEvent) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.PaymentDocument, error)
+	FindByPaymentID(ctx context.Context, paymentID uuid.UUID) ([]models.PaymentDocument, error)
+	FindIssued(ctx context.Context, paymentID uuid.UUID, docType models.DocumentType) (*models.PaymentDocument, error)
+}
+
+type AuditLogs interface {
+	Find(ctx context.Context, filter AuditLogFilter) ([]models.AuditLog, error)
+}
+
+type RiskAssessments interface {
+	Lock(ctx context.Context, subjects ...string) error
+	CountByUserSince(ctx context.Context, userID uuid.UUID, since time.Time) (total int, failed int, blocked int, err error)
+	CountByCardSince(ctx context.Context, fingerprint string, since time.Time) (int, error)
+	CountByIPSince(ctx context.Context, clientIP string, since time.Time) (int, error)
+	CardSeen(ctx context.Context, fingerprint string) (bool, error)
+	ReviewPending(ctx context.Context, attemptID uuid.UUID) (bool, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*models.RiskAssessment, error)
+	FindPendingReviews(ctx context.Context) ([]models.RiskAssessment, error)
+	Review(ctx context.Context, assessment *models.RiskAssessment, attemptStatus models.PaymentStatus) error
+}
+
+type TokenRevocations interface {
+	Create(ctx context.Context, revocation *models.TokenRevocation) error
+	FindActive(ctx context.Context, now time.Time) ([]models.TokenRevocation, error)
+	DeleteExpired(ctx context.Context, now time.Time) error
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"payment-service/pkg/models"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrReviewNotPending = errors.New("risk review is not pending")
+
+type RiskAssessmentRepository struct {
+	db *gorm.DB
+}
+
+func NewRiskAssessmentRepository(db *gorm.DB) *RiskAssessmentRepository {
+	return &RiskAssessmentRepository{
+		db: db,
+	}
+}
+
+// Lock takes a transaction-scoped advisory lock on each subject, e.g. a
+// user, card or client IP, so attempts sharing one are counted and written
+// one after another. It only holds inside a unit of work; the locks are
+// taken in order so two attempts cannot wait on each other.
+func (r *RiskAssessmentRepository) Lock(ctx context.Context, subjects ...string) error {
+	for _, subject := range slices.Sorted(slices.Values(subjects)) {
+		if err := r.db.WithContext(ctx).Exec("SELECT pg_advisory_xact_lock(hashtextextended(?, 0))", subject).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CountByUserSince returns how many attempts the user made since the given
+// time, how many of those failed on their own and how many the risk rules
+// blocked.
+func (r *RiskAssessmentRepository) CountByUserSince(ctx context.Context, userID uuid.UUID, since time.Time) (total int, failed int, blocked int, err error) {
+	var counts struct {
+		Total   int
+		Failed  int
+		Blocked int
+	}
+	err = r.db.WithContext(ctx).
+		Table("risk_assessments AS r").
+		Select("count(*) AS total, count(*) FILTER (WHERE a.status = ? AND r.decision <> ?) AS failed, count(*) FILTER (WHERE r.decision = ?) AS blocked",
+			models.PaymentStatusFailed, models.RiskDecisionBlock, models.RiskDecisionBlock).
+		Joins("JOIN payment_attempts AS a ON a.id = r.attempt_id").
+		Where("r.user_id = ? AND r.created_at > ?", userID, since).
+		Scan(&counts).Error
+	return counts.Total, counts.Failed, counts.Blocked, err
+}
+
+func (r *RiskAssessmentRepository) CountByCardSince(ctx context.Context, fingerprint string, since time.Time) (int, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.RiskAssessment{}).
+		Where("card_fingerprint = ? AND created_at > ?", fingerprint, since).
+		Count(&count).Error
+	return int(count), err
+}
+
+func (r *RiskAssessmentRepository) CountByIPSince(ctx context.Context, clientIP string, since time.Time) (int, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.RiskAssessment{}).
+		Where("client_ip = ? AND created_at > ?", clientIP, since).
+		Count(&count).Error
+	return int(count), err
+}
+
+// CardSeen reports whether any earlier attempt used the card.
+func (r *RiskAssessmentRepository) CardSeen(ctx context.Context, fingerprint string) (bool, error) {
+	var seen bool
+	err := r.db.WithContext(ctx).
+		Raw("SELECT EXISTS (SELECT 1 FROM risk_assessments WHERE card_fingerprint = ?)", fingerprint).
+		Scan(&seen).Error
+	return seen, err
+}
+
+// ReviewPending reports whether the attempt is held for a review that no
+// admin has closed yet.
+func (r *RiskAssessmentRepository) ReviewPending(ctx context.Context, attemptID uuid.UUID) (bool, error) {
+	var pending bool
+	err := r.db.WithContext(ctx).
+		Raw("SELECT EXISTS (SELECT 1 FROM risk_assessments WHERE attempt_id = ? AND review_status = ?)", attemptID, models.ReviewStatusPending).
+		Scan(&pending).Error
+	return pending, err
+}
+
+func (r *RiskAssessmentRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.RiskAssessment, error) {
+	var assessment models.RiskAssessment
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&assessment).Error; err != nil {
+		return nil, err
+	}
+	return &assessment, nil
+}
+
+// FindPendingReviews returns the review queue, oldest first.
+func (r *RiskAssessmentRepository) FindPendingReviews(ctx context.Context) ([]models.RiskAssessment, error) {
+	var assessments []models.RiskAssessment
+	if err := r.db.WithContext(ctx).Where("review_status = ?", models.ReviewStatusPending).Order("created_at, id").Find(&assessments).Error; err != nil {
+		return nil, err
+	}
+	return assessments, nil
+}
+
+// Review closes a pending review and moves its attempt to attemptStatus in
+// one transaction. It returns ErrReviewNotPending when another admin got
+// there first.
+func (r *RiskAssessmentRepository) Review(ctx context.Context, assessment *models.RiskAssessment, attemptStatus models.PaymentStatus) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.RiskAssessment{}).
+			Where("id = ? AND review_status = ?", assessment.ID, models.ReviewStatusPending).
+			Updates(map[string]interface{}{
+				"review_status": assessment.ReviewStatus,
+				"reviewed_by":   assessment.ReviewedBy,
+				"reviewed_at":   assessment.ReviewedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrReviewNotPending
+		}
+
+		// bump the attempt's version so edits based on the old status fail
+		return tx.Model(&models.PaymentAttempt{}).Where("id = ?", assessment.AttemptID).
+			Updates(map[string]interface{}{
+				"status":       attemptStatus,
+				"lock_version": gorm.Expr("lock_version + 1"),
+			}).Error
+	})
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"payment-service/pkg/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TokenRevocationRepository struct {
+	db *gorm.DB
+}
+
+func NewTokenRevocationRepository(db *gorm.DB) *TokenRevocationRepository {
+	return &TokenRevocationRepository{
+		db: db,
+	}
+}
+
+func (r *TokenRevocationRepository) Create(ctx context.Context, revocation *models.TokenRevocation) error {
+	return r.db.WithContext(ctx).Create(revocation).Error
+}
+
+// FindActive returns the revocations that have not expired at now.
+func (r *TokenRevocationRepository) FindActive(ctx context.Context, now time.Time) ([]models.TokenRevocation, error) {
+	var revocations []models.TokenRevocation
+	if err := r.db.WithContext(ctx).Where("expires_at > ?", now).Find(&revocations).Error; err != nil {
+		return nil, err
+	}
+	return revocations, nil
+}
+
+func (r *TokenRevocationRepository) DeleteExpired(ctx context.Context, now time.Time) error {
+	return r.db.WithContext(ctx).Where("expires_at <= ?", now).Delete(&models.TokenRevocation{}).Error
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Repositories are the repositories of one unit of work, all bound to its
+// transaction.
+type Repositories struct {
+	PaymentInformations PaymentInformations
+	PaymentAttempts     PaymentAttempts
+	Payments            Payments
+	Refunds             Refunds
+	PaymentLineItems    PaymentLineItems
+	CoverageRules       CoverageRules
+	PayerReceivables    PayerReceivables
+	PaymentDocuments    PaymentDocuments
+	AuditLogs           AuditLogs
+	RiskAssessments     RiskAssessments
+	TokenRevocations    TokenRevocations
+}
+
+// UnitOfWork runs fn in a transaction, with repositories bound to it. The
+// transaction commits when fn returns nil and rolls back when it returns
+// an error or panics; a panic is passed on after the rollback. Calling Do
+// again with the context fn was given nests a savepoint in the same
+// transaction, which rolls back on its own without ending the outer one.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(ctx context.Context, repos Repositories) error) error
+}
+
+type GormUnitOfWork struct {
+	db *gorm.DB
+}
+
+func NewUnitOfWork(db *gorm.DB) *GormUnitOfWork {
+	return &GormUnitOfWork{
+		db: db,
+	}
+}
+
+// txKey finds the transaction of a unit of work in the context it passes
+// to fn.
+type txKey struct {
+	unitOfWork *GormUnitOfWork
+}
+
+func (u *GormUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context, repos Repositories) error) error {
+	db := u.db
+	if tx, ok := ctx.Value(txKey{u}).(*gorm.DB); ok {
+		db = tx
+	}
+	// gorm begins a transaction, or a savepoint inside one, and rolls it
+	// back on error or panic
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{u}, tx), NewRepositories(tx))
+	})
+}
+
+// NewRepositories creates every repository over db.
+func NewRepositories(db *gorm.DB) Repositories {
+	return Repositories{
+		PaymentInformations: NewPaymentInformationRepository(db),
+		PaymentAttempts:     NewPaymentAttemptRepository(db),
+		Payments:            NewPaymentRepository(db),
+		Refunds:             NewRefundRepository(db),
+		PaymentLineItems:    NewPaymentLineItemRepository(db),
+		CoverageRules:       NewCoverageRuleRepository(db),
+		PayerReceivables:    NewPayerReceivableRepository(db),
+		PaymentDocuments:    NewPaymentDocumentRepository(db),
+		AuditLogs:           NewAuditLogRepository(db),
+		RiskAssessments:     NewRiskAssessmentRepository(db),
+		TokenRevocations:    NewTokenRevocationRepository(db),
+	}
+}
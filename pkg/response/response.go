@@ -6,8 +6,41 @@ type BaseResponse struct {
 	Data interface{} `json:"data,omitempty"`
 }
 
+// ErrorResponse is the body of every error this service sends. Code is
+// stable and meant for programs; Message is meant for people. Fields maps
+// request fields to what is wrong with them.
 type ErrorResponse struct {
-	Error string `json:"error"`
+	Code      string            `json:"code" example:"bad_request"`
+	Message   string            `json:"message" example:"invalid request body"`
+	Fields    map[string]string `json:"fields,omitempty"`
+	RequestID string            `json:"request_id,omitempty"`
+}
+
+// Error codes by status, for errors that carry no code of their own.
+var statusCodes = map[int]string{
+	fiber.StatusBadRequest:            "bad_request",
+	fiber.StatusUnauthorized:          "unauthorized",
+	fiber.StatusForbidden:             "forbidden",
+	fiber.StatusNotFound:              "not_found",
+	fiber.StatusMethodNotAllowed:      "method_not_allowed",
+	fiber.StatusConflict:              "conflict",
+	fiber.StatusRequestEntityTooLarge: "payload_too_large",
+	fiber.StatusTooManyRequests:       "too_many_requests",
+	fiber.StatusInternalServerError:   "internal",
+	fiber.StatusBadGateway:            "bad_gateway",
+	fiber.StatusServiceUnavailable:    "unavailable",
+	fiber.StatusGatewayTimeout:        "gateway_timeout",
+}
+
+// StatusCode is the error code of a status without a more specific one.
+func StatusCode(status int) string {
+	if code, ok := statusCodes[status]; ok {
+		return code
+	}
+	if status >= fiber.StatusInternalServerError {
+		return "internal"
+	}
+	return "bad_request"
 }
 
 func OK[T any](c *fiber.Ctx, data T) error {
@@ -18,38 +51,37 @@ func Created[T any](c *fiber.Ctx, data T) error {
 	return c.Status(fiber.StatusCreated).JSON(data)
 }
 
-func BadRequest(c *fiber.Ctx, message string) error {
-	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-		"error": message,
+// Error writes an ErrorResponse, tagged with the request ID.
+func Error(c *fiber.Ctx, status int, code, message string, fields map[string]string) error {
+	requestID, _ := c.Locals("requestid").(string)
+	return c.Status(status).JSON(ErrorResponse{
+		Code:      code,
+		Message:   message,
+		Fields:    fields,
+		RequestID: requestID,
 	})
 }
 
+func BadRequest(c *fiber.Ctx, message string) error {
+	return Failed(c, fiber.StatusBadRequest, message)
+}
+
 func Unauthorized(c *fiber.Ctx, message string) error {
-	return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-		"error": message,
-	})
+	return Failed(c, fiber.StatusUnauthorized, message)
 }
 
 func Forbidden(c *fiber.Ctx, message string) error {
-	return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-		"error": message,
-	})
+	return Failed(c, fiber.StatusForbidden, message)
 }
 
 func NotFound(c *fiber.Ctx, message string) error {
-	return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-		"error": message,
-	})
+	return Failed(c, fiber.StatusNotFound, message)
 }
 
 func InternalServerError(c *fiber.Ctx, message string) error {
-	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-		"error": message,
-	})
+	return Failed(c, fiber.StatusInternalServerError, message)
 }
 
 func Failed(c *fiber.Ctx, status int, message string) error {
-	return c.Status(status).JSON(fiber.Map{
-		"error": message,
-	})
+	return Error(c, status, StatusCode(status), message, nil)
 }
@@ -0,0 +1,141 @@
+package revocation
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"payment-service/pkg/jwt"
+	"payment-service/pkg/models"
+	"payment-service/pkg/repository"
+)
+
+// Store answers whether a token is revoked from memory. Run reloads the
+// denylist from the database every refresh interval, so revocations made by
+// other instances apply within that interval and those made here at once.
+type Store struct {
+	repo repository.TokenRevocations
+	// retention is how long a revocation is kept: the longest lifetime of
+	// any token it may have to deny
+	retention time.Duration
+	refresh   time.Duration
+
+	mu sync.RWMutex
+	// jtis holds when each revoked jti stops needing to be denied
+	jtis  map[string]time.Time
+	users map[string]userRevocation
+}
+
+// userRevocation is the latest revocation of every token of a user.
+type userRevocation struct {
+	revokedAt time.Time
+	expiresAt time.Time
+}
+
+func NewStore(repo repository.TokenRevocations, retention time.Duration, refresh time.Duration) *Store {
+	return &Store{
+		repo:      repo,
+		retention: retention,
+		refresh:   refresh,
+		jtis:      make(map[string]time.Time),
+		users:     make(map[string]userRevocation),
+	}
+}
+
+// IsRevoked implements jwt.RevocationChecker. It never waits on the
+// database.
+func (s *Store) IsRevoked(claims *jwt.JwtClaims) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if claims.ID != "" {
+		if _, ok := s.jtis[claims.ID]; ok {
+			return true
+		}
+	}
+	revoked, ok := s.users[claims.UserID]
+	if !ok {
+		return false
+	}
+	// iat has whole seconds, so a token issued in the second of the
+	// revocation counts as issued before it
+	return claims.IssuedAt == nil || !claims.IssuedAt.After(revoked.revokedAt.Truncate(time.Second))
+}
+
+// Run reloads the denylist every refresh interval until ctx is done. A
+// failed reload keeps the current denylist until the next one.
+func (s *Store) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.refresh)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Load(ctx); err != nil {
+				log.Printf("revocation: refresh failed: %v", err)
+			}
+		}
+	}
+}
+
+// Revoke stores a revocation and applies it to this instance right away.
+func (s *Store) Revoke(ctx context.Context, revocation *models.TokenRevocation) error {
+	revocation.CreatedAt = time.Now()
+	revocation.ExpiresAt = revocation.CreatedAt.Add(s.retention)
+	if err := s.repo.Create(ctx, revocation); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.add(revocation)
+	s.mu.Unlock()
+	return nil
+}
+
+// Load merges the active revocations into the in-memory denylist, forgets
+// the expired ones and deletes those from the database. Revocations are
+// merged rather than replaced, so one made here while Load reads is kept.
+func (s *Store) Load(ctx context.Context) error {
+	now := time.Now()
+	if err := s.repo.DeleteExpired(ctx, now); err != nil {
+		return err
+	}
+	revocations, err := s.repo.FindActive(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for jti, expiresAt := range s.jtis {
+		if !expiresAt.After(now) {
+			delete(s.jtis, jti)
+		}
+	}
+	for userID, revoked := range s.users {
+		if !revoked.expiresAt.After(now) {
+			delete(s.users, userID)
+		}
+	}
+	for i := range revocations {
+		s.add(&revocations[i])
+	}
+	return nil
+}
+
+// add records one revocation. Callers hold s.mu.
+func (s *Store) add(revocation *models.TokenRevocation) {
+	if revocation.Jti != nil {
+		if revocation.ExpiresAt.After(s.jtis[*revocation.Jti]) {
+			s.jtis[*revocation.Jti] = revocation.ExpiresAt
+		}
+	}
+	if revocation.UserID != nil {
+		userID := revocation.UserID.String()
+		if revocation.CreatedAt.After(s.users[userID].revokedAt) {
+			s.users[userID] = userRevocation{revokedAt: revocation.CreatedAt, expiresAt: revocation.ExpiresAt}
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package revocation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"payment-service/pkg/jwt"
+	"payment-service/pkg/models"
+	"payment-service/pkg/repository/memory"
+	"payment-service/pkg/utils"
+
+	jwtlib "github.com/golang-jwt/jwt/v5"
+)
+
+func claims(userID string, jti string, issuedAt time.Time) *jwt.JwtClaims {
+	return &jwt.JwtClaims{UserID: userID, RegisteredClaims: jwtlib.RegisteredClaims{ID: jti, IssuedAt: jwtlib.NewNumericDate(issuedAt)}}
+}
+
+func TestIsRevoked(t *testing.T) {
+	userID := utils.GenerateUUIDv7()
+	jti := "token-1"
+	store := NewStore(memory.NewTokenRevocationRepository(memory.New()), time.Hour, time.Minute)
+	if err := store.Revoke(context.Background(), &models.TokenRevocation{Jti: &jti}); err != nil {
+		t.Fatal(err)
+	}
+	userRevocation := &models.TokenRevocation{UserID: &userID}
+	if err := store.Revoke(context.Background(), userRevocation); err != nil {
+		t.Fatal(err)
+	}
+	revokedAt := userRevocation.CreatedAt
+
+	tests := []struct {
+		name   string
+		claims *jwt.JwtClaims
+		want   bool
+	}{
+		{"revoked jti", claims("someone", jti, time.Now()), true},
+		{"other jti", claims("someone", "token-2", time.Now()), false},
+		{"user's older token", claims(userID.String(), "", revokedAt.Add(-time.Minute)), true},
+		{"user's token from the same second", claims(userID.String(), "", revokedAt.Truncate(time.Second)), true},
+		{"user's newer token", claims(userID.String(), "", revokedAt.Add(time.Minute)), false},
+		{"user's token without iat", &jwt.JwtClaims{UserID: userID.String()}, true},
+		{"other user", claims(utils.GenerateUUIDv7().String(), "", revokedAt.Add(-time.Minute)), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := store.IsRevoked(tt.claims); got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// racingRepository revokes a token through the store while Load is reading,
+// after the rows Load gets back were read.
+type racingRepository struct {
+	*memory.TokenRevocationRepository
+	race func()
+}
+
+func (r *racingRepository) FindActive(ctx context.Context, now time.Time) ([]models.TokenRevocation, error) {
+	revocations, err := r.TokenRevocationRepository.FindActive(ctx, now)
+	if r.race != nil {
+		race := r.race
+		r.race = nil
+		race()
+	}
+	return revocations, err
+}
+
+func TestLoad(t *testing.T) {
+	db := memory.New()
+	repo := &racingRepository{TokenRevocationRepository: memory.NewTokenRevocationRepository(db)}
+	store := NewStore(repo, time.Hour, time.Minute)
+
+	// made by another instance
+	elsewhere := "elsewhere"
+	now := time.Now()
+	if err := repo.Create(context.Background(), &models.TokenRevocation{Jti: &elsewhere, CreatedAt: now, ExpiresAt: now.Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	expired := "expired"
+	if err := repo.Create(context.Background(), &models.TokenRevocation{Jti: &expired, CreatedAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	racing := "racing"
+	repo.race = func() {
+		if err := store.Revoke(context.Background(), &models.TokenRevocation{Jti: &racing}); err != nil {
+			t.Error(err)
+		}
+	}
+
+	if err := store.Load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		jti  string
+		want bool
+	}{
+		{elsewhere, true},
+		{racing, true},
+		{expired, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.jti, func(t *testing.T) {
+			if got := store.IsRevoked(claims("someone", tt.jti, now)); got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if active, _ := repo.FindActive(context.Background(), time.Time{}); len(active) != 2 {
+		t.Fatalf("got %d rows, want the expired one deleted", len(active))
+	}
+}
+
+func TestLoadForgetsExpired(t *testing.T) {
+	store := NewStore(memory.NewTokenRevocationRepository(memory.New()), 10*time.Millisecond, time.Minute)
+	jti := "token-1"
+	if err := store.Revoke(context.Background(), &models.TokenRevocation{Jti: &jti}); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	if err := store.Load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if store.IsRevoked(claims("someone", jti, time.Now())) {
+		t.Fatal("expired revocation still denies")
+	}
+}
+
+func TestRun(t *testing.T) {
+	repo := memory.NewTokenRevocationRepository(memory.New())
+	store := NewStore(repo, time.Hour, 10*time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go store.Run(ctx)
+
+	jti := "elsewhere"
+	now := time.Now()
+	if err := repo.Create(context.Background(), &models.TokenRevocation{Jti: &jti, CreatedAt: now, ExpiresAt: now.Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for !store.IsRevoked(claims("someone", jti, now)) {
+		if time.Now().After(deadline) {
+			t.Fatal("revocation from another instance never applied")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
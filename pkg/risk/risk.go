@@ -0,0 +1,165 @@
+// Package risk decides whether a payment attempt may go ahead, from
+// signals the caller gathers about the user, card and client.
+package risk
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"payment-service/pkg/models"
+)
+
+// Thresholds turn a count into a decision: reaching Review sends the
+// attempt to review, reaching Block blocks it. Zero disables a level.
+type Thresholds struct {
+	Review int
+	Block  int
+}
+
+type Config struct {
+	// FingerprintKey keys card fingerprints; see CardFingerprint
+	FingerprintKey []byte
+
+	// Window is how far back attempts are counted
+	Window time.Duration
+
+	UserAttempts Thresholds
+	CardAttempts Thresholds
+	IPAttempts   Thresholds
+
+	// FailedRatioReview and FailedRatioBlock apply to the user's failed
+	// share of attempts in the window, once there are FailedRatioMinAttempts
+	FailedRatioReview      float64
+	FailedRatioBlock       float64
+	FailedRatioMinAttempts int
+
+	// NewCardAmount sends a card's first attempt to review when the amount
+	// is at least this much. Zero disables the rule.
+	NewCardAmount float64
+
+	// CountryMismatch is the decision when the card's country differs from
+	// the client's
+	CountryMismatch models.RiskDecision
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Window:                 time.Hour,
+		UserAttempts:           Thresholds{Review: 10, Block: 20},
+		CardAttempts:           Thresholds{Review: 5, Block: 10},
+		IPAttempts:             Thresholds{Review: 20, Block: 50},
+		FailedRatioReview:      0.5,
+		FailedRatioBlock:       0.8,
+		FailedRatioMinAttempts: 5,
+		NewCardAmount:          10000,
+		CountryMismatch:        models.RiskDecisionReview,
+	}
+}
+
+// Signals are what is known about an attempt when it is made. Counts
+// cover earlier attempts in the window, not this one.
+type Signals struct {
+	UserAttempts int
+	// UserFailed counts the user's attempts that failed on their own, and
+	// UserBlocked those the rules blocked. The failed ratio leaves blocked
+	// attempts out, so a blocked burst does not keep the user blocked.
+	UserFailed     int
+	UserBlocked    int
+	CardAttempts   int
+	IPAttempts     int
+	HasCard        bool
+	NewCard        bool
+	Amount         float64
+	CardCountry    string
+	RequestCountry string
+}
+
+type Assessment struct {
+	Decision models.RiskDecision
+	Reasons  []string
+}
+
+// Evaluate applies every rule and keeps the most severe decision, with
+// the reasons of each rule that did not allow.
+func (c Config) Evaluate(s Signals) Assessment {
+	result := Assessment{Decision: models.RiskDecisionAllow}
+	apply := func(decision models.RiskDecision, reason string) {
+		if decision == models.RiskDecisionAllow {
+			return
+		}
+		result.Reasons = append(result.Reasons, reason)
+		if severity(decision) > severity(result.Decision) {
+			result.Decision = decision
+		}
+	}
+
+	// this attempt counts towards its own window
+	apply(c.UserAttempts.decide(s.UserAttempts+1), "user_velocity")
+	apply(c.IPAttempts.decide(s.IPAttempts+1), "ip_velocity")
+	if s.HasCard {
+		apply(c.CardAttempts.decide(s.CardAttempts+1), "card_velocity")
+	}
+
+	if decided := s.UserAttempts - s.UserBlocked; decided > 0 && decided >= c.FailedRatioMinAttempts {
+		ratio := float64(s.UserFailed) / float64(decided)
+		switch {
+		case c.FailedRatioBlock > 0 && ratio >= c.FailedRatioBlock:
+			apply(models.RiskDecisionBlock, "failed_ratio")
+		case c.FailedRatioReview > 0 && ratio >= c.FailedRatioReview:
+			apply(models.RiskDecisionReview, "failed_ratio")
+		}
+	}
+
+	if s.HasCard && s.NewCard && c.NewCardAmount > 0 && s.Amount >= c.NewCardAmount {
+		apply(models.RiskDecisionReview, "new_card_high_amount")
+	}
+
+	if s.CardCountry != "" && s.RequestCountry != "" && !strings.EqualFold(s.CardCountry, s.RequestCountry) {
+		apply(c.CountryMismatch, "country_mismatch")
+	}
+
+	return result
+}
+
+func (t Thresholds) decide(count int) models.RiskDecision {
+	switch {
+	case t.Block > 0 && count >= t.Block:
+		return models.RiskDecisionBlock
+	case t.Review > 0 && count >= t.Review:
+		return models.RiskDecisionReview
+	default:
+		return models.RiskDecisionAllow
+	}
+}
+
+func severity(decision models.RiskDecision) int {
+	switch decision {
+	case models.RiskDecisionBlock:
+		return 2
+	case models.RiskDecisionReview:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// CardFingerprint identifies a card across payment infos without storing
+// its number. It is keyed so the fingerprint cannot be brute forced back
+// into a card number without the key.
+func CardFingerprint(key []byte, cardNumber string) string {
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, cardNumber)
+	if digits == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(digits))
+	return hex.EncodeToString(mac.Sum(nil))
+}
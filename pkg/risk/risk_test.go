@@ -0,0 +1,91 @@
+package risk
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"payment-service/pkg/models"
+)
+
+func TestEvaluate(t *testing.T) {
+	config := DefaultConfig()
+
+	tests := []struct {
+		name     string
+		signals  Signals
+		decision models.RiskDecision
+		reasons  []string
+	}{
+		{"first attempt", Signals{Amount: 500}, models.RiskDecisionAllow, nil},
+		{"user reaches review", Signals{UserAttempts: 9}, models.RiskDecisionReview, []string{"user_velocity"}},
+		{"user reaches block", Signals{UserAttempts: 19}, models.RiskDecisionBlock, []string{"user_velocity"}},
+		{"client IP reaches review", Signals{IPAttempts: 19}, models.RiskDecisionReview, []string{"ip_velocity"}},
+		{"client IP reaches block", Signals{IPAttempts: 49}, models.RiskDecisionBlock, []string{"ip_velocity"}},
+		{"card reaches block", Signals{HasCard: true, CardAttempts: 9}, models.RiskDecisionBlock, []string{"card_velocity"}},
+		{"card count without a card", Signals{CardAttempts: 9}, models.RiskDecisionAllow, nil},
+		{"failed ratio below minimum attempts", Signals{UserAttempts: 4, UserFailed: 4}, models.RiskDecisionAllow, nil},
+		{"failed ratio reaches review", Signals{UserAttempts: 6, UserFailed: 3}, models.RiskDecisionReview, []string{"failed_ratio"}},
+		{"failed ratio reaches block", Signals{UserAttempts: 5, UserFailed: 4}, models.RiskDecisionBlock, []string{"failed_ratio"}},
+		{"blocked attempts left out of the ratio", Signals{UserAttempts: 8, UserFailed: 1, UserBlocked: 5}, models.RiskDecisionAllow, nil},
+		{"only blocked attempts", Signals{UserAttempts: 9, UserBlocked: 9}, models.RiskDecisionReview, []string{"user_velocity"}},
+		{"new card high amount", Signals{HasCard: true, NewCard: true, Amount: 10000}, models.RiskDecisionReview, []string{"new_card_high_amount"}},
+		{"known card high amount", Signals{HasCard: true, Amount: 10000}, models.RiskDecisionAllow, nil},
+		{"new card low amount", Signals{HasCard: true, NewCard: true, Amount: 9999}, models.RiskDecisionAllow, nil},
+		{"country mismatch", Signals{CardCountry: "US", RequestCountry: "TH"}, models.RiskDecisionReview, []string{"country_mismatch"}},
+		{"country match ignores case", Signals{CardCountry: "th", RequestCountry: "TH"}, models.RiskDecisionAllow, nil},
+		{"unknown request country", Signals{CardCountry: "US"}, models.RiskDecisionAllow, nil},
+		{"most severe wins", Signals{UserAttempts: 9, IPAttempts: 49, CardCountry: "US", RequestCountry: "TH"}, models.RiskDecisionBlock,
+			[]string{"user_velocity", "ip_velocity", "country_mismatch"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := config.Evaluate(tt.signals)
+			if got.Decision != tt.decision || !slices.Equal(got.Reasons, tt.reasons) {
+				t.Fatalf("got %s %v, want %s %v", got.Decision, got.Reasons, tt.decision, tt.reasons)
+			}
+		})
+	}
+}
+
+func TestEvaluateDisabledRules(t *testing.T) {
+	config := Config{Window: time.Hour, CountryMismatch: models.RiskDecisionAllow}
+	got := config.Evaluate(Signals{
+		UserAttempts: 100,
+		UserFailed:   100,
+		IPAttempts:   100,
+		HasCard:      true,
+		NewCard:      true,
+		CardAttempts: 100,
+		Amount:       1000000,
+		CardCountry:  "US",
+	})
+	if got.Decision != models.RiskDecisionAllow || len(got.Reasons) != 0 {
+		t.Fatalf("got %s %v", got.Decision, got.Reasons)
+	}
+}
+
+func TestCardFingerprint(t *testing.T) {
+	key := []byte("key")
+	tests := []struct {
+		name string
+		a, b string
+		same bool
+	}{
+		{"spacing ignored", "4111 1111 1111 1111", "4111-1111-1111-1111", true},
+		{"different cards", "4111111111111111", "5500000000000004", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CardFingerprint(key, tt.a) == CardFingerprint(key, tt.b); got != tt.same {
+				t.Fatalf("same fingerprint %v, want %v", got, tt.same)
+			}
+		})
+	}
+	if CardFingerprint(key, "no digits") != "" {
+		t.Fatal("fingerprint without a card number")
+	}
+	if CardFingerprint(key, "4111111111111111") == CardFingerprint([]byte("other"), "4111111111111111") {
+		t.Fatal("fingerprint does not depend on the key")
+	}
+}
@@ -1,18 +1,33 @@
 package routes
 
 import (
+	"expvar"
+
 	// "user-service/pkg/context"
 	// "user-service/pkg/dto"
 	_ "payment-service/docs"
+	"payment-service/pkg/constants"
 	"payment-service/pkg/handlers"
 	"payment-service/pkg/jwt"
 	"payment-service/pkg/middleware"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
 	"github.com/gofiber/swagger"
 )
 
+// Roles allowed on each route. Every route below names one of these, so
+// the permission matrix can be read off this file.
+var (
+	adminOnly       = []string{constants.RoleAdmin}
+	patientOnly     = []string{constants.RolePatient}
+	patientOrAdmin  = []string{constants.RolePatient, constants.RoleAdmin}
+	anyUser         = []string{constants.RolePatient, constants.RoleDoctor, constants.RoleAdmin}
+	internalService = []string{constants.RoleService}
+)
+
 func SetupRoutes(app *fiber.App, paymentHandler *handlers.PaymentHandler, jwtSvc *jwt.JwtService) {
+	allow := middleware.RequireRole
 
 	api := app.Group("/api")
 
@@ -23,18 +38,49 @@ func SetupRoutes(app *fiber.App, paymentHandler *handlers.PaymentHandler, jwtSvc
 	paymentV1 := payment.Group("/v1")
 	paymentV1.Use(middleware.JwtMiddleware(jwtSvc))
 	// payment
-	paymentV1.Post("/", paymentHandler.CreatePayment)
-	paymentV1.Get("/", paymentHandler.GetAllPayments)
-	paymentV1.Get("/:id", paymentHandler.GetPaymentByID)
+	paymentV1.Post("/", allow(adminOnly...), paymentHandler.CreatePayment)
+	paymentV1.Get("/", allow(adminOnly...), paymentHandler.GetAllPayments)
+	paymentV1.Get("/receivables", allow(adminOnly...), paymentHandler.GetReceivables)
+	paymentV1.Get("/audit", allow(adminOnly...), paymentHandler.GetAuditLogs)
+	paymentV1.Get("/risk/reviews", allow(adminOnly...), paymentHandler.GetRiskReviews)
+	paymentV1.Post("/risk/reviews/:reviewId/approve", allow(adminOnly...), paymentHandler.ApproveRiskReview)
+	paymentV1.Post("/risk/reviews/:reviewId/deny", allow(adminOnly...), paymentHandler.DenyRiskReview)
+	paymentV1.Post("/tokens/revoke", allow(adminOnly...), paymentHandler.RevokeToken)
+	// exports
+	paymentV1.Get("/exports/payments", allow(adminOnly...), paymentHandler.ExportPayments)
+	paymentV1.Post("/exports/payments/jobs", allow(adminOnly...), paymentHandler.StartPaymentExportJob)
+	paymentV1.Get("/exports/jobs/:jobId", allow(adminOnly...), paymentHandler.GetExportJob)
+	paymentV1.Get("/exports/jobs/:jobId/download", allow(adminOnly...), paymentHandler.DownloadExportJob)
+	paymentV1.Get("/me/payments", allow(patientOnly...), paymentHandler.GetMyPayments)
+	paymentV1.Get("/orders/:orderId", allow(patientOrAdmin...), paymentHandler.GetOrderPayments)
+	// payment document routes
+	paymentV1.Post("/documents/:documentId/reissue", allow(adminOnly...), paymentHandler.ReissueDocument)
+	paymentV1.Post("/documents/:documentId/void", allow(adminOnly...), paymentHandler.VoidDocument)
 	// payment info routes
-	paymentV1.Post("/info", paymentHandler.CreatePaymentInfo)
-	paymentV1.Put("/info", paymentHandler.UpdatePaymentInfo)
-	paymentV1.Delete("/info", paymentHandler.DeletePaymentInfo)
-	paymentV1.Get("/info", paymentHandler.GetAllPaymentInfos)
-	paymentV1.Get("/info/method", paymentHandler.GetPaymentInfoByMethod)
-	paymentV1.Get("/info/:id", paymentHandler.GetPaymentInfo)
+	paymentV1.Post("/info", allow(patientOnly...), paymentHandler.CreatePaymentInfo)
+	paymentV1.Put("/info", allow(patientOrAdmin...), paymentHandler.UpdatePaymentInfo)
+	paymentV1.Delete("/info", allow(patientOrAdmin...), paymentHandler.DeletePaymentInfo)
+	paymentV1.Get("/info", allow(adminOnly...), paymentHandler.GetAllPaymentInfos)
+	paymentV1.Get("/info/method", allow(patientOnly...), paymentHandler.GetPaymentInfoByMethod)
+	paymentV1.Get("/info/:id", allow(patientOrAdmin...), paymentHandler.GetPaymentInfo)
 	// payment attempt routes
-	paymentV1.Post("/attempt", paymentHandler.CreatePaymentAttempt)
-	paymentV1.Get("/attempt/:id", paymentHandler.GetPaymentAttempt)
-	paymentV1.Patch("/attempt", paymentHandler.UpdatePaymentAttempt)
+	paymentV1.Post("/attempt", allow(patientOnly...), paymentHandler.CreatePaymentAttempt)
+	paymentV1.Get("/attempt/:id", allow(patientOrAdmin...), paymentHandler.GetPaymentAttempt)
+	paymentV1.Get("/attempt/:id/events", allow(patientOrAdmin...), paymentHandler.StreamPaymentAttemptEvents)
+	paymentV1.Patch("/attempt", allow(adminOnly...), paymentHandler.UpdatePaymentAttempt)
+	// routes keyed by payment ID go last so they do not shadow the static ones
+	paymentV1.Get("/:id", allow(anyUser...), paymentHandler.GetPaymentByID)
+	paymentV1.Get("/:id/receipt", allow(patientOrAdmin...), paymentHandler.GetPaymentReceipt)
+	paymentV1.Post("/:id/receipt", allow(patientOrAdmin...), paymentHandler.IssueReceipt)
+	paymentV1.Get("/:id/documents", allow(patientOrAdmin...), paymentHandler.GetPaymentDocuments)
+	paymentV1.Post("/:id/tax-invoice", allow(patientOrAdmin...), paymentHandler.IssueTaxInvoice)
+
+	// Internal routes for other services, authenticated with service tokens
+	internalV1 := app.Group("/internal/v1")
+	internalV1.Use(middleware.ServiceTokenMiddleware(jwtSvc))
+	// the colon of status:batch is literal, not a parameter
+	internalV1.Post("/payments/status\\:batch", allow(internalService...), paymentHandler.GetPayableStatuses)
+	internalV1.Get("/payments/:payableType/:payableId/status", allow(internalService...), paymentHandler.GetPayableStatus)
+	internalV1.Get("/orders/:orderId", allow(internalService...), paymentHandler.GetOrderPayments)
+	internalV1.Get("/metrics/vars", allow(internalService...), adaptor.HTTPHandler(expvar.Handler()))
 }
@@ -0,0 +1,150 @@
+package routes
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"payment-service/pkg/constants"
+	"payment-service/pkg/handlers"
+	"payment-service/pkg/jwt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+)
+
+type routePermission struct {
+	method string
+	path   string
+	roles  []string
+}
+
+// permissionMatrix is the expected set of roles for every route
+// SetupRoutes registers. A route missing from here fails the test.
+var permissionMatrix = []routePermission{
+	{"POST", "/api/payment/v1/", []string{constants.RoleAdmin}},
+	{"GET", "/api/payment/v1/", []string{constants.RoleAdmin}},
+	{"GET", "/api/payment/v1/receivables", []string{constants.RoleAdmin}},
+	{"GET", "/api/payment/v1/audit", []string{constants.RoleAdmin}},
+	{"GET", "/api/payment/v1/risk/reviews", []string{constants.RoleAdmin}},
+	{"POST", "/api/payment/v1/risk/reviews/:reviewId/approve", []string{constants.RoleAdmin}},
+	{"POST", "/api/payment/v1/risk/reviews/:reviewId/deny", []string{constants.RoleAdmin}},
+	{"POST", "/api/payment/v1/tokens/revoke", []string{constants.RoleAdmin}},
+	{"GET", "/api/payment/v1/exports/payments", []string{constants.RoleAdmin}},
+	{"POST", "/api/payment/v1/exports/payments/jobs", []string{constants.RoleAdmin}},
+	{"GET", "/api/payment/v1/exports/jobs/:jobId", []string{constants.RoleAdmin}},
+	{"GET", "/api/payment/v1/exports/jobs/:jobId/download", []string{constants.RoleAdmin}},
+	{"GET", "/api/payment/v1/me/payments", []string{constants.RolePatient}},
+	{"GET", "/api/payment/v1/orders/:orderId", []string{constants.RolePatient, constants.RoleAdmin}},
+	{"POST", "/api/payment/v1/documents/:documentId/reissue", []string{constants.RoleAdmin}},
+	{"POST", "/api/payment/v1/documents/:documentId/void", []string{constants.RoleAdmin}},
+	{"POST", "/api/payment/v1/info", []string{constants.RolePatient}},
+	{"PUT", "/api/payment/v1/info", []string{constants.RolePatient, constants.RoleAdmin}},
+	{"DELETE", "/api/payment/v1/info", []string{constants.RolePatient, constants.RoleAdmin}},
+	{"GET", "/api/payment/v1/info", []string{constants.RoleAdmin}},
+	{"GET", "/api/payment/v1/info/method", []string{constants.RolePatient}},
+	{"GET", "/api/payment/v1/info/:id", []string{constants.RolePatient, constants.RoleAdmin}},
+	{"POST", "/api/payment/v1/attempt", []string{constants.RolePatient}},
+	{"GET", "/api/payment/v1/attempt/:id", []string{constants.RolePatient, constants.RoleAdmin}},
+	{"GET", "/api/payment/v1/attempt/:id/events", []string{constants.RolePatient, constants.RoleAdmin}},
+	{"PATCH", "/api/payment/v1/attempt", []string{constants.RoleAdmin}},
+	{"GET", "/api/payment/v1/:id", []string{constants.RolePatient, constants.RoleDoctor, constants.RoleAdmin}},
+	{"GET", "/api/payment/v1/:id/receipt", []string{constants.RolePatient, constants.RoleAdmin}},
+	{"POST", "/api/payment/v1/:id/receipt", []string{constants.RolePatient, constants.RoleAdmin}},
+	{"GET", "/api/payment/v1/:id/documents", []string{constants.RolePatient, constants.RoleAdmin}},
+	{"POST", "/api/payment/v1/:id/tax-invoice", []string{constants.RolePatient, constants.RoleAdmin}},
+	{"POST", "/internal/v1/payments/status:batch", []string{constants.RoleService}},
+	{"GET", "/internal/v1/payments/:payableType/:payableId/status", []string{constants.RoleService}},
+	{"GET", "/internal/v1/orders/:orderId", []string{constants.RoleService}},
+	{"GET", "/internal/v1/metrics/vars", []string{constants.RoleService}},
+}
+
+var allRoles = []string{constants.RoleAdmin, constants.RoleDoctor, constants.RolePatient, constants.RoleService}
+
+func newTestApp(jwtSvc *jwt.JwtService) *fiber.App {
+	app := fiber.New()
+	// The handler has no service behind it, so a request that gets past
+	// authorization panics; recover turns that into a 500.
+	app.Use(recover.New())
+	SetupRoutes(app, &handlers.PaymentHandler{}, jwtSvc)
+	return app
+}
+
+func TestEveryRouteIsInPermissionMatrix(t *testing.T) {
+	app := newTestApp(jwt.NewJwtService("secret", 60, "payment-service", "service-secret", 60))
+
+	known := make(map[string]bool, len(permissionMatrix))
+	for _, p := range permissionMatrix {
+		known[p.method+" "+p.path] = true
+	}
+
+	for _, route := range app.GetRoutes(true) {
+		if route.Method == fiber.MethodHead || strings.HasPrefix(route.Path, "/api/payment/swagger") {
+			continue
+		}
+		// escaped colons, as in status\:batch, are literal
+		path := strings.ReplaceAll(route.Path, `\:`, ":")
+		if !known[route.Method+" "+path] {
+			t.Errorf("route %s %s has no entry in the permission matrix", route.Method, route.Path)
+		}
+	}
+}
+
+func TestPermissionMatrix(t *testing.T) {
+	jwtSvc := jwt.NewJwtService("secret", 60, "payment-service", "service-secret", 60)
+	app := newTestApp(jwtSvc)
+
+	for _, p := range permissionMatrix {
+		for _, role := range allRoles {
+			t.Run(p.method+" "+p.path+" as "+role, func(t *testing.T) {
+				req := httptest.NewRequest(p.method, samplePath(p.path), nil)
+				authorize(t, req, jwtSvc, role)
+
+				resp, err := app.Test(req, -1)
+				if err != nil {
+					t.Fatalf("request failed: %v", err)
+				}
+
+				allowed := slices.Contains(p.roles, role)
+				denied := resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusUnauthorized
+				if allowed && denied {
+					t.Errorf("expected %s to be allowed, got %d", role, resp.StatusCode)
+				}
+				if !allowed && !denied {
+					t.Errorf("expected %s to be denied, got %d", role, resp.StatusCode)
+				}
+			})
+		}
+	}
+}
+
+// authorize attaches a credential the way each kind of caller sends it:
+// users with the access_token cookie, services with a bearer token.
+func authorize(t *testing.T, req *http.Request, jwtSvc *jwt.JwtService, role string) {
+	t.Helper()
+	if role == constants.RoleService {
+		token, err := jwtSvc.ServiceToken(jwtSvc.ServiceName)
+		if err != nil {
+			t.Fatalf("failed to sign service token: %v", err)
+		}
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+		return
+	}
+	token, err := jwtSvc.GenerateToken("0192a5f4-7c3e-7000-8000-000000000001", role)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: token})
+}
+
+func samplePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "0192a5f4-7c3e-7000-8000-000000000002"
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,120 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"payment-service/pkg/apperr"
+	contextUtils "payment-service/pkg/context"
+	"payment-service/pkg/i18n"
+	"payment-service/pkg/jwt"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// ServiceTokenInterceptor accepts only calls carrying a bearer service token
+// addressed to this service in the authorization metadata, never a user's
+// token. The x-request-id metadata, when sent, follows the call into logs.
+func ServiceTokenInterceptor(jwtService *jwt.JwtService) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		token, found := strings.CutPrefix(first(md, "authorization"), "Bearer ")
+		if !found || token == "" {
+			return nil, apperr.New(apperr.CodeUnauthorized, i18n.MissingServiceToken, nil)
+		}
+
+		claims, err := jwtService.ParseServiceToken(token)
+		if err != nil {
+			return nil, apperr.New(apperr.CodeUnauthorized, i18n.InvalidServiceToken, nil)
+		}
+
+		return handler(callerContext(ctx, claims.Subject, first(md, "x-request-id")), req)
+	}
+}
+
+// ErrorInterceptor turns errors carrying an apperr.Code into a status with
+// the matching gRPC code, in the language of the accept-language metadata.
+// Failed fields travel as a BadRequest detail. Errors without a Code are
+// internal, and their text is never sent.
+func ErrorInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		lang := language(first(md, "accept-language"))
+		ctx = context.WithValue(ctx, contextUtils.ContextKeyLanguage, lang)
+
+		resp, err := handler(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+		return nil, toStatus(err, lang).Err()
+	}
+}
+
+func toStatus(err error, lang i18n.Lang) *status.Status {
+	if st, ok := status.FromError(err); ok {
+		return st
+	}
+	var ae *apperr.Error
+	if !errors.As(err, &ae) {
+		return status.New(codes.Internal, i18n.T(i18n.Internal).In(lang))
+	}
+
+	st := status.New(Code(ae.Code), ae.Msg.In(lang))
+	if len(ae.Fields) == 0 {
+		return st
+	}
+	violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(ae.Fields))
+	for field, text := range ae.Fields {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       field,
+			Description: text.In(lang),
+		})
+	}
+	if detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); err == nil {
+		return detailed
+	}
+	return st
+}
+
+// Code is the gRPC status code of an apperr.Code.
+func Code(code apperr.Code) codes.Code {
+	switch code {
+	case apperr.CodeBadRequest:
+		return codes.InvalidArgument
+	case apperr.CodeUnauthorized:
+		return codes.Unauthenticated
+	case apperr.CodeForbidden:
+		return codes.PermissionDenied
+	case apperr.CodeNotFound:
+		return codes.NotFound
+	case apperr.CodeConflict, apperr.CodePreconditionRequired:
+		return codes.FailedPrecondition
+	case apperr.CodeBadGateway, apperr.CodeUnavailable:
+		return codes.Unavailable
+	case apperr.CodeGatewayTimeout:
+		return codes.DeadlineExceeded
+	default:
+		return codes.Internal
+	}
+}
+
+// language picks the first language of an Accept-Language value, English
+// when it is not one the service speaks.
+func language(acceptLanguage string) i18n.Lang {
+	tag, _, _ := strings.Cut(acceptLanguage, ",")
+	tag, _, _ = strings.Cut(tag, ";")
+	tag, _, _ = strings.Cut(strings.TrimSpace(tag), "-")
+	return i18n.Parse(strings.ToLower(tag))
+}
+
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
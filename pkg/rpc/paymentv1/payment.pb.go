@@ -0,0 +1,1026 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: payment/v1/payment.proto
+
+package paymentv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type LineItem struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Description string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Quantity    float64                `protobuf:"fixed64,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	// negative for discounts
+	UnitPrice float64 `protobuf:"fixed64,4,opt,name=unit_price,json=unitPrice,proto3" json:"unit_price,omitempty"`
+	Tax       float64 `protobuf:"fixed64,5,opt,name=tax,proto3" json:"tax,omitempty"`
+	// medicine, delivery_fee, consultation_fee, discount or other
+	Category      string  `protobuf:"bytes,6,opt,name=category,proto3" json:"category,omitempty"`
+	Total         float64 `protobuf:"fixed64,7,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LineItem) Reset() {
+	*x = LineItem{}
+	mi := &file_payment_v1_payment_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LineItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LineItem) ProtoMessage() {}
+
+func (x *LineItem) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_v1_payment_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LineItem.ProtoReflect.Descriptor instead.
+func (*LineItem) Descriptor() ([]byte, []int) {
+	return file_payment_v1_payment_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *LineItem) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *LineItem) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *LineItem) GetQuantity() float64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *LineItem) GetUnitPrice() float64 {
+	if x != nil {
+		return x.UnitPrice
+	}
+	return 0
+}
+
+func (x *LineItem) GetTax() float64 {
+	if x != nil {
+		return x.Tax
+	}
+	return 0
+}
+
+func (x *LineItem) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *LineItem) GetTotal() float64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type CreateAttemptRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// order, appointment, delivery or deposit
+	PayableType   string      `protobuf:"bytes,2,opt,name=payable_type,json=payableType,proto3" json:"payable_type,omitempty"`
+	PayableId     string      `protobuf:"bytes,3,opt,name=payable_id,json=payableId,proto3" json:"payable_id,omitempty"`
+	PaymentInfoId string      `protobuf:"bytes,4,opt,name=payment_info_id,json=paymentInfoId,proto3" json:"payment_info_id,omitempty"`
+	LineItems     []*LineItem `protobuf:"bytes,5,rep,name=line_items,json=lineItems,proto3" json:"line_items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAttemptRequest) Reset() {
+	*x = CreateAttemptRequest{}
+	mi := &file_payment_v1_payment_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAttemptRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAttemptRequest) ProtoMessage() {}
+
+func (x *CreateAttemptRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_v1_payment_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAttemptRequest.ProtoReflect.Descriptor instead.
+func (*CreateAttemptRequest) Descriptor() ([]byte, []int) {
+	return file_payment_v1_payment_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateAttemptRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CreateAttemptRequest) GetPayableType() string {
+	if x != nil {
+		return x.PayableType
+	}
+	return ""
+}
+
+func (x *CreateAttemptRequest) GetPayableId() string {
+	if x != nil {
+		return x.PayableId
+	}
+	return ""
+}
+
+func (x *CreateAttemptRequest) GetPaymentInfoId() string {
+	if x != nil {
+		return x.PaymentInfoId
+	}
+	return ""
+}
+
+func (x *CreateAttemptRequest) GetLineItems() []*LineItem {
+	if x != nil {
+		return x.LineItems
+	}
+	return nil
+}
+
+type CreateAttemptResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	PaymentAttemptId string                 `protobuf:"bytes,1,opt,name=payment_attempt_id,json=paymentAttemptId,proto3" json:"payment_attempt_id,omitempty"`
+	// pending while the attempt waits for a risk review
+	Status        string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAttemptResponse) Reset() {
+	*x = CreateAttemptResponse{}
+	mi := &file_payment_v1_payment_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAttemptResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAttemptResponse) ProtoMessage() {}
+
+func (x *CreateAttemptResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_v1_payment_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAttemptResponse.ProtoReflect.Descriptor instead.
+func (*CreateAttemptResponse) Descriptor() ([]byte, []int) {
+	return file_payment_v1_payment_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateAttemptResponse) GetPaymentAttemptId() string {
+	if x != nil {
+		return x.PaymentAttemptId
+	}
+	return ""
+}
+
+func (x *CreateAttemptResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type GetAttemptRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	PaymentAttemptId string                 `protobuf:"bytes,1,opt,name=payment_attempt_id,json=paymentAttemptId,proto3" json:"payment_attempt_id,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *GetAttemptRequest) Reset() {
+	*x = GetAttemptRequest{}
+	mi := &file_payment_v1_payment_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAttemptRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAttemptRequest) ProtoMessage() {}
+
+func (x *GetAttemptRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_v1_payment_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAttemptRequest.ProtoReflect.Descriptor instead.
+func (*GetAttemptRequest) Descriptor() ([]byte, []int) {
+	return file_payment_v1_payment_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetAttemptRequest) GetPaymentAttemptId() string {
+	if x != nil {
+		return x.PaymentAttemptId
+	}
+	return ""
+}
+
+type Attempt struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	PaymentAttemptId string                 `protobuf:"bytes,1,opt,name=payment_attempt_id,json=paymentAttemptId,proto3" json:"payment_attempt_id,omitempty"`
+	PayableType      string                 `protobuf:"bytes,2,opt,name=payable_type,json=payableType,proto3" json:"payable_type,omitempty"`
+	PayableId        string                 `protobuf:"bytes,3,opt,name=payable_id,json=payableId,proto3" json:"payable_id,omitempty"`
+	PaymentInfoId    string                 `protobuf:"bytes,4,opt,name=payment_info_id,json=paymentInfoId,proto3" json:"payment_info_id,omitempty"`
+	// credit_card or promptpay
+	Method string `protobuf:"bytes,5,opt,name=method,proto3" json:"method,omitempty"`
+	// pending, success or failed
+	Status        string `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	CreatedAt     string `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Attempt) Reset() {
+	*x = Attempt{}
+	mi := &file_payment_v1_payment_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Attempt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Attempt) ProtoMessage() {}
+
+func (x *Attempt) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_v1_payment_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Attempt.ProtoReflect.Descriptor instead.
+func (*Attempt) Descriptor() ([]byte, []int) {
+	return file_payment_v1_payment_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Attempt) GetPaymentAttemptId() string {
+	if x != nil {
+		return x.PaymentAttemptId
+	}
+	return ""
+}
+
+func (x *Attempt) GetPayableType() string {
+	if x != nil {
+		return x.PayableType
+	}
+	return ""
+}
+
+func (x *Attempt) GetPayableId() string {
+	if x != nil {
+		return x.PayableId
+	}
+	return ""
+}
+
+func (x *Attempt) GetPaymentInfoId() string {
+	if x != nil {
+		return x.PaymentInfoId
+	}
+	return ""
+}
+
+func (x *Attempt) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *Attempt) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Attempt) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type GetPaymentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PaymentId     string                 `protobuf:"bytes,1,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPaymentRequest) Reset() {
+	*x = GetPaymentRequest{}
+	mi := &file_payment_v1_payment_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPaymentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPaymentRequest) ProtoMessage() {}
+
+func (x *GetPaymentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_v1_payment_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPaymentRequest.ProtoReflect.Descriptor instead.
+func (*GetPaymentRequest) Descriptor() ([]byte, []int) {
+	return file_payment_v1_payment_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetPaymentRequest) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+type Payment struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	PaymentId             string                 `protobuf:"bytes,1,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	AttemptId             string                 `protobuf:"bytes,2,opt,name=attempt_id,json=attemptId,proto3" json:"attempt_id,omitempty"`
+	PayableType           string                 `protobuf:"bytes,3,opt,name=payable_type,json=payableType,proto3" json:"payable_type,omitempty"`
+	PayableId             string                 `protobuf:"bytes,4,opt,name=payable_id,json=payableId,proto3" json:"payable_id,omitempty"`
+	Amount                float64                `protobuf:"fixed64,5,opt,name=amount,proto3" json:"amount,omitempty"`
+	PatientAmount         float64                `protobuf:"fixed64,6,opt,name=patient_amount,json=patientAmount,proto3" json:"patient_amount,omitempty"`
+	PayerAmount           float64                `protobuf:"fixed64,7,opt,name=payer_amount,json=payerAmount,proto3" json:"payer_amount,omitempty"`
+	HealthcareEntitlement string                 `protobuf:"bytes,8,opt,name=healthcare_entitlement,json=healthcareEntitlement,proto3" json:"healthcare_entitlement,omitempty"`
+	PaidAt                string                 `protobuf:"bytes,9,opt,name=paid_at,json=paidAt,proto3" json:"paid_at,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *Payment) Reset() {
+	*x = Payment{}
+	mi := &file_payment_v1_payment_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Payment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Payment) ProtoMessage() {}
+
+func (x *Payment) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_v1_payment_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Payment.ProtoReflect.Descriptor instead.
+func (*Payment) Descriptor() ([]byte, []int) {
+	return file_payment_v1_payment_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Payment) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+func (x *Payment) GetAttemptId() string {
+	if x != nil {
+		return x.AttemptId
+	}
+	return ""
+}
+
+func (x *Payment) GetPayableType() string {
+	if x != nil {
+		return x.PayableType
+	}
+	return ""
+}
+
+func (x *Payment) GetPayableId() string {
+	if x != nil {
+		return x.PayableId
+	}
+	return ""
+}
+
+func (x *Payment) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Payment) GetPatientAmount() float64 {
+	if x != nil {
+		return x.PatientAmount
+	}
+	return 0
+}
+
+func (x *Payment) GetPayerAmount() float64 {
+	if x != nil {
+		return x.PayerAmount
+	}
+	return 0
+}
+
+func (x *Payment) GetHealthcareEntitlement() string {
+	if x != nil {
+		return x.HealthcareEntitlement
+	}
+	return ""
+}
+
+func (x *Payment) GetPaidAt() string {
+	if x != nil {
+		return x.PaidAt
+	}
+	return ""
+}
+
+type GetPaymentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Payment       *Payment               `protobuf:"bytes,1,opt,name=payment,proto3" json:"payment,omitempty"`
+	LineItems     []*LineItem            `protobuf:"bytes,2,rep,name=line_items,json=lineItems,proto3" json:"line_items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPaymentResponse) Reset() {
+	*x = GetPaymentResponse{}
+	mi := &file_payment_v1_payment_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPaymentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPaymentResponse) ProtoMessage() {}
+
+func (x *GetPaymentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_v1_payment_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPaymentResponse.ProtoReflect.Descriptor instead.
+func (*GetPaymentResponse) Descriptor() ([]byte, []int) {
+	return file_payment_v1_payment_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetPaymentResponse) GetPayment() *Payment {
+	if x != nil {
+		return x.Payment
+	}
+	return nil
+}
+
+func (x *GetPaymentResponse) GetLineItems() []*LineItem {
+	if x != nil {
+		return x.LineItems
+	}
+	return nil
+}
+
+type ListPaymentsByOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPaymentsByOrderRequest) Reset() {
+	*x = ListPaymentsByOrderRequest{}
+	mi := &file_payment_v1_payment_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPaymentsByOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPaymentsByOrderRequest) ProtoMessage() {}
+
+func (x *ListPaymentsByOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_v1_payment_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPaymentsByOrderRequest.ProtoReflect.Descriptor instead.
+func (*ListPaymentsByOrderRequest) Descriptor() ([]byte, []int) {
+	return file_payment_v1_payment_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListPaymentsByOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type ListPaymentsByOrderResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	OrderId string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	// unpaid, partially_paid, paid, overpaid or refunded
+	Status             string     `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	AmountDue          float64    `protobuf:"fixed64,3,opt,name=amount_due,json=amountDue,proto3" json:"amount_due,omitempty"`
+	TotalPaid          float64    `protobuf:"fixed64,4,opt,name=total_paid,json=totalPaid,proto3" json:"total_paid,omitempty"`
+	TotalRefunded      float64    `protobuf:"fixed64,5,opt,name=total_refunded,json=totalRefunded,proto3" json:"total_refunded,omitempty"`
+	OutstandingBalance float64    `protobuf:"fixed64,6,opt,name=outstanding_balance,json=outstandingBalance,proto3" json:"outstanding_balance,omitempty"`
+	Attempts           []*Attempt `protobuf:"bytes,7,rep,name=attempts,proto3" json:"attempts,omitempty"`
+	Payments           []*Payment `protobuf:"bytes,8,rep,name=payments,proto3" json:"payments,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *ListPaymentsByOrderResponse) Reset() {
+	*x = ListPaymentsByOrderResponse{}
+	mi := &file_payment_v1_payment_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPaymentsByOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPaymentsByOrderResponse) ProtoMessage() {}
+
+func (x *ListPaymentsByOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_v1_payment_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPaymentsByOrderResponse.ProtoReflect.Descriptor instead.
+func (*ListPaymentsByOrderResponse) Descriptor() ([]byte, []int) {
+	return file_payment_v1_payment_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListPaymentsByOrderResponse) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *ListPaymentsByOrderResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListPaymentsByOrderResponse) GetAmountDue() float64 {
+	if x != nil {
+		return x.AmountDue
+	}
+	return 0
+}
+
+func (x *ListPaymentsByOrderResponse) GetTotalPaid() float64 {
+	if x != nil {
+		return x.TotalPaid
+	}
+	return 0
+}
+
+func (x *ListPaymentsByOrderResponse) GetTotalRefunded() float64 {
+	if x != nil {
+		return x.TotalRefunded
+	}
+	return 0
+}
+
+func (x *ListPaymentsByOrderResponse) GetOutstandingBalance() float64 {
+	if x != nil {
+		return x.OutstandingBalance
+	}
+	return 0
+}
+
+func (x *ListPaymentsByOrderResponse) GetAttempts() []*Attempt {
+	if x != nil {
+		return x.Attempts
+	}
+	return nil
+}
+
+func (x *ListPaymentsByOrderResponse) GetPayments() []*Payment {
+	if x != nil {
+		return x.Payments
+	}
+	return nil
+}
+
+type RefundRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PaymentId     string                 `protobuf:"bytes,1,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	Amount        float64                `protobuf:"fixed64,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefundRequest) Reset() {
+	*x = RefundRequest{}
+	mi := &file_payment_v1_payment_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefundRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefundRequest) ProtoMessage() {}
+
+func (x *RefundRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_v1_payment_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefundRequest.ProtoReflect.Descriptor instead.
+func (*RefundRequest) Descriptor() ([]byte, []int) {
+	return file_payment_v1_payment_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RefundRequest) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+func (x *RefundRequest) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *RefundRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type RefundResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RefundId      string                 `protobuf:"bytes,1,opt,name=refund_id,json=refundId,proto3" json:"refund_id,omitempty"`
+	PaymentId     string                 `protobuf:"bytes,2,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	Amount        float64                `protobuf:"fixed64,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefundResponse) Reset() {
+	*x = RefundResponse{}
+	mi := &file_payment_v1_payment_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefundResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefundResponse) ProtoMessage() {}
+
+func (x *RefundResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_v1_payment_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefundResponse.ProtoReflect.Descriptor instead.
+func (*RefundResponse) Descriptor() ([]byte, []int) {
+	return file_payment_v1_payment_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RefundResponse) GetRefundId() string {
+	if x != nil {
+		return x.RefundId
+	}
+	return ""
+}
+
+func (x *RefundResponse) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+func (x *RefundResponse) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *RefundResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+var File_payment_v1_payment_proto protoreflect.FileDescriptor
+
+const file_payment_v1_payment_proto_rawDesc = "" +
+	"\n" +
+	"\x18payment/v1/payment.proto\x12\n" +
+	"payment.v1\"\xbb\x01\n" +
+	"\bLineItem\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x01R\bquantity\x12\x1d\n" +
+	"\n" +
+	"unit_price\x18\x04 \x01(\x01R\tunitPrice\x12\x10\n" +
+	"\x03tax\x18\x05 \x01(\x01R\x03tax\x12\x1a\n" +
+	"\bcategory\x18\x06 \x01(\tR\bcategory\x12\x14\n" +
+	"\x05total\x18\a \x01(\x01R\x05total\"\xce\x01\n" +
+	"\x14CreateAttemptRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12!\n" +
+	"\fpayable_type\x18\x02 \x01(\tR\vpayableType\x12\x1d\n" +
+	"\n" +
+	"payable_id\x18\x03 \x01(\tR\tpayableId\x12&\n" +
+	"\x0fpayment_info_id\x18\x04 \x01(\tR\rpaymentInfoId\x123\n" +
+	"\n" +
+	"line_items\x18\x05 \x03(\v2\x14.payment.v1.LineItemR\tlineItems\"]\n" +
+	"\x15CreateAttemptResponse\x12,\n" +
+	"\x12payment_attempt_id\x18\x01 \x01(\tR\x10paymentAttemptId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\"A\n" +
+	"\x11GetAttemptRequest\x12,\n" +
+	"\x12payment_attempt_id\x18\x01 \x01(\tR\x10paymentAttemptId\"\xf0\x01\n" +
+	"\aAttempt\x12,\n" +
+	"\x12payment_attempt_id\x18\x01 \x01(\tR\x10paymentAttemptId\x12!\n" +
+	"\fpayable_type\x18\x02 \x01(\tR\vpayableType\x12\x1d\n" +
+	"\n" +
+	"payable_id\x18\x03 \x01(\tR\tpayableId\x12&\n" +
+	"\x0fpayment_info_id\x18\x04 \x01(\tR\rpaymentInfoId\x12\x16\n" +
+	"\x06method\x18\x05 \x01(\tR\x06method\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\tR\tcreatedAt\"2\n" +
+	"\x11GetPaymentRequest\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x01 \x01(\tR\tpaymentId\"\xbb\x02\n" +
+	"\aPayment\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x01 \x01(\tR\tpaymentId\x12\x1d\n" +
+	"\n" +
+	"attempt_id\x18\x02 \x01(\tR\tattemptId\x12!\n" +
+	"\fpayable_type\x18\x03 \x01(\tR\vpayableType\x12\x1d\n" +
+	"\n" +
+	"payable_id\x18\x04 \x01(\tR\tpayableId\x12\x16\n" +
+	"\x06amount\x18\x05 \x01(\x01R\x06amount\x12%\n" +
+	"\x0epatient_amount\x18\x06 \x01(\x01R\rpatientAmount\x12!\n" +
+	"\fpayer_amount\x18\a \x01(\x01R\vpayerAmount\x125\n" +
+	"\x16healthcare_entitlement\x18\b \x01(\tR\x15healthcareEntitlement\x12\x17\n" +
+	"\apaid_at\x18\t \x01(\tR\x06paidAt\"x\n" +
+	"\x12GetPaymentResponse\x12-\n" +
+	"\apayment\x18\x01 \x01(\v2\x13.payment.v1.PaymentR\apayment\x123\n" +
+	"\n" +
+	"line_items\x18\x02 \x03(\v2\x14.payment.v1.LineItemR\tlineItems\"7\n" +
+	"\x1aListPaymentsByOrderRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\"\xc8\x02\n" +
+	"\x1bListPaymentsByOrderResponse\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"amount_due\x18\x03 \x01(\x01R\tamountDue\x12\x1d\n" +
+	"\n" +
+	"total_paid\x18\x04 \x01(\x01R\ttotalPaid\x12%\n" +
+	"\x0etotal_refunded\x18\x05 \x01(\x01R\rtotalRefunded\x12/\n" +
+	"\x13outstanding_balance\x18\x06 \x01(\x01R\x12outstandingBalance\x12/\n" +
+	"\battempts\x18\a \x03(\v2\x13.payment.v1.AttemptR\battempts\x12/\n" +
+	"\bpayments\x18\b \x03(\v2\x13.payment.v1.PaymentR\bpayments\"^\n" +
+	"\rRefundRequest\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x01 \x01(\tR\tpaymentId\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x01R\x06amount\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"|\n" +
+	"\x0eRefundResponse\x12\x1b\n" +
+	"\trefund_id\x18\x01 \x01(\tR\brefundId\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x02 \x01(\tR\tpaymentId\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x01R\x06amount\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status2\x9e\x03\n" +
+	"\x0ePaymentService\x12T\n" +
+	"\rCreateAttempt\x12 .payment.v1.CreateAttemptRequest\x1a!.payment.v1.CreateAttemptResponse\x12@\n" +
+	"\n" +
+	"GetAttempt\x12\x1d.payment.v1.GetAttemptRequest\x1a\x13.payment.v1.Attempt\x12K\n" +
+	"\n" +
+	"GetPayment\x12\x1d.payment.v1.GetPaymentRequest\x1a\x1e.payment.v1.GetPaymentResponse\x12f\n" +
+	"\x13ListPaymentsByOrder\x12&.payment.v1.ListPaymentsByOrderRequest\x1a'.payment.v1.ListPaymentsByOrderResponse\x12?\n" +
+	"\x06Refund\x12\x19.payment.v1.RefundRequest\x1a\x1a.payment.v1.RefundResponseB-Z+payment-service/pkg/rpc/paymentv1;paymentv1b\x06proto3"
+
+var (
+	file_payment_v1_payment_proto_rawDescOnce sync.Once
+	file_payment_v1_payment_proto_rawDescData []byte
+)
+
+func file_payment_v1_payment_proto_rawDescGZIP() []byte {
+	file_payment_v1_payment_proto_rawDescOnce.Do(func() {
+		file_payment_v1_payment_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_payment_v1_payment_proto_rawDesc), len(file_payment_v1_payment_proto_rawDesc)))
+	})
+	return file_payment_v1_payment_proto_rawDescData
+}
+
+var file_payment_v1_payment_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_payment_v1_payment_proto_goTypes = []any{
+	(*LineItem)(nil),                    // 0: payment.v1.LineItem
+	(*CreateAttemptRequest)(nil),        // 1: payment.v1.CreateAttemptRequest
+	(*CreateAttemptResponse)(nil),       // 2: payment.v1.CreateAttemptResponse
+	(*GetAttemptRequest)(nil),           // 3: payment.v1.GetAttemptRequest
+	(*Attempt)(nil),                     // 4: payment.v1.Attempt
+	(*GetPaymentRequest)(nil),           // 5: payment.v1.GetPaymentRequest
+	(*Payment)(nil),                     // 6: payment.v1.Payment
+	(*GetPaymentResponse)(nil),          // 7: payment.v1.GetPaymentResponse
+	(*ListPaymentsByOrderRequest)(nil),  // 8: payment.v1.ListPaymentsByOrderRequest
+	(*ListPaymentsByOrderResponse)(nil), // 9: payment.v1.ListPaymentsByOrderResponse
+	(*RefundRequest)(nil),               // 10: payment.v1.RefundRequest
+	(*RefundResponse)(nil),              // 11: payment.v1.RefundResponse
+}
+var file_payment_v1_payment_proto_depIdxs = []int32{
+	0,  // 0: payment.v1.CreateAttemptRequest.line_items:type_name -> payment.v1.LineItem
+	6,  // 1: payment.v1.GetPaymentResponse.payment:type_name -> payment.v1.Payment
+	0,  // 2: payment.v1.GetPaymentResponse.line_items:type_name -> payment.v1.LineItem
+	4,  // 3: payment.v1.ListPaymentsByOrderResponse.attempts:type_name -> payment.v1.Attempt
+	6,  // 4: payment.v1.ListPaymentsByOrderResponse.payments:type_name -> payment.v1.Payment
+	1,  // 5: payment.v1.PaymentService.CreateAttempt:input_type -> payment.v1.CreateAttemptRequest
+	3,  // 6: payment.v1.PaymentService.GetAttempt:input_type -> payment.v1.GetAttemptRequest
+	5,  // 7: payment.v1.PaymentService.GetPayment:input_type -> payment.v1.GetPaymentRequest
+	8,  // 8: payment.v1.PaymentService.ListPaymentsByOrder:input_type -> payment.v1.ListPaymentsByOrderRequest
+	10, // 9: payment.v1.PaymentService.Refund:input_type -> payment.v1.RefundRequest
+	2,  // 10: payment.v1.PaymentService.CreateAttempt:output_type -> payment.v1.CreateAttemptResponse
+	4,  // 11: payment.v1.PaymentService.GetAttempt:output_type -> payment.v1.Attempt
+	7,  // 12: payment.v1.PaymentService.GetPayment:output_type -> payment.v1.GetPaymentResponse
+	9,  // 13: payment.v1.PaymentService.ListPaymentsByOrder:output_type -> payment.v1.ListPaymentsByOrderResponse
+	11, // 14: payment.v1.PaymentService.Refund:output_type -> payment.v1.RefundResponse
+	10, // [10:15] is the sub-list for method output_type
+	5,  // [5:10] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_payment_v1_payment_proto_init() }
+func file_payment_v1_payment_proto_init() {
+	if File_payment_v1_payment_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_payment_v1_payment_proto_rawDesc), len(file_payment_v1_payment_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_payment_v1_payment_proto_goTypes,
+		DependencyIndexes: file_payment_v1_payment_proto_depIdxs,
+		MessageInfos:      file_payment_v1_payment_proto_msgTypes,
+	}.Build()
+	File_payment_v1_payment_proto = out.File
+	file_payment_v1_payment_proto_goTypes = nil
+	file_payment_v1_payment_proto_depIdxs = nil
+}
@@ -0,0 +1,200 @@
+// Package rpc serves the payment API to other services over gRPC, next to
+// the HTTP API and backed by the same PaymentService.
+package rpc
+
+import (
+	"context"
+
+	"payment-service/pkg/apperr"
+	"payment-service/pkg/constants"
+	contextUtils "payment-service/pkg/context"
+	"payment-service/pkg/dto"
+	"payment-service/pkg/i18n"
+	"payment-service/pkg/jwt"
+	"payment-service/pkg/models"
+	"payment-service/pkg/rpc/paymentv1"
+	"payment-service/pkg/utils"
+
+	"github.com/google/uuid"
+
+	"google.golang.org/grpc"
+)
+
+// Payments is the part of PaymentService the gRPC API exposes.
+type Payments interface {
+	CreatePaymentAttempt(ctx context.Context, body dto.CreatePaymentAttemptRequestDto) (*dto.CreatePaymentAttemptResponseDto, error)
+	GetPaymentAttempt(ctx context.Context, paymentAttemptID string) (*dto.GetPaymentAttemptResponseDto, error)
+	GetPaymentByID(ctx context.Context, paymentID string) (*dto.GetPaymentByIDResponseDto, error)
+	GetOrderPayments(ctx context.Context, orderID string) (*dto.GetOrderPaymentsResponseDto, error)
+	RefundPayment(ctx context.Context, body dto.RefundPaymentRequestDto) (*dto.RefundPaymentResponseDto, error)
+}
+
+// Validator checks request bodies the way the HTTP API does.
+type Validator interface {
+	Struct(s any) error
+}
+
+type Server struct {
+	paymentv1.UnimplementedPaymentServiceServer
+	payments Payments
+	validate Validator
+}
+
+// NewServer builds a gRPC server that accepts only service tokens
+// addressed to this service and answers errors with the status matching
+// their apperr.Code.
+func NewServer(jwtService *jwt.JwtService, payments Payments, validate Validator) *grpc.Server {
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		ErrorInterceptor(),
+		ServiceTokenInterceptor(jwtService),
+	))
+	paymentv1.RegisterPaymentServiceServer(server, &Server{payments: payments, validate: validate})
+	return server
+}
+
+func (s *Server) CreateAttempt(ctx context.Context, req *paymentv1.CreateAttemptRequest) (*paymentv1.CreateAttemptResponse, error) {
+	body := dto.CreatePaymentAttemptRequestDto{
+		PayableType:   models.PayableType(req.GetPayableType()),
+		PayableID:     req.GetPayableId(),
+		PaymentInfoID: req.GetPaymentInfoId(),
+	}
+	for _, item := range req.GetLineItems() {
+		body.LineItems = append(body.LineItems, dto.LineItemRequestDto{
+			Description: item.GetDescription(),
+			Quantity:    item.GetQuantity(),
+			UnitPrice:   item.GetUnitPrice(),
+			Tax:         item.GetTax(),
+			Category:    models.LineItemCategory(item.GetCategory()),
+		})
+	}
+	if err := s.validate.Struct(body); err != nil {
+		return nil, err
+	}
+	if utils.StringToUUIDv7(req.GetUserId()) == uuid.Nil {
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidUserID, nil)
+	}
+
+	// the attempt is made for the user, so ownership is checked against them
+	ctx = context.WithValue(ctx, contextUtils.ContextKeyUserID, req.GetUserId())
+	result, err := s.payments.CreatePaymentAttempt(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+	return &paymentv1.CreateAttemptResponse{
+		PaymentAttemptId: result.PaymentAttemptID,
+		Status:           string(result.Status),
+	}, nil
+}
+
+func (s *Server) GetAttempt(ctx context.Context, req *paymentv1.GetAttemptRequest) (*paymentv1.Attempt, error) {
+	result, err := s.payments.GetPaymentAttempt(ctx, req.GetPaymentAttemptId())
+	if err != nil {
+		return nil, err
+	}
+	return &paymentv1.Attempt{
+		PaymentAttemptId: result.PaymentAttemptID,
+		PayableType:      string(result.PayableType),
+		PayableId:        result.PayableID,
+		PaymentInfoId:    result.PaymentInfoID,
+		Method:           string(result.Method),
+		Status:           string(result.Status),
+		CreatedAt:        result.CreatedAt,
+	}, nil
+}
+
+func (s *Server) GetPayment(ctx context.Context, req *paymentv1.GetPaymentRequest) (*paymentv1.GetPaymentResponse, error) {
+	result, err := s.payments.GetPaymentByID(ctx, req.GetPaymentId())
+	if err != nil {
+		return nil, err
+	}
+	response := &paymentv1.GetPaymentResponse{Payment: toPayment(&result.Payment)}
+	for _, item := range result.LineItems {
+		response.LineItems = append(response.LineItems, &paymentv1.LineItem{
+			Id:          item.ID,
+			Description: item.Description,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			Tax:         item.Tax,
+			Category:    string(item.Category),
+			Total:       item.Total,
+		})
+	}
+	return response, nil
+}
+
+func (s *Server) ListPaymentsByOrder(ctx context.Context, req *paymentv1.ListPaymentsByOrderRequest) (*paymentv1.ListPaymentsByOrderResponse, error) {
+	result, err := s.payments.GetOrderPayments(ctx, req.GetOrderId())
+	if err != nil {
+		return nil, err
+	}
+	response := &paymentv1.ListPaymentsByOrderResponse{
+		OrderId:            result.OrderID,
+		Status:             string(result.Status),
+		AmountDue:          result.AmountDue,
+		TotalPaid:          result.TotalPaid,
+		TotalRefunded:      result.TotalRefunded,
+		OutstandingBalance: result.OutstandingBalance,
+	}
+	for _, attempt := range result.Attempts {
+		response.Attempts = append(response.Attempts, &paymentv1.Attempt{
+			PaymentAttemptId: attempt.PaymentAttemptID,
+			PayableType:      string(models.PayableTypeOrder),
+			PayableId:        result.OrderID,
+			PaymentInfoId:    attempt.PaymentInfoID,
+			Method:           string(attempt.Method),
+			Status:           string(attempt.Status),
+			CreatedAt:        attempt.CreatedAt,
+		})
+	}
+	for i := range result.Payments {
+		response.Payments = append(response.Payments, toPayment(&result.Payments[i]))
+	}
+	return response, nil
+}
+
+func (s *Server) Refund(ctx context.Context, req *paymentv1.RefundRequest) (*paymentv1.RefundResponse, error) {
+	body := dto.RefundPaymentRequestDto{
+		PaymentID: req.GetPaymentId(),
+		Amount:    req.GetAmount(),
+		Reason:    req.GetReason(),
+	}
+	if err := s.validate.Struct(body); err != nil {
+		return nil, err
+	}
+
+	result, err := s.payments.RefundPayment(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+	return &paymentv1.RefundResponse{
+		RefundId:  result.RefundID,
+		PaymentId: result.PaymentID,
+		Amount:    result.Amount,
+		Status:    result.Status,
+	}, nil
+}
+
+func toPayment(payment *dto.PaymentDto) *paymentv1.Payment {
+	return &paymentv1.Payment{
+		PaymentId:             payment.PaymentID,
+		AttemptId:             payment.AttemptID,
+		PayableType:           string(payment.PayableType),
+		PayableId:             payment.PayableID,
+		Amount:                payment.Amount,
+		PatientAmount:         payment.PatientAmount,
+		PayerAmount:           payment.PayerAmount,
+		HealthcareEntitlement: payment.HealthcareEntitlement,
+		PaidAt:                payment.PaidAt,
+	}
+}
+
+// callerContext carries the calling service the way the HTTP middleware
+// carries a user, so PaymentService sees the same context either way.
+func callerContext(ctx context.Context, service string, requestID string) context.Context {
+	ctx = context.WithValue(ctx, contextUtils.ContextKeyUserID, service)
+	ctx = context.WithValue(ctx, contextUtils.ContextKeyRole, constants.RoleService)
+	if requestID != "" {
+		ctx = context.WithValue(ctx, contextUtils.ContextKeyRequestID, requestID)
+	}
+	return ctx
+}
@@ -0,0 +1,267 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"payment-service/pkg/apperr"
+	"payment-service/pkg/constants"
+	contextUtils "payment-service/pkg/context"
+	"payment-service/pkg/dto"
+	"payment-service/pkg/i18n"
+	"payment-service/pkg/jwt"
+	"payment-service/pkg/models"
+	"payment-service/pkg/rpc/paymentv1"
+	"payment-service/pkg/validation"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	attemptID = "01927a6e-7c3f-7b6e-9c1a-2f4e5d6c7b8a"
+	paymentID = "01927a6e-7c3f-7b6e-9c1a-2f4e5d6c7b8b"
+	userID    = "01927a6e-7c3f-7b6e-9c1a-000000000001"
+)
+
+// fakePayments answers one known attempt and one known payment of 100
+// baht, and records the caller it saw.
+type fakePayments struct {
+	caller     string
+	role       string
+	attemptFor string
+	refunded   float64
+}
+
+func (f *fakePayments) CreatePaymentAttempt(ctx context.Context, body dto.CreatePaymentAttemptRequestDto) (*dto.CreatePaymentAttemptResponseDto, error) {
+	f.attemptFor = contextUtils.GetUserId(ctx)
+	return &dto.CreatePaymentAttemptResponseDto{PaymentAttemptID: attemptID, Status: models.PaymentStatusPending}, nil
+}
+
+func (f *fakePayments) GetPaymentAttempt(ctx context.Context, paymentAttemptID string) (*dto.GetPaymentAttemptResponseDto, error) {
+	f.caller = contextUtils.GetUserId(ctx)
+	f.role = contextUtils.GetRole(ctx)
+	if paymentAttemptID != attemptID {
+		return nil, apperr.New(apperr.CodeNotFound, i18n.AttemptNotFound, nil)
+	}
+	return &dto.GetPaymentAttemptResponseDto{
+		PaymentAttemptID: attemptID,
+		PayableType:      models.PayableTypeOrder,
+		Method:           models.PaymentMethodPromptPay,
+		Status:           models.PaymentStatusPending,
+	}, nil
+}
+
+func (f *fakePayments) GetPaymentByID(ctx context.Context, paymentID string) (*dto.GetPaymentByIDResponseDto, error) {
+	return nil, apperr.New(apperr.CodeNotFound, i18n.PaymentNotFound, nil)
+}
+
+func (f *fakePayments) GetOrderPayments(ctx context.Context, orderID string) (*dto.GetOrderPaymentsResponseDto, error) {
+	return &dto.GetOrderPaymentsResponseDto{OrderID: orderID}, nil
+}
+
+func (f *fakePayments) RefundPayment(ctx context.Context, body dto.RefundPaymentRequestDto) (*dto.RefundPaymentResponseDto, error) {
+	f.caller = contextUtils.GetUserId(ctx)
+	f.role = contextUtils.GetRole(ctx)
+	if body.PaymentID != paymentID {
+		return nil, apperr.New(apperr.CodeNotFound, i18n.PaymentNotFound, nil)
+	}
+	if f.refunded+body.Amount > 100 {
+		return nil, apperr.New(apperr.CodeConflict, i18n.RefundExceedsPaid, nil)
+	}
+	f.refunded += body.Amount
+	return &dto.RefundPaymentResponseDto{
+		RefundID:   attemptID,
+		PaymentID:  paymentID,
+		Amount:     body.Amount,
+		Status:     dto.RefundStatusCompleted,
+		Refundable: 100 - f.refunded,
+	}, nil
+}
+
+func newTestClient(t *testing.T, payments Payments) (paymentv1.PaymentServiceClient, *jwt.JwtService) {
+	t.Helper()
+	jwtService := jwt.NewJwtService("secret", 3600, "payment-service", "service-secret", 60)
+	validate, err := validation.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listener := bufconn.Listen(1 << 20)
+	server := NewServer(jwtService, payments, validate)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return paymentv1.NewPaymentServiceClient(conn), jwtService
+}
+
+func withToken(t *testing.T, token string, pairs ...string) context.Context {
+	t.Helper()
+	return metadata.NewOutgoingContext(context.Background(), metadata.Pairs(append([]string{"authorization", "Bearer " + token}, pairs...)...))
+}
+
+func TestServiceTokenRequired(t *testing.T) {
+	client, jwtService := newTestClient(t, &fakePayments{})
+
+	_, err := client.GetAttempt(context.Background(), &paymentv1.GetAttemptRequest{PaymentAttemptId: attemptID})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("call without a token: got %v, want Unauthenticated", err)
+	}
+
+	userToken, err := jwtService.GenerateToken(userID, constants.RoleAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.GetAttempt(withToken(t, userToken), &paymentv1.GetAttemptRequest{PaymentAttemptId: attemptID})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("call with a user token: got %v, want Unauthenticated", err)
+	}
+
+	otherService, err := jwtService.ServiceToken("order-service")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.GetAttempt(withToken(t, otherService), &paymentv1.GetAttemptRequest{PaymentAttemptId: attemptID})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("call with a token for another service: got %v, want Unauthenticated", err)
+	}
+}
+
+func TestGetAttemptAsService(t *testing.T) {
+	payments := &fakePayments{}
+	client, jwtService := newTestClient(t, payments)
+	token, err := jwtService.ServiceToken("payment-service")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attempt, err := client.GetAttempt(withToken(t, token), &paymentv1.GetAttemptRequest{PaymentAttemptId: attemptID})
+	if err != nil {
+		t.Fatalf("GetAttempt: %v", err)
+	}
+	if attempt.GetPaymentAttemptId() != attemptID || attempt.GetMethod() != string(models.PaymentMethodPromptPay) {
+		t.Errorf("unexpected attempt %v", attempt)
+	}
+	if payments.caller != "payment-service" || payments.role != constants.RoleService {
+		t.Errorf("service saw caller %q with role %q", payments.caller, payments.role)
+	}
+}
+
+func TestErrorsMapToStatusCodes(t *testing.T) {
+	client, jwtService := newTestClient(t, &fakePayments{})
+	token, err := jwtService.ServiceToken("payment-service")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.GetAttempt(withToken(t, token, "accept-language", "th-TH,th;q=0.9"), &paymentv1.GetAttemptRequest{PaymentAttemptId: "missing"})
+	st := status.Convert(err)
+	if st.Code() != codes.NotFound {
+		t.Errorf("got %v, want NotFound", st.Code())
+	}
+	if want := i18n.T(i18n.AttemptNotFound).In(i18n.Thai); st.Message() != want {
+		t.Errorf("got message %q, want %q", st.Message(), want)
+	}
+
+	_, err = client.CreateAttempt(withToken(t, token), &paymentv1.CreateAttemptRequest{UserId: userID})
+	st = status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("got %v, want InvalidArgument", st.Code())
+	}
+	fields := map[string]bool{}
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.GetFieldViolations() {
+				fields[violation.GetField()] = true
+			}
+		}
+	}
+	for _, field := range []string{"payable_type", "payable_id", "payment_info_id"} {
+		if !fields[field] {
+			t.Errorf("missing field violation for %s, got %v", field, fields)
+		}
+	}
+}
+
+func TestCreateAttemptForUser(t *testing.T) {
+	payments := &fakePayments{}
+	client, jwtService := newTestClient(t, payments)
+	token, err := jwtService.ServiceToken("payment-service")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.CreateAttempt(withToken(t, token), &paymentv1.CreateAttemptRequest{
+		PayableType:   string(models.PayableTypeOrder),
+		PayableId:     attemptID,
+		PaymentInfoId: attemptID,
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("attempt without a user: got %v, want InvalidArgument", err)
+	}
+
+	response, err := client.CreateAttempt(withToken(t, token), &paymentv1.CreateAttemptRequest{
+		UserId:        userID,
+		PayableType:   string(models.PayableTypeOrder),
+		PayableId:     attemptID,
+		PaymentInfoId: attemptID,
+	})
+	if err != nil {
+		t.Fatalf("CreateAttempt: %v", err)
+	}
+	if response.GetPaymentAttemptId() != attemptID || payments.attemptFor != userID {
+		t.Errorf("attempt %q was made for %q", response.GetPaymentAttemptId(), payments.attemptFor)
+	}
+}
+
+func TestRefund(t *testing.T) {
+	payments := &fakePayments{}
+	client, jwtService := newTestClient(t, payments)
+	token, err := jwtService.ServiceToken("payment-service")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		req  *paymentv1.RefundRequest
+		code codes.Code
+	}{
+		{"part of a payment", &paymentv1.RefundRequest{PaymentId: paymentID, Amount: 40, Reason: "item out of stock"}, codes.OK},
+		{"no amount", &paymentv1.RefundRequest{PaymentId: paymentID}, codes.InvalidArgument},
+		{"negative amount", &paymentv1.RefundRequest{PaymentId: paymentID, Amount: -1}, codes.InvalidArgument},
+		{"unknown payment", &paymentv1.RefundRequest{PaymentId: attemptID, Amount: 10}, codes.NotFound},
+		{"more than is left", &paymentv1.RefundRequest{PaymentId: paymentID, Amount: 60.01}, codes.FailedPrecondition},
+		{"the rest", &paymentv1.RefundRequest{PaymentId: paymentID, Amount: 60}, codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := client.Refund(withToken(t, token), tt.req)
+			if status.Code(err) != tt.code {
+				t.Fatalf("got %v, want %v", err, tt.code)
+			}
+			if err == nil && (response.GetPaymentId() != paymentID || response.GetAmount() != tt.req.GetAmount() || response.GetStatus() != dto.RefundStatusCompleted) {
+				t.Fatalf("got %v", response)
+			}
+		})
+	}
+	if payments.caller != "payment-service" || payments.role != constants.RoleService {
+		t.Errorf("service saw caller %q with role %q", payments.caller, payments.role)
+	}
+}
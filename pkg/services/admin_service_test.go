@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"payment-service/pkg/apperr"
+	"payment-service/pkg/dto"
+	"payment-service/pkg/jwt"
+	"payment-service/pkg/models"
+	"payment-service/pkg/repository/memory"
+	"payment-service/pkg/utils"
+
+	jwtlib "github.com/golang-jwt/jwt/v5"
+)
+
+func TestGetAuditLogs(t *testing.T) {
+	f := newFixture(t)
+	logs := memory.NewAuditLogRepository(f.db)
+	actor := adminID.String()
+	day := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	entries := []*models.AuditLog{
+		{EntityType: "payment_informations", EntityID: "a", Action: models.AuditActionCreate, ActorID: &actor, Hash: "1", CreatedAt: day},
+		{EntityType: "payment_informations", EntityID: "a", Action: models.AuditActionUpdate, Hash: "2", CreatedAt: day.Add(time.Hour)},
+		{EntityType: "payments", EntityID: "b", Action: models.AuditActionCreate, Hash: "3", CreatedAt: day.Add(2 * time.Hour)},
+	}
+	for _, entry := range entries {
+		if err := logs.Create(context.Background(), entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		query dto.GetAuditLogsRequestDto
+		want  []string
+		code  apperr.Code
+	}{
+		{"newest first", dto.GetAuditLogsRequestDto{}, []string{"3", "2", "1"}, 0},
+		{"by entity", dto.GetAuditLogsRequestDto{EntityType: "payment_informations", EntityID: "a"}, []string{"2", "1"}, 0},
+		{"by actor", dto.GetAuditLogsRequestDto{ActorID: actor}, []string{"1"}, 0},
+		{"by action", dto.GetAuditLogsRequestDto{Action: string(models.AuditActionCreate)}, []string{"3", "1"}, 0},
+		{"from", dto.GetAuditLogsRequestDto{From: "2026-03-01T00:30:00Z"}, []string{"3", "2"}, 0},
+		{"limit", dto.GetAuditLogsRequestDto{Limit: 1}, []string{"3"}, 0},
+		{"bad action", dto.GetAuditLogsRequestDto{Action: "read"}, nil, apperr.CodeBadRequest},
+		{"bad from", dto.GetAuditLogsRequestDto{From: "yesterday"}, nil, apperr.CodeBadRequest},
+		{"bad to", dto.GetAuditLogsRequestDto{To: "tomorrow"}, nil, apperr.CodeBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := f.service.GetAuditLogs(asAdmin(), tt.query)
+			wantCode(t, err, tt.code)
+			if err != nil {
+				return
+			}
+			var hashes []string
+			for _, entry := range got.Entries {
+				hashes = append(hashes, entry.Hash)
+			}
+			if !equalStrings(hashes, tt.want) {
+				t.Fatalf("got %v, want %v", hashes, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetReceivables(t *testing.T) {
+	f := newFixture(t)
+	card := f.card(patientID, "4111111111111111")
+	payments := memory.NewPaymentRepository(f.db)
+	for _, status := range []models.ReceivableStatus{models.ReceivableStatusAccrued, models.ReceivableStatusAccrued, models.ReceivableStatusSettled} {
+		attempt := f.attempt(f.order(patientID, 1000), card, models.PaymentStatusSuccess)
+		if err := payments.Create(context.Background(), &models.Payment{
+			AttemptID:     attempt.ID,
+			Amount:        1000,
+			PatientAmount: 900,
+			PayerAmount:   100,
+			PayableType:   attempt.PayableType,
+			PayableID:     attempt.PayableID,
+			Receivables: []models.PayerReceivable{{
+				PayerType:             models.PayerTypeInsurer,
+				PayerName:             "Insurer",
+				HealthcareEntitlement: "private",
+				Amount:                100,
+				Status:                status,
+			}},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		status string
+		want   int
+		code   apperr.Code
+	}{
+		{"accrued by default", "", 2, 0},
+		{"settled", string(models.ReceivableStatusSettled), 1, 0},
+		{"unknown status", "owed", 0, apperr.CodeBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := f.service.GetReceivables(asAdmin(), tt.status)
+			wantCode(t, err, tt.code)
+			if err == nil && len(got.Receivables) != tt.want {
+				t.Fatalf("got %d receivables, want %d", len(got.Receivables), tt.want)
+			}
+		})
+	}
+}
+
+func TestRevokeToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    dto.RevokeTokenRequestDto
+		code    apperr.Code
+		revoked jwt.JwtClaims
+	}{
+		{"by jti", dto.RevokeTokenRequestDto{Jti: "token-1", Reason: "leaked"}, 0,
+			jwt.JwtClaims{UserID: patientID.String(), RegisteredClaims: jwtlib.RegisteredClaims{ID: "token-1"}}},
+		{"by user", dto.RevokeTokenRequestDto{UserID: patientID.String()}, 0,
+			jwt.JwtClaims{UserID: patientID.String(), RegisteredClaims: jwtlib.RegisteredClaims{IssuedAt: jwtlib.NewNumericDate(time.Now().Add(-time.Minute))}}},
+		{"neither", dto.RevokeTokenRequestDto{}, apperr.CodeBadRequest, jwt.JwtClaims{}},
+		{"both", dto.RevokeTokenRequestDto{Jti: "token-1", UserID: patientID.String()}, apperr.CodeBadRequest, jwt.JwtClaims{}},
+		{"bad user", dto.RevokeTokenRequestDto{UserID: "nobody"}, apperr.CodeBadRequest, jwt.JwtClaims{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			got, err := f.service.RevokeToken(asAdmin(), tt.body)
+			wantCode(t, err, tt.code)
+			if err != nil {
+				return
+			}
+			if got.Revocation.RevokedBy != adminID.String() || got.Revocation.Reason != tt.body.Reason {
+				t.Fatalf("got %+v", got.Revocation)
+			}
+			if !f.service.revocations.IsRevoked(&tt.revoked) {
+				t.Fatal("token still accepted")
+			}
+			other := jwt.JwtClaims{UserID: otherPatientID.String(), RegisteredClaims: jwtlib.RegisteredClaims{ID: "token-2"}}
+			if f.service.revocations.IsRevoked(&other) {
+				t.Fatal("unrelated token revoked")
+			}
+		})
+	}
+}
+
+func TestRiskReviews(t *testing.T) {
+	tests := []struct {
+		name     string
+		close    func(s *PaymentService, ctx context.Context, id string) (*dto.RiskReviewResponseDto, error)
+		review   models.ReviewStatus
+		attempt  models.PaymentStatus
+		payAfter apperr.Code
+	}{
+		{"approve", (*PaymentService).ApproveRiskReview, models.ReviewStatusApproved, models.PaymentStatusSuccess, 0},
+		{"deny", (*PaymentService).DenyRiskReview, models.ReviewStatusDenied, models.PaymentStatusFailed, apperr.CodeBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			created, err := f.service.CreatePaymentAttempt(asPatient(patientID), dto.CreatePaymentAttemptRequestDto{
+				PayableType:   models.PayableTypeOrder,
+				PayableID:     f.order(patientID, 20000).String(),
+				PaymentInfoID: f.card(patientID, "4111111111111111").ID.String(),
+			})
+			wantCode(t, err, 0)
+
+			reviews, err := f.service.GetRiskReviews(asAdmin())
+			wantCode(t, err, 0)
+			if len(reviews.Reviews) != 1 || reviews.Reviews[0].AttemptID != created.PaymentAttemptID {
+				t.Fatalf("got reviews %+v", reviews.Reviews)
+			}
+			id := reviews.Reviews[0].ID
+
+			// the attempt leaves review only through the review itself
+			_, err = f.service.UpdatePaymentAttempt(asAdmin(), dto.UpdatePaymentAttemptRequestDto{PaymentAttemptID: created.PaymentAttemptID, Status: tt.attempt, AnyVersion: true})
+			wantCode(t, err, apperr.CodeConflict)
+
+			closed, err := tt.close(f.service, asAdmin(), id)
+			wantCode(t, err, 0)
+			if closed.Review.ReviewStatus != tt.review || closed.Review.ReviewedBy != adminID.String() || closed.AttemptStatus != tt.attempt {
+				t.Fatalf("got %+v", closed)
+			}
+
+			attempt, err := f.service.GetPaymentAttempt(asAdmin(), created.PaymentAttemptID)
+			wantCode(t, err, 0)
+			if attempt.Status != tt.attempt {
+				t.Fatalf("attempt is %s, want %s", attempt.Status, tt.attempt)
+			}
+			_, err = f.service.CreatePayment(asService(), dto.CreatePaymentRequestDto{PaymentAttemptID: created.PaymentAttemptID, Amount: 20000})
+			wantCode(t, err, tt.payAfter)
+
+			reviews, err = f.service.GetRiskReviews(asAdmin())
+			wantCode(t, err, 0)
+			if len(reviews.Reviews) != 0 {
+				t.Fatalf("review still pending: %+v", reviews.Reviews)
+			}
+
+			_, err = f.service.ApproveRiskReview(asAdmin(), id)
+			wantCode(t, err, apperr.CodeConflict)
+			_, err = f.service.DenyRiskReview(asAdmin(), id)
+			wantCode(t, err, apperr.CodeConflict)
+		})
+	}
+}
+
+func TestCloseRiskReviewLookup(t *testing.T) {
+	f := newFixture(t)
+
+	tests := []struct {
+		name string
+		id   string
+		code apperr.Code
+	}{
+		{"malformed", "nope", apperr.CodeBadRequest},
+		{"missing", utils.GenerateUUIDv7().String(), apperr.CodeNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.service.ApproveRiskReview(asAdmin(), tt.id)
+			wantCode(t, err, tt.code)
+			_, err = f.service.DenyRiskReview(asAdmin(), tt.id)
+			wantCode(t, err, tt.code)
+		})
+	}
+}
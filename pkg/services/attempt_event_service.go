@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"strconv"
+
+	"payment-service/pkg/apperr"
+	"payment-service/pkg/dto"
+	"payment-service/pkg/events"
+	"payment-service/pkg/i18n"
+	"payment-service/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// AttemptWatch is what a client watching an attempt starts from: the
+// attempt as it is now and where the client left off. Later events arrive
+// on Subscription, which the caller must close.
+type AttemptWatch struct {
+	Attempt      *dto.GetPaymentAttemptResponseDto
+	Resume       events.Resume
+	Subscription *events.Subscription
+}
+
+// WatchPaymentAttempt starts watching an attempt's status. lastEventID is
+// the Last-Event-ID a reconnecting client sends; anything that is not an
+// event ID is ignored.
+func (s *PaymentService) WatchPaymentAttempt(ctx context.Context, paymentAttemptID string, lastEventID string) (*AttemptWatch, error) {
+	id := utils.StringToUUIDv7(paymentAttemptID)
+	if id == uuid.Nil {
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidAttemptID, nil)
+	}
+
+	// follow changes before reading the status, so none made in between
+	// is lost
+	after, _ := strconv.ParseUint(lastEventID, 10, 64)
+	resume, sub := s.attemptEvents.Subscribe(id, after)
+
+	// looked up as GET /attempt/:id does, so only its owner and admins may
+	// watch it and anyone else finds nothing there
+	attempt, err := s.GetPaymentAttempt(ctx, paymentAttemptID)
+	if err != nil {
+		sub.Close()
+		return nil, err
+	}
+
+	return &AttemptWatch{
+		Attempt:      attempt,
+		Resume:       resume,
+		Subscription: sub,
+	}, nil
+}
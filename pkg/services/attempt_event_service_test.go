@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"payment-service/pkg/apperr"
+	"payment-service/pkg/dto"
+	"payment-service/pkg/models"
+	"payment-service/pkg/utils"
+)
+
+func TestWatchPaymentAttempt(t *testing.T) {
+	f := newFixture(t)
+	attempt := f.attempt(f.order(patientID, 100), f.card(patientID, "4111111111111111"), models.PaymentStatusPending)
+	first := f.service.attemptEvents.Publish(attempt.ID, models.PaymentStatusPending)
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		attemptID   string
+		lastEventID string
+		seen        bool
+		code        apperr.Code
+	}{
+		{"new watcher", asPatient(patientID), attempt.ID.String(), "", false, 0},
+		{"reconnecting", asPatient(patientID), attempt.ID.String(), strconv.FormatUint(first.ID, 10), true, 0},
+		{"not an event ID", asPatient(patientID), attempt.ID.String(), "latest", false, 0},
+		{"admin", asAdmin(), attempt.ID.String(), "", false, 0},
+		{"other patient", asPatient(otherPatientID), attempt.ID.String(), "", false, apperr.CodeNotFound},
+		{"malformed", asPatient(patientID), "nope", "", false, apperr.CodeBadRequest},
+		{"missing", asPatient(patientID), utils.GenerateUUIDv7().String(), "", false, apperr.CodeNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			watch, err := f.service.WatchPaymentAttempt(tt.ctx, tt.attemptID, tt.lastEventID)
+			wantCode(t, err, tt.code)
+			if err != nil {
+				return
+			}
+			defer watch.Subscription.Close()
+			if watch.Attempt.Status != models.PaymentStatusPending || (watch.Resume.Seen != nil) != tt.seen {
+				t.Fatalf("got %+v", watch)
+			}
+		})
+	}
+
+	watch, err := f.service.WatchPaymentAttempt(asPatient(patientID), attempt.ID.String(), "")
+	wantCode(t, err, 0)
+	defer watch.Subscription.Close()
+	_, err = f.service.UpdatePaymentAttempt(asService(), dto.UpdatePaymentAttemptRequestDto{PaymentAttemptID: attempt.ID.String(), Status: models.PaymentStatusSuccess, AnyVersion: true})
+	wantCode(t, err, 0)
+	select {
+	case event := <-watch.Subscription.C:
+		if event.Status != models.PaymentStatusSuccess || !event.Terminal() {
+			t.Fatalf("got %+v", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no event")
+	}
+}
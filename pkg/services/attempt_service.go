@@ -4,9 +4,13 @@ import (
 	"context"
 	"errors"
 	"payment-service/pkg/apperr"
+	"payment-service/pkg/constants"
 	contextUtils "payment-service/pkg/context"
 	"payment-service/pkg/dto"
+	"payment-service/pkg/i18n"
 	"payment-service/pkg/models"
+	"payment-service/pkg/payable"
+	"payment-service/pkg/repository"
 	"payment-service/pkg/utils"
 	"time"
 
@@ -15,65 +19,123 @@ import (
 )
 
 func (s *PaymentService) CreatePaymentAttempt(ctx context.Context, body dto.CreatePaymentAttemptRequestDto) (*dto.CreatePaymentAttemptResponseDto, error) {
-	role := contextUtils.GetRole(ctx)
-	if role != "patient" {
-		return nil, apperr.New(apperr.CodeForbidden, "only patients can create payment attempts", nil)
+	payableID := utils.StringToUUIDv7(body.PayableID)
+	if payableID == uuid.Nil {
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidPayableID, nil)
 	}
 
-	orderID := utils.StringToUUIDv7(body.OrderID)
-	if orderID == uuid.Nil {
-		return nil, apperr.New(apperr.CodeBadRequest, "invalid order ID", nil)
+	resolved, err := s.payableRegistry.Resolve(ctx, body.PayableType, payableID)
+	if err != nil {
+		if errors.Is(err, payable.ErrUnsupportedType) {
+			return nil, apperr.New(apperr.CodeBadRequest, i18n.UnsupportedPayableType, nil)
+		}
+		return nil, apperr.Propagate(err, i18n.FailedResolvePayable)
+	}
+
+	userID := utils.StringToUUIDv7(contextUtils.GetUserId(ctx))
+	if resolved.OwnerID != userID {
+		return nil, apperr.New(apperr.CodeForbidden, i18n.PayableNotOwned, nil)
+	}
+
+	paymentInfoID := utils.StringToUUIDv7(body.PaymentInfoID)
+	if paymentInfoID == uuid.Nil {
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidPaymentInfoID, nil)
+	}
+
+	paymentInfo, err := s.paymentInformationRepository.FindByID(ctx, paymentInfoID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperr.New(apperr.CodeNotFound, i18n.PaymentInfoNotFound, nil)
+		}
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedRetrievePaymentInfo, err)
+	}
+
+	if paymentInfo.UserID != userID {
+		return nil, apperr.New(apperr.CodeForbidden, i18n.PaymentInfoNotOwned, nil)
 	}
 
-	// paymentInfoID := utils.StringToUUIDv7(body.PaymentInfoID)
-	// if paymentInfoID == uuid.Nil {
-	// 	return nil, apperr.New(apperr.CodeBadRequest, "invalid payment information ID", nil)
-	// }
+	lineItems, err := toLineItemModels(body.LineItems)
+	if err != nil {
+		return nil, err
+	}
+	// an itemised bill has to come to what the payable asks for
+	if len(lineItems) > 0 && lineItemsTotal(lineItems) != utils.ToSatang(resolved.AmountDue) {
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.LineItemsMismatch, nil)
+	}
 
-	// paymentInfo, err := s.paymentInformationRepository.FindByID(ctx, paymentInfoID)
-	// if err != nil {
-	// 	if errors.Is(err, gorm.ErrRecordNotFound) {
-	// 		return nil, apperr.New(apperr.CodeNotFound, "payment information not found", nil)
-	// 	}
-	// 	return nil, apperr.New(apperr.CodeInternal, "failed to retrieve payment information", err)
-	// }
+	// the attempt is counted and written in one unit of work, so the next
+	// attempt by the same user, card or client counts this one
+	var assessment *models.RiskAssessment
+	var paymentAttempt *models.PaymentAttempt
+	err = s.unitOfWork.Do(ctx, func(ctx context.Context, repos repository.Repositories) error {
+		var err error
+		assessment, err = s.assessRisk(ctx, repos.RiskAssessments, userID, paymentInfo, resolved.AmountDue)
+		if err != nil {
+			return err
+		}
 
-	paymentAttempt := &models.PaymentAttempt{
-		ID:                   utils.GenerateUUIDv7(),
-		OrderID:              orderID,
-		PaymentInformationID: nil,
-		Method:               "credit_card",
-		Status:               models.PaymentStatusSuccess,
+		paymentAttempt = &models.PaymentAttempt{
+			ID:                   utils.GenerateUUIDv7(),
+			PayableType:          resolved.Type,
+			PayableID:            resolved.ID,
+			PaymentInformationID: &paymentInfo.ID,
+			UserID:               &userID,
+			Method:               paymentInfo.Type,
+			Status:               attemptStatusFor(assessment.Decision),
+			LineItems:            lineItems,
+			Risk:                 assessment,
+		}
+
+		// blocked attempts are kept too, so they count towards the velocity rules
+		if err := repos.PaymentAttempts.Create(ctx, paymentAttempt); err != nil {
+			return apperr.New(apperr.CodeInternal, i18n.FailedCreateAttempt, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	s.attemptEvents.Publish(paymentAttempt.ID, paymentAttempt.Status)
 
-	if err := s.paymentAttemptRepository.Create(ctx, paymentAttempt); err != nil {
-		return nil, apperr.New(apperr.CodeInternal, "failed to create payment attempt", err)
+	if assessment.Decision == models.RiskDecisionBlock {
+		return nil, apperr.New(apperr.CodeForbidden, i18n.AttemptDeclined, nil)
 	}
 
 	return &dto.CreatePaymentAttemptResponseDto{
 		PaymentAttemptID: paymentAttempt.ID.String(),
+		Status:           paymentAttempt.Status,
 	}, nil
 }
 
 func (s *PaymentService) GetPaymentAttempt(ctx context.Context, paymentAttemptID string) (*dto.GetPaymentAttemptResponseDto, error) {
 	id := utils.StringToUUIDv7(paymentAttemptID)
 	if id == uuid.Nil {
-		return nil, apperr.New(apperr.CodeBadRequest, "invalid payment attempt ID", nil)
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidAttemptID, nil)
 	}
 
 	paymentAttempt, err := s.paymentAttemptRepository.FindByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, apperr.New(apperr.CodeNotFound, "payment attempt not found", nil)
+			return nil, apperr.New(apperr.CodeNotFound, i18n.AttemptNotFound, nil)
 		}
-		return nil, apperr.New(apperr.CodeInternal, "failed to retrieve payment attempt", err)
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedRetrieveAttempt, err)
+	}
+	visible, err := s.canSeePayable(ctx, paymentAttempt.PayableType, paymentAttempt.PayableID)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, apperr.New(apperr.CodeNotFound, i18n.AttemptNotFound, nil)
 	}
 
 	response := &dto.GetPaymentAttemptResponseDto{
 		PaymentAttemptID: paymentAttempt.ID.String(),
-		OrderID:          paymentAttempt.OrderID.String(),
+		PayableType:      paymentAttempt.PayableType,
+		PayableID:        paymentAttempt.PayableID.String(),
 		Method:           paymentAttempt.Method,
 		Status:           paymentAttempt.Status,
+		Version:          paymentAttempt.LockVersion,
+		CreatedAt:        paymentAttempt.CreatedAt.Format(time.RFC3339),
 	}
 
 	if paymentAttempt.PaymentInformationID != nil {
@@ -85,37 +147,82 @@ func (s *PaymentService) GetPaymentAttempt(ctx context.Context, paymentAttemptID
 
 func (s *PaymentService) UpdatePaymentAttempt(ctx context.Context, body dto.UpdatePaymentAttemptRequestDto) (*dto.UpdatePaymentAttemptResponseDto, error) {
 	if body.Status == "" {
-		return nil, apperr.New(apperr.CodeBadRequest, "status is required", nil)
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.StatusRequired, nil)
 	}
 
 	if !isValidPaymentStatus(body.Status) {
-		return nil, apperr.New(apperr.CodeBadRequest, "invalid payment status provided", nil)
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidPaymentStatus, nil)
 	}
 
 	id := utils.StringToUUIDv7(body.PaymentAttemptID)
 	if id == uuid.Nil {
-		return nil, apperr.New(apperr.CodeBadRequest, "invalid payment attempt ID", nil)
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidAttemptID, nil)
+	}
+	if body.Version == 0 && !body.AnyVersion {
+		return nil, apperr.New(apperr.CodePreconditionRequired, i18n.VersionRequired, nil)
 	}
 
-	paymentAttempt, err := s.paymentAttemptRepository.FindByID(ctx, id)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, apperr.New(apperr.CodeNotFound, "payment attempt not found", nil)
+	// Lock the attempt so that no payment is recorded against it between
+	// the check below and the status change
+	var paymentAttempt *models.PaymentAttempt
+	changed := false
+	err := s.unitOfWork.Do(ctx, func(ctx context.Context, repos repository.Repositories) error {
+		locked, err := repos.PaymentAttempts.FindByIDForUpdate(ctx, id)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperr.New(apperr.CodeNotFound, i18n.AttemptNotFound, nil)
+			}
+			return apperr.New(apperr.CodeInternal, i18n.FailedRetrieveAttempt, err)
 		}
-		return nil, apperr.New(apperr.CodeInternal, "failed to retrieve payment attempt", err)
-	}
+		paymentAttempt = locked
 
-	paymentAttempt.Status = body.Status
+		if !body.AnyVersion {
+			paymentAttempt.LockVersion = body.Version
+		}
+		changed = paymentAttempt.Status != body.Status
+		if changed {
+			// a paid attempt's status is part of the payment's record
+			payments, err := repos.Payments.FindByAttemptID(ctx, id)
+			if err != nil {
+				return apperr.New(apperr.CodeInternal, i18n.FailedRetrievePayments, err)
+			}
+			if len(payments) > 0 {
+				return apperr.New(apperr.CodeConflict, i18n.AttemptAlreadyPaid, nil)
+			}
+			// a held attempt leaves review only through its risk review,
+			// which closes the review along with it
+			pending, err := repos.RiskAssessments.ReviewPending(ctx, id)
+			if err != nil {
+				return apperr.New(apperr.CodeInternal, i18n.FailedRetrieveRiskReview, err)
+			}
+			if pending {
+				return apperr.New(apperr.CodeConflict, i18n.AttemptInReview, nil)
+			}
+		}
+		paymentAttempt.Status = body.Status
 
-	if err := s.paymentAttemptRepository.Update(ctx, paymentAttempt); err != nil {
-		return nil, apperr.New(apperr.CodeInternal, "failed to update payment attempt", err)
+		if err := repos.PaymentAttempts.Update(ctx, paymentAttempt); err != nil {
+			if errors.Is(err, repository.ErrVersionConflict) {
+				return apperr.New(apperr.CodeConflict, i18n.AttemptChanged, nil)
+			}
+			return apperr.New(apperr.CodeInternal, i18n.FailedUpdateAttempt, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		s.attemptEvents.Publish(paymentAttempt.ID, paymentAttempt.Status)
 	}
 
 	response := &dto.UpdatePaymentAttemptResponseDto{
 		PaymentAttemptID: paymentAttempt.ID.String(),
-		OrderID:          paymentAttempt.OrderID.String(),
+		PayableType:      paymentAttempt.PayableType,
+		PayableID:        paymentAttempt.PayableID.String(),
 		Method:           paymentAttempt.Method,
 		Status:           paymentAttempt.Status,
+		Version:          paymentAttempt.LockVersion,
 	}
 
 	if paymentAttempt.PaymentInformationID != nil {
@@ -138,79 +245,271 @@ func isValidPaymentStatus(status models.PaymentStatus) bool {
 
 func (s *PaymentService) CreatePayment(ctx context.Context, body dto.CreatePaymentRequestDto) (*dto.CreatePaymentResponseDto, error) {
 	if body.PaymentAttemptID == "" {
-		return nil, apperr.New(apperr.CodeBadRequest, "attempt ID is required", nil)
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.AttemptIDRequired, nil)
 	}
 
 	if body.Amount < 0 {
-		return nil, apperr.New(apperr.CodeBadRequest, "amount must be greater than or equal to zero", nil)
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.NegativeAmount, nil)
 	}
 
 	attemptID := utils.StringToUUIDv7(body.PaymentAttemptID)
 	if attemptID == uuid.Nil {
-		return nil, apperr.New(apperr.CodeBadRequest, "invalid attempt ID", nil)
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidAttemptID, nil)
 	}
 
 	paymentAttempt, err := s.paymentAttemptRepository.FindByID(ctx, attemptID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, apperr.New(apperr.CodeNotFound, "payment attempt not found", nil)
+			return nil, apperr.New(apperr.CodeNotFound, i18n.AttemptNotFound, nil)
 		}
-		return nil, apperr.New(apperr.CodeInternal, "failed to retrieve payment attempt", err)
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedRetrieveAttempt, err)
 	}
 
 	if paymentAttempt.Status != models.PaymentStatusSuccess {
-		return nil, apperr.New(apperr.CodeBadRequest, "payment can only be created for successful attempts", nil)
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.AttemptNotSuccessful, nil)
 	}
 
 	payment := &models.Payment{
-		ID:        utils.GenerateUUIDv7(),
-		AttemptID: attemptID,
-		Amount:    body.Amount,
-		OrderID:   paymentAttempt.OrderID,
-		PaidAt:    time.Now().UTC(),
+		ID:          utils.GenerateUUIDv7(),
+		AttemptID:   attemptID,
+		Amount:      body.Amount,
+		PayableType: paymentAttempt.PayableType,
+		PayableID:   paymentAttempt.PayableID,
+		PaidAt:      time.Now().UTC(),
 	}
 
-	if err := s.paymentRepository.Create(ctx, payment); err != nil {
-		return nil, apperr.New(apperr.CodeInternal, "failed to create payment", err)
+	// the payable and entitlements come from other services, so they are
+	// fetched before the transaction rather than while it holds the attempt
+	resolved, err := s.payableRegistry.Resolve(ctx, payment.PayableType, payment.PayableID)
+	if err != nil {
+		return nil, apperr.Propagate(err, i18n.FailedResolvePayable)
+	}
+	if utils.ToSatang(body.Amount) != utils.ToSatang(resolved.AmountDue) {
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.AmountMismatch, nil)
+	}
+	entitlements, err := s.entitlementsOf(ctx, resolved.OwnerID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Update Order status via Order Service (omitted for brevity)
+	// Lock the attempt so its status, line items and payment cannot change
+	// before the payment and its audit entry are written
+	err = s.unitOfWork.Do(ctx, func(ctx context.Context, repos repository.Repositories) error {
+		locked, err := repos.PaymentAttempts.FindByIDForUpdate(ctx, attemptID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperr.New(apperr.CodeNotFound, i18n.AttemptNotFound, nil)
+			}
+			return apperr.New(apperr.CodeInternal, i18n.FailedRetrieveAttempt, err)
+		}
+		if locked.Status != models.PaymentStatusSuccess {
+			return apperr.New(apperr.CodeBadRequest, i18n.AttemptNotSuccessful, nil)
+		}
 
-	return &dto.CreatePaymentResponseDto{
-		PaymentID: payment.ID.String(),
-		AttemptID: payment.AttemptID.String(),
-		OrderID:   payment.OrderID.String(),
-		Amount:    payment.Amount,
-		PaidAt:    payment.PaidAt.Format(time.RFC3339),
-	}, nil
+		existing, err := repos.Payments.FindByAttemptID(ctx, attemptID)
+		if err != nil {
+			return apperr.New(apperr.CodeInternal, i18n.FailedRetrievePayments, err)
+		}
+		if len(existing) > 0 {
+			return apperr.New(apperr.CodeConflict, i18n.AttemptAlreadyPaid, nil)
+		}
+
+		lineItems, err := repos.PaymentLineItems.FindByAttemptID(ctx, attemptID)
+		if err != nil {
+			return apperr.New(apperr.CodeInternal, i18n.FailedRetrieveLineItems, err)
+		}
+		if len(lineItems) > 0 && lineItemsTotal(lineItems) != utils.ToSatang(body.Amount) {
+			return apperr.New(apperr.CodeBadRequest, i18n.LineItemsMismatch, nil)
+		}
+		payment.LineItems = copyLineItems(lineItems)
+
+		if err := applyCoverage(ctx, repos.CoverageRules, payment, entitlements); err != nil {
+			return err
+		}
+
+		if err := repos.Payments.Create(ctx, payment); err != nil {
+			// another instance settled the attempt first
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return apperr.New(apperr.CodeConflict, i18n.AttemptAlreadyPaid, nil)
+			}
+			return apperr.New(apperr.CodeInternal, i18n.FailedCreatePayment, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Update payable status via its owning service (omitted for brevity)
+
+	response := &dto.CreatePaymentResponseDto{
+		PaymentID:     payment.ID.String(),
+		AttemptID:     payment.AttemptID.String(),
+		PayableType:   payment.PayableType,
+		PayableID:     payment.PayableID.String(),
+		Amount:        payment.Amount,
+		PatientAmount: payment.PatientAmount,
+		PayerAmount:   payment.PayerAmount,
+		PaidAt:        payment.PaidAt.Format(time.RFC3339),
+	}
+
+	if payment.HealthcareEntitlement != nil {
+		response.HealthcareEntitlement = *payment.HealthcareEntitlement
+	}
+
+	return response, nil
 }
 
-func (s *PaymentService) GetAllPayments(ctx context.Context) (*dto.GetAllPaymentsResponseDto, error) {
-	payments, err := s.paymentRepository.FindAll(ctx)
+func (s *PaymentService) GetAllPayments(ctx context.Context, query dto.GetAllPaymentsRequestDto) (*dto.GetAllPaymentsResponseDto, error) {
+	page, err := parsePage(query.Limit, query.Cursor, query.Sort)
 	if err != nil {
-		return nil, apperr.New(apperr.CodeInternal, "failed to retrieve payments", err)
+		return nil, err
+	}
+
+	filter := repository.PaymentFilter{
+		Status: models.PaymentStatus(query.Status),
+		Method: models.PaymentMethod(query.Method),
+	}
+	if filter.Status != "" && !isValidPaymentStatus(filter.Status) {
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidPaymentStatus, nil)
+	}
+	if filter.Method != "" && !isValidPaymentMethod(filter.Method) {
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidPaymentMethod, nil)
+	}
+	if filter.PayableID, err = parseOptionalID("order ID", query.OrderID); err != nil {
+		return nil, err
+	}
+	if filter.PayableID != nil {
+		filter.PayableType = models.PayableTypeOrder
+	}
+	if filter.UserID, err = parseOptionalID("user ID", query.UserID); err != nil {
+		return nil, err
+	}
+	if filter.PaidFrom, err = parseOptionalTime(query.PaidFrom); err != nil {
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidTime, err, "paid_from")
+	}
+	if filter.PaidTo, err = parseOptionalTime(query.PaidTo); err != nil {
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidTime, err, "paid_to")
+	}
+
+	payments, next, err := s.paymentRepository.FindPage(ctx, filter, page)
+	if err != nil {
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedRetrievePayments, err)
 	}
 
 	return &dto.GetAllPaymentsResponseDto{
-		Payments: dto.ToPaymentDtoList(payments),
+		Payments:   dto.ToPaymentDtoList(payments),
+		NextCursor: nextCursor(next),
 	}, nil
 }
 
 func (s *PaymentService) GetPaymentByID(ctx context.Context, paymentID string) (*dto.GetPaymentByIDResponseDto, error) {
 	id := utils.StringToUUIDv7(paymentID)
 	if id == uuid.Nil {
-		return nil, apperr.New(apperr.CodeBadRequest, "invalid payment ID", nil)
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidPaymentID, nil)
 	}
 
 	payment, err := s.paymentRepository.FindByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, apperr.New(apperr.CodeNotFound, "payment not found", nil)
+			return nil, apperr.New(apperr.CodeNotFound, i18n.PaymentNotFound, nil)
 		}
-		return nil, apperr.New(apperr.CodeInternal, "failed to retrieve payment", err)
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedRetrievePayment, err)
+	}
+	visible, err := s.canSeePayable(ctx, payment.PayableType, payment.PayableID)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, apperr.New(apperr.CodeNotFound, i18n.PaymentNotFound, nil)
+	}
+
+	lineItems, err := s.paymentLineItemRepository.FindByPaymentID(ctx, id)
+	if err != nil {
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedRetrieveLineItems, err)
 	}
 
 	return &dto.GetPaymentByIDResponseDto{
-		Payment: dto.ToPaymentDto(payment),
+		Payment:   dto.ToPaymentDto(payment),
+		LineItems: dto.ToLineItemDtoList(lineItems),
 	}, nil
 }
+
+// ownedByCaller reports whether the caller may see a record of ownerID's.
+// Admins and other services may see everyone's.
+func ownedByCaller(ctx context.Context, ownerID uuid.UUID) bool {
+	switch contextUtils.GetRole(ctx) {
+	case constants.RoleAdmin, constants.RoleService:
+		return true
+	}
+	return ownerID == utils.StringToUUIDv7(contextUtils.GetUserId(ctx))
+}
+
+// canSeePayable reports whether the caller may see attempts and payments
+// made on a payable: its owner may, and so may the doctor it is with.
+func (s *PaymentService) canSeePayable(ctx context.Context, payableType models.PayableType, payableID uuid.UUID) (bool, error) {
+	switch contextUtils.GetRole(ctx) {
+	case constants.RoleAdmin, constants.RoleService:
+		return true, nil
+	}
+
+	resolved, err := s.payableRegistry.Resolve(ctx, payableType, payableID)
+	if err != nil {
+		return false, apperr.Propagate(err, i18n.FailedResolvePayable)
+	}
+	userID := utils.StringToUUIDv7(contextUtils.GetUserId(ctx))
+	if contextUtils.GetRole(ctx) == constants.RoleDoctor {
+		return resolved.DoctorID != nil && *resolved.DoctorID == userID, nil
+	}
+	return resolved.OwnerID == userID, nil
+}
+
+func toLineItemModels(items []dto.LineItemRequestDto) ([]models.PaymentLineItem, error) {
+	result := make([]models.PaymentLineItem, 0, len(items))
+	for _, item := range items {
+		isDiscount := item.Category == models.LineItemCategoryDiscount
+		if (isDiscount && item.UnitPrice > 0) || (!isDiscount && item.UnitPrice < 0) {
+			return nil, apperr.New(apperr.CodeBadRequest, i18n.NegativeUnitPrice, nil)
+		}
+		result = append(result, models.PaymentLineItem{
+			ID:          utils.GenerateUUIDv7(),
+			Description: item.Description,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			Tax:         item.Tax,
+			Category:    item.Category,
+		})
+	}
+
+	if lineItemsTotal(result) < 0 {
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.NegativeLineItems, nil)
+	}
+	return result, nil
+}
+
+// lineItemsTotal sums line items in satang, rounding each line the way it
+// is printed so the total matches what the patient sees.
+func lineItemsTotal(items []models.PaymentLineItem) int64 {
+	var total int64
+	for i := range items {
+		total += utils.ToSatang(items[i].Quantity*items[i].UnitPrice) + utils.ToSatang(items[i].Tax)
+	}
+	return total
+}
+
+// copyLineItems detaches attempt line items so they can be inserted again
+// under a payment.
+func copyLineItems(items []models.PaymentLineItem) []models.PaymentLineItem {
+	if len(items) == 0 {
+		return nil
+	}
+	result := make([]models.PaymentLineItem, len(items))
+	for i, item := range items {
+		item.ID = utils.GenerateUUIDv7()
+		item.AttemptID = nil
+		item.PaymentID = nil
+		item.CreatedAt = time.Time{}
+		result[i] = item
+	}
+	return result
+}
@@ -0,0 +1,460 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"payment-service/pkg/apperr"
+	"payment-service/pkg/constants"
+	"payment-service/pkg/dto"
+	"payment-service/pkg/models"
+	"payment-service/pkg/repository/memory"
+	"payment-service/pkg/utils"
+)
+
+func TestCreatePaymentAttempt(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   func(f *fixture) dto.CreatePaymentAttemptRequestDto
+		code   apperr.Code
+		status models.PaymentStatus
+	}{
+		{"allowed", func(f *fixture) dto.CreatePaymentAttemptRequestDto {
+			return dto.CreatePaymentAttemptRequestDto{
+				PayableType:   models.PayableTypeOrder,
+				PayableID:     f.order(patientID, 500).String(),
+				PaymentInfoID: f.card(patientID, "4111111111111111").ID.String(),
+			}
+		}, 0, models.PaymentStatusSuccess},
+		{"large amount on a new card is held", func(f *fixture) dto.CreatePaymentAttemptRequestDto {
+			return dto.CreatePaymentAttemptRequestDto{
+				PayableType:   models.PayableTypeOrder,
+				PayableID:     f.order(patientID, 20000).String(),
+				PaymentInfoID: f.card(patientID, "4111111111111111").ID.String(),
+			}
+		}, 0, models.PaymentStatusPending},
+		{"appointment with line items", func(f *fixture) dto.CreatePaymentAttemptRequestDto {
+			return dto.CreatePaymentAttemptRequestDto{
+				PayableType:   models.PayableTypeAppointment,
+				PayableID:     f.appointment(patientID, 800).String(),
+				PaymentInfoID: f.promptPay(patientID).ID.String(),
+				LineItems: []dto.LineItemRequestDto{
+					{Description: "Consultation", Quantity: 1, UnitPrice: 800, Category: models.LineItemCategoryConsultationFee},
+				},
+			}
+		}, 0, models.PaymentStatusSuccess},
+		{"bad payable ID", func(f *fixture) dto.CreatePaymentAttemptRequestDto {
+			return dto.CreatePaymentAttemptRequestDto{PayableType: models.PayableTypeOrder, PayableID: "nope"}
+		}, apperr.CodeBadRequest, ""},
+		{"unsupported payable", func(f *fixture) dto.CreatePaymentAttemptRequestDto {
+			return dto.CreatePaymentAttemptRequestDto{PayableType: "subscription", PayableID: utils.GenerateUUIDv7().String()}
+		}, apperr.CodeBadRequest, ""},
+		{"unknown payable", func(f *fixture) dto.CreatePaymentAttemptRequestDto {
+			return dto.CreatePaymentAttemptRequestDto{PayableType: models.PayableTypeOrder, PayableID: utils.GenerateUUIDv7().String()}
+		}, apperr.CodeNotFound, ""},
+		{"someone else's order", func(f *fixture) dto.CreatePaymentAttemptRequestDto {
+			return dto.CreatePaymentAttemptRequestDto{
+				PayableType:   models.PayableTypeOrder,
+				PayableID:     f.order(otherPatientID, 500).String(),
+				PaymentInfoID: f.card(patientID, "4111111111111111").ID.String(),
+			}
+		}, apperr.CodeForbidden, ""},
+		{"bad payment info ID", func(f *fixture) dto.CreatePaymentAttemptRequestDto {
+			return dto.CreatePaymentAttemptRequestDto{
+				PayableType:   models.PayableTypeOrder,
+				PayableID:     f.order(patientID, 500).String(),
+				PaymentInfoID: "nope",
+			}
+		}, apperr.CodeBadRequest, ""},
+		{"unknown payment info", func(f *fixture) dto.CreatePaymentAttemptRequestDto {
+			return dto.CreatePaymentAttemptRequestDto{
+				PayableType:   models.PayableTypeOrder,
+				PayableID:     f.order(patientID, 500).String(),
+				PaymentInfoID: utils.GenerateUUIDv7().String(),
+			}
+		}, apperr.CodeNotFound, ""},
+		{"someone else's card", func(f *fixture) dto.CreatePaymentAttemptRequestDto {
+			return dto.CreatePaymentAttemptRequestDto{
+				PayableType:   models.PayableTypeOrder,
+				PayableID:     f.order(patientID, 500).String(),
+				PaymentInfoID: f.card(otherPatientID, "4111111111111111").ID.String(),
+			}
+		}, apperr.CodeForbidden, ""},
+		{"negative line items", func(f *fixture) dto.CreatePaymentAttemptRequestDto {
+			return dto.CreatePaymentAttemptRequestDto{
+				PayableType:   models.PayableTypeOrder,
+				PayableID:     f.order(patientID, 500).String(),
+				PaymentInfoID: f.card(patientID, "4111111111111111").ID.String(),
+				LineItems: []dto.LineItemRequestDto{
+					{Description: "Medicine", Quantity: 1, UnitPrice: -100, Category: models.LineItemCategoryMedicine},
+				},
+			}
+		}, apperr.CodeBadRequest, ""},
+		{"line items short of the amount due", func(f *fixture) dto.CreatePaymentAttemptRequestDto {
+			return dto.CreatePaymentAttemptRequestDto{
+				PayableType:   models.PayableTypeAppointment,
+				PayableID:     f.appointment(patientID, 800).String(),
+				PaymentInfoID: f.promptPay(patientID).ID.String(),
+				LineItems: []dto.LineItemRequestDto{
+					{Description: "Consultation", Quantity: 1, UnitPrice: 700, Category: models.LineItemCategoryConsultationFee},
+				},
+			}
+		}, apperr.CodeBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			got, err := f.service.CreatePaymentAttempt(asPatient(patientID), tt.body(f))
+			wantCode(t, err, tt.code)
+			if err != nil {
+				return
+			}
+			if got.Status != tt.status {
+				t.Fatalf("got status %s, want %s", got.Status, tt.status)
+			}
+			stored, err := f.service.GetPaymentAttempt(asAdmin(), got.PaymentAttemptID)
+			wantCode(t, err, 0)
+			if stored.Status != tt.status {
+				t.Fatalf("stored status %s, want %s", stored.Status, tt.status)
+			}
+		})
+	}
+}
+
+func TestCreatePaymentAttemptBlocked(t *testing.T) {
+	f := newFixture(t)
+	info := f.card(patientID, "4111111111111111")
+	orderID := f.order(patientID, 100)
+	for i := 0; i < 20; i++ {
+		f.attempt(orderID, info, models.PaymentStatusSuccess)
+		if _, err := f.service.CreatePaymentAttempt(asPatient(patientID), dto.CreatePaymentAttemptRequestDto{
+			PayableType:   models.PayableTypeOrder,
+			PayableID:     orderID.String(),
+			PaymentInfoID: info.ID.String(),
+		}); err != nil {
+			wantCode(t, err, apperr.CodeForbidden)
+			return
+		}
+	}
+	t.Fatal("attempts were never blocked")
+}
+
+func TestGetPaymentAttempt(t *testing.T) {
+	f := newFixture(t)
+	info := f.card(patientID, "4111111111111111")
+	attempt := f.attempt(f.order(patientID, 100), info, models.PaymentStatusPending)
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		id   string
+		code apperr.Code
+	}{
+		{"found", asAdmin(), attempt.ID.String(), 0},
+		{"owner", asPatient(patientID), attempt.ID.String(), 0},
+		{"other patient", asPatient(otherPatientID), attempt.ID.String(), apperr.CodeNotFound},
+		{"malformed", asAdmin(), "nope", apperr.CodeBadRequest},
+		{"missing", asAdmin(), utils.GenerateUUIDv7().String(), apperr.CodeNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := f.service.GetPaymentAttempt(tt.ctx, tt.id)
+			wantCode(t, err, tt.code)
+			if err == nil && (got.PaymentInfoID != info.ID.String() || got.Status != models.PaymentStatusPending) {
+				t.Fatalf("got %+v", got)
+			}
+		})
+	}
+}
+
+func TestUpdatePaymentAttempt(t *testing.T) {
+	f := newFixture(t)
+	attempt := f.attempt(f.order(patientID, 100), f.card(patientID, "4111111111111111"), models.PaymentStatusPending)
+
+	tests := []struct {
+		name string
+		body dto.UpdatePaymentAttemptRequestDto
+		code apperr.Code
+	}{
+		{"no version", dto.UpdatePaymentAttemptRequestDto{PaymentAttemptID: attempt.ID.String(), Status: models.PaymentStatusSuccess}, apperr.CodePreconditionRequired},
+		{"succeeds", dto.UpdatePaymentAttemptRequestDto{PaymentAttemptID: attempt.ID.String(), Status: models.PaymentStatusSuccess, AnyVersion: true}, 0},
+		{"no status", dto.UpdatePaymentAttemptRequestDto{PaymentAttemptID: attempt.ID.String()}, apperr.CodeBadRequest},
+		{"unknown status", dto.UpdatePaymentAttemptRequestDto{PaymentAttemptID: attempt.ID.String(), Status: "refunded"}, apperr.CodeBadRequest},
+		{"malformed", dto.UpdatePaymentAttemptRequestDto{PaymentAttemptID: "nope", Status: models.PaymentStatusFailed}, apperr.CodeBadRequest},
+		{"missing", dto.UpdatePaymentAttemptRequestDto{PaymentAttemptID: utils.GenerateUUIDv7().String(), Status: models.PaymentStatusFailed, AnyVersion: true}, apperr.CodeNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := f.service.UpdatePaymentAttempt(asService(), tt.body)
+			wantCode(t, err, tt.code)
+			if err == nil && got.Status != tt.body.Status {
+				t.Fatalf("got status %s, want %s", got.Status, tt.body.Status)
+			}
+		})
+	}
+
+	stored, err := f.service.GetPaymentAttempt(asAdmin(), attempt.ID.String())
+	wantCode(t, err, 0)
+	if stored.Status != models.PaymentStatusSuccess {
+		t.Fatalf("stored status %s", stored.Status)
+	}
+}
+
+func TestUpdatePaymentAttemptVersion(t *testing.T) {
+	f := newFixture(t)
+	attempt := f.attempt(f.order(patientID, 100), f.card(patientID, "4111111111111111"), models.PaymentStatusPending)
+	update := func(status models.PaymentStatus, version int) (*dto.UpdatePaymentAttemptResponseDto, error) {
+		return f.service.UpdatePaymentAttempt(asAdmin(), dto.UpdatePaymentAttemptRequestDto{
+			PaymentAttemptID: attempt.ID.String(),
+			Status:           status,
+			Version:          version,
+		})
+	}
+
+	read, err := f.service.GetPaymentAttempt(asAdmin(), attempt.ID.String())
+	wantCode(t, err, 0)
+	got, err := update(models.PaymentStatusFailed, read.Version)
+	wantCode(t, err, 0)
+	if got.Version != read.Version+1 {
+		t.Fatalf("got version %d, want %d", got.Version, read.Version+1)
+	}
+	// a second admin who read the same version loses
+	_, err = update(models.PaymentStatusSuccess, read.Version)
+	wantCode(t, err, apperr.CodeConflict)
+
+	stored, err := f.service.GetPaymentAttempt(asAdmin(), attempt.ID.String())
+	wantCode(t, err, 0)
+	if stored.Status != models.PaymentStatusFailed || stored.Version != got.Version {
+		t.Fatalf("got %+v", stored)
+	}
+}
+
+func TestUpdatePaidAttempt(t *testing.T) {
+	f := newFixture(t)
+	attempt := f.attempt(f.order(patientID, 100), f.card(patientID, "4111111111111111"), models.PaymentStatusSuccess)
+	f.payment(attempt, 100, time.Now())
+
+	_, err := f.service.UpdatePaymentAttempt(asAdmin(), dto.UpdatePaymentAttemptRequestDto{PaymentAttemptID: attempt.ID.String(), Status: models.PaymentStatusFailed, AnyVersion: true})
+	wantCode(t, err, apperr.CodeConflict)
+
+	// setting the status it already has changes nothing
+	_, err = f.service.UpdatePaymentAttempt(asAdmin(), dto.UpdatePaymentAttemptRequestDto{PaymentAttemptID: attempt.ID.String(), Status: models.PaymentStatusSuccess, AnyVersion: true})
+	wantCode(t, err, 0)
+
+	stored, err := f.service.GetPaymentAttempt(asAdmin(), attempt.ID.String())
+	wantCode(t, err, 0)
+	if stored.Status != models.PaymentStatusSuccess {
+		t.Fatalf("stored status %s", stored.Status)
+	}
+}
+
+func TestCreatePayment(t *testing.T) {
+	consultation := models.PaymentLineItem{Description: "Consultation", Quantity: 1, UnitPrice: 800, Category: models.LineItemCategoryConsultationFee}
+
+	tests := []struct {
+		name        string
+		attemptID   func(f *fixture) string
+		amount      float64
+		code        apperr.Code
+		payerAmount float64
+	}{
+		{"settles a successful attempt", func(f *fixture) string {
+			return f.attempt(f.order(patientID, 500), f.card(patientID, "4111111111111111"), models.PaymentStatusSuccess).ID.String()
+		}, 500, 0, 0},
+		{"matches line items", func(f *fixture) string {
+			return f.attempt(f.appointment(patientID, 800), f.promptPay(patientID), models.PaymentStatusSuccess, consultation).ID.String()
+		}, 800, 0, 0},
+		{"payer covers part", func(f *fixture) string {
+			f.users.entitlements[patientID.String()] = []string{"civil_servant"}
+			if err := memory.NewCoverageRuleRepository(f.db).Create(context.Background(), &models.CoverageRule{
+				HealthcareEntitlement: "civil_servant",
+				PayerType:             models.PayerTypeGovernment,
+				PayerName:             "Comptroller General's Department",
+				CoveragePercent:       80,
+				Active:                true,
+			}); err != nil {
+				t.Fatal(err)
+			}
+			return f.attempt(f.order(patientID, 1000), f.card(patientID, "4111111111111111"), models.PaymentStatusSuccess).ID.String()
+		}, 1000, 0, 800},
+		{"no attempt ID", func(f *fixture) string { return "" }, 100, apperr.CodeBadRequest, 0},
+		{"malformed attempt ID", func(f *fixture) string { return "nope" }, 100, apperr.CodeBadRequest, 0},
+		{"negative amount", func(f *fixture) string { return utils.GenerateUUIDv7().String() }, -1, apperr.CodeBadRequest, 0},
+		{"unknown attempt", func(f *fixture) string { return utils.GenerateUUIDv7().String() }, 100, apperr.CodeNotFound, 0},
+		{"pending attempt", func(f *fixture) string {
+			return f.attempt(f.order(patientID, 500), f.card(patientID, "4111111111111111"), models.PaymentStatusPending).ID.String()
+		}, 500, apperr.CodeBadRequest, 0},
+		{"already paid", func(f *fixture) string {
+			attempt := f.attempt(f.order(patientID, 500), f.card(patientID, "4111111111111111"), models.PaymentStatusSuccess)
+			f.payment(attempt, 500, time.Now())
+			return attempt.ID.String()
+		}, 500, apperr.CodeConflict, 0},
+		{"less than the amount due", func(f *fixture) string {
+			return f.attempt(f.order(patientID, 500), f.card(patientID, "4111111111111111"), models.PaymentStatusSuccess).ID.String()
+		}, 499.99, apperr.CodeBadRequest, 0},
+		{"more than the amount due", func(f *fixture) string {
+			return f.attempt(f.order(patientID, 500), f.card(patientID, "4111111111111111"), models.PaymentStatusSuccess).ID.String()
+		}, 600, apperr.CodeBadRequest, 0},
+		{"line items disagree", func(f *fixture) string {
+			return f.attempt(f.appointment(patientID, 800), f.promptPay(patientID), models.PaymentStatusSuccess, consultation).ID.String()
+		}, 700, apperr.CodeBadRequest, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			got, err := f.service.CreatePayment(asService(), dto.CreatePaymentRequestDto{PaymentAttemptID: tt.attemptID(f), Amount: tt.amount})
+			wantCode(t, err, tt.code)
+			if err != nil {
+				return
+			}
+			if got.Amount != tt.amount || got.PayerAmount != tt.payerAmount || got.PatientAmount != tt.amount-tt.payerAmount {
+				t.Fatalf("got %+v", got)
+			}
+
+			stored, err := f.service.GetPaymentByID(asAdmin(), got.PaymentID)
+			wantCode(t, err, 0)
+			attempt, err := f.service.GetPaymentAttempt(asAdmin(), got.AttemptID)
+			wantCode(t, err, 0)
+			if stored.Payment.PayableID != attempt.PayableID {
+				t.Fatalf("payment for %s, attempt for %s", stored.Payment.PayableID, attempt.PayableID)
+			}
+
+			receivables, err := f.service.GetReceivables(asAdmin(), "")
+			wantCode(t, err, 0)
+			if want := map[bool]int{true: 1, false: 0}[tt.payerAmount > 0]; len(receivables.Receivables) != want {
+				t.Fatalf("got %d receivables, want %d", len(receivables.Receivables), want)
+			}
+		})
+	}
+}
+
+func TestCreatePaymentSplitsPayers(t *testing.T) {
+	f := newFixture(t)
+	f.users.entitlements[patientID.String()] = []string{"private"}
+	medicine := models.LineItemCategoryMedicine
+	consultation := models.LineItemCategoryConsultationFee
+	rules := memory.NewCoverageRuleRepository(f.db)
+	for _, rule := range []models.CoverageRule{
+		{HealthcareEntitlement: "private", PayerType: models.PayerTypeInsurer, PayerName: "Insurer A", Category: &medicine, CoveragePercent: 50, Priority: 1, Active: true},
+		{HealthcareEntitlement: "private", PayerType: models.PayerTypeGovernment, PayerName: "NHSO", Category: &consultation, CoveragePercent: 100, Priority: 2, Active: true},
+	} {
+		if err := rules.Create(context.Background(), &rule); err != nil {
+			t.Fatal(err)
+		}
+	}
+	attempt := f.attempt(f.appointment(patientID, 1000), f.promptPay(patientID), models.PaymentStatusSuccess,
+		models.PaymentLineItem{Description: "Medicine", Quantity: 2, UnitPrice: 300, Category: medicine},
+		models.PaymentLineItem{Description: "Consultation", Quantity: 1, UnitPrice: 400, Category: consultation},
+	)
+
+	got, err := f.service.CreatePayment(asService(), dto.CreatePaymentRequestDto{PaymentAttemptID: attempt.ID.String(), Amount: 1000})
+	wantCode(t, err, 0)
+	if got.PatientAmount != 300 || got.PayerAmount != 700 {
+		t.Fatalf("got %+v", got)
+	}
+
+	receivables, err := f.service.GetReceivables(asAdmin(), "")
+	wantCode(t, err, 0)
+	owed := map[string]float64{}
+	for _, receivable := range receivables.Receivables {
+		owed[receivable.PayerName] = receivable.Amount
+	}
+	if len(owed) != 2 || owed["Insurer A"] != 300 || owed["NHSO"] != 400 {
+		t.Fatalf("got receivables %v", owed)
+	}
+}
+
+func TestGetAllPayments(t *testing.T) {
+	f := newFixture(t)
+	orderID := f.order(patientID, 500)
+	card := f.card(patientID, "4111111111111111")
+	promptPay := f.promptPay(otherPatientID)
+	january := time.Date(2026, time.January, 15, 0, 0, 0, 0, time.UTC)
+	march := time.Date(2026, time.March, 15, 0, 0, 0, 0, time.UTC)
+
+	first := f.payment(f.attempt(orderID, card, models.PaymentStatusSuccess), 500, january)
+	second := f.payment(f.attempt(f.order(otherPatientID, 300), promptPay, models.PaymentStatusSuccess), 300, march)
+
+	tests := []struct {
+		name  string
+		query dto.GetAllPaymentsRequestDto
+		want  []string
+		code  apperr.Code
+	}{
+		{"newest first", dto.GetAllPaymentsRequestDto{}, []string{second.ID.String(), first.ID.String()}, 0},
+		{"by order", dto.GetAllPaymentsRequestDto{OrderID: orderID.String()}, []string{first.ID.String()}, 0},
+		{"by user", dto.GetAllPaymentsRequestDto{UserID: otherPatientID.String()}, []string{second.ID.String()}, 0},
+		{"by method", dto.GetAllPaymentsRequestDto{Method: string(models.PaymentMethodCreditCard)}, []string{first.ID.String()}, 0},
+		{"by status", dto.GetAllPaymentsRequestDto{Status: string(models.PaymentStatusFailed)}, nil, 0},
+		{"paid from", dto.GetAllPaymentsRequestDto{PaidFrom: "2026-02-01T00:00:00Z"}, []string{second.ID.String()}, 0},
+		{"paid to", dto.GetAllPaymentsRequestDto{PaidTo: "2026-02-01T00:00:00Z"}, []string{first.ID.String()}, 0},
+		{"bad status", dto.GetAllPaymentsRequestDto{Status: "refunded"}, nil, apperr.CodeBadRequest},
+		{"bad method", dto.GetAllPaymentsRequestDto{Method: "cash"}, nil, apperr.CodeBadRequest},
+		{"bad order", dto.GetAllPaymentsRequestDto{OrderID: "nope"}, nil, apperr.CodeBadRequest},
+		{"bad paid from", dto.GetAllPaymentsRequestDto{PaidFrom: "yesterday"}, nil, apperr.CodeBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := f.service.GetAllPayments(asAdmin(), tt.query)
+			wantCode(t, err, tt.code)
+			if err != nil {
+				return
+			}
+			var ids []string
+			for _, payment := range got.Payments {
+				ids = append(ids, payment.PaymentID)
+			}
+			if !equalStrings(ids, tt.want) {
+				t.Fatalf("got %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetAllPaymentsByUserOutlivesCard(t *testing.T) {
+	f := newFixture(t)
+	card := f.card(patientID, "4111111111111111")
+	payment := f.payment(f.attempt(f.order(patientID, 500), card, models.PaymentStatusSuccess), 500, time.Now())
+	_, err := f.service.DeletePaymentInfo(asPatient(patientID), card.ID.String())
+	wantCode(t, err, 0)
+
+	got, err := f.service.GetAllPayments(asAdmin(), dto.GetAllPaymentsRequestDto{UserID: patientID.String()})
+	wantCode(t, err, 0)
+	if len(got.Payments) != 1 || got.Payments[0].PaymentID != payment.ID.String() {
+		t.Fatalf("got %+v", got.Payments)
+	}
+}
+
+func TestGetPaymentByID(t *testing.T) {
+	f := newFixture(t)
+	attempt := f.attempt(f.appointment(patientID, 800), f.promptPay(patientID), models.PaymentStatusSuccess,
+		models.PaymentLineItem{Description: "Consultation", Quantity: 1, UnitPrice: 800, Category: models.LineItemCategoryConsultationFee})
+	created, err := f.service.CreatePayment(asService(), dto.CreatePaymentRequestDto{PaymentAttemptID: attempt.ID.String(), Amount: 800})
+	wantCode(t, err, 0)
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		id        string
+		code      apperr.Code
+		lineItems int
+	}{
+		{"with line items", asAdmin(), created.PaymentID, 0, 1},
+		{"owner", asPatient(patientID), created.PaymentID, 0, 1},
+		{"appointment's doctor", asUser(doctorID, constants.RoleDoctor), created.PaymentID, 0, 1},
+		{"other doctor", asUser(utils.GenerateUUIDv7(), constants.RoleDoctor), created.PaymentID, apperr.CodeNotFound, 0},
+		{"other patient", asPatient(otherPatientID), created.PaymentID, apperr.CodeNotFound, 0},
+		{"malformed", asAdmin(), "nope", apperr.CodeBadRequest, 0},
+		{"missing", asAdmin(), utils.GenerateUUIDv7().String(), apperr.CodeNotFound, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := f.service.GetPaymentByID(tt.ctx, tt.id)
+			wantCode(t, err, tt.code)
+			if err == nil && (got.Payment.PaymentID != tt.id || len(got.LineItems) != tt.lineItems || got.Payment.Version != 1) {
+				t.Fatalf("got %+v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"payment-service/pkg/apperr"
+	"payment-service/pkg/dto"
+	"payment-service/pkg/i18n"
+	"payment-service/pkg/models"
+	"payment-service/pkg/repository"
+	"time"
+)
+
+const (
+	defaultAuditLogLimit = 100
+	maxAuditLogLimit     = 1000
+)
+
+func (s *PaymentService) GetAuditLogs(ctx context.Context, query dto.GetAuditLogsRequestDto) (*dto.GetAuditLogsResponseDto, error) {
+	filter := repository.AuditLogFilter{
+		EntityType: query.EntityType,
+		EntityID:   query.EntityID,
+		ActorID:    query.ActorID,
+		Action:     models.AuditAction(query.Action),
+		RequestID:  query.RequestID,
+		Limit:      query.Limit,
+	}
+
+	switch filter.Action {
+	case "", models.AuditActionCreate, models.AuditActionUpdate, models.AuditActionDelete:
+	default:
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidAuditAction, nil)
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditLogLimit
+	}
+	if filter.Limit > maxAuditLogLimit {
+		filter.Limit = maxAuditLogLimit
+	}
+
+	var err error
+	if filter.From, err = parseOptionalTime(query.From); err != nil {
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidTime, err, "from")
+	}
+	if filter.To, err = parseOptionalTime(query.To); err != nil {
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidTime, err, "to")
+	}
+
+	entries, err := s.auditLogRepository.Find(ctx, filter)
+	if err != nil {
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedRetrieveAuditLog, err)
+	}
+
+	return &dto.GetAuditLogsResponseDto{
+		Entries: dto.ToAuditLogDtoList(entries),
+	}, nil
+}
+
+func parseOptionalTime(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
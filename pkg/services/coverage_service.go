@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"payment-service/pkg/apperr"
+	"payment-service/pkg/coverage"
+	"payment-service/pkg/dto"
+	"payment-service/pkg/i18n"
+	"payment-service/pkg/models"
+	"payment-service/pkg/repository"
+	"payment-service/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// entitlementsOf returns the healthcare entitlements of the patient.
+func (s *PaymentService) entitlementsOf(ctx context.Context, patientID uuid.UUID) ([]string, error) {
+	entitlements, err := s.userClient.GetPatientEntitlements(ctx, patientID.String())
+	if err != nil {
+		return nil, apperr.Propagate(err, i18n.FailedRetrieveEntitlements)
+	}
+
+	names := make([]string, 0, len(*entitlements))
+	for _, e := range *entitlements {
+		names = append(names, e.HealthcareEntitlement)
+	}
+	return names, nil
+}
+
+// applyCoverage splits the payment between the patient and the payers of
+// the best-covering of entitlements. Each payer's share is attached as an
+// accrued receivable so it is stored with the payment.
+func applyCoverage(ctx context.Context, coverageRules repository.CoverageRules, payment *models.Payment, entitlements []string) error {
+	payment.PatientAmount = payment.Amount
+	payment.PayerAmount = 0
+
+	rules, err := coverageRules.FindActiveByEntitlements(ctx, entitlements)
+	if err != nil {
+		return apperr.New(apperr.CodeInternal, i18n.FailedRetrieveCoverageRules, err)
+	}
+
+	split := coverage.Calculate(payment.Amount, payment.LineItems, rules)
+	if len(split.Shares) == 0 {
+		return nil
+	}
+
+	entitlement := split.Entitlement
+	payment.PatientAmount = split.PatientAmount
+	payment.PayerAmount = split.PayerAmount
+	payment.HealthcareEntitlement = &entitlement
+	payment.Receivables = nil
+	for _, share := range split.Shares {
+		payment.Receivables = append(payment.Receivables, models.PayerReceivable{
+			ID:                    utils.GenerateUUIDv7(),
+			PayerType:             share.Rule.PayerType,
+			PayerName:             share.Rule.PayerName,
+			HealthcareEntitlement: entitlement,
+			Amount:                share.Amount,
+			Status:                models.ReceivableStatusAccrued,
+		})
+	}
+	return nil
+}
+
+func (s *PaymentService) GetReceivables(ctx context.Context, status string) (*dto.GetReceivablesResponseDto, error) {
+	receivableStatus := models.ReceivableStatus(status)
+	if receivableStatus == "" {
+		receivableStatus = models.ReceivableStatusAccrued
+	}
+	if !isValidReceivableStatus(receivableStatus) {
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidReceivableStatus, nil)
+	}
+
+	receivables, err := s.payerReceivableRepository.FindByStatus(ctx, receivableStatus)
+	if err != nil {
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedRetrieveReceivables, err)
+	}
+
+	return &dto.GetReceivablesResponseDto{
+		Receivables: dto.ToReceivableDtoList(receivables),
+	}, nil
+}
+
+func isValidReceivableStatus(status models.ReceivableStatus) bool {
+	switch status {
+	case models.ReceivableStatusAccrued,
+		models.ReceivableStatusInvoiced,
+		models.ReceivableStatusSettled:
+		return true
+	default:
+		return false
+	}
+}
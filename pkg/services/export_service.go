@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"payment-service/pkg/apperr"
+	contextUtils "payment-service/pkg/context"
+	"payment-service/pkg/dto"
+	"payment-service/pkg/export"
+	"payment-service/pkg/i18n"
+	"payment-service/pkg/receipt"
+	"payment-service/pkg/repository"
+	"payment-service/pkg/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const exportDownloadPath = "/api/payment/v1/exports/jobs/%s/download"
+
+// exportColumn is one column finance can pick. Thai formatting writes
+// amounts with thousands separators and dates in the Buddhist era.
+type exportColumn struct {
+	key    string
+	header string
+	value  func(row *repository.PaymentExportRow, thai bool) export.Cell
+}
+
+var paymentExportColumns = []exportColumn{
+	{"payment_id", "Payment ID", func(row *repository.PaymentExportRow, _ bool) export.Cell {
+		return export.Text(row.ID.String())
+	}},
+	{"paid_at", "Paid at", func(row *repository.PaymentExportRow, thai bool) export.Cell {
+		return export.Text(formatExportTime(row.PaidAt, thai))
+	}},
+	{"payable_type", "Payable type", func(row *repository.PaymentExportRow, _ bool) export.Cell {
+		return export.Text(string(row.PayableType))
+	}},
+	{"payable_id", "Payable ID", func(row *repository.PaymentExportRow, _ bool) export.Cell {
+		return export.Text(row.PayableID.String())
+	}},
+	{"attempt_id", "Attempt ID", func(row *repository.PaymentExportRow, _ bool) export.Cell {
+		return export.Text(row.AttemptID.String())
+	}},
+	{"user_id", "User ID", func(row *repository.PaymentExportRow, _ bool) export.Cell {
+		if row.UserID == nil {
+			return export.Text("")
+		}
+		return export.Text(row.UserID.String())
+	}},
+	{"method", "Method", func(row *repository.PaymentExportRow, _ bool) export.Cell {
+		return export.Text(string(row.Method))
+	}},
+	{"amount", "Amount", func(row *repository.PaymentExportRow, thai bool) export.Cell {
+		return exportAmount(row.Amount, thai)
+	}},
+	{"patient_amount", "Patient amount", func(row *repository.PaymentExportRow, thai bool) export.Cell {
+		return exportAmount(row.PatientAmount, thai)
+	}},
+	{"payer_amount", "Payer amount", func(row *repository.PaymentExportRow, thai bool) export.Cell {
+		return exportAmount(row.PayerAmount, thai)
+	}},
+	{"healthcare_entitlement", "Healthcare entitlement", func(row *repository.PaymentExportRow, _ bool) export.Cell {
+		if row.HealthcareEntitlement == nil {
+			return export.Text("")
+		}
+		return export.Text(*row.HealthcareEntitlement)
+	}},
+}
+
+// PaymentExport is a validated export, ready to be written.
+type PaymentExport struct {
+	Format   export.Format
+	Filename string
+	Run      export.RunFunc
+}
+
+// ExportFile is the finished file of a background export.
+type ExportFile struct {
+	File        *os.File
+	Filename    string
+	ContentType string
+}
+
+// PreparePaymentExport checks an export request so that errors surface
+// before any of the file is sent.
+func (s *PaymentService) PreparePaymentExport(ctx context.Context, query dto.ExportPaymentsRequestDto) (*PaymentExport, error) {
+	format, err := export.ParseFormat(query.Format)
+	if err != nil {
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidExportFormat, err)
+	}
+
+	from, err := parseExportTime(query.From)
+	if err != nil {
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidTimeOrDate, err, "from")
+	}
+	to, err := parseExportTime(query.To)
+	if err != nil {
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidTimeOrDate, err, "to")
+	}
+	if !from.Before(to) {
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidPeriod, nil)
+	}
+
+	locale := query.Locale
+	if locale == "" {
+		locale = string(contextUtils.GetLanguage(ctx))
+	}
+	var thai bool
+	switch locale {
+	case "en":
+	case "th":
+		thai = true
+	default:
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidExportLocale, nil)
+	}
+
+	columns, err := selectExportColumns(query.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	filename := fmt.Sprintf("payments_%s_%s.%s", from.Format("20060102"), to.Format("20060102"), format)
+	run := func(ctx context.Context, w io.Writer) (int, error) {
+		writer, err := export.NewWriter(format, w)
+		if err != nil {
+			return 0, err
+		}
+
+		cells := make([]export.Cell, len(columns))
+		for i, column := range columns {
+			cells[i] = export.Text(column.header)
+		}
+		if err := writer.WriteRow(cells); err != nil {
+			return 0, err
+		}
+
+		rows := 0
+		err = s.paymentRepository.StreamForExport(ctx, from, to, func(row *repository.PaymentExportRow) error {
+			for i, column := range columns {
+				cells[i] = column.value(row, thai)
+			}
+			rows++
+			return writer.WriteRow(cells)
+		})
+		if err != nil {
+			return rows, err
+		}
+		return rows, writer.Close()
+	}
+
+	return &PaymentExport{Format: format, Filename: filename, Run: run}, nil
+}
+
+// StartPaymentExportJob runs an export in the background for exports too
+// large to wait for.
+func (s *PaymentService) StartPaymentExportJob(ctx context.Context, query dto.ExportPaymentsRequestDto) (*dto.ExportJobResponseDto, error) {
+	prepared, err := s.PreparePaymentExport(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	job := s.exportJobs.Start(ctx, contextUtils.GetUserId(ctx), prepared.Format, prepared.Filename, prepared.Run)
+	return &dto.ExportJobResponseDto{
+		Job: dto.ToExportJobDto(&job, fmt.Sprintf(exportDownloadPath, job.ID)),
+	}, nil
+}
+
+func (s *PaymentService) GetExportJob(ctx context.Context, jobID string) (*dto.ExportJobResponseDto, error) {
+	job, err := s.findExportJob(jobID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.ExportJobResponseDto{
+		Job: dto.ToExportJobDto(&job, fmt.Sprintf(exportDownloadPath, job.ID)),
+	}, nil
+}
+
+// OpenExportJobFile opens the file of a finished export; the caller closes it.
+func (s *PaymentService) OpenExportJobFile(ctx context.Context, jobID string) (*ExportFile, error) {
+	job, err := s.findExportJob(jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != export.JobStatusDone {
+		return nil, apperr.New(apperr.CodeConflict, i18n.ExportNotReady, nil, job.Status)
+	}
+
+	f, err := s.exportJobs.Open(job)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, apperr.New(apperr.CodeNotFound, i18n.ExportExpired, err)
+		}
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedOpenExport, err)
+	}
+	return &ExportFile{File: f, Filename: job.Filename, ContentType: job.Format.ContentType()}, nil
+}
+
+func (s *PaymentService) findExportJob(jobID string) (export.Job, error) {
+	id := utils.StringToUUIDv7(jobID)
+	if id == uuid.Nil {
+		return export.Job{}, apperr.New(apperr.CodeBadRequest, i18n.InvalidExportJobID, nil)
+	}
+	job, ok := s.exportJobs.Get(id)
+	if !ok {
+		return export.Job{}, apperr.New(apperr.CodeNotFound, i18n.ExportJobNotFound, nil)
+	}
+	return job, nil
+}
+
+func selectExportColumns(keys string) ([]exportColumn, error) {
+	if keys == "" {
+		return paymentExportColumns, nil
+	}
+	var columns []exportColumn
+	for _, key := range strings.Split(keys, ",") {
+		key = strings.TrimSpace(key)
+		found := false
+		for _, column := range paymentExportColumns {
+			if column.key == key {
+				columns = append(columns, column)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, apperr.New(apperr.CodeBadRequest, i18n.UnknownExportColumn, nil, key)
+		}
+	}
+	return columns, nil
+}
+
+// parseExportTime takes an RFC 3339 time or a date, which starts at local
+// midnight.
+func parseExportTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, errors.New("missing time")
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, s, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+func formatExportTime(t time.Time, thai bool) string {
+	t = t.In(time.Local)
+	if thai {
+		return fmt.Sprintf("%s/%d %s", t.Format("02/01"), t.Year()+543, t.Format("15:04:05"))
+	}
+	return t.Format(time.RFC3339)
+}
+
+// exportAmount keeps amounts numeric, so spreadsheets can sum them, unless
+// Thai formatting is asked for.
+func exportAmount(amount float64, thai bool) export.Cell {
+	if thai {
+		return export.Text(receipt.FormatAmount(amount))
+	}
+	return export.Number(strconv.FormatFloat(amount, 'f', 2, 64))
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"io"
+	"testing"
+	"time"
+
+	"payment-service/pkg/apperr"
+	"payment-service/pkg/dto"
+	"payment-service/pkg/export"
+	"payment-service/pkg/models"
+	"payment-service/pkg/utils"
+)
+
+func TestPreparePaymentExport(t *testing.T) {
+	f := newFixture(t)
+	info := f.card(patientID, "4111111111111111")
+	march := time.Date(2026, time.March, 2, 10, 0, 0, 0, time.Local)
+	first := f.payment(f.attempt(f.order(patientID, 1234.5), info, models.PaymentStatusSuccess), 1234.5, march)
+	second := f.payment(f.attempt(f.order(patientID, 100), info, models.PaymentStatusSuccess), 100, march.Add(time.Hour))
+	f.payment(f.attempt(f.order(patientID, 100), info, models.PaymentStatusSuccess), 100, march.AddDate(0, 1, 0))
+
+	tests := []struct {
+		name     string
+		query    dto.ExportPaymentsRequestDto
+		filename string
+		rows     [][]string
+		code     apperr.Code
+	}{
+		{"chosen columns", dto.ExportPaymentsRequestDto{From: "2026-03-01", To: "2026-04-01", Columns: "payment_id, amount"}, "payments_20260301_20260401.csv", [][]string{
+			{"Payment ID", "Amount"},
+			{first.ID.String(), "1234.50"},
+			{second.ID.String(), "100.00"},
+		}, 0},
+		{"thai", dto.ExportPaymentsRequestDto{From: "2026-03-01", To: march.Add(30 * time.Minute).Format(time.RFC3339), Columns: "amount,paid_at", Locale: "th"}, "payments_20260301_20260302.csv", [][]string{
+			{"Amount", "Paid at"},
+			{"1,234.50", "02/03/2569 " + march.Format("15:04:05")},
+		}, 0},
+		{"xlsx", dto.ExportPaymentsRequestDto{From: "2026-03-01", To: "2026-04-01", Format: "xlsx"}, "payments_20260301_20260401.xlsx", nil, 0},
+		{"bad format", dto.ExportPaymentsRequestDto{From: "2026-03-01", To: "2026-04-01", Format: "pdf"}, "", nil, apperr.CodeBadRequest},
+		{"no from", dto.ExportPaymentsRequestDto{To: "2026-04-01"}, "", nil, apperr.CodeBadRequest},
+		{"bad to", dto.ExportPaymentsRequestDto{From: "2026-03-01", To: "April"}, "", nil, apperr.CodeBadRequest},
+		{"empty period", dto.ExportPaymentsRequestDto{From: "2026-04-01", To: "2026-03-01"}, "", nil, apperr.CodeBadRequest},
+		{"bad locale", dto.ExportPaymentsRequestDto{From: "2026-03-01", To: "2026-04-01", Locale: "fr"}, "", nil, apperr.CodeBadRequest},
+		{"unknown column", dto.ExportPaymentsRequestDto{From: "2026-03-01", To: "2026-04-01", Columns: "amount,card_number"}, "", nil, apperr.CodeBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prepared, err := f.service.PreparePaymentExport(asAdmin(), tt.query)
+			wantCode(t, err, tt.code)
+			if err != nil {
+				return
+			}
+			if prepared.Filename != tt.filename {
+				t.Fatalf("got %s, want %s", prepared.Filename, tt.filename)
+			}
+
+			var out bytes.Buffer
+			rows, err := prepared.Run(context.Background(), &out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if prepared.Format != export.FormatCSV {
+				if rows != 2 {
+					t.Fatalf("wrote %d rows", rows)
+				}
+				return
+			}
+			// CSV starts with a byte order mark for Excel
+			got, err := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(out.Bytes(), []byte("\ufeff")))).ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.rows) {
+				t.Fatalf("got %v, want %v", got, tt.rows)
+			}
+			for i := range got {
+				if !equalStrings(got[i], tt.rows[i]) {
+					t.Fatalf("row %d: got %v, want %v", i, got[i], tt.rows[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPaymentExportJob(t *testing.T) {
+	f := newFixture(t)
+	f.paidOrder(500)
+
+	started, err := f.service.StartPaymentExportJob(asAdmin(), dto.ExportPaymentsRequestDto{
+		From:    time.Now().Add(-time.Hour).Format(time.RFC3339),
+		To:      time.Now().Add(time.Hour).Format(time.RFC3339),
+		Columns: "amount",
+	})
+	wantCode(t, err, 0)
+
+	job := started.Job
+	for deadline := time.Now().Add(5 * time.Second); job.Status != export.JobStatusDone; {
+		if job.Status == export.JobStatusFailed || time.Now().After(deadline) {
+			t.Fatalf("job is %s", job.Status)
+		}
+		if job.Status == export.JobStatusPending || job.Status == export.JobStatusRunning {
+			_, err := f.service.OpenExportJobFile(asAdmin(), job.ID)
+			if err != nil {
+				wantCode(t, err, apperr.CodeConflict)
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		polled, err := f.service.GetExportJob(asAdmin(), job.ID)
+		wantCode(t, err, 0)
+		job = polled.Job
+	}
+	if job.Rows != 1 || job.DownloadURL == "" {
+		t.Fatalf("got %+v", job)
+	}
+
+	file, err := f.service.OpenExportJobFile(asAdmin(), job.ID)
+	wantCode(t, err, 0)
+	defer file.File.Close()
+	content, err := io.ReadAll(file.File)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "\ufeffAmount\n500.00\n" || file.ContentType != export.FormatCSV.ContentType() {
+		t.Fatalf("got %s %q", file.ContentType, content)
+	}
+}
+
+func TestExportJobLookup(t *testing.T) {
+	f := newFixture(t)
+
+	tests := []struct {
+		name  string
+		jobID string
+		code  apperr.Code
+	}{
+		{"malformed", "nope", apperr.CodeBadRequest},
+		{"missing", utils.GenerateUUIDv7().String(), apperr.CodeNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.service.GetExportJob(asAdmin(), tt.jobID)
+			wantCode(t, err, tt.code)
+			_, err = f.service.OpenExportJobFile(asAdmin(), tt.jobID)
+			wantCode(t, err, tt.code)
+		})
+	}
+
+	_, err := f.service.StartPaymentExportJob(asAdmin(), dto.ExportPaymentsRequestDto{From: "2026-03-01"})
+	wantCode(t, err, apperr.CodeBadRequest)
+}
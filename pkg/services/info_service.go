@@ -8,8 +8,15 @@ import (
 	"payment-service/pkg/clients"
 	contextUtils "payment-service/pkg/context"
 	"payment-service/pkg/dto"
+	"payment-service/pkg/events"
+	"payment-service/pkg/export"
+	"payment-service/pkg/i18n"
 	"payment-service/pkg/models"
+	"payment-service/pkg/payable"
+	"payment-service/pkg/receipt"
 	"payment-service/pkg/repository"
+	"payment-service/pkg/revocation"
+	"payment-service/pkg/risk"
 	"payment-service/pkg/utils"
 	"time"
 
@@ -18,41 +25,75 @@ import (
 )
 
 type PaymentService struct {
-	db                           *gorm.DB
-	paymentInformationRepository *repository.PaymentInformationRepository
-	paymentAttemptRepository     *repository.PaymentAttemptRepository
-	paymentRepository            *repository.PaymentRepository
-	userClient                   *clients.UserClient
+	unitOfWork                   repository.UnitOfWork
+	paymentInformationRepository repository.PaymentInformations
+	paymentAttemptRepository     repository.PaymentAttempts
+	paymentRepository            repository.Payments
+	refundRepository             repository.Refunds
+	paymentLineItemRepository    repository.PaymentLineItems
+	coverageRuleRepository       repository.CoverageRules
+	payerReceivableRepository    repository.PayerReceivables
+	paymentDocumentRepository    repository.PaymentDocuments
+	auditLogRepository           repository.AuditLogs
+	riskAssessmentRepository     repository.RiskAssessments
+	userClient                   clients.Users
+	payableRegistry              *payable.Registry
+	documentRenderer             *receipt.Renderer
+	seller                       receipt.Seller
+	revocations                  *revocation.Store
+	riskConfig                   risk.Config
+	exportJobs                   *export.Jobs
+	attemptEvents                *events.Broker
 }
 
-func NewPaymentService(
-	db *gorm.DB,
-	paymentInformationRepository *repository.PaymentInformationRepository,
-	paymentAttemptRepository *repository.PaymentAttemptRepository,
-	paymentRepository *repository.PaymentRepository,
-	userClient *clients.UserClient,
-) *PaymentService {
+// Dependencies are what a PaymentService is built from. Repositories are
+// used outside units of work; UnitOfWork hands out its own, bound to the
+// transaction.
+type Dependencies struct {
+	UnitOfWork    repository.UnitOfWork
+	Repositories  repository.Repositories
+	Users         clients.Users
+	Payables      *payable.Registry
+	Renderer      *receipt.Renderer
+	Seller        receipt.Seller
+	Revocations   *revocation.Store
+	Risk          risk.Config
+	ExportJobs    *export.Jobs
+	AttemptEvents *events.Broker
+}
+
+func NewPaymentService(deps Dependencies) *PaymentService {
+	repos := deps.Repositories
 	return &PaymentService{
-		db:                           db,
-		paymentInformationRepository: paymentInformationRepository,
-		paymentAttemptRepository:     paymentAttemptRepository,
-		paymentRepository:            paymentRepository,
-		userClient:                   userClient,
+		unitOfWork:                   deps.UnitOfWork,
+		paymentInformationRepository: repos.PaymentInformations,
+		paymentAttemptRepository:     repos.PaymentAttempts,
+		paymentRepository:            repos.Payments,
+		refundRepository:             repos.Refunds,
+		paymentLineItemRepository:    repos.PaymentLineItems,
+		coverageRuleRepository:       repos.CoverageRules,
+		payerReceivableRepository:    repos.PayerReceivables,
+		paymentDocumentRepository:    repos.PaymentDocuments,
+		auditLogRepository:           repos.AuditLogs,
+		riskAssessmentRepository:     repos.RiskAssessments,
+		userClient:                   deps.Users,
+		payableRegistry:              deps.Payables,
+		documentRenderer:             deps.Renderer,
+		seller:                       deps.Seller,
+		revocations:                  deps.Revocations,
+		riskConfig:                   deps.Risk,
+		exportJobs:                   deps.ExportJobs,
+		attemptEvents:                deps.AttemptEvents,
 	}
 }
 
 // CreatePaymentInformation creates a new payment information record
 func (s *PaymentService) CreatePaymentInfo(ctx context.Context, body dto.CreatePaymentInfoRequestDto) (*dto.CreatePaymentInfoResponseDto, error) {
 	patientID := contextUtils.GetUserId(ctx)
-	role := contextUtils.GetRole(ctx)
-
-	if role != "patient" {
-		return nil, apperr.New(apperr.CodeForbidden, "only patients can create payment information", nil)
-	}
 
 	detailsJSON, err := json.Marshal(body.Details)
 	if err != nil {
-		return nil, apperr.New(apperr.CodeBadRequest, "invalid payment details", err)
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidPaymentDetails, err)
 	}
 
 	paymentInfo := &models.PaymentInformation{
@@ -64,7 +105,11 @@ func (s *PaymentService) CreatePaymentInfo(ctx context.Context, body dto.CreateP
 	}
 
 	if err := s.paymentInformationRepository.Create(ctx, paymentInfo); err != nil {
-		return nil, apperr.New(apperr.CodeInternal, "failed to create payment info", err)
+		// unique_payment_profile allows one saved method of each type
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, apperr.New(apperr.CodeConflict, i18n.PaymentMethodSaved, nil)
+		}
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedCreatePaymentInfo, err)
 	}
 
 	return &dto.CreatePaymentInfoResponseDto{
@@ -76,15 +121,19 @@ func (s *PaymentService) CreatePaymentInfo(ctx context.Context, body dto.CreateP
 func (s *PaymentService) GetPaymentInfoByID(ctx context.Context, id string) (*dto.GetPaymentInfoByIDResponseDto, error) {
 	paymentInfoID, err := uuid.Parse(id)
 	if err != nil {
-		return nil, apperr.New(apperr.CodeBadRequest, "invalid payment information ID format", err)
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidPaymentInfoID, err)
 	}
 
 	paymentInfo, err := s.paymentInformationRepository.FindByID(ctx, paymentInfoID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, apperr.New(apperr.CodeNotFound, "payment information not found", nil)
+			return nil, apperr.New(apperr.CodeNotFound, i18n.PaymentInfoNotFound, nil)
 		}
-		return nil, apperr.New(apperr.CodeInternal, "failed to retrieve payment information", err)
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedRetrievePaymentInfo, err)
+	}
+	// someone else's card is not there as far as the caller can tell
+	if !ownedByCaller(ctx, paymentInfo.UserID) {
+		return nil, apperr.New(apperr.CodeNotFound, i18n.PaymentInfoNotFound, nil)
 	}
 
 	return &dto.GetPaymentInfoByIDResponseDto{
@@ -97,13 +146,13 @@ func (s *PaymentService) GetPaymentInfoByMethod(ctx context.Context, method stri
 	paymentInfos, err := s.paymentInformationRepository.FindByUserIDAndType(ctx, utils.StringToUUIDv7(userID), method)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, apperr.New(apperr.CodeNotFound, "payment information not found", nil)
+			return nil, apperr.New(apperr.CodeNotFound, i18n.PaymentInfoNotFound, nil)
 		}
-		return nil, apperr.New(apperr.CodeInternal, "failed to retrieve payment information", err)
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedRetrievePaymentInfo, err)
 	}
 
 	if len(paymentInfos) == 0 {
-		return nil, apperr.New(apperr.CodeNotFound, "payment information not found", nil)
+		return nil, apperr.New(apperr.CodeNotFound, i18n.PaymentInfoNotFound, nil)
 	}
 
 	// For simplicity, return the first matching payment info
@@ -112,15 +161,31 @@ func (s *PaymentService) GetPaymentInfoByMethod(ctx context.Context, method stri
 	}, nil
 }
 
-// GetPaymentInformationByUserID retrieves all payment information for a user
-func (s *PaymentService) GetAllPaymentInfos(ctx context.Context) (*dto.GetAllPaymentInfosResponseDto, error) {
-	paymentInfos, err := s.paymentInformationRepository.FindAll(ctx)
+// GetAllPaymentInfos retrieves one page of payment information
+func (s *PaymentService) GetAllPaymentInfos(ctx context.Context, query dto.GetAllPaymentInfosRequestDto) (*dto.GetAllPaymentInfosResponseDto, error) {
+	page, err := parsePage(query.Limit, query.Cursor, query.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := repository.PaymentInformationFilter{
+		Method: models.PaymentMethod(query.Method),
+	}
+	if filter.Method != "" && !isValidPaymentMethod(filter.Method) {
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidPaymentMethod, nil)
+	}
+	if filter.UserID, err = parseOptionalID("user ID", query.UserID); err != nil {
+		return nil, err
+	}
+
+	paymentInfos, next, err := s.paymentInformationRepository.FindPage(ctx, filter, page)
 	if err != nil {
-		return nil, apperr.New(apperr.CodeInternal, "Failed to retrieve payment information", err)
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedRetrievePaymentInfo, err)
 	}
 
 	return &dto.GetAllPaymentInfosResponseDto{
 		DeliveryInfos: dto.ToPaymentInfoList(paymentInfos),
+		NextCursor:    nextCursor(next),
 	}, nil
 }
 
@@ -128,16 +193,22 @@ func (s *PaymentService) GetAllPaymentInfos(ctx context.Context) (*dto.GetAllPay
 func (s *PaymentService) UpdatePaymentInfo(ctx context.Context, body dto.UpdatePaymentInfoRequestDto) (*dto.UpdatePaymentInfoResponseDto, error) {
 	paymentID, err := uuid.Parse(body.ID)
 	if err != nil {
-		return nil, apperr.New(apperr.CodeBadRequest, "invalid payment ID format", err)
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidPaymentID, err)
+	}
+	if body.Version == 0 && !body.AnyVersion {
+		return nil, apperr.New(apperr.CodePreconditionRequired, i18n.VersionRequired, nil)
 	}
 
 	// Get existing payment info
 	existingPaymentInfo, err := s.paymentInformationRepository.FindByID(ctx, paymentID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, apperr.New(apperr.CodeNotFound, "payment information not found", nil)
+			return nil, apperr.New(apperr.CodeNotFound, i18n.PaymentInfoNotFound, nil)
 		}
-		return nil, apperr.New(apperr.CodeInternal, "Failed to retrieve payment information", err)
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedRetrievePaymentInfo, err)
+	}
+	if !ownedByCaller(ctx, existingPaymentInfo.UserID) {
+		return nil, apperr.New(apperr.CodeNotFound, i18n.PaymentInfoNotFound, nil)
 	}
 
 	// Update fields
@@ -147,12 +218,21 @@ func (s *PaymentService) UpdatePaymentInfo(ctx context.Context, body dto.UpdateP
 	if body.Details != nil {
 		existingPaymentInfo.Details = body.Details
 	}
-	existingPaymentInfo.Version += 1
+	if !body.AnyVersion {
+		existingPaymentInfo.Version = body.Version
+	}
 
-	// Save updates
+	// Save updates; the repository bumps the version
 	err = s.paymentInformationRepository.Update(ctx, existingPaymentInfo)
 	if err != nil {
-		return nil, apperr.New(apperr.CodeInternal, "Failed to update payment information", err)
+		if errors.Is(err, repository.ErrVersionConflict) {
+			return nil, apperr.New(apperr.CodeConflict, i18n.PaymentInfoChanged, nil)
+		}
+		// switching to a type the patient already saved
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return nil, apperr.New(apperr.CodeConflict, i18n.PaymentMethodSaved, nil)
+		}
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedUpdatePaymentInfo, err)
 	}
 
 	return &dto.UpdatePaymentInfoResponseDto{
@@ -169,20 +249,23 @@ func (s *PaymentService) UpdatePaymentInfo(ctx context.Context, body dto.UpdateP
 func (s *PaymentService) DeletePaymentInfo(ctx context.Context, id string) (*dto.DeletePaymentInfoResponseDto, error) {
 	paymentInfoID, err := uuid.Parse(id)
 	if err != nil {
-		return nil, apperr.New(apperr.CodeBadRequest, "invalid payment information ID format", err)
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidPaymentInfoID, err)
 	}
 
-	_, err = s.paymentInformationRepository.FindByID(ctx, paymentInfoID)
+	paymentInfo, err := s.paymentInformationRepository.FindByID(ctx, paymentInfoID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, apperr.New(apperr.CodeNotFound, "payment information not found", nil)
+			return nil, apperr.New(apperr.CodeNotFound, i18n.PaymentInfoNotFound, nil)
 		}
-		return nil, apperr.New(apperr.CodeInternal, "failed to retrieve payment information", err)
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedRetrievePaymentInfo, err)
+	}
+	if !ownedByCaller(ctx, paymentInfo.UserID) {
+		return nil, apperr.New(apperr.CodeNotFound, i18n.PaymentInfoNotFound, nil)
 	}
 
 	err = s.paymentInformationRepository.Delete(ctx, paymentInfoID)
 	if err != nil {
-		return nil, apperr.New(apperr.CodeInternal, "failed to delete payment information", err)
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedDeletePaymentInfo, err)
 	}
 
 	return &dto.DeletePaymentInfoResponseDto{
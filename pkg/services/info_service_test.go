@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"payment-service/pkg/apperr"
+	"payment-service/pkg/dto"
+	"payment-service/pkg/models"
+	"payment-service/pkg/utils"
+)
+
+func TestCreatePaymentInfo(t *testing.T) {
+	tests := []struct {
+		name string
+		body dto.CreatePaymentInfoRequestDto
+	}{
+		{"credit card", dto.CreatePaymentInfoRequestDto{PaymentMethod: models.PaymentMethodCreditCard, Details: cardDetails("4111111111111111")}},
+		{"promptpay", dto.CreatePaymentInfoRequestDto{PaymentMethod: models.PaymentMethodPromptPay, Details: []byte(`{"promptpay_id":"0812345678","promptpay_type":"phone"}`)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			got, err := f.service.CreatePaymentInfo(asPatient(patientID), tt.body)
+			wantCode(t, err, 0)
+			if got.PaymentInfo.UserID != patientID.String() || got.PaymentInfo.PaymentMethod != tt.body.PaymentMethod || got.PaymentInfo.Version != 1 {
+				t.Fatalf("got %+v", got.PaymentInfo)
+			}
+
+			stored, err := f.service.GetPaymentInfoByID(asPatient(patientID), got.PaymentInfo.ID)
+			wantCode(t, err, 0)
+			if string(rawDetails(&models.PaymentInformation{Details: stored.PaymentInfo.Details})) != string(tt.body.Details) {
+				t.Fatalf("stored details %s", stored.PaymentInfo.Details)
+			}
+		})
+	}
+}
+
+func TestCreatePaymentInfoTypeSaved(t *testing.T) {
+	f := newFixture(t)
+	f.card(patientID, "4111111111111111")
+
+	_, err := f.service.CreatePaymentInfo(asPatient(patientID), dto.CreatePaymentInfoRequestDto{
+		PaymentMethod: models.PaymentMethodCreditCard,
+		Details:       cardDetails("5500000000000004"),
+	})
+	wantCode(t, err, apperr.CodeConflict)
+}
+
+func TestGetPaymentInfoByID(t *testing.T) {
+	f := newFixture(t)
+	info := f.card(patientID, "4111111111111111")
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		id   string
+		code apperr.Code
+	}{
+		{"found", asPatient(patientID), info.ID.String(), 0},
+		{"admin", asAdmin(), info.ID.String(), 0},
+		{"other patient", asPatient(otherPatientID), info.ID.String(), apperr.CodeNotFound},
+		{"malformed", asPatient(patientID), "not-a-uuid", apperr.CodeBadRequest},
+		{"missing", asPatient(patientID), utils.GenerateUUIDv7().String(), apperr.CodeNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := f.service.GetPaymentInfoByID(tt.ctx, tt.id)
+			wantCode(t, err, tt.code)
+			if err == nil && got.PaymentInfo.ID != tt.id {
+				t.Fatalf("got %s, want %s", got.PaymentInfo.ID, tt.id)
+			}
+		})
+	}
+}
+
+func TestGetPaymentInfoByMethod(t *testing.T) {
+	f := newFixture(t)
+	info := f.card(patientID, "4111111111111111")
+	f.promptPay(otherPatientID)
+
+	tests := []struct {
+		name   string
+		user   string
+		method string
+		code   apperr.Code
+	}{
+		{"own card", patientID.String(), string(models.PaymentMethodCreditCard), 0},
+		{"no promptpay", patientID.String(), string(models.PaymentMethodPromptPay), apperr.CodeNotFound},
+		{"other user's card", otherPatientID.String(), string(models.PaymentMethodCreditCard), apperr.CodeNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := f.service.GetPaymentInfoByMethod(asPatient(utils.StringToUUIDv7(tt.user)), tt.method)
+			wantCode(t, err, tt.code)
+			if err == nil && got.PaymentInfo.ID != info.ID.String() {
+				t.Fatalf("got %s, want %s", got.PaymentInfo.ID, info.ID)
+			}
+		})
+	}
+}
+
+func TestGetAllPaymentInfos(t *testing.T) {
+	f := newFixture(t)
+	first := f.card(patientID, "4111111111111111")
+	second := f.promptPay(patientID)
+	third := f.card(otherPatientID, "5500000000000004")
+
+	tests := []struct {
+		name  string
+		query dto.GetAllPaymentInfosRequestDto
+		want  []string
+		code  apperr.Code
+	}{
+		{"newest first", dto.GetAllPaymentInfosRequestDto{}, []string{third.ID.String(), second.ID.String(), first.ID.String()}, 0},
+		{"oldest first", dto.GetAllPaymentInfosRequestDto{Sort: "asc"}, []string{first.ID.String(), second.ID.String(), third.ID.String()}, 0},
+		{"by user", dto.GetAllPaymentInfosRequestDto{Sort: "asc", UserID: patientID.String()}, []string{first.ID.String(), second.ID.String()}, 0},
+		{"by method", dto.GetAllPaymentInfosRequestDto{Sort: "asc", Method: string(models.PaymentMethodCreditCard)}, []string{first.ID.String(), third.ID.String()}, 0},
+		{"after cursor", dto.GetAllPaymentInfosRequestDto{Sort: "asc", Cursor: first.ID.String()}, []string{second.ID.String(), third.ID.String()}, 0},
+		{"bad method", dto.GetAllPaymentInfosRequestDto{Method: "cash"}, nil, apperr.CodeBadRequest},
+		{"bad user", dto.GetAllPaymentInfosRequestDto{UserID: "nobody"}, nil, apperr.CodeBadRequest},
+		{"bad cursor", dto.GetAllPaymentInfosRequestDto{Cursor: "nowhere"}, nil, apperr.CodeBadRequest},
+		{"bad sort", dto.GetAllPaymentInfosRequestDto{Sort: "sideways"}, nil, apperr.CodeBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := f.service.GetAllPaymentInfos(asAdmin(), tt.query)
+			wantCode(t, err, tt.code)
+			if err != nil {
+				return
+			}
+			var ids []string
+			for _, info := range got.DeliveryInfos {
+				ids = append(ids, info.ID)
+			}
+			if !equalStrings(ids, tt.want) {
+				t.Fatalf("got %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetAllPaymentInfosPages(t *testing.T) {
+	f := newFixture(t)
+	first := f.card(patientID, "4111111111111111")
+	second := f.promptPay(patientID)
+
+	got, err := f.service.GetAllPaymentInfos(asAdmin(), dto.GetAllPaymentInfosRequestDto{Sort: "asc", Limit: 1})
+	wantCode(t, err, 0)
+	if len(got.DeliveryInfos) != 1 || got.DeliveryInfos[0].ID != first.ID.String() || got.NextCursor == nil {
+		t.Fatalf("first page %+v", got)
+	}
+
+	got, err = f.service.GetAllPaymentInfos(asAdmin(), dto.GetAllPaymentInfosRequestDto{Sort: "asc", Limit: 1, Cursor: *got.NextCursor})
+	wantCode(t, err, 0)
+	if len(got.DeliveryInfos) != 1 || got.DeliveryInfos[0].ID != second.ID.String() || got.NextCursor != nil {
+		t.Fatalf("last page %+v", got)
+	}
+}
+
+func TestUpdatePaymentInfo(t *testing.T) {
+	card := func(f *fixture) string { return f.card(patientID, "4111111111111111").ID.String() }
+
+	tests := []struct {
+		name string
+		id   func(f *fixture) string
+		// sent is the version the request is based on; any stands for
+		// If-Match: *
+		sent    int
+		any     bool
+		code    apperr.Code
+		version int
+	}{
+		{"bumps version", card, 1, false, 0, 2},
+		{"any version", card, 0, true, 0, 2},
+		{"no version", card, 0, false, apperr.CodePreconditionRequired, 0},
+		{"malformed", func(f *fixture) string { return "not-a-uuid" }, 1, false, apperr.CodeBadRequest, 0},
+		{"missing", func(f *fixture) string { return utils.GenerateUUIDv7().String() }, 1, false, apperr.CodeNotFound, 0},
+		{"any version of a missing one", func(f *fixture) string { return utils.GenerateUUIDv7().String() }, 0, true, apperr.CodeNotFound, 0},
+		{"other patient's card", func(f *fixture) string { return f.card(otherPatientID, "4111111111111111").ID.String() }, 1, false, apperr.CodeNotFound, 0},
+		{"another patient at the next version", func(f *fixture) string {
+			info := f.card(patientID, "4111111111111111")
+			f.paymentInfo(otherPatientID, models.PaymentMethodCreditCard, cardDetails("5500000000000004"), 2)
+			return info.ID.String()
+		}, 1, false, 0, 2},
+		{"switching to a saved type", func(f *fixture) string {
+			f.card(patientID, "5500000000000004")
+			return f.promptPay(patientID).ID.String()
+		}, 1, false, apperr.CodeConflict, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			got, err := f.service.UpdatePaymentInfo(asPatient(patientID), dto.UpdatePaymentInfoRequestDto{
+				ID:            tt.id(f),
+				PaymentMethod: models.PaymentMethodCreditCard,
+				Details:       cardDetails("4000000000000002"),
+				Version:       tt.sent,
+				AnyVersion:    tt.any,
+			})
+			wantCode(t, err, tt.code)
+			if err == nil && (got.Version != tt.version || string(got.Details) != string(cardDetails("4000000000000002"))) {
+				t.Fatalf("got %+v", got)
+			}
+		})
+	}
+}
+
+func TestUpdatePaymentInfoVersion(t *testing.T) {
+	f := newFixture(t)
+	info := f.card(patientID, "4111111111111111")
+	update := func(version int) (*dto.UpdatePaymentInfoResponseDto, error) {
+		return f.service.UpdatePaymentInfo(asPatient(patientID), dto.UpdatePaymentInfoRequestDto{
+			ID:            info.ID.String(),
+			PaymentMethod: models.PaymentMethodCreditCard,
+			Details:       cardDetails("4000000000000002"),
+			Version:       version,
+		})
+	}
+
+	got, err := update(1)
+	wantCode(t, err, 0)
+	if got.Version != 2 {
+		t.Fatalf("got version %d, want 2", got.Version)
+	}
+	// a second editor who also read version 1 loses
+	_, err = update(1)
+	wantCode(t, err, apperr.CodeConflict)
+
+	stored, err := f.service.GetPaymentInfoByID(asPatient(patientID), info.ID.String())
+	wantCode(t, err, 0)
+	if stored.PaymentInfo.Version != 2 {
+		t.Fatalf("stored version %d, want 2", stored.PaymentInfo.Version)
+	}
+}
+
+func TestDeletePaymentInfo(t *testing.T) {
+	f := newFixture(t)
+	info := f.card(patientID, "4111111111111111")
+	attempt := f.attempt(f.order(patientID, 100), info, models.PaymentStatusSuccess)
+	other := f.card(otherPatientID, "5500000000000004")
+
+	tests := []struct {
+		name string
+		id   string
+		code apperr.Code
+	}{
+		{"malformed", "not-a-uuid", apperr.CodeBadRequest},
+		{"other patient's card", other.ID.String(), apperr.CodeNotFound},
+		{"existing", info.ID.String(), 0},
+		{"already deleted", info.ID.String(), apperr.CodeNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.service.DeletePaymentInfo(asPatient(patientID), tt.id)
+			wantCode(t, err, tt.code)
+		})
+	}
+
+	// attempts outlive the payment information they were made with
+	got, err := f.service.GetPaymentAttempt(asAdmin(), attempt.ID.String())
+	wantCode(t, err, 0)
+	if got.PaymentInfoID != "" {
+		t.Fatalf("attempt still points at %s", got.PaymentInfoID)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
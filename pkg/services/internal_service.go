@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"payment-service/pkg/apperr"
+	"payment-service/pkg/dto"
+	"payment-service/pkg/i18n"
+	"payment-service/pkg/models"
+	"payment-service/pkg/repository"
+	"payment-service/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// maxPayableStatusBatch caps how many payables one batch lookup may name.
+const maxPayableStatusBatch = 100
+
+// GetPayableStatus reports the payment state of a payable to the service
+// that owns it.
+func (s *PaymentService) GetPayableStatus(ctx context.Context, payableType string, payableID string) (*dto.PayableStatusResponseDto, error) {
+	if payableType == "" {
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.PayableTypeRequired, nil)
+	}
+
+	id := utils.StringToUUIDv7(payableID)
+	if id == uuid.Nil {
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidPayableID, nil)
+	}
+
+	attempts, err := s.paymentAttemptRepository.FindByPayable(ctx, models.PayableType(payableType), id)
+	if err != nil {
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedRetrieveAttempts, err)
+	}
+
+	payments, err := s.paymentRepository.FindByPayable(ctx, models.PayableType(payableType), id)
+	if err != nil {
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedRetrievePayments, err)
+	}
+
+	var totalPaid int64
+	for i := range payments {
+		totalPaid += utils.ToSatang(payments[i].Amount)
+	}
+
+	response := &dto.PayableStatusResponseDto{
+		PayableType:  models.PayableType(payableType),
+		PayableID:    id.String(),
+		HasPayments:  len(payments) > 0,
+		TotalPaid:    utils.FromSatang(totalPaid),
+		PaymentCount: len(payments),
+		AttemptCount: len(attempts),
+	}
+
+	var latest *models.PaymentAttempt
+	for i := range attempts {
+		if latest == nil || attempts[i].CreatedAt.After(latest.CreatedAt) {
+			latest = &attempts[i]
+		}
+	}
+	if latest != nil {
+		response.LatestAttemptStatus = latest.Status
+	}
+
+	return response, nil
+}
+
+// GetPayableStatuses reports the payment state of many payables at once,
+// for services that list them, with a single aggregate query.
+func (s *PaymentService) GetPayableStatuses(ctx context.Context, body dto.BatchPayableStatusRequestDto) (*dto.BatchPayableStatusResponseDto, error) {
+	if len(body.Payables) == 0 {
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.PayablesRequired, nil)
+	}
+	if len(body.Payables) > maxPayableStatusBatch {
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.TooManyPayables, nil, maxPayableStatusBatch)
+	}
+
+	refs := make([]repository.PayableRef, len(body.Payables))
+	for i, payable := range body.Payables {
+		if !s.payableRegistry.Supports(payable.PayableType) {
+			return nil, apperr.New(apperr.CodeBadRequest, i18n.UnsupportedPayableOf, nil, payable.PayableType)
+		}
+		id := utils.StringToUUIDv7(payable.PayableID)
+		if id == uuid.Nil {
+			return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidPayableIDOf, nil, payable.PayableID)
+		}
+		refs[i] = repository.PayableRef{Type: payable.PayableType, ID: id}
+	}
+
+	summaries, err := s.paymentRepository.SummarizeByPayables(ctx, refs)
+	if err != nil {
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedRetrievePaymentStatus, err)
+	}
+	byRef := make(map[repository.PayableRef]repository.PayableSummary, len(summaries))
+	for _, summary := range summaries {
+		byRef[repository.PayableRef{Type: summary.PayableType, ID: summary.PayableID}] = summary
+	}
+
+	statuses := make([]dto.PayableStatusResponseDto, len(refs))
+	for i, ref := range refs {
+		summary := byRef[ref]
+		statuses[i] = dto.PayableStatusResponseDto{
+			PayableType:         ref.Type,
+			PayableID:           ref.ID.String(),
+			HasPayments:         summary.PaymentCount > 0,
+			TotalPaid:           utils.FromSatang(utils.ToSatang(summary.TotalPaid)),
+			PaymentCount:        summary.PaymentCount,
+			AttemptCount:        summary.AttemptCount,
+			LatestAttemptStatus: summary.LatestAttemptStatus,
+		}
+	}
+
+	return &dto.BatchPayableStatusResponseDto{
+		Statuses: statuses,
+	}, nil
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"payment-service/pkg/apperr"
+	contextUtils "payment-service/pkg/context"
+	"payment-service/pkg/dto"
+	"payment-service/pkg/i18n"
+	"payment-service/pkg/models"
+	"payment-service/pkg/repository"
+	"payment-service/pkg/utils"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// receiptPath is where a patient downloads the receipt of a payment.
+const receiptPath = "/api/payment/v1/%s/receipt"
+
+// GetMyPayments lists the current patient's payments, newest first, with
+// what the history screen shows next to each: the payment method, masked,
+// the refund status, the receipt link and the doctor.
+func (s *PaymentService) GetMyPayments(ctx context.Context, query dto.GetMyPaymentsRequestDto) (*dto.GetMyPaymentsResponseDto, error) {
+	userID := utils.StringToUUIDv7(contextUtils.GetUserId(ctx))
+	if userID == uuid.Nil {
+		return nil, apperr.New(apperr.CodeUnauthorized, i18n.InvalidUserID, nil)
+	}
+
+	page, err := parsePage(query.Limit, query.Cursor, "desc")
+	if err != nil {
+		return nil, err
+	}
+
+	filter := repository.PaymentFilter{UserID: &userID}
+	if query.Year != 0 {
+		if query.Year < 2000 || query.Year > 9999 {
+			return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidYear, nil)
+		}
+		// years run in local time, Asia/Bangkok
+		from := time.Date(query.Year, time.January, 1, 0, 0, 0, 0, time.Local)
+		to := from.AddDate(1, 0, 0)
+		filter.PaidFrom, filter.PaidTo = &from, &to
+	}
+
+	payments, next, err := s.paymentRepository.FindPage(ctx, filter, page)
+	if err != nil {
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedRetrievePayments, err)
+	}
+
+	paymentInfos, err := s.paymentInfosOf(ctx, payments)
+	if err != nil {
+		return nil, err
+	}
+	doctors := s.doctorsOf(ctx, payments)
+
+	result := make([]dto.MyPaymentDto, len(payments))
+	for i := range payments {
+		payment := &payments[i]
+		item := dto.MyPaymentDto{
+			PaymentID:     payment.ID.String(),
+			PayableType:   payment.PayableType,
+			PayableID:     payment.PayableID.String(),
+			Amount:        payment.Amount,
+			PatientAmount: payment.PatientAmount,
+			PaidAt:        payment.PaidAt.Format(time.RFC3339),
+			// this service does not record refunds yet
+			RefundStatus: dto.RefundStatusNone,
+			ReceiptURL:   fmt.Sprintf(receiptPath, payment.ID),
+		}
+		if info, ok := paymentInfos[payment.AttemptID]; ok {
+			item.PaymentMethod = info.Type
+			item.MaskedPaymentMethod = maskPaymentInfo(info)
+		}
+		if doctor, ok := doctors[payableKey(payment)]; ok {
+			item.DoctorID = doctor.id
+			item.DoctorName = doctor.name
+		}
+		result[i] = item
+	}
+
+	return &dto.GetMyPaymentsResponseDto{
+		Payments:   result,
+		NextCursor: nextCursor(next),
+	}, nil
+}
+
+// paymentInfosOf maps the attempt of each payment to the payment
+// information it was made with, in two queries for the whole page.
+func (s *PaymentService) paymentInfosOf(ctx context.Context, payments []models.Payment) (map[uuid.UUID]*models.PaymentInformation, error) {
+	if len(payments) == 0 {
+		return nil, nil
+	}
+	attemptIDs := make([]uuid.UUID, len(payments))
+	for i := range payments {
+		attemptIDs[i] = payments[i].AttemptID
+	}
+	attempts, err := s.paymentAttemptRepository.FindByIDs(ctx, attemptIDs)
+	if err != nil {
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedRetrieveAttempts, err)
+	}
+
+	var infoIDs []uuid.UUID
+	for i := range attempts {
+		if attempts[i].PaymentInformationID != nil {
+			infoIDs = append(infoIDs, *attempts[i].PaymentInformationID)
+		}
+	}
+	if len(infoIDs) == 0 {
+		return nil, nil
+	}
+	infos, err := s.paymentInformationRepository.FindByIDs(ctx, infoIDs)
+	if err != nil {
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedRetrievePaymentInfo, err)
+	}
+	byID := make(map[uuid.UUID]*models.PaymentInformation, len(infos))
+	for i := range infos {
+		byID[infos[i].ID] = &infos[i]
+	}
+
+	byAttempt := make(map[uuid.UUID]*models.PaymentInformation, len(attempts))
+	for i := range attempts {
+		if attempts[i].PaymentInformationID == nil {
+			continue
+		}
+		if info, ok := byID[*attempts[i].PaymentInformationID]; ok {
+			byAttempt[attempts[i].ID] = info
+		}
+	}
+	return byAttempt, nil
+}
+
+type doctorRef struct {
+	id   string
+	name string
+}
+
+// doctorsOf maps each payable of payments to its doctor. Distinct payables
+// are resolved together and all doctor names come from one batch call. A
+// payable or name that cannot be looked up only leaves that doctor out, so
+// the history still shows.
+func (s *PaymentService) doctorsOf(ctx context.Context, payments []models.Payment) map[repository.PayableRef]doctorRef {
+	byType := make(map[models.PayableType][]uuid.UUID)
+	seenPayable := make(map[repository.PayableRef]bool)
+	for i := range payments {
+		key := payableKey(&payments[i])
+		if !seenPayable[key] {
+			seenPayable[key] = true
+			byType[key.Type] = append(byType[key.Type], key.ID)
+		}
+	}
+
+	doctorIDs := make(map[repository.PayableRef]string)
+	var ids []string
+	seenDoctor := make(map[string]bool)
+	for payableType, payableIDs := range byType {
+		resolved, failed := s.payableRegistry.ResolveMany(ctx, payableType, payableIDs)
+		for id, err := range failed {
+			log.Printf("payment history: cannot resolve %s %s: %v", payableType, id, err)
+		}
+		for id, p := range resolved {
+			if p.DoctorID == nil {
+				continue
+			}
+			doctorID := p.DoctorID.String()
+			doctorIDs[repository.PayableRef{Type: payableType, ID: id}] = doctorID
+			if !seenDoctor[doctorID] {
+				seenDoctor[doctorID] = true
+				ids = append(ids, doctorID)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	names := make(map[string]string, len(ids))
+	profiles, err := s.userClient.GetDoctorByIds(ctx, ids)
+	if err != nil {
+		log.Printf("payment history: cannot look up doctors: %v", err)
+	} else {
+		for _, profile := range *profiles {
+			names[profile.ID] = strings.TrimSpace(profile.FirstName + " " + profile.LastName)
+		}
+	}
+
+	doctors := make(map[repository.PayableRef]doctorRef, len(doctorIDs))
+	for key, id := range doctorIDs {
+		doctors[key] = doctorRef{id: id, name: names[id]}
+	}
+	return doctors
+}
+
+func payableKey(payment *models.Payment) repository.PayableRef {
+	return repository.PayableRef{Type: payment.PayableType, ID: payment.PayableID}
+}
+
+// maskPaymentInfo shows only the last four characters of a card number or
+// PromptPay ID.
+func maskPaymentInfo(info *models.PaymentInformation) string {
+	if card, ok := creditCardDetails(info); ok {
+		return maskTail(card.CardNumber)
+	}
+	if promptPay, ok := promptPayDetails(info); ok {
+		return maskTail(promptPay.PromptPayID)
+	}
+	return ""
+}
+
+func maskTail(s string) string {
+	s = strings.NewReplacer(" ", "", "-", "").Replace(s)
+	if len(s) <= 4 {
+		return strings.Repeat("*", len(s))
+	}
+	return "**** " + s[len(s)-4:]
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"payment-service/pkg/apperr"
+	"payment-service/pkg/constants"
+	contextUtils "payment-service/pkg/context"
+	"payment-service/pkg/dto"
+	"payment-service/pkg/i18n"
+	"payment-service/pkg/models"
+	"payment-service/pkg/utils"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// GetOrderPayments lists every attempt and payment of an order with the
+// totals and payment status derived from them, so that the order service
+// and the frontend read the same numbers.
+func (s *PaymentService) GetOrderPayments(ctx context.Context, orderID string) (*dto.GetOrderPaymentsResponseDto, error) {
+	id := utils.StringToUUIDv7(orderID)
+	if id == uuid.Nil {
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidOrderID, nil)
+	}
+
+	order, err := s.payableRegistry.Resolve(ctx, models.PayableTypeOrder, id)
+	if err != nil {
+		return nil, apperr.Propagate(err, i18n.FailedResolveOrder)
+	}
+	// admins, and other services over gRPC or the internal route, may read
+	// any order; to anyone else another user's order does not exist
+	role := contextUtils.GetRole(ctx)
+	if role != constants.RoleAdmin && role != constants.RoleService && order.OwnerID != utils.StringToUUIDv7(contextUtils.GetUserId(ctx)) {
+		return nil, apperr.New(apperr.CodeNotFound, i18n.OrderNotFound, nil)
+	}
+
+	attempts, err := s.paymentAttemptRepository.FindByOrderID(ctx, id)
+	if err != nil {
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedRetrieveAttempts, err)
+	}
+	sort.Slice(attempts, func(i, j int) bool { return attempts[i].CreatedAt.Before(attempts[j].CreatedAt) })
+
+	payments, err := s.paymentRepository.FindByOrderID(ctx, id)
+	if err != nil {
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedRetrievePayments, err)
+	}
+	sort.Slice(payments, func(i, j int) bool { return payments[i].PaidAt.Before(payments[j].PaidAt) })
+
+	paymentIDs := make([]uuid.UUID, len(payments))
+	var paid int64
+	for i := range payments {
+		paymentIDs[i] = payments[i].ID
+		paid += utils.ToSatang(payments[i].Amount)
+	}
+	refunds, err := s.refundRepository.FindByPaymentIDs(ctx, paymentIDs)
+	if err != nil {
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedRetrieveRefunds, err)
+	}
+	var refunded int64
+	for i := range refunds {
+		refunded += utils.ToSatang(refunds[i].Amount)
+	}
+	due := utils.ToSatang(order.AmountDue)
+
+	outstanding := due - (paid - refunded)
+	if outstanding < 0 {
+		outstanding = 0
+	}
+
+	return &dto.GetOrderPaymentsResponseDto{
+		OrderID:            id.String(),
+		Status:             orderPaymentStatus(due, paid, refunded),
+		AmountDue:          utils.FromSatang(due),
+		TotalPaid:          utils.FromSatang(paid),
+		TotalRefunded:      utils.FromSatang(refunded),
+		OutstandingBalance: utils.FromSatang(outstanding),
+		Attempts:           dto.ToPaymentAttemptDtoList(attempts),
+		Payments:           dto.ToPaymentDtoList(payments),
+	}, nil
+}
+
+// orderPaymentStatus derives the order status from amounts in satang. An
+// order counts as refunded once refunds cancel out everything paid.
+func orderPaymentStatus(due, paid, refunded int64) dto.OrderPaymentStatus {
+	net := paid - refunded
+	switch {
+	case refunded > 0 && net <= 0:
+		return dto.OrderPaymentStatusRefunded
+	case net <= 0:
+		return dto.OrderPaymentStatusUnpaid
+	case net < due:
+		return dto.OrderPaymentStatusPartiallyPaid
+	case net == due:
+		return dto.OrderPaymentStatusPaid
+	default:
+		return dto.OrderPaymentStatusOverpaid
+	}
+}
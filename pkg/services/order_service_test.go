@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"payment-service/pkg/apperr"
+	"payment-service/pkg/dto"
+	"payment-service/pkg/models"
+	"payment-service/pkg/utils"
+)
+
+func TestGetOrderPayments(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		paid []float64
+		// refunded is refunded from the first payment
+		refunded    []float64
+		status      dto.OrderPaymentStatus
+		outstanding float64
+		code        apperr.Code
+	}{
+		{"unpaid", asPatient(patientID), nil, nil, dto.OrderPaymentStatusUnpaid, 1000, 0},
+		{"partially paid", asPatient(patientID), []float64{400.25}, nil, dto.OrderPaymentStatusPartiallyPaid, 599.75, 0},
+		{"paid in two", asPatient(patientID), []float64{400.1, 599.9}, nil, dto.OrderPaymentStatusPaid, 0, 0},
+		{"overpaid", asPatient(patientID), []float64{1200}, nil, dto.OrderPaymentStatusOverpaid, 0, 0},
+		{"overpayment refunded", asPatient(patientID), []float64{1200}, []float64{200}, dto.OrderPaymentStatusPaid, 0, 0},
+		{"partly refunded", asPatient(patientID), []float64{400.1, 599.9}, []float64{100, 0.1}, dto.OrderPaymentStatusPartiallyPaid, 100.1, 0},
+		{"refunded", asPatient(patientID), []float64{1000}, []float64{600, 400}, dto.OrderPaymentStatusRefunded, 1000, 0},
+		{"admin reads any order", asAdmin(), []float64{1000}, nil, dto.OrderPaymentStatusPaid, 0, 0},
+		{"service reads any order", asService(), nil, nil, dto.OrderPaymentStatusUnpaid, 1000, 0},
+		{"someone else's order", asPatient(otherPatientID), nil, nil, "", 0, apperr.CodeNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			orderID := f.order(patientID, 1000)
+			info := f.card(patientID, "4111111111111111")
+			f.attempt(orderID, info, models.PaymentStatusFailed)
+			var payments []*models.Payment
+			for _, amount := range tt.paid {
+				payments = append(payments, f.payment(f.attempt(orderID, info, models.PaymentStatusSuccess), amount, time.Now()))
+			}
+			var refunded float64
+			for _, amount := range tt.refunded {
+				_, err := f.service.RefundPayment(asService(), dto.RefundPaymentRequestDto{PaymentID: payments[0].ID.String(), Amount: amount})
+				wantCode(t, err, 0)
+				refunded += amount
+			}
+
+			got, err := f.service.GetOrderPayments(tt.ctx, orderID.String())
+			wantCode(t, err, tt.code)
+			if err != nil {
+				return
+			}
+			if got.Status != tt.status || got.OutstandingBalance != tt.outstanding || got.TotalRefunded != refunded {
+				t.Fatalf("got %s with %v outstanding and %v refunded, want %s with %v and %v",
+					got.Status, got.OutstandingBalance, got.TotalRefunded, tt.status, tt.outstanding, refunded)
+			}
+			if len(got.Attempts) != len(tt.paid)+1 || len(got.Payments) != len(tt.paid) {
+				t.Fatalf("got %d attempts and %d payments", len(got.Attempts), len(got.Payments))
+			}
+		})
+	}
+}
+
+func TestGetOrderPaymentsLookup(t *testing.T) {
+	f := newFixture(t)
+
+	tests := []struct {
+		name    string
+		orderID string
+		code    apperr.Code
+	}{
+		{"malformed", "nope", apperr.CodeBadRequest},
+		{"unknown order", utils.GenerateUUIDv7().String(), apperr.CodeNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.service.GetOrderPayments(asAdmin(), tt.orderID)
+			wantCode(t, err, tt.code)
+		})
+	}
+}
+
+func TestGetPayableStatus(t *testing.T) {
+	f := newFixture(t)
+	info := f.card(patientID, "4111111111111111")
+	appointmentID := f.appointment(patientID, 800)
+	f.attempt(appointmentID, info, models.PaymentStatusFailed)
+	f.payment(f.attempt(appointmentID, info, models.PaymentStatusSuccess), 800, time.Now())
+
+	tests := []struct {
+		name        string
+		payableType string
+		payableID   string
+		want        dto.PayableStatusResponseDto
+		code        apperr.Code
+	}{
+		{"paid appointment", string(models.PayableTypeAppointment), appointmentID.String(), dto.PayableStatusResponseDto{
+			PayableType:         models.PayableTypeAppointment,
+			PayableID:           appointmentID.String(),
+			HasPayments:         true,
+			TotalPaid:           800,
+			PaymentCount:        1,
+			AttemptCount:        2,
+			LatestAttemptStatus: models.PaymentStatusSuccess,
+		}, 0},
+		{"nothing yet", string(models.PayableTypeOrder), appointmentID.String(), dto.PayableStatusResponseDto{
+			PayableType: models.PayableTypeOrder,
+			PayableID:   appointmentID.String(),
+		}, 0},
+		{"no type", "", appointmentID.String(), dto.PayableStatusResponseDto{}, apperr.CodeBadRequest},
+		{"malformed", string(models.PayableTypeOrder), "nope", dto.PayableStatusResponseDto{}, apperr.CodeBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := f.service.GetPayableStatus(asService(), tt.payableType, tt.payableID)
+			wantCode(t, err, tt.code)
+			if err == nil && *got != tt.want {
+				t.Fatalf("got %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetPayableStatuses(t *testing.T) {
+	f := newFixture(t)
+	info := f.card(patientID, "4111111111111111")
+	orderID := f.order(patientID, 300)
+	f.payment(f.attempt(orderID, info, models.PaymentStatusSuccess), 100.1, time.Now())
+	f.payment(f.attempt(orderID, info, models.PaymentStatusSuccess), 200.2, time.Now())
+	unpaidID := f.appointment(patientID, 800)
+
+	refs := func(ids ...string) []dto.PayableRefDto {
+		result := make([]dto.PayableRefDto, len(ids))
+		for i, id := range ids {
+			result[i] = dto.PayableRefDto{PayableType: models.PayableTypeOrder, PayableID: id}
+		}
+		return result
+	}
+	tooMany := make([]string, maxPayableStatusBatch+1)
+	for i := range tooMany {
+		tooMany[i] = utils.GenerateUUIDv7().String()
+	}
+
+	tests := []struct {
+		name     string
+		payables []dto.PayableRefDto
+		want     []dto.PayableStatusResponseDto
+		code     apperr.Code
+	}{
+		{"in request order", []dto.PayableRefDto{
+			{PayableType: models.PayableTypeAppointment, PayableID: unpaidID.String()},
+			{PayableType: models.PayableTypeOrder, PayableID: orderID.String()},
+		}, []dto.PayableStatusResponseDto{
+			{PayableType: models.PayableTypeAppointment, PayableID: unpaidID.String()},
+			{PayableType: models.PayableTypeOrder, PayableID: orderID.String(), HasPayments: true, TotalPaid: 300.3, PaymentCount: 2, AttemptCount: 2, LatestAttemptStatus: models.PaymentStatusSuccess},
+		}, 0},
+		{"empty", nil, nil, apperr.CodeBadRequest},
+		{"too many", refs(tooMany...), nil, apperr.CodeBadRequest},
+		{"unsupported type", []dto.PayableRefDto{{PayableType: "subscription", PayableID: orderID.String()}}, nil, apperr.CodeBadRequest},
+		{"malformed", refs("nope"), nil, apperr.CodeBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := f.service.GetPayableStatuses(asService(), dto.BatchPayableStatusRequestDto{Payables: tt.payables})
+			wantCode(t, err, tt.code)
+			if err != nil {
+				return
+			}
+			if len(got.Statuses) != len(tt.want) {
+				t.Fatalf("got %d statuses, want %d", len(got.Statuses), len(tt.want))
+			}
+			for i := range tt.want {
+				if got.Statuses[i] != tt.want[i] {
+					t.Fatalf("status %d: got %+v, want %+v", i, got.Statuses[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGetMyPayments(t *testing.T) {
+	f := newFixture(t)
+	card := f.card(patientID, "4111 1111 1111 1234")
+	promptPay := f.promptPay(patientID)
+	lastYear := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.Local)
+	thisYear := time.Date(2026, time.June, 1, 12, 0, 0, 0, time.Local)
+
+	order := f.payment(f.attempt(f.order(patientID, 500), card, models.PaymentStatusSuccess), 500, lastYear)
+	visit := f.payment(f.attempt(f.appointment(patientID, 800), promptPay, models.PaymentStatusSuccess), 800, thisYear)
+	f.payment(f.attempt(f.order(otherPatientID, 300), f.card(otherPatientID, "5500000000000004"), models.PaymentStatusSuccess), 300, thisYear)
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		query  dto.GetMyPaymentsRequestDto
+		want   []string
+		masked []string
+		code   apperr.Code
+	}{
+		{"newest first", asPatient(patientID), dto.GetMyPaymentsRequestDto{}, []string{visit.ID.String(), order.ID.String()}, []string{"**** 5678", "**** 1234"}, 0},
+		{"by year", asPatient(patientID), dto.GetMyPaymentsRequestDto{Year: 2025}, []string{order.ID.String()}, []string{"**** 1234"}, 0},
+		{"after cursor", asPatient(patientID), dto.GetMyPaymentsRequestDto{Cursor: visit.ID.String()}, []string{order.ID.String()}, []string{"**** 1234"}, 0},
+		{"nothing paid", asPatient(adminID), dto.GetMyPaymentsRequestDto{}, nil, nil, 0},
+		{"bad year", asPatient(patientID), dto.GetMyPaymentsRequestDto{Year: 99}, nil, nil, apperr.CodeBadRequest},
+		{"no user", asService(), dto.GetMyPaymentsRequestDto{}, nil, nil, apperr.CodeUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := f.service.GetMyPayments(tt.ctx, tt.query)
+			wantCode(t, err, tt.code)
+			if err != nil {
+				return
+			}
+			var ids, masked []string
+			for _, payment := range got.Payments {
+				ids = append(ids, payment.PaymentID)
+				masked = append(masked, payment.MaskedPaymentMethod)
+			}
+			if !equalStrings(ids, tt.want) || !equalStrings(masked, tt.masked) {
+				t.Fatalf("got %v %v, want %v %v", ids, masked, tt.want, tt.masked)
+			}
+		})
+	}
+
+	got, err := f.service.GetMyPayments(asPatient(patientID), dto.GetMyPaymentsRequestDto{Limit: 1})
+	wantCode(t, err, 0)
+	if got.Payments[0].DoctorID != doctorID.String() || got.Payments[0].DoctorName != "Suda Rakdee" || got.NextCursor == nil {
+		t.Fatalf("got %+v", got)
+	}
+}
+
+func TestGetMyPaymentsWithoutDoctor(t *testing.T) {
+	f := newFixture(t)
+	promptPay := f.promptPay(patientID)
+	visit := f.payment(f.attempt(f.appointment(patientID, 800), promptPay, models.PaymentStatusSuccess), 800, time.Now())
+	// the order service no longer knows this order
+	gone := f.payment(f.attempt(utils.GenerateUUIDv7(), promptPay, models.PaymentStatusSuccess), 500, time.Now().Add(-time.Hour))
+
+	got, err := f.service.GetMyPayments(asPatient(patientID), dto.GetMyPaymentsRequestDto{})
+	wantCode(t, err, 0)
+	if len(got.Payments) != 2 {
+		t.Fatalf("got %+v", got.Payments)
+	}
+	doctors := map[string]string{}
+	for _, payment := range got.Payments {
+		doctors[payment.PaymentID] = payment.DoctorName
+	}
+	if doctors[visit.ID.String()] != "Suda Rakdee" || doctors[gone.ID.String()] != "" {
+		t.Fatalf("got doctors %v", doctors)
+	}
+}
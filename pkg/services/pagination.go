@@ -0,0 +1,75 @@
+package service
+
+import (
+	"payment-service/pkg/apperr"
+	"payment-service/pkg/i18n"
+	"payment-service/pkg/models"
+	"payment-service/pkg/repository"
+	"payment-service/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+)
+
+// parsePage turns the limit, cursor and sort query params of a listing into
+// a keyset page. Listings are newest first unless sort is "asc".
+func parsePage(limit int, cursor, sort string) (repository.KeysetPage, error) {
+	page := repository.KeysetPage{Limit: limit}
+	if page.Limit <= 0 {
+		page.Limit = defaultPageLimit
+	}
+	if page.Limit > maxPageLimit {
+		page.Limit = maxPageLimit
+	}
+
+	switch sort {
+	case "", "desc":
+		page.Descending = true
+	case "asc":
+	default:
+		return page, apperr.New(apperr.CodeBadRequest, i18n.InvalidSort, nil)
+	}
+
+	if cursor != "" {
+		after := utils.StringToUUIDv7(cursor)
+		if after == uuid.Nil {
+			return page, apperr.New(apperr.CodeBadRequest, i18n.InvalidCursor, nil)
+		}
+		page.After = &after
+	}
+	return page, nil
+}
+
+// nextCursor renders the cursor a repository returned for the response.
+func nextCursor(next *uuid.UUID) *string {
+	if next == nil {
+		return nil
+	}
+	cursor := next.String()
+	return &cursor
+}
+
+// parseOptionalID parses an optional ID filter named field.
+func parseOptionalID(field, s string) (*uuid.UUID, error) {
+	if s == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidField, err, field)
+	}
+	return &id, nil
+}
+
+func isValidPaymentMethod(method models.PaymentMethod) bool {
+	switch method {
+	case models.PaymentMethodCreditCard, models.PaymentMethodPromptPay:
+		return true
+	default:
+		return false
+	}
+}
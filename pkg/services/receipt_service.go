@@ -0,0 +1,333 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"payment-service/pkg/apperr"
+	"payment-service/pkg/constants"
+	contextUtils "payment-service/pkg/context"
+	"payment-service/pkg/dto"
+	"payment-service/pkg/i18n"
+	"payment-service/pkg/models"
+	"payment-service/pkg/receipt"
+	"payment-service/pkg/repository"
+	"payment-service/pkg/utils"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetPaymentDocumentFile renders the live document of the given type for a
+// payment. Both receipts and tax invoices must have been issued first.
+func (s *PaymentService) GetPaymentDocumentFile(ctx context.Context, paymentID string, docType string) (*dto.PaymentDocumentFileDto, error) {
+	payment, err := s.findAccessiblePayment(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	documentType := models.DocumentType(docType)
+	if documentType == "" {
+		documentType = models.DocumentTypeReceipt
+	}
+	if documentType != models.DocumentTypeReceipt && documentType != models.DocumentTypeTaxInvoice {
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidDocumentType, nil)
+	}
+
+	doc, err := s.paymentDocumentRepository.FindIssued(ctx, payment.ID, documentType)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedRetrieveDocument, err)
+	}
+	if doc == nil {
+		if documentType == models.DocumentTypeTaxInvoice {
+			return nil, apperr.New(apperr.CodeNotFound, i18n.TaxInvoiceNotIssued, nil)
+		}
+		return nil, apperr.New(apperr.CodeNotFound, i18n.ReceiptNotIssued, nil)
+	}
+
+	lineItems, err := s.paymentLineItemRepository.FindByPaymentID(ctx, payment.ID)
+	if err != nil {
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedRetrieveLineItems, err)
+	}
+
+	content, err := s.documentRenderer.Render(doc, lineItems, contextUtils.GetLanguage(ctx))
+	if err != nil {
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedRenderDocument, err)
+	}
+
+	return &dto.PaymentDocumentFileDto{
+		FileName: doc.Number + ".pdf",
+		Content:  content,
+	}, nil
+}
+
+// IssueReceipt issues the receipt of a payment, made out to the patient.
+func (s *PaymentService) IssueReceipt(ctx context.Context, paymentID string) (*dto.PaymentDocumentResponseDto, error) {
+	payment, err := s.findAccessiblePayment(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.paymentDocumentRepository.FindIssued(ctx, payment.ID, models.DocumentTypeReceipt)
+	if err == nil {
+		return nil, apperr.New(apperr.CodeConflict, i18n.ReceiptExists, nil)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedRetrieveDocument, err)
+	}
+
+	doc, err := s.issueReceipt(ctx, payment)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.PaymentDocumentResponseDto{
+		Document: dto.ToPaymentDocumentDto(doc),
+	}, nil
+}
+
+func (s *PaymentService) IssueTaxInvoice(ctx context.Context, paymentID string, body dto.IssueTaxInvoiceRequestDto) (*dto.PaymentDocumentResponseDto, error) {
+	payment, err := s.findAccessiblePayment(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.paymentDocumentRepository.FindIssued(ctx, payment.ID, models.DocumentTypeTaxInvoice)
+	if err == nil {
+		return nil, apperr.New(apperr.CodeConflict, i18n.TaxInvoiceExists, nil)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedRetrieveDocument, err)
+	}
+
+	doc := s.newDocument(payment, models.DocumentTypeTaxInvoice, body.BuyerName)
+	doc.BuyerTaxID = &body.BuyerTaxID
+	doc.BuyerAddress = &body.BuyerAddress
+	doc.BuyerBranch = optionalString(body.BuyerBranch)
+
+	event := newDocumentEvent(ctx, models.DocumentActionIssued, nil)
+	if err := s.paymentDocumentRepository.Issue(ctx, doc, event); err != nil {
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedIssueTaxInvoice, err)
+	}
+
+	return &dto.PaymentDocumentResponseDto{
+		Document: dto.ToPaymentDocumentDto(doc),
+	}, nil
+}
+
+func (s *PaymentService) GetPaymentDocuments(ctx context.Context, paymentID string) (*dto.GetPaymentDocumentsResponseDto, error) {
+	payment, err := s.findAccessiblePayment(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := s.paymentDocumentRepository.FindByPaymentID(ctx, payment.ID)
+	if err != nil {
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedRetrieveDocuments, err)
+	}
+
+	return &dto.GetPaymentDocumentsResponseDto{
+		Documents: dto.ToPaymentDocumentDtoList(docs),
+	}, nil
+}
+
+// ReissueDocument voids a document and issues a corrected copy under the
+// next number of the same series.
+func (s *PaymentService) ReissueDocument(ctx context.Context, documentID string, body dto.ReissueDocumentRequestDto) (*dto.PaymentDocumentResponseDto, error) {
+	old, err := s.findIssuedDocument(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().In(receipt.Bangkok)
+	replacement := *old
+	replacement.ID = utils.GenerateUUIDv7()
+	replacement.Year = now.Year()
+	replacement.IssuedAt = now.UTC()
+	replacement.VoidReason = nil
+	replacement.VoidedAt = nil
+	replacement.Events = nil
+	if body.BuyerName != "" {
+		replacement.BuyerName = body.BuyerName
+	}
+	if body.BuyerTaxID != "" {
+		replacement.BuyerTaxID = &body.BuyerTaxID
+	}
+	if body.BuyerAddress != "" {
+		replacement.BuyerAddress = &body.BuyerAddress
+	}
+	if body.BuyerBranch != "" {
+		replacement.BuyerBranch = &body.BuyerBranch
+	}
+
+	voidReason := "reissued: " + body.Reason
+	voidEvent := newDocumentEvent(ctx, models.DocumentActionVoided, &voidReason)
+	issueEvent := newDocumentEvent(ctx, models.DocumentActionReissued, &body.Reason)
+	if err := s.paymentDocumentRepository.Reissue(ctx, old, voidEvent, &replacement, issueEvent); err != nil {
+		if errors.Is(err, repository.ErrDocumentNotIssued) {
+			return nil, apperr.New(apperr.CodeConflict, i18n.DocumentAlreadyVoided, nil)
+		}
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedReissueDocument, err)
+	}
+
+	return &dto.PaymentDocumentResponseDto{
+		Document: dto.ToPaymentDocumentDto(&replacement),
+	}, nil
+}
+
+func (s *PaymentService) VoidDocument(ctx context.Context, documentID string, body dto.VoidDocumentRequestDto) (*dto.PaymentDocumentResponseDto, error) {
+	doc, err := s.findIssuedDocument(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	event := newDocumentEvent(ctx, models.DocumentActionVoided, &body.Reason)
+	if err := s.paymentDocumentRepository.Void(ctx, doc, event); err != nil {
+		if errors.Is(err, repository.ErrDocumentNotIssued) {
+			return nil, apperr.New(apperr.CodeConflict, i18n.DocumentAlreadyVoided, nil)
+		}
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedVoidDocument, err)
+	}
+
+	return &dto.PaymentDocumentResponseDto{
+		Document: dto.ToPaymentDocumentDto(doc),
+	}, nil
+}
+
+func (s *PaymentService) issueReceipt(ctx context.Context, payment *models.Payment) (*models.PaymentDocument, error) {
+	owner, err := s.payableRegistry.Resolve(ctx, payment.PayableType, payment.PayableID)
+	if err != nil {
+		return nil, apperr.Propagate(err, i18n.FailedResolvePayable)
+	}
+
+	patients, err := s.userClient.GetPatientByIds(ctx, []string{owner.OwnerID.String()})
+	if err != nil {
+		return nil, apperr.Propagate(err, i18n.FailedRetrievePatientProfile)
+	}
+	if len(*patients) == 0 {
+		return nil, apperr.New(apperr.CodeNotFound, i18n.PatientProfileNotFound, nil)
+	}
+	patient := (*patients)[0]
+
+	doc := s.newDocument(payment, models.DocumentTypeReceipt, strings.TrimSpace(patient.FirstName+" "+patient.LastName))
+	event := newDocumentEvent(ctx, models.DocumentActionIssued, nil)
+	if err := s.paymentDocumentRepository.Issue(ctx, doc, event); err != nil {
+		// A concurrent request may have issued the receipt first; its
+		// transaction rolled ours back, so no number was consumed.
+		if _, findErr := s.paymentDocumentRepository.FindIssued(ctx, payment.ID, models.DocumentTypeReceipt); findErr == nil {
+			return nil, apperr.New(apperr.CodeConflict, i18n.ReceiptExists, nil)
+		}
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedIssueReceipt, err)
+	}
+	return doc, nil
+}
+
+// newDocument snapshots the seller and the payment amounts. VAT comes from
+// the line items when there are any; otherwise the amount is treated as
+// VAT inclusive at the configured rate.
+func (s *PaymentService) newDocument(payment *models.Payment, docType models.DocumentType, buyerName string) *models.PaymentDocument {
+	total := utils.ToSatang(payment.Amount)
+	var vat int64
+	if len(payment.LineItems) > 0 {
+		for i := range payment.LineItems {
+			vat += utils.ToSatang(payment.LineItems[i].Tax)
+		}
+	} else {
+		vat = int64(math.Round(float64(total) * s.seller.VatRate / (100 + s.seller.VatRate)))
+	}
+
+	// document years follow the Thai calendar day, not the server's
+	now := time.Now().In(receipt.Bangkok)
+	return &models.PaymentDocument{
+		ID:            utils.GenerateUUIDv7(),
+		PaymentID:     payment.ID,
+		Type:          docType,
+		BranchCode:    s.seller.BranchCode,
+		Year:          now.Year(),
+		SellerName:    s.seller.Name,
+		SellerTaxID:   s.seller.TaxID,
+		SellerAddress: s.seller.Address,
+		BuyerName:     buyerName,
+		Subtotal:      utils.FromSatang(total - vat),
+		VatRate:       s.seller.VatRate,
+		VatAmount:     utils.FromSatang(vat),
+		Total:         payment.Amount,
+		PayerAmount:   payment.PayerAmount,
+		PatientAmount: payment.PatientAmount,
+		IssuedAt:      now.UTC(),
+	}
+}
+
+// findAccessiblePayment loads a payment the caller may see: admins see
+// every payment, everyone else only payments for payables they own.
+func (s *PaymentService) findAccessiblePayment(ctx context.Context, paymentID string) (*models.Payment, error) {
+	id := utils.StringToUUIDv7(paymentID)
+	if id == uuid.Nil {
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidPaymentID, nil)
+	}
+
+	payment, err := s.paymentRepository.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperr.New(apperr.CodeNotFound, i18n.PaymentNotFound, nil)
+		}
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedRetrievePayment, err)
+	}
+
+	lineItems, err := s.paymentLineItemRepository.FindByPaymentID(ctx, id)
+	if err != nil {
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedRetrieveLineItems, err)
+	}
+	payment.LineItems = lineItems
+
+	if contextUtils.GetRole(ctx) == constants.RoleAdmin {
+		return payment, nil
+	}
+
+	owner, err := s.payableRegistry.Resolve(ctx, payment.PayableType, payment.PayableID)
+	if err != nil {
+		return nil, apperr.Propagate(err, i18n.FailedResolvePayable)
+	}
+	if owner.OwnerID != utils.StringToUUIDv7(contextUtils.GetUserId(ctx)) {
+		return nil, apperr.New(apperr.CodeForbidden, i18n.PaymentNotOwned, nil)
+	}
+	return payment, nil
+}
+
+func (s *PaymentService) findIssuedDocument(ctx context.Context, documentID string) (*models.PaymentDocument, error) {
+	id := utils.StringToUUIDv7(documentID)
+	if id == uuid.Nil {
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidDocumentID, nil)
+	}
+
+	doc, err := s.paymentDocumentRepository.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperr.New(apperr.CodeNotFound, i18n.DocumentNotFound, nil)
+		}
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedRetrieveDocument, err)
+	}
+	if doc.Status != models.DocumentStatusIssued {
+		return nil, apperr.New(apperr.CodeConflict, i18n.DocumentAlreadyVoided, nil)
+	}
+	return doc, nil
+}
+
+func newDocumentEvent(ctx context.Context, action models.DocumentAction, reason *string) *models.PaymentDocumentEvent {
+	return &models.PaymentDocumentEvent{
+		ID:        utils.GenerateUUIDv7(),
+		Action:    action,
+		ActorID:   utils.StringToUUIDv7(contextUtils.GetUserId(ctx)),
+		ActorRole: contextUtils.GetRole(ctx),
+		Reason:    reason,
+	}
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,292 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"payment-service/pkg/apperr"
+	"payment-service/pkg/dto"
+	"payment-service/pkg/models"
+	"payment-service/pkg/receipt"
+	"payment-service/pkg/utils"
+)
+
+var taxInvoice = dto.IssueTaxInvoiceRequestDto{
+	BuyerName:    "Jaidee Co., Ltd.",
+	BuyerTaxID:   "0105560000001",
+	BuyerAddress: "Chiang Mai",
+	BuyerBranch:  "00001",
+}
+
+// paidOrder stores a settled order of patientID and returns its payment.
+func (f *fixture) paidOrder(amount float64) *models.Payment {
+	return f.payment(f.attempt(f.order(patientID, amount), f.card(patientID, "4111111111111111"), models.PaymentStatusSuccess), amount, time.Now())
+}
+
+func TestGetPaymentDocumentFile(t *testing.T) {
+	skipWithoutFont(t)
+
+	issueReceipt := func(f *fixture, payment *models.Payment) {
+		_, err := f.service.IssueReceipt(asPatient(patientID), payment.ID.String())
+		wantCode(f.t, err, 0)
+	}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		docType string
+		setup   func(f *fixture, payment *models.Payment)
+		code    apperr.Code
+	}{
+		{"issued receipt", asPatient(patientID), "", issueReceipt, 0},
+		{"admin reads any receipt", asAdmin(), string(models.DocumentTypeReceipt), issueReceipt, 0},
+		{"issued tax invoice", asPatient(patientID), string(models.DocumentTypeTaxInvoice), func(f *fixture, payment *models.Payment) {
+			_, err := f.service.IssueTaxInvoice(asPatient(patientID), payment.ID.String(), taxInvoice)
+			wantCode(f.t, err, 0)
+		}, 0},
+		{"receipt not issued", asPatient(patientID), "", nil, apperr.CodeNotFound},
+		{"tax invoice not issued", asPatient(patientID), string(models.DocumentTypeTaxInvoice), nil, apperr.CodeNotFound},
+		{"unknown type", asPatient(patientID), "credit_note", nil, apperr.CodeBadRequest},
+		{"someone else's payment", asPatient(otherPatientID), "", issueReceipt, apperr.CodeForbidden},
+		{"renderer fails", asPatient(patientID), "", func(f *fixture, payment *models.Payment) {
+			issueReceipt(f, payment)
+			f.service.documentRenderer = receipt.NewRenderer("missing.ttf")
+		}, apperr.CodeInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			payment := f.paidOrder(1070)
+			if tt.setup != nil {
+				tt.setup(f, payment)
+			}
+
+			got, err := f.service.GetPaymentDocumentFile(tt.ctx, payment.ID.String(), tt.docType)
+			wantCode(t, err, tt.code)
+			if err != nil {
+				return
+			}
+			if !bytes.HasPrefix(got.Content, []byte("%PDF")) {
+				t.Fatalf("%s is not a PDF", got.FileName)
+			}
+
+			// reading a document never issues another
+			again, err := f.service.GetPaymentDocumentFile(tt.ctx, payment.ID.String(), tt.docType)
+			wantCode(t, err, 0)
+			if again.FileName != got.FileName {
+				t.Fatalf("got %s, then %s", got.FileName, again.FileName)
+			}
+		})
+	}
+}
+
+func TestIssueReceipt(t *testing.T) {
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		setup func(f *fixture, payment *models.Payment)
+		code  apperr.Code
+	}{
+		{"issued", asPatient(patientID), nil, 0},
+		{"already issued", asPatient(patientID), func(f *fixture, payment *models.Payment) {
+			_, err := f.service.IssueReceipt(asPatient(patientID), payment.ID.String())
+			wantCode(f.t, err, 0)
+		}, apperr.CodeConflict},
+		{"no patient profile", asPatient(patientID), func(f *fixture, payment *models.Payment) {
+			delete(f.users.patients, patientID.String())
+		}, apperr.CodeNotFound},
+		{"someone else's payment", asPatient(otherPatientID), nil, apperr.CodeForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			payment := f.paidOrder(1070)
+			if tt.setup != nil {
+				tt.setup(f, payment)
+			}
+
+			got, err := f.service.IssueReceipt(tt.ctx, payment.ID.String())
+			wantCode(t, err, tt.code)
+			if err != nil {
+				return
+			}
+			doc := got.Document
+			if doc.Type != models.DocumentTypeReceipt || doc.Status != models.DocumentStatusIssued || doc.BuyerName != "Somchai Jaidee" {
+				t.Fatalf("got %+v", doc)
+			}
+		})
+	}
+}
+
+func TestGetPaymentDocumentFileLookup(t *testing.T) {
+	f := newFixture(t)
+
+	tests := []struct {
+		name      string
+		paymentID string
+		code      apperr.Code
+	}{
+		{"malformed", "nope", apperr.CodeBadRequest},
+		{"missing", utils.GenerateUUIDv7().String(), apperr.CodeNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.service.GetPaymentDocumentFile(asAdmin(), tt.paymentID, "")
+			wantCode(t, err, tt.code)
+		})
+	}
+}
+
+func TestIssueTaxInvoice(t *testing.T) {
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		setup func(f *fixture, payment *models.Payment)
+		code  apperr.Code
+	}{
+		{"issued", asPatient(patientID), nil, 0},
+		{"already issued", asPatient(patientID), func(f *fixture, payment *models.Payment) {
+			_, err := f.service.IssueTaxInvoice(asPatient(patientID), payment.ID.String(), taxInvoice)
+			wantCode(f.t, err, 0)
+		}, apperr.CodeConflict},
+		{"someone else's payment", asPatient(otherPatientID), nil, apperr.CodeForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			payment := f.paidOrder(1070)
+			if tt.setup != nil {
+				tt.setup(f, payment)
+			}
+
+			got, err := f.service.IssueTaxInvoice(tt.ctx, payment.ID.String(), taxInvoice)
+			wantCode(t, err, tt.code)
+			if err != nil {
+				return
+			}
+			doc := got.Document
+			if doc.Type != models.DocumentTypeTaxInvoice || doc.Status != models.DocumentStatusIssued ||
+				doc.BuyerTaxID != taxInvoice.BuyerTaxID || doc.Subtotal != 1000 || doc.VatAmount != 70 {
+				t.Fatalf("got %+v", doc)
+			}
+		})
+	}
+}
+
+func TestGetPaymentDocuments(t *testing.T) {
+	f := newFixture(t)
+	payment := f.paidOrder(1070)
+	issued, err := f.service.IssueTaxInvoice(asPatient(patientID), payment.ID.String(), taxInvoice)
+	wantCode(t, err, 0)
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want int
+		code apperr.Code
+	}{
+		{"owner", asPatient(patientID), 1, 0},
+		{"admin", asAdmin(), 1, 0},
+		{"someone else", asPatient(otherPatientID), 0, apperr.CodeForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := f.service.GetPaymentDocuments(tt.ctx, payment.ID.String())
+			wantCode(t, err, tt.code)
+			if err != nil {
+				return
+			}
+			if len(got.Documents) != tt.want || got.Documents[0].ID != issued.Document.ID || len(got.Documents[0].Events) != 1 {
+				t.Fatalf("got %+v", got.Documents)
+			}
+		})
+	}
+}
+
+func TestReissueDocument(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  dto.ReissueDocumentRequestDto
+		setup func(f *fixture, documentID string) string
+		code  apperr.Code
+		buyer string
+	}{
+		{"keeps buyer", dto.ReissueDocumentRequestDto{Reason: "typo"}, nil, 0, taxInvoice.BuyerName},
+		{"corrects buyer", dto.ReissueDocumentRequestDto{Reason: "wrong name", BuyerName: "Jaidee Group"}, nil, 0, "Jaidee Group"},
+		{"voided", dto.ReissueDocumentRequestDto{Reason: "typo"}, func(f *fixture, documentID string) string {
+			_, err := f.service.VoidDocument(asAdmin(), documentID, dto.VoidDocumentRequestDto{Reason: "cancelled"})
+			wantCode(f.t, err, 0)
+			return documentID
+		}, apperr.CodeConflict, ""},
+		{"malformed", dto.ReissueDocumentRequestDto{Reason: "typo"}, func(f *fixture, documentID string) string {
+			return "nope"
+		}, apperr.CodeBadRequest, ""},
+		{"missing", dto.ReissueDocumentRequestDto{Reason: "typo"}, func(f *fixture, documentID string) string {
+			return utils.GenerateUUIDv7().String()
+		}, apperr.CodeNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+			payment := f.paidOrder(1070)
+			issued, err := f.service.IssueTaxInvoice(asPatient(patientID), payment.ID.String(), taxInvoice)
+			wantCode(t, err, 0)
+			documentID := issued.Document.ID
+			if tt.setup != nil {
+				documentID = tt.setup(f, documentID)
+			}
+
+			got, err := f.service.ReissueDocument(asAdmin(), documentID, tt.body)
+			wantCode(t, err, tt.code)
+			if err != nil {
+				return
+			}
+			if got.Document.BuyerName != tt.buyer || got.Document.Number == issued.Document.Number {
+				t.Fatalf("got %+v", got.Document)
+			}
+
+			docs, err := f.service.GetPaymentDocuments(asAdmin(), payment.ID.String())
+			wantCode(t, err, 0)
+			statuses := map[string]models.DocumentStatus{}
+			for _, doc := range docs.Documents {
+				statuses[doc.ID] = doc.Status
+			}
+			if statuses[documentID] != models.DocumentStatusVoided || statuses[got.Document.ID] != models.DocumentStatusIssued {
+				t.Fatalf("got statuses %v", statuses)
+			}
+		})
+	}
+}
+
+func TestVoidDocument(t *testing.T) {
+	f := newFixture(t)
+	payment := f.paidOrder(1070)
+	issued, err := f.service.IssueTaxInvoice(asPatient(patientID), payment.ID.String(), taxInvoice)
+	wantCode(t, err, 0)
+
+	tests := []struct {
+		name       string
+		documentID string
+		code       apperr.Code
+	}{
+		{"voids", issued.Document.ID, 0},
+		{"already voided", issued.Document.ID, apperr.CodeConflict},
+		{"malformed", "nope", apperr.CodeBadRequest},
+		{"missing", utils.GenerateUUIDv7().String(), apperr.CodeNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := f.service.VoidDocument(asAdmin(), tt.documentID, dto.VoidDocumentRequestDto{Reason: "cancelled"})
+			wantCode(t, err, tt.code)
+			if err == nil && (got.Document.Status != models.DocumentStatusVoided || got.Document.VoidReason != "cancelled") {
+				t.Fatalf("got %+v", got.Document)
+			}
+		})
+	}
+
+	// a voided tax invoice can be issued again
+	_, err = f.service.IssueTaxInvoice(asPatient(patientID), payment.ID.String(), taxInvoice)
+	wantCode(t, err, 0)
+}
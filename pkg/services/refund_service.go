@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"payment-service/pkg/apperr"
+	contextUtils "payment-service/pkg/context"
+	"payment-service/pkg/dto"
+	"payment-service/pkg/i18n"
+	"payment-service/pkg/models"
+	"payment-service/pkg/repository"
+	"payment-service/pkg/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RefundPayment records a refund of part or all of a payment. The payment
+// is locked while its earlier refunds are added up, so concurrent refunds
+// cannot together give back more than was paid.
+func (s *PaymentService) RefundPayment(ctx context.Context, body dto.RefundPaymentRequestDto) (*dto.RefundPaymentResponseDto, error) {
+	paymentID := utils.StringToUUIDv7(body.PaymentID)
+	if paymentID == uuid.Nil {
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidPaymentID, nil)
+	}
+	amount := utils.ToSatang(body.Amount)
+	if amount <= 0 {
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.RefundNotPositive, nil)
+	}
+
+	refund := &models.Refund{
+		ID:         utils.GenerateUUIDv7(),
+		PaymentID:  paymentID,
+		Amount:     utils.FromSatang(amount),
+		RefundedBy: contextUtils.GetUserId(ctx),
+	}
+	if body.Reason != "" {
+		refund.Reason = &body.Reason
+	}
+
+	var refundable int64
+	err := s.unitOfWork.Do(ctx, func(ctx context.Context, repos repository.Repositories) error {
+		payment, err := repos.Payments.FindByIDForUpdate(ctx, paymentID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperr.New(apperr.CodeNotFound, i18n.PaymentNotFound, nil)
+			}
+			return apperr.New(apperr.CodeInternal, i18n.FailedRetrievePayment, err)
+		}
+
+		earlier, err := repos.Refunds.FindByPaymentIDs(ctx, []uuid.UUID{paymentID})
+		if err != nil {
+			return apperr.New(apperr.CodeInternal, i18n.FailedRetrieveRefunds, err)
+		}
+		refundable = utils.ToSatang(payment.Amount)
+		for _, r := range earlier {
+			refundable -= utils.ToSatang(r.Amount)
+		}
+		if amount > refundable {
+			return apperr.New(apperr.CodeConflict, i18n.RefundExceedsPaid, nil)
+		}
+
+		if err := repos.Refunds.Create(ctx, refund); err != nil {
+			return apperr.New(apperr.CodeInternal, i18n.FailedCreateRefund, err)
+		}
+		refundable -= amount
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.RefundPaymentResponseDto{
+		RefundID:   refund.ID.String(),
+		PaymentID:  refund.PaymentID.String(),
+		Amount:     refund.Amount,
+		Status:     dto.RefundStatusCompleted,
+		Refundable: utils.FromSatang(refundable),
+	}, nil
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"payment-service/pkg/apperr"
+	"payment-service/pkg/dto"
+	"payment-service/pkg/models"
+	"payment-service/pkg/repository/memory"
+	"payment-service/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+func TestRefundPayment(t *testing.T) {
+	f := newFixture(t)
+	payment := f.payment(f.attempt(f.order(patientID, 1000), f.card(patientID, "4111111111111111"), models.PaymentStatusSuccess), 1000, time.Now())
+
+	tests := []struct {
+		name       string
+		body       dto.RefundPaymentRequestDto
+		code       apperr.Code
+		refundable float64
+	}{
+		{"part of a payment", dto.RefundPaymentRequestDto{PaymentID: payment.ID.String(), Amount: 400.25, Reason: "item out of stock"}, 0, 599.75},
+		{"zero", dto.RefundPaymentRequestDto{PaymentID: payment.ID.String()}, apperr.CodeBadRequest, 0},
+		{"less than a satang", dto.RefundPaymentRequestDto{PaymentID: payment.ID.String(), Amount: 0.004}, apperr.CodeBadRequest, 0},
+		{"malformed", dto.RefundPaymentRequestDto{PaymentID: "nope", Amount: 1}, apperr.CodeBadRequest, 0},
+		{"missing", dto.RefundPaymentRequestDto{PaymentID: utils.GenerateUUIDv7().String(), Amount: 1}, apperr.CodeNotFound, 0},
+		{"more than is left", dto.RefundPaymentRequestDto{PaymentID: payment.ID.String(), Amount: 599.76}, apperr.CodeConflict, 0},
+		{"the rest", dto.RefundPaymentRequestDto{PaymentID: payment.ID.String(), Amount: 599.75}, 0, 0},
+		{"after a full refund", dto.RefundPaymentRequestDto{PaymentID: payment.ID.String(), Amount: 0.01}, apperr.CodeConflict, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := f.service.RefundPayment(asService(), tt.body)
+			wantCode(t, err, tt.code)
+			if err == nil && (got.Amount != tt.body.Amount || got.Refundable != tt.refundable || got.Status != dto.RefundStatusCompleted) {
+				t.Fatalf("got %+v", got)
+			}
+		})
+	}
+
+	refunds, err := memory.NewRefundRepository(f.db).FindByPaymentIDs(context.Background(), []uuid.UUID{payment.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(refunds) != 2 || refunds[0].Reason == nil || *refunds[0].Reason != "item out of stock" || refunds[0].RefundedBy != uuid.Nil.String() {
+		t.Fatalf("stored %+v", refunds)
+	}
+}
+
+func TestRefundPaymentConcurrently(t *testing.T) {
+	f := newFixture(t)
+	payment := f.payment(f.attempt(f.order(patientID, 100), f.card(patientID, "4111111111111111"), models.PaymentStatusSuccess), 100, time.Now())
+
+	// each refund fits on its own but not both together
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = f.service.RefundPayment(asService(), dto.RefundPaymentRequestDto{PaymentID: payment.ID.String(), Amount: 60})
+		}()
+	}
+	wg.Wait()
+
+	if (errs[0] == nil) == (errs[1] == nil) {
+		t.Fatalf("want exactly one refund to succeed, got %v and %v", errs[0], errs[1])
+	}
+	for _, err := range errs {
+		if err != nil && !apperr.IsCode(err, apperr.CodeConflict) {
+			t.Fatalf("got %v, want a conflict", err)
+		}
+	}
+}
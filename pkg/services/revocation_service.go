@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"payment-service/pkg/apperr"
+	contextUtils "payment-service/pkg/context"
+	"payment-service/pkg/dto"
+	"payment-service/pkg/i18n"
+	"payment-service/pkg/models"
+	"payment-service/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// RevokeToken denies one token by its jti, or every token a user holds,
+// until they would have expired anyway.
+func (s *PaymentService) RevokeToken(ctx context.Context, body dto.RevokeTokenRequestDto) (*dto.RevokeTokenResponseDto, error) {
+	if (body.Jti == "") == (body.UserID == "") {
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidRevocation, nil)
+	}
+
+	revocation := &models.TokenRevocation{
+		ID:        utils.GenerateUUIDv7(),
+		Reason:    optionalString(body.Reason),
+		RevokedBy: utils.StringToUUIDv7(contextUtils.GetUserId(ctx)),
+	}
+	if body.Jti != "" {
+		revocation.Jti = &body.Jti
+	} else {
+		userID := utils.StringToUUIDv7(body.UserID)
+		if userID == uuid.Nil {
+			return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidUserID, nil)
+		}
+		revocation.UserID = &userID
+	}
+
+	if err := s.revocations.Revoke(ctx, revocation); err != nil {
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedRevokeToken, err)
+	}
+
+	return &dto.RevokeTokenResponseDto{
+		Revocation: dto.ToTokenRevocationDto(revocation),
+	}, nil
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"payment-service/pkg/apperr"
+	contextUtils "payment-service/pkg/context"
+	"payment-service/pkg/dto"
+	"payment-service/pkg/i18n"
+	"payment-service/pkg/models"
+	"payment-service/pkg/repository"
+	"payment-service/pkg/risk"
+	"payment-service/pkg/utils"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// assessRisk gathers the velocity and card signals for a new attempt and
+// runs the risk rules over them. It runs in the unit of work that writes
+// the attempt, and locks the attempt's user, card and client IP first, so
+// a burst of attempts is counted one by one rather than all at once.
+func (s *PaymentService) assessRisk(ctx context.Context, risks repository.RiskAssessments, userID uuid.UUID, paymentInfo *models.PaymentInformation, amount float64) (*models.RiskAssessment, error) {
+	since := time.Now().Add(-s.riskConfig.Window)
+	signals := risk.Signals{
+		Amount:         amount,
+		RequestCountry: contextUtils.GetClientCountry(ctx),
+	}
+	assessment := &models.RiskAssessment{
+		ID:             utils.GenerateUUIDv7(),
+		UserID:         userID,
+		ClientIP:       optionalString(contextUtils.GetClientIP(ctx)),
+		RequestCountry: optionalString(signals.RequestCountry),
+		Amount:         amount,
+	}
+
+	card, hasCard := creditCardDetails(paymentInfo)
+	if hasCard {
+		if fingerprint := risk.CardFingerprint(s.riskConfig.FingerprintKey, card.CardNumber); fingerprint != "" {
+			signals.HasCard = true
+			assessment.CardFingerprint = &fingerprint
+		}
+		signals.CardCountry = strings.ToUpper(card.IssuerCountry)
+		assessment.CardCountry = optionalString(signals.CardCountry)
+	}
+
+	subjects := []string{"risk:user:" + userID.String()}
+	if assessment.ClientIP != nil {
+		subjects = append(subjects, "risk:ip:"+*assessment.ClientIP)
+	}
+	if assessment.CardFingerprint != nil {
+		subjects = append(subjects, "risk:card:"+*assessment.CardFingerprint)
+	}
+	if err := risks.Lock(ctx, subjects...); err != nil {
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedLockRiskSubjects, err)
+	}
+
+	var err error
+	if signals.UserAttempts, signals.UserFailed, signals.UserBlocked, err = risks.CountByUserSince(ctx, userID, since); err != nil {
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedCountUserAttempts, err)
+	}
+	if assessment.ClientIP != nil {
+		if signals.IPAttempts, err = risks.CountByIPSince(ctx, *assessment.ClientIP, since); err != nil {
+			return nil, apperr.New(apperr.CodeInternal, i18n.FailedCountClientAttempts, err)
+		}
+	}
+	if assessment.CardFingerprint != nil {
+		if signals.CardAttempts, err = risks.CountByCardSince(ctx, *assessment.CardFingerprint, since); err != nil {
+			return nil, apperr.New(apperr.CodeInternal, i18n.FailedCountCardAttempts, err)
+		}
+		seen, err := risks.CardSeen(ctx, *assessment.CardFingerprint)
+		if err != nil {
+			return nil, apperr.New(apperr.CodeInternal, i18n.FailedLookUpCard, err)
+		}
+		signals.NewCard = !seen
+	}
+
+	result := s.riskConfig.Evaluate(signals)
+	assessment.Decision = result.Decision
+	assessment.Reasons = result.Reasons
+	if assessment.Reasons == nil {
+		assessment.Reasons = []string{}
+	}
+	if result.Decision == models.RiskDecisionReview {
+		pending := models.ReviewStatusPending
+		assessment.ReviewStatus = &pending
+	}
+	return assessment, nil
+}
+
+func creditCardDetails(paymentInfo *models.PaymentInformation) (*dto.CreditCardDetails, bool) {
+	if paymentInfo == nil || paymentInfo.Type != models.PaymentMethodCreditCard {
+		return nil, false
+	}
+	var card dto.CreditCardDetails
+	if err := json.Unmarshal(rawDetails(paymentInfo), &card); err != nil {
+		return nil, false
+	}
+	return &card, true
+}
+
+func promptPayDetails(paymentInfo *models.PaymentInformation) (*dto.PromptPayDetails, bool) {
+	if paymentInfo == nil || paymentInfo.Type != models.PaymentMethodPromptPay {
+		return nil, false
+	}
+	var promptPay dto.PromptPayDetails
+	if err := json.Unmarshal(rawDetails(paymentInfo), &promptPay); err != nil {
+		return nil, false
+	}
+	return &promptPay, true
+}
+
+// rawDetails returns the details JSON of paymentInfo. CreatePaymentInfo
+// stores the details it was sent as a base64 JSON string.
+func rawDetails(paymentInfo *models.PaymentInformation) []byte {
+	var encoded []byte
+	if err := json.Unmarshal(paymentInfo.Details, &encoded); err == nil {
+		return encoded
+	}
+	return paymentInfo.Details
+}
+
+// attemptStatusFor maps a risk decision to the status a new attempt starts in.
+func attemptStatusFor(decision models.RiskDecision) models.PaymentStatus {
+	switch decision {
+	case models.RiskDecisionBlock:
+		return models.PaymentStatusFailed
+	case models.RiskDecisionReview:
+		return models.PaymentStatusPending
+	default:
+		return models.PaymentStatusSuccess
+	}
+}
+
+func (s *PaymentService) GetRiskReviews(ctx context.Context) (*dto.GetRiskReviewsResponseDto, error) {
+	assessments, err := s.riskAssessmentRepository.FindPendingReviews(ctx)
+	if err != nil {
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedRetrieveRiskReviews, err)
+	}
+
+	return &dto.GetRiskReviewsResponseDto{
+		Reviews: dto.ToRiskAssessmentDtoList(assessments),
+	}, nil
+}
+
+// ApproveRiskReview lets an attempt held for review go ahead.
+func (s *PaymentService) ApproveRiskReview(ctx context.Context, assessmentID string) (*dto.RiskReviewResponseDto, error) {
+	return s.closeRiskReview(ctx, assessmentID, models.ReviewStatusApproved, models.PaymentStatusSuccess)
+}
+
+// DenyRiskReview fails an attempt held for review.
+func (s *PaymentService) DenyRiskReview(ctx context.Context, assessmentID string) (*dto.RiskReviewResponseDto, error) {
+	return s.closeRiskReview(ctx, assessmentID, models.ReviewStatusDenied, models.PaymentStatusFailed)
+}
+
+func (s *PaymentService) closeRiskReview(ctx context.Context, assessmentID string, reviewStatus models.ReviewStatus, attemptStatus models.PaymentStatus) (*dto.RiskReviewResponseDto, error) {
+	id := utils.StringToUUIDv7(assessmentID)
+	if id == uuid.Nil {
+		return nil, apperr.New(apperr.CodeBadRequest, i18n.InvalidRiskReviewID, nil)
+	}
+
+	assessment, err := s.riskAssessmentRepository.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperr.New(apperr.CodeNotFound, i18n.RiskReviewNotFound, nil)
+		}
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedRetrieveRiskReview, err)
+	}
+
+	reviewedBy := utils.StringToUUIDv7(contextUtils.GetUserId(ctx))
+	reviewedAt := time.Now().UTC()
+	assessment.ReviewStatus = &reviewStatus
+	assessment.ReviewedBy = &reviewedBy
+	assessment.ReviewedAt = &reviewedAt
+
+	if err := s.riskAssessmentRepository.Review(ctx, assessment, attemptStatus); err != nil {
+		if errors.Is(err, repository.ErrReviewNotPending) {
+			return nil, apperr.New(apperr.CodeConflict, i18n.RiskReviewClosed, nil)
+		}
+		return nil, apperr.New(apperr.CodeInternal, i18n.FailedCloseRiskReview, err)
+	}
+	s.attemptEvents.Publish(assessment.AttemptID, attemptStatus)
+
+	return &dto.RiskReviewResponseDto{
+		Review:        dto.ToRiskAssessmentDto(assessment),
+		AttemptStatus: attemptStatus,
+	}, nil
+}
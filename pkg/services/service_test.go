@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"payment-service/pkg/apperr"
+	"payment-service/pkg/clients"
+	client_dto "payment-service/pkg/clients/dto"
+	"payment-service/pkg/constants"
+	contextUtils "payment-service/pkg/context"
+	"payment-service/pkg/events"
+	"payment-service/pkg/export"
+	"payment-service/pkg/i18n"
+	"payment-service/pkg/models"
+	"payment-service/pkg/payable"
+	"payment-service/pkg/receipt"
+	"payment-service/pkg/repository"
+	"payment-service/pkg/repository/memory"
+	"payment-service/pkg/revocation"
+	"payment-service/pkg/risk"
+	"payment-service/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// The in-memory repositories must stand in for the gorm ones.
+var (
+	_ repository.PaymentInformations = (*memory.PaymentInformationRepository)(nil)
+	_ repository.PaymentAttempts     = (*memory.PaymentAttemptRepository)(nil)
+	_ repository.Payments            = (*memory.PaymentRepository)(nil)
+	_ repository.PaymentLineItems    = (*memory.PaymentLineItemRepository)(nil)
+	_ repository.CoverageRules       = (*memory.CoverageRuleRepository)(nil)
+	_ repository.PayerReceivables    = (*memory.PayerReceivableRepository)(nil)
+	_ repository.PaymentDocuments    = (*memory.PaymentDocumentRepository)(nil)
+	_ repository.AuditLogs           = (*memory.AuditLogRepository)(nil)
+	_ repository.RiskAssessments     = (*memory.RiskAssessmentRepository)(nil)
+	_ repository.TokenRevocations    = (*memory.TokenRevocationRepository)(nil)
+	_ clients.Users                  = (*clients.CachedUserClient)(nil)
+)
+
+// receiptFont is a TrueType font that is usually installed; tests that
+// render a document are skipped without it.
+const receiptFont = "/usr/share/fonts/truetype/dejavu/DejaVuSans.ttf"
+
+var (
+	patientID      = utils.GenerateUUIDv7()
+	otherPatientID = utils.GenerateUUIDv7()
+	adminID        = utils.GenerateUUIDv7()
+	doctorID       = utils.GenerateUUIDv7()
+)
+
+func asUser(userID uuid.UUID, role string) context.Context {
+	ctx := context.WithValue(context.Background(), contextUtils.ContextKeyUserID, userID.String())
+	ctx = context.WithValue(ctx, contextUtils.ContextKeyRole, role)
+	return context.WithValue(ctx, contextUtils.ContextKeyLanguage, i18n.English)
+}
+
+func asPatient(userID uuid.UUID) context.Context { return asUser(userID, constants.RolePatient) }
+func asAdmin() context.Context                   { return asUser(adminID, constants.RoleAdmin) }
+func asService() context.Context                 { return asUser(uuid.Nil, constants.RoleService) }
+
+// fakeUsers is the user service, answering from maps.
+type fakeUsers struct {
+	patients     map[string]client_dto.GetPatientProfileResponseDto
+	doctors      map[string]client_dto.GetDoctorProfileResponseDto
+	entitlements map[string][]string
+}
+
+func (u *fakeUsers) GetDoctorByIds(ctx context.Context, doctorIDs []string) (*[]client_dto.GetDoctorProfileResponseDto, error) {
+	profiles := []client_dto.GetDoctorProfileResponseDto{}
+	for _, id := range doctorIDs {
+		if profile, ok := u.doctors[id]; ok {
+			profiles = append(profiles, profile)
+		}
+	}
+	return &profiles, nil
+}
+
+func (u *fakeUsers) GetDoctorById(ctx context.Context, doctorID string) (*client_dto.GetDoctorProfileResponseDto, error) {
+	profile, ok := u.doctors[doctorID]
+	if !ok {
+		return nil, apperr.New(apperr.CodeNotFound, i18n.NotFound, nil)
+	}
+	return &profile, nil
+}
+
+func (u *fakeUsers) GetPatientByIds(ctx context.Context, patientIDs []string) (*[]client_dto.GetPatientProfileResponseDto, error) {
+	profiles := []client_dto.GetPatientProfileResponseDto{}
+	for _, id := range patientIDs {
+		if profile, ok := u.patients[id]; ok {
+			profiles = append(profiles, profile)
+		}
+	}
+	return &profiles, nil
+}
+
+func (u *fakeUsers) GetPatientEntitlements(ctx context.Context, patientID string) (*[]client_dto.GetPatientEntitlementResponseDto, error) {
+	entitlements := []client_dto.GetPatientEntitlementResponseDto{}
+	for _, name := range u.entitlements[patientID] {
+		entitlements = append(entitlements, client_dto.GetPatientEntitlementResponseDto{HealthcareEntitlement: name})
+	}
+	return &entitlements, nil
+}
+
+// fixture is a PaymentService over in-memory repositories, payables and
+// user service.
+type fixture struct {
+	t        *testing.T
+	db       *memory.DB
+	service  *PaymentService
+	users    *fakeUsers
+	payables map[repository.PayableRef]payable.Payable
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	f := &fixture{
+		t:  t,
+		db: memory.New(),
+		users: &fakeUsers{
+			patients: map[string]client_dto.GetPatientProfileResponseDto{
+				patientID.String(): {ID: patientID.String(), FirstName: "Somchai", LastName: "Jaidee"},
+			},
+			doctors: map[string]client_dto.GetDoctorProfileResponseDto{
+				doctorID.String(): {ID: doctorID.String(), FirstName: "Suda", LastName: "Rakdee"},
+			},
+			entitlements: make(map[string][]string),
+		},
+		payables: make(map[repository.PayableRef]payable.Payable),
+	}
+
+	registry := payable.NewRegistry()
+	for _, payableType := range []models.PayableType{models.PayableTypeOrder, models.PayableTypeAppointment} {
+		registry.Register(payableType, payable.ResolverFunc(func(ctx context.Context, id uuid.UUID) (*payable.Payable, error) {
+			resolved, ok := f.payables[repository.PayableRef{Type: payableType, ID: id}]
+			if !ok {
+				return nil, apperr.New(apperr.CodeNotFound, i18n.NotFound, nil)
+			}
+			return &resolved, nil
+		}))
+	}
+
+	exportJobs, err := export.NewJobs(t.TempDir(), time.Hour, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	riskConfig := risk.DefaultConfig()
+	riskConfig.FingerprintKey = []byte("test")
+
+	f.service = NewPaymentService(Dependencies{
+		UnitOfWork:    memory.NewUnitOfWork(f.db),
+		Repositories:  memory.NewRepositories(f.db),
+		Users:         f.users,
+		Payables:      registry,
+		Renderer:      receipt.NewRenderer(receiptFont),
+		Seller:        receipt.Seller{Name: "Clinic", TaxID: "0105500000001", Address: "Bangkok", BranchCode: "00000", VatRate: 7},
+		Revocations:   revocation.NewStore(memory.NewTokenRevocationRepository(f.db), time.Hour, time.Minute),
+		Risk:          riskConfig,
+		ExportJobs:    exportJobs,
+		AttemptEvents: events.NewBroker(16, time.Hour),
+	})
+	return f
+}
+
+// order registers an order of owner with amountDue and returns its ID.
+func (f *fixture) order(owner uuid.UUID, amountDue float64) uuid.UUID {
+	id := utils.GenerateUUIDv7()
+	f.payables[repository.PayableRef{Type: models.PayableTypeOrder, ID: id}] = payable.Payable{
+		Type:      models.PayableTypeOrder,
+		ID:        id,
+		OwnerID:   owner,
+		AmountDue: amountDue,
+	}
+	return id
+}
+
+// appointment registers an appointment of owner with doctorID.
+func (f *fixture) appointment(owner uuid.UUID, fee float64) uuid.UUID {
+	id := utils.GenerateUUIDv7()
+	doctor := doctorID
+	f.payables[repository.PayableRef{Type: models.PayableTypeAppointment, ID: id}] = payable.Payable{
+		Type:      models.PayableTypeAppointment,
+		ID:        id,
+		OwnerID:   owner,
+		AmountDue: fee,
+		DoctorID:  &doctor,
+	}
+	return id
+}
+
+// card stores credit card details for owner, encoded as CreatePaymentInfo
+// stores them.
+func (f *fixture) card(owner uuid.UUID, number string) *models.PaymentInformation {
+	details, _ := json.Marshal(cardDetails(number))
+	return f.paymentInfo(owner, models.PaymentMethodCreditCard, details, 1)
+}
+
+func (f *fixture) promptPay(owner uuid.UUID) *models.PaymentInformation {
+	details, _ := json.Marshal([]byte(`{"promptpay_id":"0812345678","promptpay_type":"phone"}`))
+	return f.paymentInfo(owner, models.PaymentMethodPromptPay, details, 1)
+}
+
+func (f *fixture) paymentInfo(owner uuid.UUID, method models.PaymentMethod, details []byte, version int) *models.PaymentInformation {
+	f.t.Helper()
+	info := &models.PaymentInformation{
+		ID:      utils.GenerateUUIDv7(),
+		UserID:  owner,
+		Type:    method,
+		Details: details,
+		Version: version,
+	}
+	if err := memory.NewPaymentInformationRepository(f.db).Create(context.Background(), info); err != nil {
+		f.t.Fatal(err)
+	}
+	return info
+}
+
+// attempt stores an attempt on a registered payable with the given status.
+func (f *fixture) attempt(payableID uuid.UUID, info *models.PaymentInformation, status models.PaymentStatus, items ...models.PaymentLineItem) *models.PaymentAttempt {
+	f.t.Helper()
+	payableType := models.PayableTypeOrder
+	for ref := range f.payables {
+		if ref.ID == payableID {
+			payableType = ref.Type
+		}
+	}
+	attempt := &models.PaymentAttempt{
+		ID:                   utils.GenerateUUIDv7(),
+		PayableType:          payableType,
+		PayableID:            payableID,
+		PaymentInformationID: &info.ID,
+		UserID:               &info.UserID,
+		Method:               info.Type,
+		Status:               status,
+		LineItems:            items,
+	}
+	if err := memory.NewPaymentAttemptRepository(f.db).Create(context.Background(), attempt); err != nil {
+		f.t.Fatal(err)
+	}
+	return attempt
+}
+
+// payment stores a payment settling attempt.
+func (f *fixture) payment(attempt *models.PaymentAttempt, amount float64, paidAt time.Time) *models.Payment {
+	f.t.Helper()
+	payment := &models.Payment{
+		ID:            utils.GenerateUUIDv7(),
+		AttemptID:     attempt.ID,
+		Amount:        amount,
+		PatientAmount: amount,
+		PayableType:   attempt.PayableType,
+		PayableID:     attempt.PayableID,
+		PaidAt:        paidAt,
+	}
+	if err := memory.NewPaymentRepository(f.db).Create(context.Background(), payment); err != nil {
+		f.t.Fatal(err)
+	}
+	return payment
+}
+
+func cardDetails(number string) []byte {
+	return []byte(`{"card_number":"` + number + `","cvv":"123","expiry_month":12,"expiry_year":2030,"card_holder_name":"Somchai Jaidee"}`)
+}
+
+// wantCode fails unless err carries code; code 0 wants no error.
+func wantCode(t *testing.T, err error, code apperr.Code) {
+	t.Helper()
+	if code == 0 {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return
+	}
+	if !apperr.IsCode(err, code) {
+		t.Fatalf("got error %v, want code %s", err, code)
+	}
+}
+
+func skipWithoutFont(t *testing.T) {
+	t.Helper()
+	if _, err := os.Stat(receiptFont); err != nil {
+		t.Skipf("no receipt font: %v", err)
+	}
+}
@@ -0,0 +1,13 @@
+package utils
+
+import "math"
+
+// ToSatang converts a baht amount to whole satang so that sums of amounts
+// can be compared exactly instead of as floats.
+func ToSatang(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+func FromSatang(satang int64) float64 {
+	return float64(satang) / 100
+}
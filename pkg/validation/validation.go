@@ -0,0 +1,164 @@
+// Package validation decodes and validates request bodies and reports what
+// is wrong field by field, in words, keyed by the fields' JSON names.
+package validation
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"payment-service/pkg/apperr"
+	"payment-service/pkg/i18n"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/th"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	entranslations "github.com/go-playground/validator/v10/translations/en"
+	thtranslations "github.com/go-playground/validator/v10/translations/th"
+)
+
+type Validator struct {
+	validate *validator.Validate
+	trans    map[i18n.Lang]ut.Translator
+}
+
+func New() (*Validator, error) {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(jsonName)
+
+	uni := ut.New(en.New(), en.New(), th.New())
+	english, _ := uni.GetTranslator("en")
+	thai, _ := uni.GetTranslator("th")
+	if err := entranslations.RegisterDefaultTranslations(validate, english); err != nil {
+		return nil, fmt.Errorf("register English validation messages: %w", err)
+	}
+	if err := thtranslations.RegisterDefaultTranslations(validate, thai); err != nil {
+		return nil, fmt.Errorf("register Thai validation messages: %w", err)
+	}
+	return &Validator{
+		validate: validate,
+		trans:    map[i18n.Lang]ut.Translator{i18n.English: english, i18n.Thai: thai},
+	}, nil
+}
+
+// Struct validates s and returns an *apperr.Error listing every failed
+// field, or nil.
+func (v *Validator) Struct(s any) error {
+	err := v.validate.Struct(s)
+	var failures validator.ValidationErrors
+	if !errors.As(err, &failures) {
+		return err
+	}
+
+	fields := make(map[string]i18n.Text, len(failures))
+	for _, failure := range failures {
+		fields[fieldPath(failure)] = fieldMessage{v: v, failure: failure}
+	}
+	return apperr.Invalid(i18n.InvalidRequestBody, fields)
+}
+
+// DecodeJSON is the app's JSON decoder: it rejects unknown fields and
+// trailing data, then validates structs.
+func (v *Validator) DecodeJSON(b []byte, out any) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(out); err != nil {
+		return decodeError(err)
+	}
+	if err := dec.Decode(new(struct{})); err != io.EOF {
+		return apperr.New(apperr.CodeBadRequest, i18n.BodyNotSingleValue, nil)
+	}
+
+	rv := reflect.ValueOf(out)
+	for rv.Kind() == reflect.Pointer {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	return v.Struct(out)
+}
+
+// fieldMessage is a failure put in words in the client's language, without
+// the field name the translations mention, since the field is the key it is
+// filed under.
+type fieldMessage struct {
+	v       *Validator
+	failure validator.FieldError
+}
+
+func (m fieldMessage) In(lang i18n.Lang) string {
+	trans, ok := m.v.trans[lang]
+	if !ok {
+		trans = m.v.trans[i18n.English]
+	}
+	message := m.failure.Translate(trans)
+	if message == m.failure.Error() {
+		// no translation for this tag
+		return i18n.T(i18n.FieldFailedCheck, m.failure.Tag()).In(lang)
+	}
+	return strings.TrimPrefix(message, m.failure.Field()+" ")
+}
+
+// decodeError puts JSON decoding failures in words, filed under the field
+// they concern when there is one.
+func decodeError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return apperr.Invalid(i18n.InvalidRequestBody, map[string]i18n.Text{
+			typeErr.Field: i18n.T(jsonType(typeErr.Type)),
+		})
+	}
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return apperr.Invalid(i18n.InvalidRequestBody, map[string]i18n.Text{
+			strings.Trim(field, `"`): i18n.T(i18n.FieldUnknown),
+		})
+	}
+	return apperr.New(apperr.CodeBadRequest, i18n.BodyNotJSON, err)
+}
+
+// fieldPath is the failing field's path from the top of the body, such as
+// payables[0].payable_id.
+func fieldPath(failure validator.FieldError) string {
+	namespace := failure.Namespace()
+	if _, path, ok := strings.Cut(namespace, "."); ok {
+		return path
+	}
+	return namespace
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	default:
+		return name
+	}
+}
+
+// jsonType is the message saying what JSON a field of type t takes.
+func jsonType(t reflect.Type) i18n.Key {
+	switch t.Kind() {
+	case reflect.String:
+		return i18n.FieldString
+	case reflect.Bool:
+		return i18n.FieldBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return i18n.FieldInteger
+	case reflect.Float32, reflect.Float64:
+		return i18n.FieldNumber
+	case reflect.Slice, reflect.Array:
+		return i18n.FieldArray
+	default:
+		return i18n.FieldObject
+	}
+}